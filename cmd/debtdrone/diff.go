@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/config"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/store"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/spf13/cobra"
+)
+
+// databaseConfigured reports whether the environment points the CLI at a
+// database. DB_HOST has a default for the server, so the CLI only treats the
+// database as configured when it is set explicitly.
+func databaseConfigured() bool {
+	return os.Getenv("DB_HOST") != ""
+}
+
+// requireDatabase reports a usage error for flag when no database is configured.
+func requireDatabase(flag string) error {
+	if !databaseConfigured() {
+		return fmt.Errorf("%s requires a database: set DB_HOST (and DB_PORT, DB_USER, DB_PASSWORD, DB_NAME as needed)", flag)
+	}
+	return nil
+}
+
+// openDatabase connects to the configured database and checks its schema,
+// so an unreachable or unmigrated database exits 3 with one actionable error
+// rather than failing on the first query.
+func openDatabase(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DatabaseDSN())
+	if err != nil {
+		return nil, internalError(fmt.Errorf("failed to open database: %w", err))
+	}
+	if err := store.HealthCheck(ctx, db); err != nil {
+		db.Close()
+		return nil, internalError(err)
+	}
+	return db, nil
+}
+
+// openIssueStore opens the issue store of the configured database with
+// openDatabase, inserting ISSUE_BATCH_SIZE issues per statement.
+func openIssueStore(ctx context.Context) (*store.DBTechnicalDebtIssueStore, func() error, error) {
+	cfg := config.Load()
+	db, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	issueStore := store.NewDBTechnicalDebtIssueStore(db)
+	issueStore.BatchSize = cfg.IssueBatchSize
+	return issueStore, db.Close, nil
+}
+
+// parseDiffRun validates the --diff-run value before the scan starts.
+func parseDiffRun(value string) (uuid.UUID, error) {
+	runID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid --diff-run value %q: must be an analysis run ID", value)
+	}
+	if err := requireDatabase("--diff-run"); err != nil {
+		return uuid.Nil, err
+	}
+	return runID, nil
+}
+
+// diffAgainstRun loads the issues of the stored run baseRunID and compares the
+// current scan's issues against them.
+func diffAgainstRun(ctx context.Context, baseRunID uuid.UUID, issues []models.TechnicalDebtIssue) (analysis.RunDiff, error) {
+	issueStore, closeDB, err := openIssueStore(ctx)
+	if err != nil {
+		return analysis.RunDiff{}, err
+	}
+	defer closeDB()
+
+	base, err := issueStore.ListByAnalysisRun(ctx, baseRunID)
+	if err != nil {
+		return analysis.RunDiff{}, err
+	}
+
+	diff := analysis.DiffIssues(base, issues)
+	diff.BaseRunID = baseRunID
+	return diff, nil
+}
+
+// storedRepositoryName returns the full name, e.g. "acme/api", of the
+// repository checked out at path, from its origin remote. Stored runs are
+// recorded under the repository the server synced, whose ID has nothing in
+// common with the path-derived ID of a local scan, so the name is what ties
+// the two together.
+var storedRepositoryName = func(ctx context.Context, path string) (string, error) {
+	return git.NewService().GetRemoteFullName(ctx, path)
+}
+
+// newSinceLastRun returns the issues the latest completed run of their
+// repository did not record. repositories maps the Root of the issues to the
+// full name of the repository their runs are stored under. A repository
+// without a completed run contributes nothing, so the first scan establishes
+// the baseline.
+func newSinceLastRun(ctx context.Context, issues []models.TechnicalDebtIssue, repositories map[string]string) ([]models.TechnicalDebtIssue, error) {
+	issueStore, closeDB, err := openIssueStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	type previousRun struct {
+		keys  map[string]bool
+		found bool
+	}
+	previous := make(map[string]previousRun)
+
+	var fresh []models.TechnicalDebtIssue
+	for _, issue := range issues {
+		fullName := repositories[issue.Root]
+		run, loaded := previous[fullName]
+		if !loaded {
+			keys, found, err := issueStore.GetLatestRunIssueKeys(ctx, fullName)
+			if err != nil {
+				return nil, err
+			}
+			run = previousRun{keys: keys, found: found}
+			previous[fullName] = run
+		}
+		if !run.found {
+			continue
+		}
+
+		if !issueRecorded(run.keys, issue) {
+			fresh = append(fresh, issue)
+		}
+	}
+	return fresh, nil
+}
+
+// issueRecorded reports whether keys hold the analysis.DiffKey of issue.
+func issueRecorded(keys map[string]bool, issue models.TechnicalDebtIssue) bool {
+	return keys[analysis.DiffKey(issue)]
+}
+
+// runBaseline is the stored run --baseline-from-run pins as the baseline of a
+// scan: only the issues it did not record are reported.
+type runBaseline struct {
+	runID uuid.UUID
+	keys  map[string]bool
+}
+
+// loadRunBaseline validates the --baseline-from-run value and loads the issue
+// keys of the run, which must have been recorded for the repository checked
+// out at path, whichever machine recorded it. Its errors are already
+// classified for the exit code.
+func loadRunBaseline(ctx context.Context, value, path string) (*runBaseline, error) {
+	runID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, usageError(fmt.Errorf("invalid --baseline-from-run value %q: must be an analysis run ID", value))
+	}
+	if err := requireDatabase("--baseline-from-run"); err != nil {
+		return nil, usageError(err)
+	}
+	fullName, err := storedRepositoryName(ctx, path)
+	if err != nil {
+		return nil, usageError(fmt.Errorf("--baseline-from-run cannot identify the scanned repository: %w", err))
+	}
+
+	issueStore, closeDB, err := openIssueStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	runRepository, keys, err := issueStore.GetRunIssueKeys(ctx, runID)
+	if errors.Is(err, store.ErrRunNotFound) {
+		return nil, usageError(fmt.Errorf("invalid --baseline-from-run value: analysis run %s not found", runID))
+	}
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if runRepository == "" {
+		return nil, usageError(fmt.Errorf("invalid --baseline-from-run value: the repository of analysis run %s is no longer stored", runID))
+	}
+	if !strings.EqualFold(runRepository, fullName) {
+		return nil, usageError(fmt.Errorf("invalid --baseline-from-run value: analysis run %s belongs to repository %s, not to the scanned repository %s", runID, runRepository, fullName))
+	}
+	return &runBaseline{runID: runID, keys: keys}, nil
+}
+
+// newIssues returns the issues the baseline run did not record.
+func (b *runBaseline) newIssues(issues []models.TechnicalDebtIssue) []models.TechnicalDebtIssue {
+	fresh := []models.TechnicalDebtIssue{}
+	for _, issue := range issues {
+		if !issueRecorded(b.keys, issue) {
+			fresh = append(fresh, issue)
+		}
+	}
+	return fresh
+}
+
+// newSinceState returns the issues the run recorded in state did not find. A
+// nil state, before the first run is recorded, contributes nothing, just as a
+// repository without a stored run does for newSinceLastRun.
+func newSinceState(state *analysis.LocalState, issues []models.TechnicalDebtIssue) []models.TechnicalDebtIssue {
+	if state == nil {
+		return nil
+	}
+	return analysis.DiffIssues(state.IssuesForDiff(), issues).Added
+}
+
+// diffAgainstState compares the current scan's issues with the run recorded
+// in state.
+func diffAgainstState(state *analysis.LocalState, issues []models.TechnicalDebtIssue) analysis.RunDiff {
+	diff := analysis.DiffIssues(state.IssuesForDiff(), issues)
+	runAt := state.RunAt
+	diff.BaseRunAt = &runAt
+	return diff
+}
+
+// printDiff outputs a RunDiff as JSON or as a text table of the changes.
+func printDiff(cmd *cobra.Command, diff analysis.RunDiff, format string) error {
+	if strings.HasPrefix(strings.ToLower(format), "json") {
+		encoder := jsonEncoder(cmd)
+		return encoder.Encode(diff)
+	}
+
+	out := cmd.OutOrStdout()
+	base := "run " + diff.BaseRunID.String()
+	if diff.BaseRunAt != nil {
+		base = "the last run at " + diff.BaseRunAt.Format("2006-01-02 15:04 MST")
+	}
+	fmt.Fprintf(out, "Compared with %s: %d added, %d resolved, %d severity changed, %+.2fh debt\n",
+		base, len(diff.Added), len(diff.Resolved), len(diff.SeverityChanged), diff.DebtHoursDelta)
+	if len(diff.Added)+len(diff.Resolved)+len(diff.SeverityChanged) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tSEVERITY\tFILE:LINE\tMESSAGE")
+	fmt.Fprintln(w, "------\t--------\t---------\t-------")
+	for _, issue := range diff.Added {
+		fmt.Fprintf(w, "ADDED\t%s\t%s\t%s\n", strings.ToUpper(issue.Severity), issueLocation(issue), issue.Message)
+	}
+	for _, issue := range diff.Resolved {
+		fmt.Fprintf(w, "RESOLVED\t%s\t%s\t%s\n", strings.ToUpper(issue.Severity), issueLocation(issue), issue.Message)
+	}
+	for _, change := range diff.SeverityChanged {
+		severity := strings.ToUpper(change.OldSeverity) + "->" + strings.ToUpper(change.NewSeverity)
+		fmt.Fprintf(w, "CHANGED\t%s\t%s\t%s\n", severity, issueLocation(change.Issue), change.Issue.Message)
+	}
+	return w.Flush()
+}
+
+func issueLocation(issue models.TechnicalDebtIssue) string {
+	if issue.LineNumber != nil {
+		return fmt.Sprintf("%s:%d", issue.FilePath, *issue.LineNumber)
+	}
+	return issue.FilePath
+}
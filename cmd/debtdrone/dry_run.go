@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// runDryRun prints the plan of every root instead of scanning: the analyzers
+// that would run, the files per language and the skipped directories. As in
+// the scan itself, the container image is only planned for the first root.
+func runDryRun(ctx context.Context, cmd *cobra.Command, svc *service.ScanService, targetPaths, absPaths []string, opts service.ScanOptions, format string) error {
+	plans := make([]*service.ScanPlan, 0, len(absPaths))
+	for i, absPath := range absPaths {
+		if i > 0 {
+			opts.ContainerImage = ""
+		}
+		plan, err := svc.Plan(ctx, absPath, opts)
+		if errors.Is(err, git.ErrUnknownRef) {
+			return usageError(fmt.Errorf("invalid --only-changed-functions value: %w", err))
+		}
+		if err != nil {
+			return internalError(fmt.Errorf("planning the scan of %q failed: %w", targetPaths[i], err))
+		}
+		plans = append(plans, plan)
+	}
+
+	switch strings.ToLower(format) {
+	case "json", "json-full":
+		encoder := jsonEncoder(cmd)
+		if err := encoder.Encode(plans); err != nil {
+			return internalError(err)
+		}
+		return nil
+	}
+	for i, plan := range plans {
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		if err := printPlan(cmd, targetPaths[i], plan); err != nil {
+			return internalError(err)
+		}
+	}
+	return nil
+}
+
+// printPlan outputs the plan of one root as text.
+func printPlan(cmd *cobra.Command, targetPath string, plan *service.ScanPlan) error {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Dry run for %s (nothing was analyzed)\n", targetPath)
+	fmt.Fprintf(out, "Analyzers: %s\n\n", strings.Join(plan.Analyzers, ", "))
+
+	languages := make([]string, 0, len(plan.Languages))
+	for language := range plan.Languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tFILES")
+	fmt.Fprintln(w, "--------\t-----")
+	for _, language := range languages {
+		fmt.Fprintf(w, "%s\t%d\n", language, plan.Languages[language])
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\n", plan.Files)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(plan.SkippedDirs) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SKIPPED DIRECTORY\tREASON")
+	fmt.Fprintln(w, "-----------------\t------")
+	for _, dir := range plan.SkippedDirs {
+		fmt.Fprintf(w, "%s\t%s\n", dir.Path, dir.Reason)
+	}
+	return w.Flush()
+}
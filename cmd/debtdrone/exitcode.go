@@ -0,0 +1,40 @@
+package main
+
+import "errors"
+
+// Process exit codes. CI pipelines depend on these values to tell a failed
+// quality gate apart from a broken invocation or a tool failure, so they are
+// part of the public contract and must never be renumbered.
+const (
+	exitSuccess     = 0 // Scan completed and the quality gate (if any) passed
+	exitQualityGate = 1 // Findings at or above --fail-on, or a debt score over --debt-budget, were detected
+	exitUsage       = 2 // Invalid flags, arguments or target path
+	exitInternal    = 3 // The scan itself failed (repository, analyzer or I/O error)
+)
+
+// exitError attaches a process exit code to an error returned from a RunE.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+func qualityGateError(err error) error { return &exitError{code: exitQualityGate, err: err} }
+func usageError(err error) error       { return &exitError{code: exitUsage, err: err} }
+func internalError(err error) error    { return &exitError{code: exitInternal, err: err} }
+
+// exitCodeFor maps an error returned by rootCmd.Execute to a process exit code.
+// Errors without an attached code originate from cobra's own flag and argument
+// validation, so they are reported as usage errors.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitUsage
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitCodes_ScanScenarios(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	t.Setenv("DB_HOST", "")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{
+			name:     "Clean run without a gate exits 0",
+			args:     []string{"scan", testRepo},
+			wantCode: exitSuccess,
+		},
+		{
+			name:     "Quality gate failure exits 1",
+			args:     []string{"scan", testRepo, "--fail-on", "high"},
+			wantCode: exitQualityGate,
+		},
+		{
+			name:     "Invalid --fail-on value exits 2",
+			args:     []string{"scan", testRepo, "--fail-on", "severe"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Unknown analyzer name exits 2",
+			args:     []string{"scan", testRepo, "--analyzers", "complexity,duplication"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Out-of-range --min-confidence exits 2",
+			args:     []string{"scan", testRepo, "--min-confidence", "1.5"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Malformed --diff-run exits 2",
+			args:     []string{"scan", testRepo, "--diff-run", "not-a-uuid"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "--fail-on-new without --fail-on exits 2",
+			args:     []string{"scan", testRepo, "--fail-on-new"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "--fail-on-new without a database exits 2",
+			args:     []string{"scan", testRepo, "--fail-on", "low", "--fail-on-new"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Exceeded --timeout exits 3",
+			args:     []string{"scan", testRepo, "--timeout", "1ns"},
+			wantCode: exitInternal,
+		},
+		{
+			name:     "Negative --timeout exits 2",
+			args:     []string{"scan", testRepo, "--timeout", "-1s"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Missing --import file exits 2",
+			args:     []string{"scan", testRepo, "--import", filepath.Join(testRepo, "missing.json")},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Unknown flag exits 2",
+			args:     []string{"scan", testRepo, "--no-such-flag"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "One missing path among several exits 2",
+			args:     []string{"scan", testRepo, filepath.Join(testRepo, "missing")},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Missing scan path exits 2",
+			args:     []string{"scan", filepath.Join(testRepo, "does-not-exist")},
+			wantCode: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := createRootWithScan()
+			root.SilenceUsage = true
+
+			output, err := executeCommand(root, tt.args...)
+
+			if got := exitCodeFor(err); got != tt.wantCode {
+				t.Errorf("exitCodeFor() = %d, want %d (err: %v). Output:\n%s", got, tt.wantCode, err, output)
+			}
+		})
+	}
+}
+
+// failingWriter fails every write, like stdout redirected to a full disk or
+// a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("no space left on device") }
+
+func TestExitCodes_InternalFailure(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	t.Setenv("DB_HOST", "")
+
+	for _, format := range []string{"text", "json"} {
+		t.Run(format, func(t *testing.T) {
+			root := createRootWithScan()
+			root.SilenceUsage = true
+			root.SetOut(failingWriter{})
+			root.SetErr(new(bytes.Buffer))
+			root.SetArgs([]string{"scan", testRepo, "--format", format})
+
+			err := root.Execute()
+			if got := exitCodeFor(err); got != exitInternal {
+				t.Errorf("exitCodeFor() = %d, want %d when the report cannot be written (err: %v)", got, exitInternal, err)
+			}
+		})
+	}
+}
+
+func TestExitCodeFor_WrappedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"nil error", nil, exitSuccess},
+		{"quality gate", qualityGateError(errors.New("gate")), exitQualityGate},
+		{"usage", usageError(errors.New("bad flag")), exitUsage},
+		{"internal", internalError(errors.New("analyzer crashed")), exitInternal},
+		{"internal wrapped again", fmt.Errorf("outer: %w", internalError(errors.New("inner"))), exitInternal},
+		{"plain cobra error", errors.New("unknown command"), exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.wantCode {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.wantCode)
+			}
+		})
+	}
+}
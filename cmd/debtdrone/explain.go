@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/config"
+	"github.com/endrilickollari/debtdrone-cli/internal/store"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// runExplainIssue prints the explanation of a stored issue for
+// --explain-issue. An unknown or malformed ID is a usage error.
+func runExplainIssue(cmd *cobra.Command, issueID, format string) error {
+	if _, err := uuid.Parse(issueID); err != nil {
+		return usageError(fmt.Errorf("invalid --explain-issue value %q: must be an issue ID", issueID))
+	}
+	if err := requireDatabase("--explain-issue"); err != nil {
+		return usageError(err)
+	}
+	db, err := openDatabase(cmd.Context(), config.Load())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	explanation, err := store.NewDBIssueActivityStore(db).ExplainIssue(issueID)
+	if errors.Is(err, store.ErrIssueNotFound) {
+		return usageError(fmt.Errorf("invalid --explain-issue value: %w", err))
+	}
+	if err != nil {
+		return internalError(err)
+	}
+	if err := printExplanation(cmd, explanation, format); err != nil {
+		return internalError(err)
+	}
+	return nil
+}
+
+// printExplanation outputs an IssueExplanation as JSON or as text: the issue,
+// then its activity, related issues and type trend.
+func printExplanation(cmd *cobra.Command, explanation *store.IssueExplanation, format string) error {
+	if strings.HasPrefix(strings.ToLower(format), "json") {
+		encoder := jsonEncoder(cmd)
+		return encoder.Encode(explanation)
+	}
+
+	out := cmd.OutOrStdout()
+	issue := explanation.Issue
+	fmt.Fprintf(out, "Issue %s\n", issue.ID)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "Location\t%s\n", issueLocation(issue))
+	fmt.Fprintf(w, "Type\t%s (%s)\n", issue.IssueType, issue.Category)
+	fmt.Fprintf(w, "Severity\t%s\n", strings.ToUpper(issue.Severity))
+	fmt.Fprintf(w, "Status\t%s\n", issue.Status)
+	fmt.Fprintf(w, "Debt\t%.1fh\n", issue.TechnicalDebtHours)
+	fmt.Fprintf(w, "Message\t%s\n", issue.Message)
+	if issue.Description != nil && *issue.Description != "" {
+		fmt.Fprintf(w, "Description\t%s\n", *issue.Description)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	if len(explanation.Activity) == 0 {
+		fmt.Fprintln(out, "No recorded activity.")
+	} else {
+		w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "DATE\tACTIVITY\tBY\tDETAILS")
+		fmt.Fprintln(w, "----\t--------\t--\t-------")
+		for _, activity := range explanation.Activity {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", activity.CreatedAt.Format("2006-01-02 15:04"), activity.ActivityType, activity.UserName, activity.Details)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out)
+	if len(explanation.Related) == 0 {
+		fmt.Fprintln(out, "No related issues.")
+	} else {
+		w = tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "RELATED\tSEVERITY\tFILE:LINE\tMESSAGE")
+		fmt.Fprintln(w, "-------\t--------\t---------\t-------")
+		for _, related := range explanation.Related {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", related.ID, strings.ToUpper(related.Severity), issueLocation(related), related.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if trends := explanation.Trends; trends != nil {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "Trend for %s issues: %d total, %d open, %d resolved (%d in the last 30 days)",
+			trends.IssueType, trends.TotalOccurrences, trends.OpenCount, trends.ResolvedCount, trends.ResolvedLast30Days)
+		if trends.AvgResolutionTimeHours != nil {
+			fmt.Fprintf(out, ", %.1fh average resolution time", *trends.AvgResolutionTimeHours)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/store"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func TestScanCmd_ExplainIssueValidation(t *testing.T) {
+	t.Setenv("DB_HOST", "")
+
+	_, err := executeCommand(createRootWithScan(), "scan", "--explain-issue", "not-a-uuid")
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), `invalid --explain-issue value "not-a-uuid"`) {
+		t.Errorf("Expected a usage error for a malformed ID, got %v", err)
+	}
+
+	_, err = executeCommand(createRootWithScan(), "scan", "--explain-issue", uuid.New().String())
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "--explain-issue requires a database") {
+		t.Errorf("Expected a usage error without a database, got %v", err)
+	}
+}
+
+func TestScanCmd_ExplainIssueUnreachableDatabase(t *testing.T) {
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_PORT", "1")
+
+	_, err := executeCommand(createRootWithScan(), "scan", "--explain-issue", uuid.New().String())
+	if exitCodeFor(err) != exitInternal || !strings.Contains(err.Error(), "database unreachable") {
+		t.Errorf("Expected an internal error naming the unreachable database, got %v", err)
+	}
+}
+
+func TestPrintExplanation(t *testing.T) {
+	line := 42
+	avg := 12.5
+	description := "Split the function."
+	explanation := &store.IssueExplanation{
+		Issue: models.TechnicalDebtIssue{
+			ID: uuid.New(), FilePath: "/internal/app.go", LineNumber: &line, IssueType: "complexity", Category: "complexity",
+			Severity: "high", Status: "open", Message: "too complex", Description: &description, TechnicalDebtHours: 2,
+		},
+		Activity: []models.IssueActivityLog{{ActivityType: "created", UserName: "System", Details: "Issue detected during analysis", CreatedAt: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}},
+		Trends:   &models.IssueTrends{IssueType: "complexity", TotalOccurrences: 9, OpenCount: 5, ResolvedCount: 4, ResolvedLast30Days: 1, AvgResolutionTimeHours: &avg},
+	}
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := printExplanation(cmd, explanation, "text"); err != nil {
+		t.Fatalf("printExplanation failed: %v", err)
+	}
+	for _, want := range []string{
+		"/internal/app.go:42",
+		"HIGH",
+		"Split the function.",
+		"2026-01-02 03:04   created",
+		"No related issues.",
+		"Trend for complexity issues: 9 total, 5 open, 4 resolved (1 in the last 30 days), 12.5h average resolution time",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the text output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := printExplanation(cmd, explanation, "json"); err != nil {
+		t.Fatalf("printExplanation failed: %v", err)
+	}
+	var decoded struct {
+		Issue    models.TechnicalDebtIssue `json:"issue"`
+		Activity []json.RawMessage         `json:"activity"`
+		Trends   *models.IssueTrends       `json:"trends"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if decoded.Issue.ID != explanation.Issue.ID || len(decoded.Activity) != 1 || decoded.Trends == nil || decoded.Trends.OpenCount != 5 {
+		t.Errorf("Unexpected JSON explanation: %s", out.String())
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/integrations/github"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// githubCheckOptions holds the --github-* flags of scan.
+type githubCheckOptions struct {
+	enabled  bool
+	token    string
+	repo     string
+	sha      string
+	required bool
+}
+
+func (o *githubCheckOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.enabled, "github-check", false, "Publish the findings as a GitHub check run with inline annotations")
+	cmd.Flags().StringVar(&o.token, "github-token", "", "Token for --github-check (default: $GITHUB_TOKEN)")
+	cmd.Flags().StringVar(&o.repo, "github-repo", "", "Repository owner/name for --github-check (default: $GITHUB_REPOSITORY)")
+	cmd.Flags().StringVar(&o.sha, "github-sha", "", "Commit SHA the check run is attached to (default: $GITHUB_SHA)")
+	cmd.Flags().BoolVar(&o.required, "github-check-required", false, "Fail the scan (exit 3) when the check run cannot be published instead of warning")
+}
+
+// resolve fills the unset values from the environment GitHub Actions
+// provides and reports a usage error for what is still missing.
+func (o *githubCheckOptions) resolve() error {
+	if !o.enabled {
+		return nil
+	}
+	if o.token == "" {
+		o.token = os.Getenv("GITHUB_TOKEN")
+	}
+	if o.repo == "" {
+		o.repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if o.sha == "" {
+		o.sha = os.Getenv("GITHUB_SHA")
+	}
+
+	var missing []string
+	if o.token == "" {
+		missing = append(missing, "--github-token (or GITHUB_TOKEN)")
+	}
+	if o.repo == "" {
+		missing = append(missing, "--github-repo (or GITHUB_REPOSITORY)")
+	}
+	if o.sha == "" {
+		missing = append(missing, "--github-sha (or GITHUB_SHA)")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("--github-check requires %s", strings.Join(missing, ", "))
+	}
+	if owner, name, ok := strings.Cut(o.repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid --github-repo value %q: want owner/name", o.repo)
+	}
+	return nil
+}
+
+// publish creates the check run for issues. The conclusion follows the
+// quality gate: failure when it failed, success when it passed and neutral
+// when no gate was set. A failure to publish is only reported on stderr
+// unless the check is required.
+func (o *githubCheckOptions) publish(ctx context.Context, cmd *cobra.Command, issues []models.TechnicalDebtIssue, gate string) error {
+	if !o.enabled {
+		return nil
+	}
+
+	conclusion := "neutral"
+	switch gate {
+	case summaryGateFailed:
+		conclusion = "failure"
+	case summaryGatePassed:
+		conclusion = "success"
+	}
+	summary := analysis.Summarize(issues)
+	run := github.CheckRun{
+		Name:        github.DefaultCheckName,
+		HeadSHA:     o.sha,
+		Conclusion:  conclusion,
+		Title:       fmt.Sprintf("%d issues, %.1fh of technical debt", summary.TotalIssues, summary.TotalDebtHours),
+		Summary:     checkSummary(summary),
+		Annotations: github.AnnotationsFromIssues(issues),
+	}
+
+	client := github.NewClient(o.token, os.Getenv("GITHUB_API_URL"))
+	if _, err := client.CreateCheckRun(ctx, o.repo, run); err != nil {
+		err = fmt.Errorf("failed to publish the GitHub check run: %w", err)
+		if o.required {
+			return internalError(err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s%v\n", emoji("⚠️"), err)
+		return nil
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%sPublished GitHub check run with %d annotations\n", emoji("✅"), len(run.Annotations))
+	return nil
+}
+
+// checkSummary renders the markdown summary of the check run: the issue
+// counts by severity and by category.
+func checkSummary(summary analysis.RunSummary) string {
+	var b strings.Builder
+	b.WriteString("| Severity | Issues |\n|---|---|\n")
+	for _, severity := range []string{"critical", "high", "medium", "low", "info"} {
+		fmt.Fprintf(&b, "| %s | %d |\n", severity, summary.SeverityCounts[severity])
+	}
+	if categories := summary.SortedCategories(); len(categories) > 0 {
+		b.WriteString("\n| Category | Issues | Debt |\n|---|---|---|\n")
+		for _, category := range categories {
+			fmt.Fprintf(&b, "| %s | %d | %.1fh |\n", category.Category, category.Issues, category.DebtHours)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScanCmd_GitHubCheck(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	var conclusion string
+	var annotations int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/app/check-runs" || r.Header.Get("Authorization") != "Bearer env-token" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Conclusion string `json:"conclusion"`
+			HeadSHA    string `json:"head_sha"`
+			Output     struct {
+				Annotations []json.RawMessage `json:"annotations"`
+			} `json:"output"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid request body: %v", err)
+		}
+		if body.HeadSHA != "abc123" {
+			t.Errorf("Expected head_sha abc123, got %q", body.HeadSHA)
+		}
+		conclusion = body.Conclusion
+		annotations = len(body.Output.Annotations)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	t.Setenv("GITHUB_REPOSITORY", "octo/app")
+
+	root := createRootWithScan()
+	root.SilenceUsage = true
+	_, stderr, err := executeCommandWithStderr(root, "scan", testRepo, "--security-scan=false", "--github-check", "--github-sha", "abc123", "--fail-on", "low")
+	if exitCodeFor(err) != exitQualityGate {
+		t.Fatalf("Expected the quality gate to fail, got %v. Stderr:\n%s", err, stderr)
+	}
+	if conclusion != "failure" {
+		t.Errorf("Expected conclusion failure, got %q", conclusion)
+	}
+	if annotations == 0 {
+		t.Error("Expected the check run to carry annotations")
+	}
+	if !strings.Contains(stderr, "Published GitHub check run") {
+		t.Errorf("Expected a confirmation on stderr, got:\n%s", stderr)
+	}
+}
+
+func TestScanCmd_GitHubCheckFailures(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_SHA", "")
+	args := []string{"scan", testRepo, "--security-scan=false", "--github-check"}
+
+	root := createRootWithScan()
+	root.SilenceUsage = true
+	_, err := executeCommand(root, args...)
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "--github-token (or GITHUB_TOKEN)") {
+		t.Errorf("Expected a usage error naming the missing token, got %v", err)
+	}
+
+	args = append(args, "--github-token", "t", "--github-repo", "octo/app", "--github-sha", "abc123")
+	_, stderr, err := executeCommandWithStderr(createRootWithScan(), args...)
+	if err != nil {
+		t.Errorf("Expected a publishing failure to only warn, got %v", err)
+	}
+	if !strings.Contains(stderr, "failed to publish the GitHub check run") || !strings.Contains(stderr, "Resource not accessible") {
+		t.Errorf("Expected a warning on stderr, got:\n%s", stderr)
+	}
+
+	root = createRootWithScan()
+	root.SilenceUsage = true
+	_, err = executeCommand(root, append(args, "--github-check-required")...)
+	if exitCodeFor(err) != exitInternal {
+		t.Errorf("Expected --github-check-required to fail the scan with exit %d, got %v", exitInternal, err)
+	}
+}
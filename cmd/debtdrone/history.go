@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"text/tabwriter"
 	"time"
@@ -70,13 +69,13 @@ func newHistoryCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
 	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum number of historical entries to list")
+	addJSONPrettyFlag(cmd)
 
 	return cmd
 }
 
 func printHistoryJSON(cmd *cobra.Command, runs []models.AnalysisRun) error {
-	encoder := json.NewEncoder(cmd.OutOrStdout())
-	encoder.SetIndent("", "  ")
+	encoder := jsonEncoder(cmd)
 	return encoder.Encode(runs)
 }
 
@@ -36,7 +36,7 @@ ignore_paths:
 
 			// 3. Write to file
 			if err := os.WriteFile(configFilename, []byte(defaultConfig), 0644); err != nil {
-				return fmt.Errorf("failed to write .debtdrone.yaml: %w", err)
+				return internalError(fmt.Errorf("failed to write .debtdrone.yaml: %w", err))
 			}
 
 			fmt.Fprintln(cmd.OutOrStdout(), "Initialized .debtdrone.yaml successfully.")
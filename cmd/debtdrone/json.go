@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonPrettyFlag names the flag that switches JSON output between indented
+// and compact.
+const jsonPrettyFlag = "json-pretty"
+
+// addJSONPrettyFlag registers --json-pretty on cmd. It defaults to true, so
+// consumers of the indented output see no change.
+func addJSONPrettyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(jsonPrettyFlag, true, "Indent JSON output; --json-pretty=false writes compact single-line JSON, e.g. to keep CI logs small")
+}
+
+// jsonEncoder returns an encoder writing to the output of cmd, indented
+// unless --json-pretty=false is set. Commands without the flag indent.
+func jsonEncoder(cmd *cobra.Command) *json.Encoder {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	if jsonPretty(cmd) {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder
+}
+
+// jsonPretty reports whether JSON output of cmd is indented: unless
+// --json-pretty=false is set, or when cmd has no such flag.
+func jsonPretty(cmd *cobra.Command) bool {
+	pretty, err := cmd.Flags().GetBool(jsonPrettyFlag)
+	return err != nil || pretty
+}
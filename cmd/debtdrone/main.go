@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers/complexity"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
 	"github.com/endrilickollari/debtdrone-cli/internal/tui"
 	"github.com/endrilickollari/debtdrone-cli/internal/update"
 	"github.com/spf13/cobra"
@@ -45,7 +48,36 @@ func runAutoUpdate() {
 	}
 }
 
+// addTimeoutFlag registers the global --timeout flag on root. A positive value
+// puts a deadline on the context every subcommand receives from
+// cmd.Context(). The returned function releases the deadline and must be
+// called once Execute returns.
+func addTimeoutFlag(root *cobra.Command) func() {
+	var timeout time.Duration
+	cancel := func() {}
+	root.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the run after this duration, e.g. 90s or 10m (0 means no limit)")
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if timeout < 0 {
+			return usageError(fmt.Errorf("invalid --timeout value %s: must not be negative", timeout))
+		}
+		if timeout > 0 {
+			ctx, stop := context.WithTimeout(cmd.Context(), timeout)
+			cancel = stop
+			cmd.SetContext(ctx)
+		}
+		return nil
+	}
+	return func() { cancel() }
+}
+
 func main() {
+	// A misconfigured analyzer table would otherwise panic mid-scan or
+	// silently leave a language unanalyzed.
+	if err := complexity.ValidateLanguages(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitInternal)
+	}
+
 	// ── Root command ──────────────────────────────────────────────────────
 	//
 	// Cobra routing: when the user runs 'debtdrone' with no subcommand,
@@ -79,7 +111,10 @@ For CI/CD pipelines and scripted workflows, use the 'scan' subcommand:
 			runAutoUpdate()
 
 			fmt.Println("Starting DebtDrone TUI...")
-			return tui.RunTUI()
+			if err := tui.RunTUI(); err != nil {
+				return internalError(err)
+			}
+			return nil
 		},
 	}
 
@@ -89,10 +124,29 @@ For CI/CD pipelines and scripted workflows, use the 'scan' subcommand:
 
 	// ── Subcommands ───────────────────────────────────────────────────────
 	rootCmd.AddCommand(newScanCmd(), newInitCmd(), newConfigCmd(), newHistoryCmd())
+	cancelTimeout := addTimeoutFlag(rootCmd)
+	stopProfiles := addProfileFlags(rootCmd)
+	addNoColorFlag(rootCmd)
+
+	// Clones and exports of earlier runs that were killed before they could
+	// clean up would otherwise pile up on long-lived CI runners.
+	git.RemoveStaleTempDirs(git.DefaultStaleTempDirAge)
 
 	// Execute parses os.Args, routes to the matching command, and prints any
-	// error to stderr. We only need to set the exit code here.
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// error to stderr. We only need to translate it into the exit-code
+	// contract defined in exitcode.go.
+	ctx, stopSignals := cleanupOnSignal(context.Background())
+	err := rootCmd.ExecuteContext(ctx)
+	stopSignals()
+	git.RemoveTempDirs()
+	cancelTimeout()
+	if profileErr := stopProfiles(); profileErr != nil {
+		fmt.Fprintln(os.Stderr, "Error:", profileErr)
+		if err == nil {
+			err = internalError(profileErr)
+		}
+	}
+	if err != nil {
+		os.Exit(exitCodeFor(err))
 	}
 }
@@ -10,20 +10,36 @@ import (
 )
 
 // executeCommand is a test helper that runs a Cobra command with specific args
-// and captures its stdout and stderr into a single string.
+// and captures its stdout. Stderr, which carries the DEBTDRONE_SUMMARY line,
+// is discarded so machine-readable output can be parsed as is.
 func executeCommand(root *cobra.Command, args ...string) (output string, err error) {
-	buf := new(bytes.Buffer)
-	root.SetOut(buf)
-	root.SetErr(buf)
+	output, _, err = executeCommandWithStderr(root, args...)
+	return output, err
+}
+
+// executeCommandWithStderr runs a Cobra command and captures stdout and stderr
+// separately.
+func executeCommandWithStderr(root *cobra.Command, args ...string) (stdout, stderr string, err error) {
+	outBuf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	root.SetOut(outBuf)
+	root.SetErr(errBuf)
 	root.SetArgs(args)
 
 	err = root.Execute()
-	return buf.String(), err
+	return outBuf.String(), errBuf.String(), err
 }
 
 func TestMain(m *testing.M) {
-	// Standard TestMain for cleanup or setup if needed.
+	// Keep scan caches out of the real user cache directory.
+	cacheDir, err := os.MkdirTemp("", "debtdrone-cache")
+	if err != nil {
+		panic(err)
+	}
+	userCacheDir = func() (string, error) { return cacheDir, nil }
+
 	code := m.Run()
+	os.RemoveAll(cacheDir)
 	os.Exit(code)
 }
 
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/spf13/cobra"
+)
+
+// addProfileFlags registers the hidden global --cpuprofile and --memprofile
+// flags on root, for troubleshooting slow or memory hungry scans without a
+// custom build. CPU profiling starts before the subcommand runs; the
+// returned function stops it and writes the heap profile, and must be called
+// once Execute returns. Without the flags it does nothing. Like
+// addNoColorFlag it wraps the PersistentPreRunE already set on root.
+func addProfileFlags(root *cobra.Command) func() error {
+	var cpuProfile, memProfile string
+	root.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile of the run to `file` (for troubleshooting)")
+	root.PersistentFlags().StringVar(&memProfile, "memprofile", "", "Write a heap profile at the end of the run to `file` (for troubleshooting)")
+	root.PersistentFlags().MarkHidden("cpuprofile")
+	root.PersistentFlags().MarkHidden("memprofile")
+
+	var cpuFile *os.File
+	next := root.PersistentPreRunE
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if cpuProfile != "" {
+			f, err := os.Create(cpuProfile)
+			if err != nil {
+				return usageError(fmt.Errorf("invalid --cpuprofile value: %w", err))
+			}
+			if err := pprof.StartCPUProfile(f); err != nil {
+				f.Close()
+				return internalError(fmt.Errorf("failed to start CPU profile: %w", err))
+			}
+			cpuFile = f
+		}
+		if next != nil {
+			return next(cmd, args)
+		}
+		return nil
+	}
+
+	return func() error {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return fmt.Errorf("failed to write CPU profile: %w", err)
+			}
+			cpuFile = nil
+		}
+		if memProfile == "" {
+			return nil
+		}
+		f, err := os.Create(memProfile)
+		if err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		defer f.Close()
+		// Collect garbage first so the profile shows live memory only.
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return f.Close()
+	}
+}
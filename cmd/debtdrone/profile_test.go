@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddProfileFlags(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.pprof")
+	memProfile := filepath.Join(dir, "mem.pprof")
+
+	root := createRootWithScan()
+	stop := addProfileFlags(root)
+	if _, err := executeCommand(root, "scan", testRepo, "--security-scan=false", "--cpuprofile", cpuProfile, "--memprofile", memProfile); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("Failed to write the profiles: %v", err)
+	}
+	for _, path := range []string{cpuProfile, memProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Expected a non-empty profile at %s: %v", path, err)
+		}
+	}
+
+	// The flags are for troubleshooting and stay out of the help output.
+	root = createRootWithScan()
+	addProfileFlags(root)
+	help, err := executeCommand(root, "scan", "--help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(help, "cpuprofile") {
+		t.Errorf("Expected --cpuprofile to be hidden, got:\n%s", help)
+	}
+}
+
+func TestAddProfileFlags_Unset(t *testing.T) {
+	root := createRootWithScan()
+	stop := addProfileFlags(root)
+	if _, err := executeCommand(root, "scan", setupTestRepo(t), "--security-scan=false"); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Errorf("Expected no profiling without the flags, got: %v", err)
+	}
+}
@@ -18,7 +18,7 @@ func printReport(issues []models.TechnicalDebtIssue, format string) {
 		return
 	}
 
-	fmt.Printf("\n📊 Analysis Report\n")
+	fmt.Printf("\n%sAnalysis Report\n", emoji("📊"))
 	fmt.Printf("==================\n")
 	fmt.Printf("Total Issues: %d\n\n", len(issues))
 
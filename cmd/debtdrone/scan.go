@@ -2,151 +2,1020 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"net"
+	"net/url"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers/complexity"
+	"github.com/endrilickollari/debtdrone-cli/internal/archive"
+	"github.com/endrilickollari/debtdrone-cli/internal/config"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/report"
 	"github.com/endrilickollari/debtdrone-cli/internal/service"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
 // newScanCmd constructs the 'debtdrone scan' subcommand for headless execution.
 func newScanCmd() *cobra.Command {
 	var (
-		format        string
-		failOn        string
-		maxComplexity int
-		securityScan  bool
+		format         string
+		failOn         string
+		maxComplexity  int
+		securityScan   bool
+		strict         bool
+		configPath     string
+		enabled        []string
+		disabled       []string
+		minConfidence  float64
+		diffRun        string
+		baselineRun    string
+		failOnNew      bool
+		stateFile      string
+		sinceLastRun   bool
+		noGitignore    bool
+		withGenerated  bool
+		noCache        bool
+		imports        []string
+		golangci       string
+		staged         bool
+		changedSince   string
+		ref            string
+		archivePath    string
+		deadCode       bool
+		outliers       bool
+		licenseScan    bool
+		profile        string
+		image          string
+		quiet          bool
+		dryRun         bool
+		listLanguages  bool
+		listFormats    bool
+		explainIssue   string
+		jobs           int
+		textWidth      int
+		subprocessMem  string
+		subprocessCPUs int
+		showSuppressed bool
+		mergeIssues    bool
+		maxIssues      int
+		debtBudget     int
+		debtWeights    map[string]int
+		githubCheck    githubCheckOptions
 	)
 
 	cmd := &cobra.Command{
-		Use:   "scan [path]",
+		Use:   "scan [path...]",
 		Short: "Run a headless technical debt scan",
 		Long: `Scan a repository for technical debt without launching the TUI.
-This command is optimized for CI/CD pipelines and automated workflows.`,
-		Args: cobra.MaximumNArgs(1),
+This command is optimized for CI/CD pipelines and automated workflows.
+
+Several paths may be given to scan multiple roots (e.g. monorepo services)
+in one invocation; their findings are combined into a single report and a
+single quality-gate decision.`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// 1. Resolve Target Path
-			targetPath := "."
-			if len(args) > 0 {
-				targetPath = args[0]
+			if listLanguages {
+				return printLanguages(cmd, format)
+			}
+			if listFormats {
+				return printFormats(cmd, format)
+			}
+			if explainIssue != "" {
+				return runExplainIssue(cmd, explainIssue, format)
+			}
+
+			// 1. Resolve Target Paths
+			targetPaths := args
+			if archivePath != "" {
+				if len(args) > 0 {
+					return usageError(fmt.Errorf("--archive cannot be combined with scan paths"))
+				}
+				if info, err := os.Stat(archivePath); err == nil && info.IsDir() {
+					return usageError(fmt.Errorf("invalid --archive value %q: is a directory", archivePath))
+				}
+				targetPaths = []string{archivePath}
+			}
+			if len(targetPaths) == 0 {
+				targetPaths = []string{"."}
+			}
+			absPaths := make([]string, len(targetPaths))
+			for i, targetPath := range targetPaths {
+				absPath, err := filepath.Abs(targetPath)
+				if err != nil {
+					return usageError(fmt.Errorf("failed to resolve path %q: %w", targetPath, err))
+				}
+				if _, err := os.Stat(absPath); err != nil {
+					return usageError(fmt.Errorf("invalid scan path %q: %w", targetPath, err))
+				}
+				absPaths[i] = absPath
+			}
+
+			if err := report.Default().Validate(format); err != nil {
+				return usageError(fmt.Errorf("invalid --format value: %w", err))
+			}
+
+			// Validate the gate threshold up front so a typo never costs a full scan.
+			severityMap := map[string]int{
+				"critical": 4,
+				"high":     3,
+				"medium":   2,
+				"low":      1,
+			}
+			requestedThreshold, ok := severityMap[strings.ToLower(failOn)]
+			if failOn != "" && !ok {
+				return usageError(fmt.Errorf("invalid --fail-on value: %q (valid: critical, high, medium, low)", failOn))
+			}
+
+			var weights map[string]int
+			if cmd.Flags().Changed("debt-budget") {
+				if debtBudget < 0 {
+					return usageError(fmt.Errorf("invalid --debt-budget value: %d (must be 0 or greater)", debtBudget))
+				}
+				if err := analysis.ValidateSeverityWeights(debtWeights); err != nil {
+					return usageError(fmt.Errorf("invalid --debt-weights value: %w", err))
+				}
+				weights = make(map[string]int, len(analysis.DefaultSeverityWeights)+len(debtWeights))
+				for severity, weight := range analysis.DefaultSeverityWeights {
+					weights[severity] = weight
+				}
+				for severity, weight := range debtWeights {
+					weights[strings.ToLower(severity)] = weight
+				}
+			} else if len(debtWeights) > 0 {
+				return usageError(fmt.Errorf("--debt-weights requires --debt-budget"))
+			}
+
+			if maxIssues < 0 {
+				return usageError(fmt.Errorf("invalid --max-issues value: %d (must be 0 or greater)", maxIssues))
+			}
+
+			if err := analysis.ValidateMinConfidence(minConfidence); err != nil {
+				return usageError(fmt.Errorf("invalid --min-confidence value: %w", err))
+			}
+
+			// storedRepositories maps each scan path to the repository its
+			// stored runs are recorded under, for --fail-on-new.
+			var storedRepositories map[string]string
+			if failOnNew {
+				if failOn == "" {
+					return usageError(fmt.Errorf("--fail-on-new requires --fail-on"))
+				}
+				// A state file stands in for the database.
+				if stateFile == "" {
+					if err := requireDatabase("--fail-on-new"); err != nil {
+						return usageError(err)
+					}
+					storedRepositories = make(map[string]string, len(absPaths))
+					for i, absPath := range absPaths {
+						fullName, err := storedRepositoryName(cmd.Context(), absPath)
+						if err != nil {
+							return usageError(fmt.Errorf("--fail-on-new cannot identify the repository of %q: %w", targetPaths[i], err))
+						}
+						storedRepositories[targetPaths[i]] = fullName
+					}
+				}
+			}
+
+			// A staged-only scan would report every unstaged issue as resolved.
+			if staged && diffRun != "" {
+				return usageError(fmt.Errorf("--staged cannot be combined with --diff-run"))
+			}
+			if changedSince != "" && (staged || diffRun != "") {
+				return usageError(fmt.Errorf("--only-changed-functions cannot be combined with --staged or --diff-run"))
+			}
+			// A ref is scanned from its git objects, not the working tree
+			// that staged and changed-since scans compare against.
+			if ref != "" && (staged || changedSince != "" || dryRun) {
+				return usageError(fmt.Errorf("--ref cannot be combined with --staged, --only-changed-functions or --dry-run"))
+			}
+			// An archive has no git history or working tree to compare.
+			if archivePath != "" && (ref != "" || staged || changedSince != "" || dryRun) {
+				return usageError(fmt.Errorf("--archive cannot be combined with --ref, --staged, --only-changed-functions or --dry-run"))
+			}
+			if sinceLastRun {
+				if stateFile == "" {
+					return usageError(fmt.Errorf("--since-last-run requires --state-file"))
+				}
+				if staged || changedSince != "" || diffRun != "" {
+					return usageError(fmt.Errorf("--since-last-run cannot be combined with --staged, --only-changed-functions or --diff-run"))
+				}
+			}
+			// A pinned run replaces the baselines kept by --state-file and
+			// the latest stored run.
+			if baselineRun != "" {
+				if failOnNew || stateFile != "" || diffRun != "" {
+					return usageError(fmt.Errorf("--baseline-from-run cannot be combined with --fail-on-new, --state-file or --diff-run"))
+				}
+				if len(absPaths) > 1 {
+					return usageError(fmt.Errorf("--baseline-from-run takes a single scan path, got %d", len(absPaths)))
+				}
+			}
+
+			var previousState *analysis.LocalState
+			if stateFile != "" {
+				state, found, err := analysis.LoadLocalState(stateFile)
+				if err != nil {
+					return usageError(fmt.Errorf("invalid --state-file: %w", err))
+				}
+				if found {
+					previousState = state
+				}
+			}
+
+			var baseRunID uuid.UUID
+			if diffRun != "" {
+				runID, err := parseDiffRun(diffRun)
+				if err != nil {
+					return usageError(err)
+				}
+				baseRunID = runID
+			}
+
+			// The baseline run is loaded up front so a wrong ID or a run
+			// of another repository never costs a full scan.
+			var baseline *runBaseline
+			if baselineRun != "" {
+				loaded, err := loadRunBaseline(cmd.Context(), baselineRun, absPaths[0])
+				if err != nil {
+					return err
+				}
+				baseline = loaded
+			}
+
+			if jobs < 1 {
+				return usageError(fmt.Errorf("invalid --jobs value: %d (must be at least 1)", jobs))
 			}
-			absPath, err := filepath.Abs(targetPath)
+
+			if textWidth < 0 {
+				return usageError(fmt.Errorf("invalid --width value: %d (must be 0 for automatic or greater)", textWidth))
+			}
+
+			subprocessLimits := analysis.SubprocessLimits{Memory: subprocessMem, CPUs: subprocessCPUs}
+			if err := analysis.ValidateSubprocessLimits(subprocessLimits); err != nil {
+				return usageError(fmt.Errorf("invalid subprocess limit: %w", err))
+			}
+
+			if err := githubCheck.resolve(); err != nil {
+				return usageError(err)
+			}
+
+			registry := service.DefaultRegistry()
+			if err := registry.Validate(enabled); err != nil {
+				return usageError(fmt.Errorf("invalid --analyzers value: %w", err))
+			}
+			if err := registry.Validate(disabled); err != nil {
+				return usageError(fmt.Errorf("invalid --disable-analyzers value: %w", err))
+			}
+
+			projectConfig, err := config.LoadProjectConfig(configPath, cmd.Flags().Changed("config"))
+			if err != nil {
+				return usageError(err)
+			}
+			if err := analysis.ValidateSeverityOverrides(projectConfig.SeverityOverrides); err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			effortRules, err := effortRulesFromConfig(projectConfig.EffortMultipliers)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			minified, err := minifiedThresholdsFromConfig(projectConfig.Minified)
 			if err != nil {
-				return fmt.Errorf("failed to resolve path %q: %w", targetPath, err)
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			securityDebt, err := securityDebtCostsFromConfig(projectConfig.SecurityDebt)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			licensePolicy, err := licensePolicyFromConfig(projectConfig.Licenses)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			endpointPolicy, err := endpointPolicyFromConfig(projectConfig.Endpoints)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			licensePolicy.Enabled = licensePolicy.Enabled || licenseScan
+			if licensePolicy.Enabled && !securityScan {
+				return usageError(fmt.Errorf("--license-scan requires the security scan (remove --security-scan=false)"))
+			}
+			var analysisProfile models.AnalysisProfile
+			if cmd.Flags().Changed("profile") {
+				if analysisProfile, err = models.ParseAnalysisProfile(profile); err != nil {
+					return usageError(fmt.Errorf("invalid --profile value: %w", err))
+				}
+			} else if analysisProfile, err = models.ParseAnalysisProfile(projectConfig.AnalysisDepth); err != nil {
+				return usageError(fmt.Errorf("%s: analysis_depth: %w", configPath, err))
+			}
+			languageThresholds, err := languageThresholdsFromConfig(projectConfig.Thresholds.Languages)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			refactoringDocs, err := refactoringDocsFromConfig(projectConfig.RefactoringDocs)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			generatedPatterns, err := generatedPatternsFromConfig(projectConfig.GeneratedFiles)
+			if err != nil {
+				return usageError(fmt.Errorf("%s: %w", configPath, err))
+			}
+			if projectConfig.Thresholds.MaxFunctionsPerFile < 0 {
+				return usageError(fmt.Errorf("%s: thresholds.max_functions_per_file must not be negative, got %d", configPath, projectConfig.Thresholds.MaxFunctionsPerFile))
+			}
+			if projectConfig.Thresholds.MaxReturns < 0 {
+				return usageError(fmt.Errorf("%s: thresholds.max_returns must not be negative, got %d", configPath, projectConfig.Thresholds.MaxReturns))
+			}
+
+			var imported []models.TechnicalDebtIssue
+			for _, importPath := range imports {
+				found, err := analysis.LoadExternalIssues(importPath)
+				if err != nil {
+					return usageError(fmt.Errorf("invalid --import file: %w", err))
+				}
+				imported = append(imported, found...)
+			}
+			if golangci != "" {
+				found, err := analysis.LoadGolangCIReport(golangci)
+				if err != nil {
+					return usageError(fmt.Errorf("invalid --golangci report: %w", err))
+				}
+				imported = append(imported, found...)
 			}
 
 			// 2. Engine Initialization & Execution
 			svc := service.NewScanService()
-			ctx := context.WithValue(context.Background(), "isCLI", true)
+			ctx := analysis.WithCLI(cmd.Context())
 			opts := service.ScanOptions{
-				MaxComplexity: maxComplexity,
-				SecurityScan:  securityScan,
+				Profile:           analysisProfile,
+				MaxComplexity:     maxComplexity,
+				SecurityScan:      securityScan,
+				Strict:            strict,
+				Analyzers:         enabled,
+				DisabledAnalyzers: disabled,
+				BlockingAPIs:      projectConfig.BlockingCalls,
+				NoGitignore:       noGitignore,
+				ToolVersion:       version,
+				Staged:            staged,
+				ChangedSince:      changedSince,
+				Ref:               ref,
+				Archive:           archivePath != "",
+				DeadCode:          deadCode,
+				ContainerImage:    image,
+				Minified:          minified,
+				SecurityDebt:      securityDebt,
+				LicensePolicy:     licensePolicy,
+				EndpointPolicy:    endpointPolicy,
+				Jobs:              jobs,
+				SubprocessLimits:  subprocessLimits,
+
+				MaxFunctionsPerFile: projectConfig.Thresholds.MaxFunctionsPerFile,
+				MaxReturns:          projectConfig.Thresholds.MaxReturns,
+				OutlierDetection:    outliers,
+				LanguageThresholds:  languageThresholds,
+				RefactoringDocs:     refactoringDocs,
+				IncludeGenerated:    withGenerated,
+				GeneratedPatterns:   generatedPatterns,
+			}
+			if !noCache {
+				opts.CacheDir = scanCacheDir()
+			}
+			if dryRun {
+				return runDryRun(ctx, cmd, svc, targetPaths, absPaths, opts, format)
 			}
 
-			// Execute the scan synchronously (no progress bars in headless mode)
-			issues, err := svc.Run(ctx, absPath, opts, nil)
-			if err != nil {
-				return fmt.Errorf("scan failed: %w", err)
+			// Execute the scans synchronously (no progress bars in headless mode).
+			// Each root is analyzed independently and its issues are tagged with
+			// the path it came from before being merged into a single report.
+			//
+			// When --timeout expires, the results gathered so far are still
+			// reported before the scan fails.
+			var issues []models.TechnicalDebtIssue
+			var suppressed []analysis.Suppression
+			var skipped analysis.Skipped
+			metrics := make(map[string]interface{})
+			var timedOut error
+			// refCommit is the commit --ref resolved to for the first root,
+			// which the imported findings are linked at.
+			var refCommit string
+			// truncated is set once --max-issues issues were collected; the
+			// roots after that are not scanned.
+			truncated := false
+			for i, absPath := range absPaths {
+				// The container image belongs to no root in particular, so
+				// it is only scanned once, with the first.
+				if i > 0 {
+					opts.ContainerImage = ""
+				}
+				if maxIssues > 0 {
+					if len(issues) >= maxIssues {
+						truncated = true
+						break
+					}
+					opts.MaxIssues = maxIssues - len(issues)
+				}
+				result, err := svc.Run(ctx, absPath, opts, nil)
+				if err != nil && errors.Is(err, context.DeadlineExceeded) && result != nil {
+					timedOut = fmt.Errorf("scan timed out (--timeout) while scanning %q; the results above are partial: %w", targetPaths[i], err)
+				} else if errors.Is(err, archive.ErrInvalid) {
+					return usageError(fmt.Errorf("invalid --archive file %q: %w", targetPaths[i], err))
+				} else if errors.Is(err, git.ErrUnknownRef) && ref != "" {
+					return usageError(fmt.Errorf("invalid --ref value for %q: %w", targetPaths[i], err))
+				} else if errors.Is(err, git.ErrUnknownRef) {
+					return usageError(fmt.Errorf("invalid --only-changed-functions value: %w", err))
+				} else if err != nil {
+					return internalError(fmt.Errorf("scan of %q failed: %w", targetPaths[i], err))
+				}
+				for j := range result.Issues {
+					result.Issues[j].Root = targetPaths[i]
+				}
+				if i == 0 {
+					refCommit = result.Commit
+				}
+				if !staged && archivePath == "" {
+					attachPermalinks(ctx, absPath, result.Commit, result.Issues)
+				}
+				issues = append(issues, result.Issues...)
+				for j := range result.Suppressed {
+					result.Suppressed[j].Issue.Root = targetPaths[i]
+				}
+				suppressed = append(suppressed, result.Suppressed...)
+				skipped.Merge(&result.Skipped)
+				mergeLineCounts(metrics, result.Metrics)
+				mergeComplexityStats(metrics, result.Metrics)
+				truncated = truncated || result.Truncated
+				if timedOut != nil {
+					break
+				}
+			}
+			// Imported findings belong to the first root: their file paths
+			// are relative to it.
+			for i := range imported {
+				imported[i].RepositoryID = service.RepositoryID(absPaths[0])
+				imported[i].Root = targetPaths[0]
+				imported[i].FingerprintHash = imported[i].Fingerprint()
+			}
+			if !staged && archivePath == "" {
+				attachPermalinks(ctx, absPaths[0], refCommit, imported)
+			}
+			if maxIssues > 0 && len(issues)+len(imported) > maxIssues {
+				imported = imported[:max(maxIssues-len(issues), 0)]
+				truncated = true
+			}
+			issues = append(issues, imported...)
+			if truncated {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: stopped collecting issues at %d (--max-issues); the report and the quality gate cover only these, and no state is recorded.\n", maxIssues)
+			}
+			if len(absPaths) > 1 {
+				issues = dedupeIssues(issues, targetPaths, absPaths)
+			}
+
+			// Severity overrides, effort multipliers and the confidence filter
+			// are applied once, here, so every output format and the quality
+			// gate see the same adjusted values.
+			analysis.ApplySeverityOverrides(issues, projectConfig.SeverityOverrides)
+			analysis.ApplyEffortMultipliers(issues, effortRules)
+			issues = analysis.FilterByConfidence(issues, minConfidence)
+			if mergeIssues {
+				issues = analysis.MergeIssues(issues)
+			}
+			if baseline != nil {
+				issues = baseline.newIssues(issues)
 			}
 
 			// 3. Output Formatting
-			switch strings.ToLower(format) {
-			case "json":
-				if err := printJSON(cmd, issues); err != nil {
-					return err
+			summary := analysis.Summarize(issues)
+			summary.Truncated = truncated
+			summary.ComplexityHistogram, _ = metrics["complexity_histogram"].(models.ComplexityHistogram)
+			// A partial or truncated scan is never diffed: every issue it
+			// missed would be reported as resolved.
+			complete := timedOut == nil && !truncated
+			if diffRun != "" && complete {
+				diff, err := diffAgainstRun(ctx, baseRunID, issues)
+				if err != nil {
+					return internalError(err)
 				}
-			default:
-				if err := printText(cmd, issues); err != nil {
-					return err
+				if err := printDiff(cmd, diff, format); err != nil {
+					return internalError(err)
+				}
+			} else if sinceLastRun && previousState != nil && complete {
+				if err := printDiff(cmd, diffAgainstState(previousState, issues), format); err != nil {
+					return internalError(err)
+				}
+			} else {
+				if sinceLastRun && previousState == nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "No previous run recorded in %s yet; showing the full report.\n", stateFile)
+				}
+				if !showSuppressed {
+					suppressed = nil
+				}
+				reporter, err := report.Default().New(format, report.Options{
+					Width:      reportWidth(textWidth, cmd.OutOrStdout()),
+					Quiet:      quiet,
+					Pretty:     jsonPretty(cmd),
+					Suppressed: suppressed,
+					Skipped:    &skipped,
+					Metrics:    metrics,
+				})
+				if err != nil {
+					return internalError(err)
+				}
+				if err := reporter.Report(cmd.OutOrStdout(), issues, summary); err != nil {
+					return internalError(err)
 				}
 			}
 
+			if timedOut != nil {
+				return internalError(timedOut)
+			}
+
 			// 4. CI/CD Quality Gate Logic
+			gate := summaryGateOff
+			var gateErr error
 			if failOn != "" {
-				severityMap := map[string]int{
-					"critical": 4,
-					"high":     3,
-					"medium":   2,
-					"low":      1,
-				}
-
-				requestedThreshold, ok := severityMap[strings.ToLower(failOn)]
-				if !ok {
-					return fmt.Errorf("invalid --fail-on value: %q (valid: critical, high, medium, low)", failOn)
+				gate = summaryGatePassed
+				gateIssues := issues
+				scope := "issues"
+				if failOnNew && stateFile != "" {
+					gateIssues = newSinceState(previousState, issues)
+					scope = "new issues"
+				} else if failOnNew {
+					gateIssues, err = newSinceLastRun(ctx, issues, storedRepositories)
+					if err != nil {
+						return internalError(err)
+					}
+					scope = "new issues"
 				}
-
-				for _, issue := range issues {
+				for _, issue := range gateIssues {
 					if issueSeverity, exists := severityMap[strings.ToLower(issue.Severity)]; exists {
 						if issueSeverity >= requestedThreshold {
+							gate = summaryGateFailed
 							// Return a custom error that Cobra will handle
-							return fmt.Errorf("quality gate failed: found issues matching or exceeding severity '%s'", failOn)
+							gateErr = qualityGateError(fmt.Errorf("quality gate failed: found %s matching or exceeding severity '%s'", scope, failOn))
+							break
 						}
 					}
 				}
 			}
+			// The debt budget weighs every issue of the report, alongside
+			// --fail-on rather than instead of it.
+			if weights != nil {
+				score := analysis.ComputeDebtScore(issues, weights)
+				fmt.Fprintf(cmd.ErrOrStderr(), "Debt score: %d (budget %d): %s\n", score.Score, debtBudget, score.Breakdown())
+				if gate == summaryGateOff {
+					gate = summaryGatePassed
+				}
+				if score.Score > debtBudget {
+					gate = summaryGateFailed
+					if gateErr == nil {
+						gateErr = qualityGateError(fmt.Errorf("quality gate failed: debt score %d exceeds --debt-budget %d", score.Score, debtBudget))
+					}
+				}
+			}
+
+			// A partial scan would record every issue it skipped as
+			// resolved, and recording a run that failed the gate would let
+			// a re-run pass it.
+			if stateFile != "" && !staged && changedSince == "" && !truncated && gate != summaryGateFailed {
+				if err := analysis.NewLocalState(issues, version, time.Now()).Save(stateFile); err != nil {
+					return internalError(err)
+				}
+			}
+
+			checkErr := githubCheck.publish(ctx, cmd, issues, gate)
 
-			return nil
+			fmt.Fprintln(cmd.ErrOrStderr(), summaryLine(summary, gate))
+			if checkErr != nil {
+				return checkErr
+			}
+			return gateErr
 		},
 	}
 
 	// Flags
-	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json, json-full or one listed by --list-formats")
+	cmd.Flags().IntVar(&textWidth, "width", 0, "Width the text report is fitted to, truncating long paths and messages (default: COLUMNS, the terminal width or 80)")
 	cmd.Flags().StringVar(&failOn, "fail-on", "", "Fail the build if issues with this severity or higher are found (critical, high, medium, low)")
+	cmd.Flags().IntVar(&debtBudget, "debt-budget", 0, "Fail the build if the severity-weighted debt score of the issues exceeds this budget (works alongside --fail-on)")
+	cmd.Flags().StringToIntVar(&debtWeights, "debt-weights", nil, "Points per issue of each severity in the --debt-budget score, e.g. critical=8,low=1 (default: critical=4,high=2,medium=1,low=0)")
 	cmd.Flags().IntVar(&maxComplexity, "max-complexity", 15, "Cyclomatic complexity threshold per function")
 	cmd.Flags().BoolVar(&securityScan, "security-scan", true, "Enable security vulnerability scanning")
+	cmd.Flags().StringVar(&image, "image", "", "Also scan the container image `ref` with Trivy; it is never inferred from a Dockerfile, so nothing is pulled unasked")
+	cmd.Flags().IntVar(&jobs, "jobs", 1, "Number of analyzers to run concurrently, e.g. the security scan alongside the complexity analysis")
+	cmd.Flags().StringVar(&subprocessMem, "subprocess-memory-limit", "", "Soft memory limit for external scanners such as Trivy, in GOMEMLIMIT syntax (e.g. 2GiB)")
+	cmd.Flags().IntVar(&subprocessCPUs, "subprocess-cpus", 0, "Soft cap on the CPUs external scanners such as Trivy use (0: no cap)")
+	cmd.Flags().StringVar(&configPath, "config", config.ProjectConfigFile, "Path to the project configuration file")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail the scan (exit 3) if any analyzer errors instead of skipping it")
+	cmd.Flags().StringSliceVar(&enabled, "analyzers", nil, "Comma-separated analyzers to run (default: all): "+strings.Join(service.DefaultRegistry().Names(), ", "))
+	cmd.Flags().StringVar(&profile, "profile", string(models.DefaultAnalysisProfile), "Analysis profile when --analyzers is not set: quick, standard or deep (overrides analysis_depth)")
+	cmd.Flags().BoolVar(&licenseScan, "license-scan", false, "Also report dependency licenses with Trivy's license scanner (off by default)")
+	addJSONPrettyFlag(cmd)
+	cmd.Flags().BoolVar(&deadCode, "dead-code", false, "Report unexported Go functions nothing in their package uses (heuristic, off by default)")
+	cmd.Flags().BoolVar(&outliers, "outlier-detection", false, "Report functions far longer or more complex than the repository's own average (needs at least 30 functions, off by default)")
+	cmd.Flags().StringSliceVar(&disabled, "disable-analyzers", nil, "Comma-separated analyzers to skip")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.0, "Drop issues whose confidence score is below this value (0.0-1.0)")
+	cmd.Flags().BoolVar(&failOnNew, "fail-on-new", false, "Apply --fail-on only to issues not found by the last run recorded in --state-file or, without it, the repository's last stored run (requires DB_HOST)")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "Record each run's issues and summary in this local JSON `file` (e.g. "+analysis.DefaultStateFile+"), for --fail-on-new and --since-last-run without a database")
+	cmd.Flags().BoolVar(&sinceLastRun, "since-last-run", false, "Report how this scan differs from the run recorded in --state-file")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-analyze every file instead of reusing results cached for unchanged files")
+	cmd.Flags().BoolVar(&withGenerated, "include-generated", false, "Report complexity in generated files too (e.g. *.pb.go or files marked \"Code generated ... DO NOT EDIT.\"), which are skipped by default")
+	cmd.Flags().BoolVar(&noGitignore, "no-gitignore", false, "Analyze files excluded by .gitignore rules too (tracked files are always analyzed)")
+	cmd.Flags().BoolVar(&staged, "staged", false, "Analyze only the files staged in the git index, skipping the security scan (for pre-commit hooks)")
+	cmd.Flags().StringArrayVar(&imports, "import", nil, "Merge findings of other tools from a JSON or YAML file into the report and gate (repeatable)")
+	cmd.Flags().StringVar(&golangci, "golangci", "", "Merge the issues of a golangci-lint JSON report into the report and gate")
+	cmd.Flags().BoolVar(&quiet, "quiet", false, "In text output, print only the findings table, without the breakdowns and summary footer")
+	cmd.Flags().IntVar(&maxIssues, "max-issues", service.DefaultMaxIssues, "Stop collecting issues once this many were found and mark the report as truncated (0: no limit)")
+	cmd.Flags().BoolVar(&mergeIssues, "merge-issues", false, "Collapse issues several tools report on the same file, line and category into one, keeping the highest severity")
+	cmd.Flags().BoolVar(&showSuppressed, "show-suppressed", false, "List the issues dropped by debtdrone:ignore annotations and why (text and json-full formats)")
+	cmd.Flags().StringVar(&changedSince, "only-changed-functions", "", "Analyze only the files changed since this git `ref` and report complexity only for the functions whose body changed, skipping the security scan (for pull requests)")
+	cmd.Flags().StringVar(&ref, "ref", "", "Analyze the repository as of this git commit, tag or branch `ref`, read from the git objects without checking it out")
+	cmd.Flags().StringVar(&archivePath, "archive", "", "Analyze the sources in this .tar.gz, .tgz, .tar or .zip `file`, such as a build artifact, from a temporary extraction; git-based features are skipped")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the analyzers, files per language and skipped directories a scan would cover, then exit without analyzing")
+	cmd.Flags().BoolVar(&listLanguages, "list-languages", false, "Print the languages and file extensions the complexity analysis supports, then exit")
+	cmd.Flags().BoolVar(&listFormats, "list-formats", false, "Print the output formats --format accepts, then exit")
+	githubCheck.addFlags(cmd)
+	cmd.Flags().StringVar(&explainIssue, "explain-issue", "", "Print a stored issue with its activity log, related issues and issue type trend, then exit without scanning (requires DB_HOST)")
+	cmd.Flags().StringVar(&baselineRun, "baseline-from-run", "", "Report and gate only the issues the stored analysis run `id` of this repository did not find (requires DB_HOST)")
+	cmd.Flags().StringVar(&diffRun, "diff-run", "", "Report how this scan differs from a stored analysis run (requires DB_HOST)")
 
 	return cmd
 }
 
-// printJSON outputs the scan results as a pretty-printed JSON array.
-func printJSON(cmd *cobra.Command, issues []models.TechnicalDebtIssue) error {
-	if issues == nil {
-		issues = []models.TechnicalDebtIssue{}
+// userCacheDir is os.UserCacheDir, replaced in tests.
+var userCacheDir = os.UserCacheDir
+
+// scanCacheDir returns the directory of the per-repository analysis caches,
+// or "" (no caching) when the platform has no user cache directory.
+func scanCacheDir() string {
+	dir, err := userCacheDir()
+	if err != nil {
+		return ""
 	}
-	encoder := json.NewEncoder(cmd.OutOrStdout())
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(issues)
+	return filepath.Join(dir, "debtdrone")
 }
 
-// printText outputs the scan results in a clean table using text/tabwriter.
-func printText(cmd *cobra.Command, issues []models.TechnicalDebtIssue) error {
-	if len(issues) == 0 {
-		fmt.Fprintln(cmd.OutOrStdout(), "No technical debt issues found.")
-		return nil
+// Values of the gate key of the DEBTDRONE_SUMMARY line.
+const (
+	summaryGateOff    = "off"
+	summaryGatePassed = "passed"
+	summaryGateFailed = "failed"
+)
+
+// summaryLine renders the DEBTDRONE_SUMMARY line written to stderr after every
+// scan. Its keys, their order and the value formats are a stable interface for
+// shell scripts; new keys may only be appended.
+func summaryLine(summary analysis.RunSummary, gate string) string {
+	debtHours := strconv.FormatFloat(math.Round(summary.TotalDebtHours*100)/100, 'f', -1, 64)
+	return fmt.Sprintf("DEBTDRONE_SUMMARY issues=%d critical=%d high=%d medium=%d low=%d debt_hours=%s gate=%s truncated=%t",
+		summary.TotalIssues,
+		summary.SeverityCounts["critical"],
+		summary.SeverityCounts["high"],
+		summary.SeverityCounts["medium"],
+		summary.SeverityCounts["low"],
+		debtHours,
+		gate,
+		summary.Truncated,
+	)
+}
+
+// effortRulesFromConfig converts the effort_multipliers entries of the project
+// config and validates them.
+func effortRulesFromConfig(entries []config.EffortMultiplierRule) ([]analysis.EffortRule, error) {
+	rules := make([]analysis.EffortRule, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Multiplier == nil {
+			return nil, fmt.Errorf("effort_multipliers entry %q: multiplier is required", entry.Path)
+		}
+		rules = append(rules, analysis.EffortRule{Pattern: entry.Path, Multiplier: *entry.Multiplier})
 	}
+	if err := analysis.ValidateEffortRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
 
-	// Initialize tabwriter for a clean columnar layout
-	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
-	
-	// Print Header
-	fmt.Fprintln(w, "SEVERITY\tFILE:LINE\tRULE\tMESSAGE")
-	fmt.Fprintln(w, "--------\t---------\t----\t-------")
+// minifiedThresholdsFromConfig converts the minified section of the project
+// config and validates it. Extensions may be given with or without the dot.
+func minifiedThresholdsFromConfig(cfg config.MinifiedConfig) (models.MinifiedThresholds, error) {
+	if cfg.AvgLineLength < 0 {
+		return models.MinifiedThresholds{}, fmt.Errorf("minified.avg_line_length must not be negative, got %d", cfg.AvgLineLength)
+	}
+	if cfg.MinBytes < 0 {
+		return models.MinifiedThresholds{}, fmt.Errorf("minified.min_bytes must not be negative, got %d", cfg.MinBytes)
+	}
+	thresholds := models.MinifiedThresholds{AvgLineLength: cfg.AvgLineLength, MinBytes: cfg.MinBytes}
+	for _, ext := range cfg.Extensions {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			return models.MinifiedThresholds{}, fmt.Errorf("minified.extensions must not contain empty entries")
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		thresholds.Extensions = append(thresholds.Extensions, ext)
+	}
+	return thresholds, nil
+}
+
+// languageThresholdsFromConfig converts thresholds.languages of the project
+// config to the thresholds of each language named, starting from its
+// defaults, and validates it.
+func languageThresholdsFromConfig(cfg map[string]config.LanguageThresholdsConfig) (map[string]models.ComplexityThresholds, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	defaults := complexity.DefaultLanguageThresholds()
+	languages := make(map[string]string, len(defaults))
+	for language := range defaults {
+		languages[strings.ToLower(language)] = language
+	}
 
+	thresholds := make(map[string]models.ComplexityThresholds, len(cfg))
+	for _, key := range slices.Sorted(maps.Keys(cfg)) {
+		language, ok := languages[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("unknown language %q in thresholds.languages (valid: %s)", key, strings.Join(slices.Sorted(maps.Keys(languages)), ", "))
+		}
+		if _, dup := thresholds[language]; dup {
+			return nil, fmt.Errorf("thresholds.languages lists %s more than once", language)
+		}
+		override := cfg[key]
+		t := defaults[language]
+		for _, field := range []struct {
+			name  string
+			value int
+			dst   *int
+		}{
+			{"cyclomatic_high", override.CyclomaticHigh, &t.CyclomaticHigh},
+			{"cyclomatic_critical", override.CyclomaticCritical, &t.CyclomaticCritical},
+			{"cognitive_high", override.CognitiveHigh, &t.CognitiveHigh},
+			{"cognitive_critical", override.CognitiveCritical, &t.CognitiveCritical},
+			{"nesting_warning", override.NestingWarning, &t.NestingWarning},
+			{"nesting_critical", override.NestingCritical, &t.NestingCritical},
+			{"parameter_warning", override.ParameterWarning, &t.ParameterWarning},
+			{"parameter_critical", override.ParameterCritical, &t.ParameterCritical},
+			{"lines_of_code_warning", override.LinesOfCodeWarning, &t.LinesOfCodeWarning},
+			{"lines_of_code_critical", override.LinesOfCodeCritical, &t.LinesOfCodeCritical},
+		} {
+			if field.value < 0 {
+				return nil, fmt.Errorf("thresholds.languages.%s.%s must not be negative, got %d", key, field.name, field.value)
+			}
+			if field.value > 0 {
+				*field.dst = field.value
+			}
+		}
+		for _, pair := range []struct {
+			name              string
+			warning, critical int
+		}{
+			{"cyclomatic_high", t.CyclomaticHigh, t.CyclomaticCritical},
+			{"cognitive_high", t.CognitiveHigh, t.CognitiveCritical},
+			{"nesting_warning", t.NestingWarning, t.NestingCritical},
+			{"parameter_warning", t.ParameterWarning, t.ParameterCritical},
+			{"lines_of_code_warning", t.LinesOfCodeWarning, t.LinesOfCodeCritical},
+		} {
+			if pair.warning > pair.critical {
+				return nil, fmt.Errorf("thresholds.languages.%s.%s (%d) must not exceed its critical value (%d)", key, pair.name, pair.warning, pair.critical)
+			}
+		}
+		thresholds[language] = t
+	}
+	return thresholds, nil
+}
+
+// securityDebtCostsFromConfig converts the security_debt section of the
+// project config and validates it.
+func securityDebtCostsFromConfig(cfg config.SecurityDebtConfig) (models.SecurityDebtCosts, error) {
+	valid := models.DefaultSecurityDebtCosts().SeverityHours
+	for _, severity := range slices.Sorted(maps.Keys(cfg.SeverityHours)) {
+		if _, ok := valid[strings.ToLower(severity)]; !ok {
+			return models.SecurityDebtCosts{}, fmt.Errorf("invalid security_debt.severity_hours key %q (valid: critical, high, medium, low, info)", severity)
+		}
+		if cfg.SeverityHours[severity] < 0 {
+			return models.SecurityDebtCosts{}, fmt.Errorf("security_debt.severity_hours.%s must not be negative, got %v", severity, cfg.SeverityHours[severity])
+		}
+	}
+	if cfg.SecretHours < 0 {
+		return models.SecurityDebtCosts{}, fmt.Errorf("security_debt.secret_hours must not be negative, got %v", cfg.SecretHours)
+	}
+	for _, category := range slices.Sorted(maps.Keys(cfg.SecretCategoryHours)) {
+		if cfg.SecretCategoryHours[category] < 0 {
+			return models.SecurityDebtCosts{}, fmt.Errorf("security_debt.secret_category_hours.%s must not be negative, got %v", category, cfg.SecretCategoryHours[category])
+		}
+	}
+	return models.SecurityDebtCosts{
+		SeverityHours:       cfg.SeverityHours,
+		SecretHours:         cfg.SecretHours,
+		SecretCategoryHours: cfg.SecretCategoryHours,
+	}, nil
+}
+
+// licensePolicyFromConfig converts the licenses section of the project
+// config and validates it.
+func licensePolicyFromConfig(cfg config.LicensesConfig) (models.LicensePolicy, error) {
+	for _, license := range cfg.Allow {
+		if strings.TrimSpace(license) == "" {
+			return models.LicensePolicy{}, fmt.Errorf("licenses.allow must not contain empty entries")
+		}
+	}
+	return models.LicensePolicy{Enabled: cfg.Scan, Allowed: cfg.Allow}, nil
+}
+
+// endpointPolicyFromConfig converts the endpoints section of the project
+// config and validates it.
+func endpointPolicyFromConfig(cfg config.EndpointsConfig) (models.EndpointPolicy, error) {
+	for _, host := range cfg.AllowHosts {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			return models.EndpointPolicy{}, fmt.Errorf("endpoints.allow_hosts must not contain empty entries")
+		}
+		if strings.Contains(host, "/") {
+			if _, _, err := net.ParseCIDR(host); err != nil {
+				return models.EndpointPolicy{}, fmt.Errorf("endpoints.allow_hosts: invalid CIDR range %q", host)
+			}
+		}
+	}
+	return models.EndpointPolicy{
+		AllowedHosts:    cfg.AllowHosts,
+		IncludeTests:    cfg.IncludeTests,
+		IncludeComments: cfg.IncludeComments,
+	}, nil
+}
+
+// refactoringDocsFromConfig applies the refactoring_docs section of the
+// project config to the built-in catalog, after checking that every URL is
+// an absolute http(s) URL or empty.
+func refactoringDocsFromConfig(overrides map[string]string) (map[string]string, error) {
+	for suggestionType, link := range overrides {
+		if link == "" {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("refactoring_docs.%s: expected an http or https URL, got %q", suggestionType, link)
+		}
+	}
+	return models.RefactoringDocs(overrides), nil
+}
+
+// generatedPatternsFromConfig validates the generated_files patterns of the
+// project config.
+func generatedPatternsFromConfig(patterns []string) ([]string, error) {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return nil, fmt.Errorf("generated_files must not contain empty patterns")
+		}
+		if err := analysis.ValidatePathPattern(pattern); err != nil {
+			return nil, fmt.Errorf("generated_files: invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return patterns, nil
+}
+
+// dedupeIssues drops issues reported more than once because the scanned roots
+// overlap (e.g. "." and "./svc-a"). Identity is the issue fingerprint computed
+// over the file's absolute location, so the same finding reached through two
+// roots collapses to the first occurrence.
+func dedupeIssues(issues []models.TechnicalDebtIssue, roots, absRoots []string) []models.TechnicalDebtIssue {
+	absRootFor := make(map[string]string, len(roots))
+	for i, root := range roots {
+		absRootFor[root] = absRoots[i]
+	}
+
+	seen := make(map[string]bool, len(issues))
+	deduped := make([]models.TechnicalDebtIssue, 0, len(issues))
 	for _, issue := range issues {
-		// Format File:Line
-		location := issue.FilePath
-		if issue.LineNumber != nil {
-			location = fmt.Sprintf("%s:%d", issue.FilePath, *issue.LineNumber)
+		key := issue
+		key.RepositoryID = uuid.Nil
+		key.FilePath = filepath.Join(absRootFor[issue.Root], filepath.FromSlash(issue.FilePath))
+
+		fingerprint := key.Fingerprint()
+		if seen[fingerprint] {
+			continue
 		}
+		seen[fingerprint] = true
+		deduped = append(deduped, issue)
+	}
+	return deduped
+}
+
+// mergeLineCounts sums the LineCounter's per-language breakdown of src into dst.
+func mergeLineCounts(dst, src map[string]interface{}) {
+	languages, ok := src["languages"].(map[string]analyzers.LanguageLineStats)
+	if !ok {
+		return
+	}
+	merged, _ := dst["languages"].(map[string]analyzers.LanguageLineStats)
+	if merged == nil {
+		merged = make(map[string]analyzers.LanguageLineStats, len(languages))
+	}
+	for name, stats := range languages {
+		total := merged[name]
+		total.Files += stats.Files
+		total.TotalLines += stats.TotalLines
+		total.CodeLines += stats.CodeLines
+		total.CommentLines += stats.CommentLines
+		total.BlankLines += stats.BlankLines
+		merged[name] = total
+	}
+	dst["languages"] = merged
+}
+
+// mergeComplexityStats combines the complexity analyzer's function count,
+// average cyclomatic complexity and complexity histogram of src into dst,
+// weighting the averages by the number of functions behind them.
+func mergeComplexityStats(dst, src map[string]interface{}) {
+	if histogram, ok := src["complexity_histogram"].(models.ComplexityHistogram); ok {
+		merged, _ := dst["complexity_histogram"].(models.ComplexityHistogram)
+		dst["complexity_histogram"] = merged.Add(histogram)
+	}
+	functions, _ := src["complexity_functions_analyzed"].(int)
+	if functions == 0 {
+		return
+	}
+	average, _ := src["complexity_avg_cyclomatic"].(float64)
+	total, _ := dst["complexity_functions_analyzed"].(int)
+	merged, _ := dst["complexity_avg_cyclomatic"].(float64)
+	dst["complexity_functions_analyzed"] = total + functions
+	dst["complexity_avg_cyclomatic"] = (merged*float64(total) + average*float64(functions)) / float64(total+functions)
+}
 
-		// Format Rule
-		rule := "N/A"
-		if issue.ToolRuleID != nil && *issue.ToolRuleID != "" {
-			rule = *issue.ToolRuleID
+// attachPermalinks sets the URL of issues found under root to their line at
+// commit, or at the HEAD commit when it is empty, on the git host. Roots
+// outside a git repository, without an origin remote or on an unknown host
+// get no links. Staged scans are not linked: the staged content is not at
+// HEAD.
+func attachPermalinks(ctx context.Context, root, commit string, issues []models.TechnicalDebtIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	links, err := git.NewService().GetPermalinks(ctx, root)
+	if err != nil {
+		return
+	}
+	if commit != "" {
+		links.Commit = commit
+	}
+	for i := range issues {
+		if issues[i].FilePath == "" {
+			continue
 		}
+		line := 0
+		if issues[i].LineNumber != nil {
+			line = *issues[i].LineNumber
+		}
+		issues[i].URL = links.URL(filepath.Join(root, filepath.FromSlash(issues[i].FilePath)), line)
+	}
+}
+
+// printLanguages outputs the complexity analyzers' languages and extensions,
+// as a table or, for the json formats, a JSON array.
+func printLanguages(cmd *cobra.Command, format string) error {
+	languages := complexity.NewFactory(models.DefaultComplexityThresholds()).Languages()
+
+	switch strings.ToLower(format) {
+	case "json", "json-full":
+		encoder := jsonEncoder(cmd)
+		return encoder.Encode(languages)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tEXTENSIONS")
+	fmt.Fprintln(w, "--------\t----------")
+	for _, language := range languages {
+		fmt.Fprintf(w, "%s\t%s\n", language.Language, strings.Join(language.Extensions, ", "))
+	}
+	return w.Flush()
+}
 
-		// Print Row
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", 
-			strings.ToUpper(issue.Severity),
-			location,
-			rule,
-			issue.Message,
-		)
+// printFormats outputs the registered output formats and what they write, as
+// a table or, for the json formats, a JSON array.
+func printFormats(cmd *cobra.Command, format string) error {
+	formats := report.Default().Formats()
+
+	switch strings.ToLower(format) {
+	case "json", "json-full":
+		encoder := jsonEncoder(cmd)
+		return encoder.Encode(formats)
 	}
 
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "FORMAT\tDESCRIPTION")
+	fmt.Fprintln(w, "------\t-----------")
+	for _, f := range formats {
+		fmt.Fprintf(w, "%s\t%s\n", f.Name, f.Description)
+	}
 	return w.Flush()
 }
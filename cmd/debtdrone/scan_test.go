@@ -1,16 +1,32 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/config"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/service"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
 func createRootWithScan() *cobra.Command {
 	root := &cobra.Command{Use: "debtdrone"}
 	root.AddCommand(newScanCmd())
+	// Tests never cancel early; an expired deadline releases its own timer.
+	addTimeoutFlag(root)
+	addNoColorFlag(root)
 	return root
 }
 
@@ -94,6 +110,46 @@ func TestScanCmd_OutputFormats(t *testing.T) {
 		}
 	})
 
+	t.Run("--format=json-full", func(t *testing.T) {
+		root := createRootWithScan()
+		output, err := executeCommand(root, "scan", testRepo, "--format", "json-full", "--security-scan=false")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var report struct {
+			Issues  []json.RawMessage `json:"issues"`
+			Summary struct {
+				TotalIssues    int            `json:"total_issues"`
+				SeverityCounts map[string]int `json:"severity_counts"`
+				AffectedFiles  int            `json:"affected_files"`
+
+				CategoryBreakdown map[string]struct {
+					Issues int `json:"issues"`
+				} `json:"category_breakdown"`
+			} `json:"summary"`
+			Languages map[string]struct {
+				Files     int `json:"files"`
+				CodeLines int `json:"code_lines"`
+			} `json:"languages"`
+		}
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not a JSON object: %v\n%s", err, output)
+		}
+		if len(report.Issues) == 0 || report.Summary.TotalIssues != len(report.Issues) {
+			t.Errorf("Expected summary.total_issues to match %d issues, got %+v", len(report.Issues), report.Summary)
+		}
+		if report.Summary.AffectedFiles != 1 {
+			t.Errorf("Expected 1 affected file, got %d", report.Summary.AffectedFiles)
+		}
+		if report.Summary.CategoryBreakdown["maintainability"].Issues == 0 {
+			t.Errorf("Expected maintainability issues in summary.category_breakdown, got %+v", report.Summary.CategoryBreakdown)
+		}
+		if python := report.Languages["Python"]; python.Files != 1 || python.CodeLines == 0 {
+			t.Errorf("Expected the Python line counts in languages, got %+v", report.Languages)
+		}
+	})
+
 	t.Run("--format=text", func(t *testing.T) {
 		root := createRootWithScan()
 		output, err := executeCommand(root, "scan", testRepo, "--format", "text")
@@ -102,7 +158,7 @@ func TestScanCmd_OutputFormats(t *testing.T) {
 			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		headers := []string{"SEVERITY", "FILE:LINE", "RULE", "MESSAGE"}
+		headers := []string{"SEVERITY", "FILE:LINE", "RULE", "MESSAGE", "CATEGORY", "ISSUES", "DEBT"}
 		for _, header := range headers {
 			if !strings.Contains(output, header) {
 				t.Errorf("Text output missing expected header %q. Got:\n%s", header, output)
@@ -115,3 +171,1868 @@ func TestScanCmd_OutputFormats(t *testing.T) {
 		}
 	})
 }
+
+func TestScanCmd_StableFingerprints(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	scanFingerprints := func() []string {
+		root := createRootWithScan()
+		output, err := executeCommand(root, "scan", testRepo, "--format", "json", "--security-scan=false")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var issues []struct {
+			FingerprintHash string `json:"fingerprint_hash"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+
+		fingerprints := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			if issue.FingerprintHash == "" {
+				t.Fatalf("Expected every issue to carry a fingerprint_hash. Got:\n%s", output)
+			}
+			fingerprints = append(fingerprints, issue.FingerprintHash)
+		}
+		return fingerprints
+	}
+
+	first, second := scanFingerprints(), scanFingerprints()
+	if len(first) == 0 {
+		t.Fatal("Expected the dirty test repo to produce issues")
+	}
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Errorf("Expected identical fingerprints across scans:\n%v\n%v", first, second)
+	}
+}
+
+func TestScanCmd_MultiplePaths(t *testing.T) {
+	base := setupTestRepo(t)
+	svcA := filepath.Join(base, "svc-a")
+	if err := os.MkdirAll(svcA, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(base, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(svcA, "complex.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	type rootedIssue struct {
+		Root     string `json:"root"`
+		FilePath string `json:"file_path"`
+	}
+	scan := func(args ...string) []rootedIssue {
+		root := createRootWithScan()
+		output, err := executeCommand(root, append([]string{"scan", "--format", "json", "--security-scan=false"}, args...)...)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var issues []rootedIssue
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not a flat JSON array: %v\n%s", err, output)
+		}
+		return issues
+	}
+
+	t.Run("issues are tagged with their root", func(t *testing.T) {
+		otherRepo := setupTestRepo(t)
+		issues := scan(svcA, otherRepo)
+
+		roots := map[string]int{}
+		for _, issue := range issues {
+			roots[issue.Root]++
+		}
+		if roots[svcA] == 0 || roots[otherRepo] == 0 {
+			t.Errorf("Expected issues from both roots, got %v", roots)
+		}
+	})
+
+	t.Run("overlapping roots are de-duplicated", func(t *testing.T) {
+		single := scan(base)
+		overlapping := scan(base, svcA)
+
+		if len(overlapping) != len(single) {
+			t.Errorf("Expected %d issues after de-duplication, got %d: %+v", len(single), len(overlapping), overlapping)
+		}
+	})
+
+	t.Run("quality gate is decided across all roots", func(t *testing.T) {
+		cleanDir := t.TempDir()
+		root := createRootWithScan()
+		_, err := executeCommand(root, "scan", cleanDir, svcA, "--security-scan=false", "--fail-on", "high")
+		if exitCodeFor(err) != exitQualityGate {
+			t.Errorf("Expected the gate to fail because one root is dirty, got: %v", err)
+		}
+	})
+}
+
+func TestScanCmd_JSONPretty(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	for _, format := range []string{"json", "json-full"} {
+		t.Run(format, func(t *testing.T) {
+			pretty, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", format, "--security-scan=false", "--no-cache")
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			compact, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", format, "--security-scan=false", "--no-cache", "--json-pretty=false")
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if strings.Count(strings.TrimSpace(pretty), "\n") == 0 {
+				t.Errorf("Expected indented JSON by default, got:\n%s", pretty)
+			}
+			if strings.Count(strings.TrimSpace(compact), "\n") != 0 {
+				t.Errorf("Expected a single line with --json-pretty=false, got:\n%s", compact)
+			}
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(compact), &decoded); err != nil {
+				t.Errorf("Compact output is not valid JSON: %v\n%s", err, compact)
+			}
+		})
+	}
+}
+
+func TestScanCmd_SeverityOverrides(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	configPath := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+	if err := os.WriteFile(configPath, []byte("severity_overrides:\n  complexity: low\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := createRootWithScan()
+	output, err := executeCommand(root, "scan", testRepo, "--format", "json", "--security-scan=false",
+		"--config", configPath, "--fail-on", "medium")
+	if err != nil {
+		t.Fatalf("Expected the gate to pass after downgrading complexity to low, got %v. Output:\n%s", err, output)
+	}
+
+	var issues []struct {
+		IssueType string `json:"issue_type"`
+		Severity  string `json:"severity"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(issues) == 0 {
+		t.Fatal("Expected the dirty test repo to produce issues")
+	}
+	for _, issue := range issues {
+		if issue.IssueType == "complexity" && issue.Severity != "low" {
+			t.Errorf("Expected overridden severity 'low', got %q", issue.Severity)
+		}
+	}
+
+	t.Run("invalid override is a usage error", func(t *testing.T) {
+		badConfig := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+		if err := os.WriteFile(badConfig, []byte("severity_overrides:\n  complexity: blocker\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := executeCommand(createRootWithScan(), "scan", testRepo, "--config", badConfig)
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("Expected a usage error, got %v", err)
+		}
+	})
+
+	t.Run("effort multiplier without a value is a usage error", func(t *testing.T) {
+		badConfig := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+		if err := os.WriteFile(badConfig, []byte("effort_multipliers:\n  - path: legacy/**\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := executeCommand(createRootWithScan(), "scan", testRepo, "--config", badConfig)
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("Expected a usage error, got %v", err)
+		}
+	})
+
+	t.Run("explicit missing config is a usage error", func(t *testing.T) {
+		_, err := executeCommand(createRootWithScan(), "scan", testRepo, "--config", filepath.Join(t.TempDir(), "nope.yaml"))
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("Expected a usage error, got %v", err)
+		}
+	})
+}
+
+func TestScanCmd_LanguageThresholds(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	complexitySeverity := func(config string) (string, error) {
+		t.Helper()
+		configPath := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+		if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		root := createRootWithScan()
+		root.SilenceUsage = true
+		output, err := executeCommand(root, "scan", testRepo, "--format", "json", "--security-scan=false",
+			"--no-cache", "--analyzers", "complexity", "--config", configPath)
+		if err != nil {
+			return "", err
+		}
+		var issues []struct {
+			IssueType string `json:"issue_type"`
+			Severity  string `json:"severity"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		for _, issue := range issues {
+			if issue.IssueType == "complexity" {
+				return issue.Severity, nil
+			}
+		}
+		return "", nil
+	}
+
+	if severity, err := complexitySeverity("{}\n"); err != nil || severity != "critical" {
+		t.Fatalf("Expected the deeply nested function to be critical by default, got %q / %v", severity, err)
+	}
+	severity, err := complexitySeverity("thresholds:\n  languages:\n    python:\n      nesting_warning: 12\n      nesting_critical: 15\n      cognitive_high: 60\n      cognitive_critical: 80\n")
+	if err != nil || severity == "critical" || severity == "high" {
+		t.Errorf("Expected the Python thresholds to relax the severity, got %q / %v", severity, err)
+	}
+	severity, err = complexitySeverity("thresholds:\n  languages:\n    go:\n      nesting_warning: 12\n      nesting_critical: 15\n      cognitive_high: 60\n      cognitive_critical: 80\n")
+	if err != nil || severity != "critical" {
+		t.Errorf("Expected Go thresholds to leave Python alone, got %q / %v", severity, err)
+	}
+
+	for name, config := range map[string]string{
+		"unknown language":      "thresholds:\n  languages:\n    cobol:\n      cyclomatic_high: 5\n",
+		"negative value":        "thresholds:\n  languages:\n    rust:\n      cyclomatic_high: -1\n",
+		"high above critical":   "thresholds:\n  languages:\n    rust:\n      cyclomatic_high: 40\n",
+		"language listed twice": "thresholds:\n  languages:\n    rust:\n      cyclomatic_high: 12\n    Rust:\n      cyclomatic_high: 14\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := complexitySeverity(config); exitCodeFor(err) != exitUsage {
+				t.Errorf("Expected a usage error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScanCmd_EffortMultipliers(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	configPath := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+	if err := os.WriteFile(configPath, []byte("effort_multipliers:\n  - path: \"*.py\"\n    multiplier: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", "json-full",
+		"--security-scan=false", "--config", configPath)
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+
+	var report struct {
+		Issues []struct {
+			EffortMultiplier float64 `json:"effort_multiplier"`
+		} `json:"issues"`
+		Summary struct {
+			EffectiveDebtHours float64 `json:"effective_debt_hours"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(report.Issues) == 0 {
+		t.Fatal("Expected the dirty test repo to produce issues")
+	}
+	for _, issue := range report.Issues {
+		if issue.EffortMultiplier != 0 {
+			t.Errorf("Expected multiplier 0 for the Python file, got %v", issue.EffortMultiplier)
+		}
+	}
+	if report.Summary.EffectiveDebtHours != 0 {
+		t.Errorf("Expected zero effective debt, got %v", report.Summary.EffectiveDebtHours)
+	}
+}
+
+func TestScanCmd_JavaScriptDebtHours(t *testing.T) {
+	repo := t.TempDir()
+	content := `function route(a, b, c) {
+  if (a) {
+    if (b) {
+      if (c) {
+        if (a && b) {
+          if (b || c) {
+            if (a && c) {
+              return 1;
+            }
+          }
+        }
+      }
+    }
+  }
+  return 0;
+}
+`
+	if err := os.WriteFile(filepath.Join(repo, "dirty.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := createRootWithScan()
+	output, err := executeCommand(root, "scan", repo, "--format", "json", "--security-scan=false")
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+
+	var issues []struct {
+		IssueType          string  `json:"issue_type"`
+		FilePath           string  `json:"file_path"`
+		TechnicalDebtHours float64 `json:"technical_debt_hours"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+
+	found := false
+	for _, issue := range issues {
+		if issue.IssueType != "complexity" {
+			continue
+		}
+		found = true
+		if issue.TechnicalDebtHours <= 0 {
+			t.Errorf("Expected non-zero technical_debt_hours for %s, got %v", issue.FilePath, issue.TechnicalDebtHours)
+		}
+	}
+	if !found {
+		t.Fatalf("Expected a complexity issue for the dirty JS file. Output:\n%s", output)
+	}
+}
+
+func TestScanCmd_AnalyzerSelection(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	countComplexity := func(args ...string) int {
+		t.Helper()
+		root := createRootWithScan()
+		output, err := executeCommand(root, append([]string{"scan", testRepo, "--format", "json", "--security-scan=false"}, args...)...)
+		if err != nil {
+			t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+		}
+		var issues []struct {
+			IssueType string `json:"issue_type"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		count := 0
+		for _, issue := range issues {
+			if issue.IssueType == "complexity" {
+				count++
+			}
+		}
+		return count
+	}
+
+	if countComplexity("--analyzers", "complexity") == 0 {
+		t.Error("Expected --analyzers complexity to report complexity issues")
+	}
+	if n := countComplexity("--disable-analyzers", "complexity"); n != 0 {
+		t.Errorf("Expected --disable-analyzers complexity to suppress complexity issues, got %d", n)
+	}
+}
+
+func TestScanCmd_SummaryLine(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	summaryPattern := regexp.MustCompile(`(?m)^DEBTDRONE_SUMMARY issues=(\d+) critical=\d+ high=\d+ medium=\d+ low=\d+ debt_hours=[0-9.]+ gate=(\w+) truncated=false$`)
+
+	tests := []struct {
+		name string
+		args []string
+		gate string
+	}{
+		{name: "no gate", args: []string{"--format", "json"}, gate: "off"},
+		{name: "failed gate", args: []string{"--format", "json", "--fail-on", "low"}, gate: "failed"},
+		{name: "text output", args: []string{"--format", "text"}, gate: "off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := createRootWithScan()
+			stdout, stderr, _ := executeCommandWithStderr(root, append([]string{"scan", testRepo, "--security-scan=false"}, tt.args...)...)
+
+			m := summaryPattern.FindStringSubmatch(stderr)
+			if m == nil {
+				t.Fatalf("Expected a DEBTDRONE_SUMMARY line on stderr, got:\n%s", stderr)
+			}
+			if m[2] != tt.gate {
+				t.Errorf("Expected gate=%s, got gate=%s", tt.gate, m[2])
+			}
+			if m[1] == "0" {
+				t.Errorf("Expected the dirty repo to report issues, got: %s", m[0])
+			}
+			if strings.Contains(stdout, "DEBTDRONE_SUMMARY") {
+				t.Errorf("Summary line must not be written to stdout:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestSummaryLine_Format(t *testing.T) {
+	summary := analysis.RunSummary{
+		TotalIssues:    42,
+		SeverityCounts: map[string]int{"critical": 3, "high": 10, "medium": 20, "low": 9},
+		TotalDebtHours: 31.499999,
+	}
+	want := "DEBTDRONE_SUMMARY issues=42 critical=3 high=10 medium=20 low=9 debt_hours=31.5 gate=failed truncated=false"
+	if got := summaryLine(summary, summaryGateFailed); got != want {
+		t.Errorf("summaryLine() = %q, want %q", got, want)
+	}
+}
+
+func TestScanCmd_MaxIssues(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	content, err := os.ReadFile(filepath.Join(testRepo, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testRepo, "complex_copy.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := executeCommandWithStderr(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--format", "json-full", "--max-issues", "1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var report struct {
+		Issues  []models.TechnicalDebtIssue `json:"issues"`
+		Summary analysis.RunSummary         `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(report.Issues) != 1 || !report.Summary.Truncated {
+		t.Errorf("Expected one issue and a truncated summary, got %d issues, truncated=%t", len(report.Issues), report.Summary.Truncated)
+	}
+	if !strings.Contains(stderr, "stopped collecting issues at 1 (--max-issues)") || !strings.Contains(stderr, "truncated=true") {
+		t.Errorf("Expected a truncation warning and summary key on stderr, got:\n%s", stderr)
+	}
+
+	stdout, err = executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--max-issues", "1", "--quiet", "--fail-on", "low")
+	if exitCodeFor(err) != exitQualityGate {
+		t.Errorf("Expected the gate to run on the collected issues and fail, got %v", err)
+	}
+	if !strings.Contains(stdout, "TRUNCATED: issue collection stopped at --max-issues") {
+		t.Errorf("Expected the text report to say it is truncated. Got:\n%s", stdout)
+	}
+
+	stdout, err = executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--format", "json-full")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(stdout, `"truncated"`) {
+		t.Errorf("Expected no truncated key below the default cap. Got:\n%s", stdout)
+	}
+
+	if _, err := executeCommand(createRootWithScan(), "scan", testRepo, "--max-issues", "-1"); exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected a negative --max-issues to be a usage error, got %v", err)
+	}
+}
+
+func TestScanCmd_DebtBudget(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	// The test repository has a single issue; weighing every severity
+	// alike makes its score 3 whatever its severity.
+	weights := "critical=3,high=3,medium=3,low=3"
+
+	_, stderr, err := executeCommandWithStderr(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--quiet", "--debt-budget", "3", "--debt-weights", weights)
+	if err != nil {
+		t.Errorf("Expected a score equal to the budget to pass, got %v", err)
+	}
+	if !strings.Contains(stderr, "Debt score: 3 (budget 3): 1 ") || !strings.Contains(stderr, "gate=passed") {
+		t.Errorf("Expected the score, its breakdown and a passed gate on stderr, got:\n%s", stderr)
+	}
+
+	_, stderr, err = executeCommandWithStderr(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--quiet", "--debt-budget", "2", "--debt-weights", weights)
+	if exitCodeFor(err) != exitQualityGate {
+		t.Errorf("Expected a score over the budget to fail the gate, got %v", err)
+	}
+	if !strings.Contains(stderr, "gate=failed") {
+		t.Errorf("Expected a failed gate on stderr, got:\n%s", stderr)
+	}
+
+	for _, args := range [][]string{
+		{"--debt-budget", "-1"},
+		{"--debt-budget", "5", "--debt-weights", "blocker=2"},
+		{"--debt-budget", "5", "--debt-weights", "high=-1"},
+		{"--debt-weights", "high=1"},
+	} {
+		args = append([]string{"scan", testRepo}, args...)
+		if _, err := executeCommand(createRootWithScan(), args...); exitCodeFor(err) != exitUsage {
+			t.Errorf("Expected %v to be a usage error, got %v", args[2:], err)
+		}
+	}
+}
+
+func TestScanCmd_Gitignore(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	distDir := filepath.Join(testRepo, "dist")
+	if err := os.MkdirAll(distDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(testRepo, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(distDir, "bundle.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testRepo, ".gitignore"), []byte("dist/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := func(args ...string) map[string]bool {
+		t.Helper()
+		root := createRootWithScan()
+		output, err := executeCommand(root, append([]string{"scan", testRepo, "--format", "json", "--security-scan=false"}, args...)...)
+		if err != nil {
+			t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+		}
+		var issues []struct {
+			FilePath string `json:"file_path"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		found := map[string]bool{}
+		for _, issue := range issues {
+			found[issue.FilePath] = true
+		}
+		return found
+	}
+
+	if found := files(); found["/dist/bundle.py"] || !found["/complex.py"] {
+		t.Errorf("Expected only /complex.py to be analyzed by default, got %v", found)
+	}
+	if found := files("--no-gitignore"); !found["/dist/bundle.py"] {
+		t.Errorf("Expected --no-gitignore to analyze /dist/bundle.py, got %v", found)
+	}
+
+	// .debtdroneignore applies on top of .gitignore, even with --no-gitignore.
+	if err := os.WriteFile(filepath.Join(testRepo, ".debtdroneignore"), []byte("/complex.py\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if found := files(); len(found) != 0 {
+		t.Errorf("Expected .debtdroneignore to exclude /complex.py, got %v", found)
+	}
+	if found := files("--no-gitignore"); found["/complex.py"] || !found["/dist/bundle.py"] {
+		t.Errorf("Expected --no-gitignore to keep the .debtdroneignore rules, got %v", found)
+	}
+}
+
+func TestScanCmd_Timeout(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	root := createRootWithScan()
+	root.SilenceUsage = true
+	output, err := executeCommand(root, "scan", testRepo, "--format", "json", "--timeout", "1ns")
+	if err == nil {
+		t.Fatalf("Expected the scan to time out. Output:\n%s", output)
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout message, got %q", err.Error())
+	}
+	if !json.Valid([]byte(output)) {
+		t.Errorf("Expected the partial results to be valid JSON, got:\n%s", output)
+	}
+}
+
+func TestAddTimeoutFlag_Release(t *testing.T) {
+	root := &cobra.Command{Use: "debtdrone", RunE: func(*cobra.Command, []string) error { return nil }}
+	release := addTimeoutFlag(root)
+	if _, err := executeCommand(root, "--timeout", "1m"); err != nil {
+		t.Fatal(err)
+	}
+	// Releasing the deadline used to recurse until the stack overflowed.
+	release()
+}
+
+func TestScanCmd_Cache(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	cacheDir := t.TempDir()
+	previous := userCacheDir
+	userCacheDir = func() (string, error) { return cacheDir, nil }
+	t.Cleanup(func() { userCacheDir = previous })
+
+	scan := func(args ...string) string {
+		t.Helper()
+		root := createRootWithScan()
+		output, err := executeCommand(root, append([]string{"scan", testRepo, "--format", "json", "--security-scan=false"}, args...)...)
+		if err != nil {
+			t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+		}
+		return output
+	}
+	cacheFiles := func() []string {
+		t.Helper()
+		files, err := filepath.Glob(filepath.Join(cacheDir, "debtdrone", "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	scan("--no-cache")
+	if files := cacheFiles(); len(files) != 0 {
+		t.Fatalf("Expected --no-cache to write no cache, got %v", files)
+	}
+
+	first := scan()
+	files := cacheFiles()
+	if len(files) != 1 {
+		t.Fatalf("Expected one cache file, got %v", files)
+	}
+	if second := scan(); !sameIssueFingerprints(t, first, second) {
+		t.Errorf("Cached run reported different issues.\nFirst:\n%s\nSecond:\n%s", first, second)
+	}
+
+	// A corrupt cache is discarded and rewritten rather than failing the scan.
+	if err := os.WriteFile(files[0], []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if third := scan(); !sameIssueFingerprints(t, first, third) {
+		t.Errorf("Run over a corrupt cache reported different issues:\n%s", third)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(content) {
+		t.Errorf("Expected the corrupt cache to be regenerated, got:\n%s", content)
+	}
+}
+
+func sameIssueFingerprints(t *testing.T, a, b string) bool {
+	t.Helper()
+	fingerprints := func(output string) []string {
+		var issues []struct {
+			FingerprintHash string `json:"fingerprint_hash"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		var out []string
+		for _, issue := range issues {
+			out = append(out, issue.FingerprintHash)
+		}
+		sort.Strings(out)
+		return out
+	}
+	fa, fb := fingerprints(a), fingerprints(b)
+	return len(fa) > 0 && strings.Join(fa, ",") == strings.Join(fb, ",")
+}
+
+func TestScanCmd_Import(t *testing.T) {
+	cleanDir := t.TempDir()
+	importPath := filepath.Join(t.TempDir(), "eslint.yaml")
+	report := "version: 1\ntool: eslint\nfindings:\n  - file: src/app.js\n    line: 3\n    rule: no-eval\n    severity: critical\n    category: security\n    message: eval can be harmful\n"
+	if err := os.WriteFile(importPath, []byte(report), 0644); err != nil {
+		t.Fatalf("Failed to write import file: %v", err)
+	}
+
+	root := createRootWithScan()
+	root.SilenceUsage = true
+	output, err := executeCommand(root, "scan", cleanDir, "--format", "json", "--security-scan=false", "--import", importPath, "--fail-on", "critical")
+	if err == nil || !strings.Contains(err.Error(), "quality gate failed") {
+		t.Errorf("Expected the imported critical finding to fail the gate, got: %v", err)
+	}
+
+	var issues []struct {
+		ToolName        string `json:"tool_name"`
+		FilePath        string `json:"file_path"`
+		FingerprintHash string `json:"fingerprint_hash"`
+		Root            string `json:"root"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(issues) != 1 || issues[0].ToolName != "eslint" || issues[0].FilePath != "/src/app.js" {
+		t.Fatalf("Expected the imported eslint finding, got %+v", issues)
+	}
+	if issues[0].FingerprintHash == "" || issues[0].Root != cleanDir {
+		t.Errorf("Expected the imported finding to be fingerprinted and tagged with the first root, got %+v", issues[0])
+	}
+
+	t.Run("invalid file is rejected before scanning", func(t *testing.T) {
+		badPath := filepath.Join(t.TempDir(), "bad.yaml")
+		if err := os.WriteFile(badPath, []byte("version: 1\ntool: eslint\nfindings:\n  - file: a.js\n    message: no severity\n"), 0644); err != nil {
+			t.Fatalf("Failed to write import file: %v", err)
+		}
+		_, err := executeCommand(createRootWithScan(), "scan", cleanDir, "--import", badPath)
+		if err == nil || !strings.Contains(err.Error(), "bad.yaml:4: finding 0: invalid severity") {
+			t.Errorf("Expected a located validation error, got: %v", err)
+		}
+	})
+}
+
+func TestScanCmd_Jobs(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	findings := func(jobs string) []string {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", "json", "--security-scan=false", "--no-cache", "--jobs", jobs)
+		if err != nil {
+			t.Fatalf("Scan with --jobs %s failed: %v", jobs, err)
+		}
+		var issues []struct {
+			FilePath  string `json:"file_path"`
+			IssueType string `json:"issue_type"`
+			Message   string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		var keys []string
+		for _, issue := range issues {
+			keys = append(keys, issue.FilePath+" "+issue.IssueType+" "+issue.Message)
+		}
+		return keys
+	}
+
+	sequential := findings("1")
+	if len(sequential) == 0 {
+		t.Fatal("Expected the test repository to have findings")
+	}
+	if concurrent := findings("4"); strings.Join(concurrent, "\n") != strings.Join(sequential, "\n") {
+		t.Errorf("Expected --jobs 4 to report the findings of --jobs 1 in the same order.\nGot:\n%v\nWant:\n%v", concurrent, sequential)
+	}
+
+	_, err := executeCommand(createRootWithScan(), "scan", testRepo, "--jobs", "0")
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "invalid --jobs value: 0") {
+		t.Errorf("Expected a usage error for --jobs 0, got %v", err)
+	}
+}
+
+func TestScanCmd_SubprocessLimits(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	if _, err := executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--no-cache", "--subprocess-memory-limit", "512MiB", "--subprocess-cpus", "2"); err != nil {
+		t.Fatalf("Scan with subprocess limits failed: %v", err)
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--subprocess-memory-limit", "2GB"}, `invalid memory limit "2GB"`},
+		{[]string{"--subprocess-cpus", "-1"}, "invalid CPU limit -1"},
+	}
+	for _, tt := range tests {
+		_, err := executeCommand(createRootWithScan(), append([]string{"scan", testRepo}, tt.args...)...)
+		if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected a usage error containing %q for %v, got %v", tt.want, tt.args, err)
+		}
+	}
+}
+
+func TestScanCmd_MergeIssues(t *testing.T) {
+	cleanDir := t.TempDir()
+	importDir := t.TempDir()
+	reports := map[string]string{
+		"eslint.yaml":  "version: 1\ntool: eslint\nfindings:\n  - file: src/app.js\n    line: 3\n    rule: no-eval\n    severity: medium\n    category: security\n    message: eval can be harmful\n",
+		"semgrep.yaml": "version: 1\ntool: semgrep\nfindings:\n  - file: src/app.js\n    line: 3\n    rule: eval-injection\n    severity: high\n    category: security\n    message: user input reaches eval\n",
+	}
+	var args []string
+	for name, report := range reports {
+		path := filepath.Join(importDir, name)
+		if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+			t.Fatalf("Failed to write import file: %v", err)
+		}
+		args = append(args, "--import", path)
+	}
+
+	scan := func(extra ...string) []map[string]interface{} {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), append([]string{"scan", cleanDir, "--format", "json", "--security-scan=false"}, append(args, extra...)...)...)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		var issues []map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		return issues
+	}
+
+	if issues := scan(); len(issues) != 2 {
+		t.Errorf("Expected both findings without --merge-issues, got %d", len(issues))
+	}
+
+	issues := scan("--merge-issues")
+	if len(issues) != 1 {
+		t.Fatalf("Expected one merged issue, got %+v", issues)
+	}
+	if issues[0]["severity"] != "high" {
+		t.Errorf("Expected the highest severity, got %v", issues[0]["severity"])
+	}
+	metadata, _ := issues[0]["metadata"].(map[string]interface{})
+	if tools, _ := metadata["merged_tools"].([]interface{}); len(tools) != 2 || tools[0] != "eslint" || tools[1] != "semgrep" {
+		t.Errorf("Expected the contributing tools in the metadata, got %v", metadata)
+	}
+	message, _ := issues[0]["message"].(string)
+	if !strings.Contains(message, "eval can be harmful") || !strings.Contains(message, "user input reaches eval") {
+		t.Errorf("Expected a combined message, got %q", message)
+	}
+}
+
+func TestScanCmd_GolangCI(t *testing.T) {
+	cleanDir := t.TempDir()
+	reportPath := filepath.Join(t.TempDir(), "golangci.json")
+	report := `{"Issues": [{"FromLinter": "gosec", "Text": "G101: Potential hardcoded credentials", "Severity": "error", "Pos": {"Filename": "config.go", "Line": 7, "Column": 2}}]}`
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		t.Fatalf("Failed to write golangci-lint report: %v", err)
+	}
+
+	root := createRootWithScan()
+	root.SilenceUsage = true
+	output, err := executeCommand(root, "scan", cleanDir, "--security-scan=false", "--golangci", reportPath, "--fail-on", "high")
+	if err == nil || !strings.Contains(err.Error(), "quality gate failed") {
+		t.Errorf("Expected the imported high severity lint issue to fail the gate, got: %v", err)
+	}
+	if !strings.Contains(output, "/config.go:7") || !strings.Contains(output, "gosec") {
+		t.Errorf("Expected the lint issue in the text report, got:\n%s", output)
+	}
+}
+
+func TestScanCmd_Permalinks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := setupTestRepo(t)
+	runGit := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+
+	scan := func() []map[string]interface{} {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), "scan", repo, "--format", "json", "--security-scan=false", "--no-cache")
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		var issues []map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		if len(issues) == 0 {
+			t.Fatal("Expected the test repo to report issues")
+		}
+		return issues
+	}
+
+	for _, issue := range scan() {
+		if _, ok := issue["url"]; ok {
+			t.Errorf("Expected no url without an origin remote, got %v", issue["url"])
+		}
+	}
+
+	runGit("remote", "add", "origin", "https://github.com/acme/api.git")
+	prefix := "https://github.com/acme/api/blob/" + runGit("rev-parse", "HEAD") + "/"
+	for _, issue := range scan() {
+		url, _ := issue["url"].(string)
+		if !strings.HasPrefix(url, prefix+strings.TrimLeft(issue["file_path"].(string), "/")) {
+			t.Errorf("Expected a permalink under %s for %v, got %q", prefix, issue["file_path"], url)
+		}
+	}
+}
+
+func TestScanCmd_Staged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := setupTestRepo(t)
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(repo, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runGit("init", "-q")
+	runGit("add", "complex.py")
+	runGit("commit", "-q", "-m", "initial")
+
+	scanStaged := func(args ...string) ([]string, error) {
+		t.Helper()
+		root := createRootWithScan()
+		root.SilenceUsage = true
+		output, err := executeCommand(root, append([]string{"scan", repo, "--staged", "--format", "json"}, args...)...)
+		var issues []struct {
+			FilePath string `json:"file_path"`
+		}
+		if jsonErr := json.Unmarshal([]byte(output), &issues); jsonErr != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", jsonErr, output)
+		}
+		var files []string
+		for _, issue := range issues {
+			files = append(files, issue.FilePath)
+		}
+		return files, err
+	}
+
+	t.Run("nothing staged passes the gate", func(t *testing.T) {
+		files, err := scanStaged("--fail-on", "low")
+		if err != nil || len(files) != 0 {
+			t.Errorf("Expected no issues and no error with nothing staged, got %v / %v", files, err)
+		}
+	})
+
+	t.Run("only staged files are analyzed", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(repo, "staged.py"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "unstaged.py"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit("add", "staged.py")
+		runGit("rm", "-q", "complex.py")
+
+		files, err := scanStaged("--fail-on", "low")
+		if exitCodeFor(err) != exitQualityGate {
+			t.Errorf("Expected the staged issue to fail the gate, got: %v", err)
+		}
+		if len(files) == 0 {
+			t.Fatal("Expected issues from the staged file")
+		}
+		for _, file := range files {
+			if file != "/staged.py" {
+				t.Errorf("Expected issues from /staged.py only, got %s", file)
+			}
+		}
+	})
+
+	t.Run("cannot be combined with --diff-run", func(t *testing.T) {
+		_, err := executeCommand(createRootWithScan(), "scan", repo, "--staged", "--diff-run", "5b0e2f3c-1d2a-4c6b-9a8e-7f1d2c3b4a59")
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("Expected a usage error, got: %v", err)
+		}
+	})
+}
+
+
+func TestScanCmd_Ref(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := setupTestRepo(t)
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(repo, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGit("init", "-q")
+	runGit("add", "complex.py")
+	runGit("commit", "-q", "-m", "initial")
+	runGit("tag", "v1")
+
+	// After the tag, the complex function is simplified and a new complex
+	// file appears in the working tree only.
+	simple := []byte("def complex_function():\n    return 1\n")
+	if err := os.WriteFile(filepath.Join(repo, "complex.py"), simple, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "untracked.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(createRootWithScan(), "scan", repo, "--ref", "v1", "--security-scan=false", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var issues []models.TechnicalDebtIssue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(issues) == 0 {
+		t.Fatal("Expected the complex function of v1 to be reported")
+	}
+	for _, issue := range issues {
+		if issue.FilePath != "/complex.py" {
+			t.Errorf("Expected issues of the files at v1 only, got %s", issue.FilePath)
+		}
+		if issue.RepositoryID != service.RepositoryID(repo) {
+			t.Errorf("Expected the repository ID of the scanned path, got %s", issue.RepositoryID)
+		}
+	}
+	if got, _ := os.ReadFile(filepath.Join(repo, "complex.py")); string(got) != string(simple) {
+		t.Errorf("Expected the working tree to be left alone, complex.py is now:\n%s", got)
+	}
+
+	_, err = executeCommand(createRootWithScan(), "scan", repo, "--ref", "no-such-tag")
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "invalid --ref value") {
+		t.Errorf("Expected an unknown ref to be a usage error, got %v", err)
+	}
+	if _, err := executeCommand(createRootWithScan(), "scan", repo, "--ref", "v1", "--staged"); exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected --ref with --staged to be a usage error, got %v", err)
+	}
+}
+func TestScanCmd_Archive(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join(setupTestRepo(t), "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeArchive := func(name string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "src.tar.gz")
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := writeArchive("app-1.0/complex.py")
+	output, err := executeCommand(createRootWithScan(), "scan", "--archive", path, "--security-scan=false", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var issues []models.TechnicalDebtIssue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(issues) == 0 {
+		t.Fatal("Expected the complex function of the archive to be reported")
+	}
+	for _, issue := range issues {
+		if issue.FilePath != "/complex.py" {
+			t.Errorf("Expected paths relative to the archive's top-level directory, got %s", issue.FilePath)
+		}
+		if issue.URL != "" {
+			t.Errorf("Expected no permalinks for an archive, got %s", issue.URL)
+		}
+	}
+
+	_, err = executeCommand(createRootWithScan(), "scan", "--archive", writeArchive("../evil.py"), "--security-scan=false")
+	if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "invalid --archive file") {
+		t.Errorf("Expected a path traversal entry to be a usage error, got %v", err)
+	}
+	if _, err := executeCommand(createRootWithScan(), "scan", ".", "--archive", path); exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected --archive with a scan path to be a usage error, got %v", err)
+	}
+	if _, err := executeCommand(createRootWithScan(), "scan", "--archive", path, "--ref", "v1"); exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected --archive with --ref to be a usage error, got %v", err)
+	}
+}
+
+func TestScanCmd_OnlyChangedFunctions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := setupTestRepo(t)
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	path := filepath.Join(repo, "complex.py")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := strings.Replace(string(content), "complex_function", "other_function", 1)
+	if err := os.WriteFile(path, []byte(string(content)+other), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+
+	// Change one of the two functions and add a file; neither is committed,
+	// but the new file must be tracked to count as changed.
+	changed := strings.Replace(other, "CRITICAL", "still CRITICAL", 1)
+	if err := os.WriteFile(path, []byte(string(content)+changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "added.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "added.py")
+
+	output, err := executeCommand(createRootWithScan(), "scan", repo, "--only-changed-functions", "HEAD", "--format", "json")
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+	var issues []struct {
+		FilePath string `json:"file_path"`
+		Message  string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	reported := map[string]bool{}
+	for _, issue := range issues {
+		if name, ok := strings.CutPrefix(issue.Message, "Function '"); ok {
+			reported[issue.FilePath+":"+name[:strings.Index(name, "'")]] = true
+		}
+	}
+	for _, want := range []string{"/complex.py:other_function", "/added.py:complex_function"} {
+		if !reported[want] {
+			t.Errorf("Expected %s to be reported, got %v", want, reported)
+		}
+	}
+	if reported["/complex.py:complex_function"] {
+		t.Errorf("Expected the unchanged function not to be reported, got %v", reported)
+	}
+
+	for name, args := range map[string][]string{
+		"unknown ref":   {"--only-changed-functions", "no-such-ref"},
+		"with --staged": {"--only-changed-functions", "HEAD", "--staged"},
+	} {
+		_, err := executeCommand(createRootWithScan(), append([]string{"scan", repo}, args...)...)
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("%s: expected a usage error, got: %v", name, err)
+		}
+	}
+}
+
+func TestScanCmd_StateFile(t *testing.T) {
+	t.Setenv("DB_HOST", "")
+	repo := setupTestRepo(t)
+	stateFile := filepath.Join(t.TempDir(), ".debtdrone-state.json")
+	scan := func(args ...string) (string, error) {
+		t.Helper()
+		root := createRootWithScan()
+		root.SilenceUsage = true
+		return executeCommand(root, append([]string{"scan", repo, "--security-scan=false", "--no-cache", "--state-file", stateFile}, args...)...)
+	}
+
+	// Without a recorded run nothing is new, and the run is recorded.
+	if _, err := scan("--fail-on", "low", "--fail-on-new"); err != nil {
+		t.Fatalf("Expected the first run to pass the gate, got: %v", err)
+	}
+	recorded, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("Expected the state file to be written: %v", err)
+	}
+	if strings.Contains(string(recorded), repo) {
+		t.Errorf("Expected no absolute paths in the state file:\n%s", recorded)
+	}
+
+	content, err := os.ReadFile(filepath.Join(repo, "complex.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "fresh.py"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := scan("--since-last-run", "--format", "json")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	var diff struct {
+		BaseRunAt *string `json:"base_run_at"`
+		Added     []struct {
+			FilePath string `json:"file_path"`
+		} `json:"added"`
+		Resolved []json.RawMessage `json:"resolved"`
+	}
+	if err := json.Unmarshal([]byte(output), &diff); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if diff.BaseRunAt == nil || len(diff.Added) == 0 || len(diff.Resolved) != 0 {
+		t.Fatalf("Expected only additions since the recorded run, got:\n%s", output)
+	}
+	for _, issue := range diff.Added {
+		if issue.FilePath != "/fresh.py" {
+			t.Errorf("Expected only /fresh.py to be added, got %s", issue.FilePath)
+		}
+	}
+
+	// The previous scan recorded fresh.py; remove that record by restoring
+	// the first state, then a failing gate must leave the state alone so a
+	// re-run fails too.
+	if err := os.WriteFile(stateFile, recorded, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := scan("--fail-on", "low", "--fail-on-new"); exitCodeFor(err) != exitQualityGate {
+			t.Fatalf("Run %d: expected the new issue to fail the gate, got: %v", i+1, err)
+		}
+	}
+
+	for name, args := range map[string][]string{
+		"--since-last-run without --state-file": {"scan", repo, "--since-last-run"},
+		"--since-last-run with --staged":        {"scan", repo, "--state-file", stateFile, "--since-last-run", "--staged"},
+	} {
+		_, err := executeCommand(createRootWithScan(), args...)
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("%s: expected a usage error, got: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(stateFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scan(); exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected a corrupt state file to be a usage error, got: %v", err)
+	}
+}
+
+func TestScanCmd_DeadCode(t *testing.T) {
+	testRepo := t.TempDir()
+	source := "package main\n\nfunc main() {}\n\nfunc unused() {}\n"
+	if err := os.WriteFile(filepath.Join(testRepo, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	countDeadCode := func(args ...string) int {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), append([]string{"scan", testRepo, "--format", "json", "--security-scan=false"}, args...)...)
+		if err != nil {
+			t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+		}
+		var issues []struct {
+			IssueType string `json:"issue_type"`
+		}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		count := 0
+		for _, issue := range issues {
+			if issue.IssueType == "dead_code" {
+				count++
+			}
+		}
+		return count
+	}
+
+	if n := countDeadCode(); n != 0 {
+		t.Errorf("Expected dead code detection to be off by default, got %d issues", n)
+	}
+	if n := countDeadCode("--dead-code"); n != 1 {
+		t.Errorf("Expected --dead-code to report 1 unused function, got %d", n)
+	}
+	if n := countDeadCode("--analyzers", "deadcode"); n != 1 {
+		t.Errorf("Expected --analyzers deadcode to report 1 unused function, got %d", n)
+	}
+}
+
+func TestScanCmd_ShowSuppressed(t *testing.T) {
+	testRepo := t.TempDir()
+	source := "package main\n\nfunc main() {}\n\n// debtdrone:ignore dead_code -- kept for the plugin loader\nfunc unused() {}\n"
+	if err := os.WriteFile(filepath.Join(testRepo, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"scan", testRepo, "--dead-code", "--security-scan=false", "--fail-on", "low"}
+
+	// The suppressed issue neither appears nor fails the gate.
+	output, err := executeCommand(createRootWithScan(), args...)
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+	if strings.Contains(output, "unused") {
+		t.Errorf("Expected the annotated function to be suppressed, got:\n%s", output)
+	}
+	if strings.Contains(output, "SUPPRESSED") {
+		t.Errorf("Expected suppressed issues to be listed only with --show-suppressed, got:\n%s", output)
+	}
+
+	output, err = executeCommand(createRootWithScan(), append(args, "--show-suppressed")...)
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+	for _, want := range []string{"SUPPRESSED", "dead_code", "/main.go:6", "line 5", "kept for the plugin loader"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in --show-suppressed output, got:\n%s", want, output)
+		}
+	}
+
+	output, err = executeCommand(createRootWithScan(), append(args, "--show-suppressed", "--format", "json-full")...)
+	if err != nil {
+		t.Fatalf("Scan failed: %v. Output:\n%s", err, output)
+	}
+	var report struct {
+		Suppressed []struct {
+			Line   int    `json:"line"`
+			Reason string `json:"reason"`
+		} `json:"suppressed"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(report.Suppressed) != 1 || report.Suppressed[0].Line != 5 || report.Suppressed[0].Reason != "kept for the plugin loader" {
+		t.Errorf("Unexpected suppressed list: %+v", report.Suppressed)
+	}
+}
+
+func TestScanCmd_ListLanguages(t *testing.T) {
+	output, err := executeCommand(createRootWithScan(), "scan", "--list-languages")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"LANGUAGE", "EXTENSIONS", "Go", ".kt, .kts"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the language list. Got:\n%s", want, output)
+		}
+	}
+
+	output, err = executeCommand(createRootWithScan(), "scan", "--list-languages", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var languages []struct {
+		Language   string   `json:"language"`
+		Extensions []string `json:"extensions"`
+	}
+	if err := json.Unmarshal([]byte(output), &languages); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(languages) == 0 || languages[0].Language != "Go" || languages[0].Extensions[0] != ".go" {
+		t.Errorf("Expected Go to be listed first, got %+v", languages)
+	}
+}
+
+func TestScanCmd_ListFormats(t *testing.T) {
+	output, err := executeCommand(createRootWithScan(), "scan", "--list-formats")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"FORMAT", "DESCRIPTION", "text", "json", "json-full"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the format list. Got:\n%s", want, output)
+		}
+	}
+
+	output, err = executeCommand(createRootWithScan(), "scan", "--list-formats", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var formats []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(output), &formats); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(formats) < 3 || formats[0].Name != "text" {
+		t.Errorf("Expected text to be listed first, got %+v", formats)
+	}
+}
+
+func TestScanCmd_UnknownFormat(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	_, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", "xml", "--security-scan=false")
+	if err == nil {
+		t.Fatal("Expected an unknown format to be rejected")
+	}
+	if exitCodeFor(err) != exitUsage {
+		t.Errorf("Expected exit code %d, got %d", exitUsage, exitCodeFor(err))
+	}
+	if !strings.Contains(err.Error(), "available: text, json, json-full") {
+		t.Errorf("Expected the error to list the formats, got %v", err)
+	}
+}
+
+func TestScanCmd_SummaryFooter(t *testing.T) {
+	testRepo := setupTestRepo(t)
+
+	output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, footer, found := strings.Cut(output, "\nSUMMARY\n")
+	if !found {
+		t.Fatalf("Expected a SUMMARY footer. Got:\n%s", output)
+	}
+	lines := strings.Split(strings.TrimSpace(footer), "\n")
+	if len(lines) > 10 {
+		t.Errorf("Expected a footer of at most 10 lines, got %d:\n%s", len(lines), footer)
+	}
+	for _, want := range []string{"Issues ", "Technical debt ", "Files affected ", "Avg complexity ", "critical "} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("Expected %q in the footer. Got:\n%s", want, footer)
+		}
+	}
+
+	output, err = executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false", "--quiet")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, unwanted := range []string{"SUMMARY", "CATEGORY", "LANGUAGE"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected --quiet to omit %q. Got:\n%s", unwanted, output)
+		}
+	}
+	if !strings.Contains(output, "SEVERITY") {
+		t.Errorf("Expected --quiet to keep the findings table. Got:\n%s", output)
+	}
+}
+
+func TestMergeComplexityStats(t *testing.T) {
+	metrics := map[string]interface{}{}
+	mergeComplexityStats(metrics, map[string]interface{}{"complexity_functions_analyzed": 3, "complexity_avg_cyclomatic": 2.0})
+	mergeComplexityStats(metrics, map[string]interface{}{"complexity_functions_analyzed": 0})
+	mergeComplexityStats(metrics, map[string]interface{}{"complexity_functions_analyzed": 1, "complexity_avg_cyclomatic": 6.0})
+
+	if got := metrics["complexity_functions_analyzed"]; got != 4 {
+		t.Errorf("Expected 4 functions, got %v", got)
+	}
+	if got := metrics["complexity_avg_cyclomatic"]; got != 3.0 {
+		t.Errorf("Expected a weighted average of 3, got %v", got)
+	}
+
+	metrics = map[string]interface{}{}
+	mergeComplexityStats(metrics, map[string]interface{}{"complexity_functions_analyzed": 0, "complexity_histogram": models.NewComplexityHistogram(nil)})
+	mergeComplexityStats(metrics, map[string]interface{}{
+		"complexity_functions_analyzed": 1,
+		"complexity_avg_cyclomatic":     12.0,
+		"complexity_histogram":          models.NewComplexityHistogram([]models.ComplexityMetric{{CyclomaticComplexity: 12}}),
+	})
+	histogram, _ := metrics["complexity_histogram"].(models.ComplexityHistogram)
+	if histogram.Functions() != 1 || histogram[2].Functions != 1 {
+		t.Errorf("Expected the histograms to be summed, got %+v", histogram)
+	}
+}
+
+func TestScanCmd_ComplexityHistogram(t *testing.T) {
+	type report struct {
+		Summary struct {
+			ComplexityHistogram []struct {
+				Range     string `json:"range"`
+				Functions int    `json:"functions"`
+			} `json:"complexity_histogram"`
+		} `json:"summary"`
+	}
+	histogram := func(t *testing.T, dir string) map[string]int {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), "scan", dir, "--format", "json-full", "--security-scan=false")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var r report
+		if err := json.Unmarshal([]byte(output), &r); err != nil {
+			t.Fatalf("Output is not a JSON object: %v\n%s", err, output)
+		}
+		counts := map[string]int{}
+		for _, bucket := range r.Summary.ComplexityHistogram {
+			counts[bucket.Range] = bucket.Functions
+		}
+		return counts
+	}
+
+	if got := histogram(t, setupTestRepo(t)); len(got) != 4 || got["11-20"] != 1 || got["1-5"] != 0 {
+		t.Errorf("Expected the function of complex.py in the 11-20 bucket, got %v", got)
+	}
+	if got := histogram(t, t.TempDir()); len(got) != 4 || got["1-5"] != 0 || got["21+"] != 0 {
+		t.Errorf("Expected an all-zero histogram for an empty repository, got %v", got)
+	}
+
+	output, err := executeCommand(createRootWithScan(), "scan", setupTestRepo(t), "--security-scan=false")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "COMPLEXITY   FUNCTIONS") || !strings.Contains(output, "11-20") {
+		t.Errorf("Expected the complexity histogram in the text report. Got:\n%s", output)
+	}
+}
+
+func TestMinifiedThresholdsFromConfig(t *testing.T) {
+	thresholds, err := minifiedThresholdsFromConfig(config.MinifiedConfig{AvgLineLength: 400, Extensions: []string{"js", ".bundle"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if thresholds.AvgLineLength != 400 || thresholds.MinBytes != 0 || strings.Join(thresholds.Extensions, ",") != ".js,.bundle" {
+		t.Errorf("Unexpected thresholds %+v", thresholds)
+	}
+
+	for _, cfg := range []config.MinifiedConfig{{AvgLineLength: -1}, {MinBytes: -5}, {Extensions: []string{" "}}} {
+		if _, err := minifiedThresholdsFromConfig(cfg); err == nil {
+			t.Errorf("Expected %+v to be rejected", cfg)
+		}
+	}
+}
+
+func TestSecurityDebtCostsFromConfig(t *testing.T) {
+	costs, err := securityDebtCostsFromConfig(config.SecurityDebtConfig{
+		SeverityHours:       map[string]float64{"Critical": 12},
+		SecretCategoryHours: map[string]float64{"AsymmetricPrivateKey": 16},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	costs = costs.WithDefaults()
+	if costs.VulnerabilityHours("critical") != 12 || costs.VulnerabilityHours("high") != 4 {
+		t.Errorf("Expected critical to be overridden and high to keep its default, got %+v", costs.SeverityHours)
+	}
+	if costs.SecretCost("asymmetricprivatekey") != 16 || costs.SecretCost("Slack") != 4 {
+		t.Errorf("Unexpected secret costs %+v", costs)
+	}
+
+	for _, cfg := range []config.SecurityDebtConfig{
+		{SeverityHours: map[string]float64{"urgent": 1}},
+		{SeverityHours: map[string]float64{"low": -1}},
+		{SecretHours: -2},
+		{SecretCategoryHours: map[string]float64{"Slack": -1}},
+	} {
+		if _, err := securityDebtCostsFromConfig(cfg); err == nil {
+			t.Errorf("Expected %+v to be rejected", cfg)
+		}
+	}
+}
+
+func TestRefactoringDocsFromConfig(t *testing.T) {
+	docs, err := refactoringDocsFromConfig(map[string]string{
+		"extract_method": "https://wiki.example.com/extract-method",
+		"reduce_nesting": "",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if docs["extract_method"] != "https://wiki.example.com/extract-method" || docs["reduce_nesting"] != "" || docs["split_file"] == "" {
+		t.Errorf("Unexpected catalog %v", docs)
+	}
+
+	for _, link := range []string{"wiki/extract-method", "ftp://example.com/doc", "https://"} {
+		if _, err := refactoringDocsFromConfig(map[string]string{"extract_method": link}); err == nil {
+			t.Errorf("Expected %q to be rejected", link)
+		}
+	}
+}
+
+func TestGeneratedPatternsFromConfig(t *testing.T) {
+	patterns, err := generatedPatternsFromConfig([]string{"*.sql.go", "internal/schema/**"})
+	if err != nil || len(patterns) != 2 {
+		t.Fatalf("Expected both patterns to be accepted, got %v (%v)", patterns, err)
+	}
+	for _, pattern := range []string{"", "gen/[a-.go"} {
+		if _, err := generatedPatternsFromConfig([]string{pattern}); err == nil {
+			t.Errorf("Expected %q to be rejected", pattern)
+		}
+	}
+}
+
+func TestScanCmd_DryRun(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	if err := os.MkdirAll(filepath.Join(testRepo, "node_modules", "dep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := t.TempDir()
+	previous := userCacheDir
+	userCacheDir = func() (string, error) { return cacheDir, nil }
+	t.Cleanup(func() { userCacheDir = previous })
+
+	output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--dry-run", "--disable-analyzers", "security")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"nothing was analyzed", "Analyzers: lines, complexity", "LANGUAGE", "Python ", "node_modules", "excluded by default"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the plan. Got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "SEVERITY") || strings.Contains(output, "security") {
+		t.Errorf("Expected no findings and no security analyzer in a dry run. Got:\n%s", output)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) > 0 {
+		t.Errorf("Expected a dry run to leave the cache untouched, found %d entries", len(entries))
+	}
+
+	output, err = executeCommand(createRootWithScan(), "scan", testRepo, "--dry-run", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var plans []struct {
+		Analyzers []string       `json:"analyzers"`
+		Languages map[string]int `json:"languages"`
+		Files     int            `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(output), &plans); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(plans) != 1 || plans[0].Files == 0 || plans[0].Languages["Python"] == 0 {
+		t.Errorf("Expected one plan counting the Python files, got %+v", plans)
+	}
+}
+
+func TestScanCmd_Profile(t *testing.T) {
+	testRepo := setupTestRepo(t)
+	plannedAnalyzers := func(args ...string) ([]string, error) {
+		t.Helper()
+		root := createRootWithScan()
+		root.SilenceUsage = true
+		output, err := executeCommand(root, append([]string{"scan", testRepo, "--dry-run", "--format", "json"}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+		var plans []struct {
+			Analyzers []string `json:"analyzers"`
+		}
+		if err := json.Unmarshal([]byte(output), &plans); err != nil || len(plans) != 1 {
+			t.Fatalf("Expected one JSON plan, got %v:\n%s", err, output)
+		}
+		return plans[0].Analyzers, nil
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"default is standard", nil, "lines,complexity,errcheck,blocking,indentation,endpoints,todos,security"},
+		{"quick", []string{"--profile", "quick"}, "complexity"},
+		{"deep", []string{"--profile", "Deep"}, "lines,complexity,errcheck,deadcode,blocking,indentation,endpoints,todos,dependencies,security"},
+		{"standard with dead code", []string{"--dead-code"}, "lines,complexity,errcheck,deadcode,blocking,indentation,endpoints,todos,security"},
+		{"analyzers override the profile", []string{"--profile", "quick", "--analyzers", "lines"}, "lines"},
+		{"security scan off", []string{"--profile", "deep", "--security-scan=false"}, "lines,complexity,errcheck,deadcode,blocking,indentation,endpoints,todos,dependencies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := plannedAnalyzers(tt.args...)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("Expected analyzers %s, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("analysis_depth in the config", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".debtdrone.yaml")
+		if err := os.WriteFile(configPath, []byte("analysis_depth: quick\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := plannedAnalyzers("--config", configPath)
+		if err != nil || strings.Join(got, ",") != "complexity" {
+			t.Errorf("Expected the quick profile from the config, got %v / %v", got, err)
+		}
+		got, err = plannedAnalyzers("--config", configPath, "--profile", "standard")
+		if err != nil || !strings.Contains(strings.Join(got, ","), "security") {
+			t.Errorf("Expected --profile to override analysis_depth, got %v / %v", got, err)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := plannedAnalyzers("--profile", "thorough")
+		if err == nil || !strings.Contains(err.Error(), "invalid --profile value") {
+			t.Errorf("Expected an invalid --profile error, got %v", err)
+		}
+	})
+}
+
+func TestScanCmd_QuickProfileScansChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := setupTestRepo(t)
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+
+	scanQuick := func() int {
+		t.Helper()
+		output, err := executeCommand(createRootWithScan(), "scan", repo, "--profile", "quick", "--format", "json", "--no-cache")
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		var issues []map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &issues); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+		return len(issues)
+	}
+
+	if n := scanQuick(); n != 0 {
+		t.Errorf("Expected no issues with nothing changed since HEAD, got %d", n)
+	}
+	f, err := os.OpenFile(filepath.Join(repo, "complex.py"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("                                            print(\"edited\")\n")
+	f.Close()
+	if n := scanQuick(); n == 0 {
+		t.Error("Expected the edited file to be analyzed")
+	}
+}
+
+func TestScanCmd_SkippedSection(t *testing.T) {
+	type skippedReport struct {
+		Skipped *struct {
+			Analyzers []struct {
+				Analyzer string `json:"analyzer"`
+				Reason   string `json:"reason"`
+			} `json:"analyzers"`
+			Files map[string]int `json:"files"`
+		} `json:"skipped"`
+	}
+
+	t.Run("omitted when nothing was skipped", func(t *testing.T) {
+		testRepo := setupTestRepo(t)
+		output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", "json-full", "--security-scan=false")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var report skippedReport
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not a JSON object: %v\n%s", err, output)
+		}
+		if report.Skipped != nil {
+			t.Errorf("Expected no skipped section, got %+v", report.Skipped)
+		}
+
+		output, err = executeCommand(createRootWithScan(), "scan", testRepo, "--security-scan=false")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strings.Contains(output, "SKIPPED") {
+			t.Errorf("Expected no skipped table, got:\n%s", output)
+		}
+	})
+
+	t.Run("lists skipped analyzers and files", func(t *testing.T) {
+		testRepo := setupTestRepo(t)
+		if err := os.WriteFile(filepath.Join(testRepo, "README.md"), []byte("# Readme\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(testRepo, "app.min.js"), []byte("var a=1;"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Without trivy on the PATH the security analyzer skips itself.
+		t.Setenv("PATH", t.TempDir())
+
+		output, err := executeCommand(createRootWithScan(), "scan", testRepo, "--format", "json-full")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var report skippedReport
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not a JSON object: %v\n%s", err, output)
+		}
+		if report.Skipped == nil {
+			t.Fatalf("Expected a skipped section, got:\n%s", output)
+		}
+		if len(report.Skipped.Analyzers) != 1 || report.Skipped.Analyzers[0].Analyzer != "security" || report.Skipped.Analyzers[0].Reason != "trivy not installed" {
+			t.Errorf("Expected the security analyzer to be skipped, got %+v", report.Skipped.Analyzers)
+		}
+		if report.Skipped.Files["unsupported"] != 1 || report.Skipped.Files["minified"] != 1 {
+			t.Errorf("Expected 1 unsupported and 1 minified file, got %v", report.Skipped.Files)
+		}
+
+		output, err = executeCommand(createRootWithScan(), "scan", testRepo)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, want := range []string{"SKIPPED", "security analyzer", "trivy not installed", "1 file", "unsupported file type", "minified or bundled output"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected text output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestScanCmd_BaselineFromRun(t *testing.T) {
+	t.Setenv("DB_HOST", "")
+	repo := setupTestRepo(t)
+	runID := "5b0e2f3c-1d2a-4c6b-9a8e-7f1d2c3b4a59"
+
+	for name, tt := range map[string]struct {
+		args []string
+		want string
+	}{
+		"malformed run ID": {[]string{"--baseline-from-run", "not-a-uuid"}, "must be an analysis run ID"},
+		"without database": {[]string{"--baseline-from-run", runID}, "requires a database"},
+		"with --state-file": {[]string{"--baseline-from-run", runID, "--state-file", filepath.Join(t.TempDir(), "state.json")}, "cannot be combined"},
+		"with --diff-run":   {[]string{"--baseline-from-run", runID, "--diff-run", runID}, "cannot be combined"},
+		"several paths":     {[]string{"--baseline-from-run", runID, repo}, "single scan path"},
+	} {
+		_, err := executeCommand(createRootWithScan(), append([]string{"scan", repo}, tt.args...)...)
+		if exitCodeFor(err) != exitUsage {
+			t.Errorf("%s: expected a usage error, got: %v", name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q in the error, got: %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestScanCmd_StoredRunsNeedTheRepositoryName(t *testing.T) {
+	// Nothing listens here; the checks fail before the database is opened.
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_PORT", "1")
+	repo := setupTestRepo(t)
+
+	for name, args := range map[string][]string{
+		"--fail-on-new":       {"--fail-on", "high", "--fail-on-new"},
+		"--baseline-from-run": {"--baseline-from-run", "5b0e2f3c-1d2a-4c6b-9a8e-7f1d2c3b4a59"},
+	} {
+		_, err := executeCommand(createRootWithScan(), append([]string{"scan", repo}, args...)...)
+		if exitCodeFor(err) != exitUsage || !strings.Contains(err.Error(), "cannot identify") {
+			t.Errorf("%s: expected a usage error for a checkout without an origin remote, got %v", name, err)
+		}
+	}
+}
+
+func TestScanCmd_DiffRunUnreachableDatabase(t *testing.T) {
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_PORT", "1")
+	repo := setupTestRepo(t)
+
+	_, err := executeCommand(createRootWithScan(), "scan", repo, "--diff-run", "5b0e2f3c-1d2a-4c6b-9a8e-7f1d2c3b4a59")
+	if exitCodeFor(err) != exitInternal || !strings.Contains(err.Error(), "database unreachable") {
+		t.Errorf("Expected an internal error naming the unreachable database, got %v", err)
+	}
+}
+
+func TestRunBaseline_NewIssues(t *testing.T) {
+	// The run was stored under the server's repository ID; the scan has the
+	// ID derived from the local checkout path.
+	stored := models.TechnicalDebtIssue{RepositoryID: uuid.New(), FilePath: "/a.go", IssueType: "complexity", Message: "Function 'handler1' has cyclomatic complexity of 14"}
+	recorded := models.TechnicalDebtIssue{RepositoryID: service.RepositoryID("/src/api"), FilePath: "/a.go", IssueType: "complexity", Message: "Function 'handler1' has cyclomatic complexity of 16"}
+	recorded.FingerprintHash = recorded.Fingerprint()
+	fresh := recorded
+	fresh.Message = "Function 'handler2' has cyclomatic complexity of 16"
+	fresh.FingerprintHash = fresh.Fingerprint()
+
+	baseline := &runBaseline{keys: map[string]bool{analysis.DiffKey(stored): true}}
+	issues := baseline.newIssues([]models.TechnicalDebtIssue{recorded, fresh})
+	if len(issues) != 1 || issues[0].Message != fresh.Message {
+		t.Errorf("Expected only the issue missing from the baseline run, got %+v", issues)
+	}
+}
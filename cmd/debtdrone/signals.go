@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+)
+
+// cleanupOnSignal returns a context that is cancelled by the first SIGINT or
+// SIGTERM, so the running scan stops and removes its clones and exports as
+// it returns. A second signal removes them right away and exits without
+// waiting for the scan. The returned function stops listening and must be
+// called once Execute returns.
+func cleanupOnSignal(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; !ok {
+			return
+		}
+		cancel()
+		if _, ok := <-signals; !ok {
+			return
+		}
+		git.RemoveTempDirs()
+		fmt.Fprintln(os.Stderr, "Error: interrupted")
+		os.Exit(exitInternal)
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		close(signals)
+		cancel()
+	}
+}
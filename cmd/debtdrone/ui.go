@@ -2,14 +2,23 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/fatih/color"
 	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
 )
 
+// plainOutput is set when stderr is not a terminal, NO_COLOR is set or
+// --no-color is given. Banners, reports and log lines are then written
+// without ANSI colors or emoji so CI log viewers render them cleanly.
+var plainOutput bool
+
 const DroneBanner = `                                  
 +--------------------------------------------------------------------------------------------------------+
 |                                                                                                        |
@@ -33,7 +42,96 @@ const DroneBanner = `
 +--------------------------------------------------------------------------------------------------------+
 `
 
+// addNoColorFlag registers the global --no-color flag on root and decides
+// between pretty and plain output before any subcommand runs. It wraps the
+// PersistentPreRunE already set on root, so it must be added last.
+func addNoColorFlag(root *cobra.Command) {
+	var noColor bool
+	root.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors and emoji (also disabled when NO_COLOR is set or stderr is not a terminal)")
+	next := root.PersistentPreRunE
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		configureOutput(noColor || os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stderr))
+		if next != nil {
+			return next(cmd, args)
+		}
+		return nil
+	}
+}
+
+// configureOutput switches colors and emoji off when plain is set, including
+// in the log lines analyzers write to stderr.
+func configureOutput(plain bool) {
+	plainOutput = plain
+	if plain {
+		color.NoColor = true
+		log.SetOutput(emojiStripper{w: os.Stderr})
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// emoji returns symbol followed by a space, or "" in plain output.
+func emoji(symbol string) string {
+	if plainOutput {
+		return ""
+	}
+	return symbol + " "
+}
+
+// emojiStripper writes through to w with emoji removed.
+type emojiStripper struct {
+	w io.Writer
+}
+
+func (e emojiStripper) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(e.w, stripEmoji(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// stripEmoji removes emoji, their variation selectors and the spaces that
+// follow them, so "⚠️  Trivy not installed" becomes "Trivy not installed".
+// Other non-ASCII text, such as file names, is kept.
+func stripEmoji(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	skipSpaces := false
+	for _, r := range s {
+		switch {
+		case isEmoji(r):
+			skipSpaces = true
+			continue
+		case skipSpaces && r == ' ':
+			continue
+		}
+		skipSpaces = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r == '\u200d', r == '\ufe0f', r == '\u20e3':
+		return true
+	case r >= 0x2600 && r <= 0x27bf, r >= 0x2b00 && r <= 0x2bff:
+		return unicode.IsSymbol(r)
+	case r >= 0x1f000 && r <= 0x1faff:
+		return true
+	}
+	return false
+}
+
 func printBanner() {
+	if plainOutput {
+		fmt.Fprintln(os.Stderr, "DebtDrone - Technical Debt Analyzer")
+		fmt.Fprintln(os.Stderr)
+		return
+	}
 	cyan := color.New(color.FgCyan).SprintFunc()
 	banner := strings.TrimPrefix(DroneBanner, "\n")
 
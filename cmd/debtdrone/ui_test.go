@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"⚠️  Trivy not installed - skipping security scan", "Trivy not installed - skipping security scan"},
+		{"2026/01/02 10:00:00 ⚠️ [ScanService] cache is corrupt", "2026/01/02 10:00:00 [ScanService] cache is corrupt"},
+		{"✅ Analyzed 3 functions", "Analyzed 3 functions"},
+		{"📊 Analysis Report", "Analysis Report"},
+		{"Failed to read /src/café.go: denied", "Failed to read /src/café.go: denied"},
+		{"plain ascii", "plain ascii"},
+	}
+	for _, tt := range tests {
+		if got := stripEmoji(tt.in); got != tt.want {
+			t.Errorf("stripEmoji(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEmojiStripper(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(emojiStripper{w: &buf}, "", 0)
+	logger.Println("✅ done")
+	if buf.String() != "done\n" {
+		t.Errorf("Expected log lines without emoji, got %q", buf.String())
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	defer func(noColor bool) {
+		color.NoColor = noColor
+		plainOutput = false
+		log.SetOutput(os.Stderr)
+	}(color.NoColor)
+
+	for _, tt := range []struct {
+		name    string
+		noColor string
+		args    []string
+	}{
+		{name: "--no-color flag", args: []string{"--no-color"}},
+		{name: "NO_COLOR environment variable", noColor: "1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			color.NoColor = false
+			plainOutput = false
+
+			root := createRootWithScan()
+			if _, err := executeCommand(root, append([]string{"scan", t.TempDir(), "--security-scan=false"}, tt.args...)...); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if !plainOutput || !color.NoColor {
+				t.Errorf("Expected plain output, got plainOutput=%v color.NoColor=%v", plainOutput, color.NoColor)
+			}
+			if got := emoji("📊"); got != "" {
+				t.Errorf("emoji() = %q in plain output, want empty", got)
+			}
+		})
+	}
+}
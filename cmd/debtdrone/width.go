@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// defaultReportWidth is the text report width when neither --width, COLUMNS
+// nor a terminal gives one, e.g. in CI logs.
+const defaultReportWidth = 80
+
+// reportWidth returns the width the text report is fitted to: flagWidth when
+// set, then the COLUMNS environment variable, then the size of the terminal
+// out writes to, and defaultReportWidth otherwise.
+func reportWidth(flagWidth int, out io.Writer) int {
+	if flagWidth > 0 {
+		return flagWidth
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if f, ok := out.(*os.File); ok && isTerminal(f) {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return defaultReportWidth
+}
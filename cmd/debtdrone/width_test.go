@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReportWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if got := reportWidth(0, &bytes.Buffer{}); got != defaultReportWidth {
+		t.Errorf("Expected %d for a non-terminal without COLUMNS, got %d", defaultReportWidth, got)
+	}
+	t.Setenv("COLUMNS", "100")
+	if got := reportWidth(0, &bytes.Buffer{}); got != 100 {
+		t.Errorf("Expected COLUMNS to set the width, got %d", got)
+	}
+	if got := reportWidth(60, &bytes.Buffer{}); got != 60 {
+		t.Errorf("Expected --width to win over COLUMNS, got %d", got)
+	}
+	t.Setenv("COLUMNS", "wide")
+	if got := reportWidth(0, &bytes.Buffer{}); got != defaultReportWidth {
+		t.Errorf("Expected a malformed COLUMNS to be ignored, got %d", got)
+	}
+}
@@ -0,0 +1,296 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// blockingCallDebtHours is the estimated effort to replace one blocking call
+// with its asynchronous counterpart.
+const blockingCallDebtHours = 0.25
+
+// DefaultBlockingAPIs are the synchronous Node.js APIs flagged when no
+// blocking_calls list is configured. A dotted entry matches that exact callee
+// or, for destructured imports, a bare call of its last segment; an undotted
+// entry matches a bare call or any method of that name.
+var DefaultBlockingAPIs = []string{
+	"fs.readFileSync",
+	"fs.writeFileSync",
+	"fs.appendFileSync",
+	"fs.readdirSync",
+	"fs.statSync",
+	"fs.existsSync",
+	"fs.mkdirSync",
+	"fs.unlinkSync",
+	"execSync",
+	"execFileSync",
+	"spawnSync",
+	"crypto.pbkdf2Sync",
+	"crypto.scryptSync",
+	"zlib.gzipSync",
+	"zlib.gunzipSync",
+	"JSON.parse",
+}
+
+// routeMethods are the Express/router registration methods whose callback
+// arguments are treated as request handlers.
+var routeMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "patch": true, "delete": true,
+	"all": true, "use": true, "head": true, "options": true,
+}
+
+// BlockingCallAnalyzer flags synchronous APIs called from JavaScript and
+// TypeScript code that runs on the event loop's request path: inside async
+// functions and Express-style route handlers. Top-level code such as startup
+// scripts is not reported.
+type BlockingCallAnalyzer struct{}
+
+func NewBlockingCallAnalyzer() *BlockingCallAnalyzer {
+	return &BlockingCallAnalyzer{}
+}
+
+func (a *BlockingCallAnalyzer) Name() string {
+	return "BlockingCallAnalyzer"
+}
+
+func (a *BlockingCallAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	apis := analysis.BlockingAPIsFromContext(ctx)
+	if len(apis) == 0 {
+		apis = DefaultBlockingAPIs
+	}
+
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	issues := []models.TechnicalDebtIssue{}
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "dist", "build":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		language := blockingCallLanguage(filepath.Ext(path))
+		if language == nil {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(repo.Path, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = "/" + filepath.ToSlash(relPath)
+
+		for _, site := range findBlockingCalls(language, content, apis) {
+			line := site.line
+			ruleID := "blocking-call"
+			description := fmt.Sprintf("%s blocks the event loop while it runs, stalling every other request handled by the process. Use the asynchronous equivalent (e.g. the fs/promises API) or move the work off the request path.", site.callee)
+			issues = append(issues, models.TechnicalDebtIssue{
+				ID:                 uuid.New(),
+				UserID:             userID,
+				RepositoryID:       repositoryID,
+				AnalysisRunID:      analysisRunID,
+				FilePath:           relPath,
+				LineNumber:         &line,
+				IssueType:          "blocking_call",
+				Severity:           "medium",
+				Category:           "performance",
+				Message:            fmt.Sprintf("Blocking call %s inside %s", site.callee, site.scope),
+				Description:        &description,
+				ToolName:           "blocking_call",
+				ToolRuleID:         &ruleID,
+				ConfidenceScore:    0.8,
+				TechnicalDebtHours: blockingCallDebtHours,
+				EffortMultiplier:   1.0,
+				Status:             "open",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Blocking call check found %d issues", len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"blocking_calls_count": len(issues),
+		},
+	}, nil
+}
+
+func blockingCallLanguage(ext string) *sitter.Language {
+	switch strings.ToLower(ext) {
+	case ".js", ".jsx", ".mjs", ".cjs":
+		return javascript.GetLanguage()
+	case ".ts", ".mts", ".cts":
+		return typescript.GetLanguage()
+	case ".tsx":
+		return tsx.GetLanguage()
+	default:
+		return nil
+	}
+}
+
+type blockingCallSite struct {
+	line   int
+	callee string
+	scope  string
+}
+
+// findBlockingCalls parses content and returns the blocking calls made inside
+// an async function or route handler. Functions nested in such a scope inherit
+// it, since callbacks like arr.map(...) run synchronously on the same path.
+func findBlockingCalls(language *sitter.Language, content []byte, apis []string) []blockingCallSite {
+	parser := sitter.NewParser()
+	parser.SetLanguage(language)
+	tree, err := parser.ParseCtx(context.Background(), nil, content)
+	if err != nil || tree == nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var sites []blockingCallSite
+	var visit func(n *sitter.Node, scope string)
+	visit = func(n *sitter.Node, scope string) {
+		switch n.Type() {
+		case "function_declaration", "function_expression", "function", "arrow_function", "method_definition", "generator_function_declaration":
+			if scope == "" {
+				if isAsyncFunction(n) {
+					scope = "async function"
+				} else if isRouteHandler(n, content) {
+					scope = "route handler"
+				}
+			}
+		case "call_expression":
+			if scope != "" {
+				if fn := n.ChildByFieldName("function"); fn != nil {
+					callee := fn.Content(content)
+					if matchesBlockingAPI(callee, apis) {
+						sites = append(sites, blockingCallSite{
+							line:   int(n.StartPoint().Row) + 1,
+							callee: callee,
+							scope:  scope,
+						})
+					}
+				}
+			}
+		}
+
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			visit(n.NamedChild(i), scope)
+		}
+	}
+	visit(tree.RootNode(), "")
+
+	return sites
+}
+
+func isAsyncFunction(fn *sitter.Node) bool {
+	for i := 0; i < int(fn.ChildCount()); i++ {
+		if fn.Child(i).Type() == "async" {
+			return true
+		}
+	}
+	return false
+}
+
+// isRouteHandler reports whether fn is a callback passed to a route
+// registration such as app.get("/x", (req, res) => ...).
+func isRouteHandler(fn *sitter.Node, content []byte) bool {
+	args := fn.Parent()
+	if args == nil || args.Type() != "arguments" {
+		return false
+	}
+	call := args.Parent()
+	if call == nil || call.Type() != "call_expression" {
+		return false
+	}
+	callee := call.ChildByFieldName("function")
+	if callee == nil || callee.Type() != "member_expression" {
+		return false
+	}
+	property := callee.ChildByFieldName("property")
+	if property == nil || !routeMethods[property.Content(content)] {
+		return false
+	}
+
+	params := fn.ChildByFieldName("parameters")
+	if params == nil {
+		return false
+	}
+	count := int(params.NamedChildCount())
+	return count >= 2 && count <= 4
+}
+
+func matchesBlockingAPI(callee string, apis []string) bool {
+	lastSegment := callee
+	if i := strings.LastIndex(callee, "."); i >= 0 {
+		lastSegment = callee[i+1:]
+	}
+	bare := !strings.Contains(callee, ".")
+
+	for _, api := range apis {
+		if callee == api {
+			return true
+		}
+		if i := strings.LastIndex(api, "."); i >= 0 {
+			if bare && callee == api[i+1:] {
+				return true
+			}
+		} else if lastSegment == api {
+			return true
+		}
+	}
+	return false
+}
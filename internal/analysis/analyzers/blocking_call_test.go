@@ -0,0 +1,69 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockingCallAnalyzer(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/blocking")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	t.Run("default APIs", func(t *testing.T) {
+		result, err := NewBlockingCallAnalyzer().Analyze(ctx, repo)
+		require.NoError(t, err)
+
+		var found []string
+		for _, issue := range result.Issues {
+			assert.Equal(t, "blocking_call", issue.IssueType)
+			assert.Equal(t, "performance", issue.Category)
+			assert.Equal(t, "medium", issue.Severity)
+			require.NotNil(t, issue.LineNumber)
+			found = append(found, issue.FilePath+":"+issue.Message)
+		}
+		sort.Strings(found)
+
+		// Top-level startup reads and the plain helper/sync method are not flagged.
+		assert.Equal(t, []string{
+			"/server.js:Blocking call execSync inside async function",
+			"/server.js:Blocking call fs.readFileSync inside route handler",
+			"/server.js:Blocking call fs.statSync inside async function",
+			"/worker.ts:Blocking call fs.writeFileSync inside async function",
+		}, found)
+		assert.Equal(t, 4, result.Metrics["blocking_calls_count"])
+	})
+
+	t.Run("configured APIs replace the defaults", func(t *testing.T) {
+		ctx := analysis.WithBlockingAPIs(ctx, []string{"fs.statSync"})
+		result, err := NewBlockingCallAnalyzer().Analyze(ctx, repo)
+		require.NoError(t, err)
+
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, 22, *result.Issues[0].LineNumber)
+	})
+}
+
+func TestMatchesBlockingAPI(t *testing.T) {
+	apis := []string{"fs.readFileSync", "execSync"}
+
+	assert.True(t, matchesBlockingAPI("fs.readFileSync", apis))
+	assert.True(t, matchesBlockingAPI("readFileSync", apis), "destructured import")
+	assert.True(t, matchesBlockingAPI("cp.execSync", apis), "undotted entry matches any receiver")
+	assert.False(t, matchesBlockingAPI("other.readFileSync", apis))
+	assert.False(t, matchesBlockingAPI("fs.readFile", apis))
+}
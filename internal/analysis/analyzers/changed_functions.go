@@ -0,0 +1,70 @@
+package analyzers
+
+import (
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers/complexity"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// changedFunctions keeps the metrics of the functions of content that are new
+// since the base ref or whose body differs from it, and returns how many it
+// dropped as unchanged. Functions are matched by name, so a renamed function
+// counts as new; bodies are compared line by line ignoring trailing
+// whitespace and line endings, so moving a function within its file is not
+// a change. When the base content cannot be analyzed every function is kept,
+// and so are notebook functions, whose lines are relative to their cell.
+func (a *ComplexityAnalyzer) changedFunctions(path, relPath string, content []byte, metrics []models.ComplexityMetric, base []byte, analyzer complexity.Analyzer, cache *analysis.FileCache) ([]models.ComplexityMetric, int) {
+	var baseMetrics []models.ComplexityMetric
+	if !cache.Get(a.cacheKey(path, base), &baseMetrics) {
+		var err error
+		if baseMetrics, err = analyzer.AnalyzeFile(relPath, base); err != nil {
+			return metrics, 0
+		}
+	}
+
+	// Several functions can share a name, e.g. "<anonymous>", so each name
+	// maps to the bodies it had at the base; a match is used up.
+	baseBodies := map[string]map[string]int{}
+	for _, m := range baseMetrics {
+		if m.NotebookCell != nil {
+			continue
+		}
+		if baseBodies[m.FunctionName] == nil {
+			baseBodies[m.FunctionName] = map[string]int{}
+		}
+		baseBodies[m.FunctionName][functionBody(base, m)]++
+	}
+
+	changed := metrics[:0]
+	unchanged := 0
+	for _, m := range metrics {
+		if m.NotebookCell == nil {
+			body := functionBody(content, m)
+			if bodies := baseBodies[m.FunctionName]; bodies[body] > 0 {
+				bodies[body]--
+				unchanged++
+				continue
+			}
+		}
+		changed = append(changed, m)
+	}
+	return changed, unchanged
+}
+
+// functionBody returns the lines of content a metric spans, each trimmed of
+// trailing whitespace. Leading whitespace is kept since it is significant in
+// Python.
+func functionBody(content []byte, metric models.ComplexityMetric) string {
+	lines := strings.Split(string(content), "\n")
+	from, to := max(metric.StartLine, 1), min(metric.EndLine, len(lines))
+	if from > to {
+		return ""
+	}
+	body := make([]string, 0, to-from+1)
+	for _, line := range lines[from-1 : to] {
+		body = append(body, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Join(body, "\n")
+}
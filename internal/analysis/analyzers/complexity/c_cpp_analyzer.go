@@ -2,6 +2,7 @@ package complexity
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
@@ -28,8 +29,8 @@ func (a *CCppAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	var metrics []models.ComplexityMetric
 
 	ctx := context.Background()
-	parser := sitter.NewParser()
-	parser.SetLanguage(cpp.GetLanguage())
+	parser := cppParsers.get()
+	defer cppParsers.put(parser)
 
 	tree, err := parser.ParseCtx(ctx, nil, content)
 	if err != nil {
@@ -37,15 +38,22 @@ func (a *CCppAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
-	functions := findCCppFunctions(root, content)
+	functions, err := findCCppFunctions(root, content)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, fn := range functions {
 		nodes := mapCCppNodes(fn.Node, content)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.BodyContent, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 		debtMinutes := estimateCCppTechnicalDebt(cyclomatic, cognitive, loc)
 		suggestions := generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, fn.ParamCount, loc)
 
@@ -69,6 +77,7 @@ func (a *CCppAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 			CodeSnippet:            &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -82,14 +91,17 @@ type cCppFunctionInfo struct {
 	ParamCount  int
 }
 
-func findCCppFunctions(root *sitter.Node, content []byte) []cCppFunctionInfo {
+func findCCppFunctions(root *sitter.Node, content []byte) ([]cCppFunctionInfo, error) {
 	var functions []cCppFunctionInfo
 
 	queryStr := `
 		(function_definition declarator: (_) @declarator body: (_) @body)
 		(lambda_expression body: (_) @body) @lambda
 	`
-	q, _ := sitter.NewQuery([]byte(queryStr), cpp.GetLanguage())
+	q, err := sitter.NewQuery([]byte(queryStr), cpp.GetLanguage())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile C/C++ function query: %w", err)
+	}
 	qc := sitter.NewQueryCursor()
 	defer qc.Close()
 	defer q.Close()
@@ -132,7 +144,7 @@ func findCCppFunctions(root *sitter.Node, content []byte) []cCppFunctionInfo {
 		})
 	}
 
-	return functions
+	return functions, nil
 }
 
 func extractFunctionName(declarator *sitter.Node, content []byte) string {
@@ -233,6 +245,7 @@ func generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cyclomatic > 15 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "extract_method",
 			Priority:    "high",
 			Title:       "Extract Functions",
 			Description: "Break down this function into smaller, focused functions. Consider using inline functions for performance-critical paths",
@@ -241,6 +254,7 @@ func generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if nesting > 3 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "reduce_nesting",
 			Priority:    "high",
 			Title:       "Reduce Nesting Depth",
 			Description: "Use early returns, guard clauses, or extract nested logic into helper functions",
@@ -249,6 +263,7 @@ func generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if paramCount > 5 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "introduce_parameter_object",
 			Priority:    "medium",
 			Title:       "Too Many Parameters",
 			Description: "Consider using a struct/class to group related parameters or use parameter objects",
@@ -257,6 +272,7 @@ func generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if loc > 50 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "split_function",
 			Priority:    "high",
 			Title:       "Function Too Long",
 			Description: "Split this function into smaller functions. Consider separating algorithm from data structure manipulation",
@@ -265,6 +281,7 @@ func generateCCppRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cognitive > 20 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "simplify_logic",
 			Priority:    "medium",
 			Title:       "Simplify Logic",
 			Description: "Simplify control flow, reduce pointer complexity, or use RAII patterns to improve readability",
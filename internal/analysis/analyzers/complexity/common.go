@@ -1,11 +1,25 @@
 package complexity
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
+// ErrParseFailed is returned by AnalyzeFile when a file's syntax errors are too
+// extensive for its metrics to be meaningful. The ComplexityAnalyzer skips
+// such files and reports how many it skipped as parse_errors.
+var ErrParseFailed = errors.New("too many syntax errors")
+
+// maxParseErrorRatio is the share of a file's bytes that may sit under ERROR
+// nodes before the file is skipped. Tree-sitter recovers locally from most
+// mistakes, so a few broken statements still leave usable function metrics.
+const maxParseErrorRatio = 0.5
+
 type functionInfo struct {
 	name       string
 	line       int
@@ -13,6 +27,16 @@ type functionInfo struct {
 	body       string
 	paramCount int
 	Node       *sitter.Node
+
+	// flagParams and returnValues feed GenerateSignatureSuggestions for
+	// analyzers that extract them.
+	flagParams   []string
+	returnValues int
+
+	// returns and guardReturns count the function's return statements, see
+	// countReturns.
+	returns      int
+	guardReturns int
 }
 
 func WalkTree(node *sitter.Node, visitor func(*sitter.Node)) {
@@ -54,17 +78,127 @@ func WalkTree(node *sitter.Node, visitor func(*sitter.Node)) {
 	}
 }
 
-func classifyComplexitySeverity(cyclomatic, cognitive, nesting int) string {
-	if cyclomatic > 20 || cognitive > 25 || nesting > 5 {
+// checkParseErrors reports ErrParseFailed when ERROR nodes cover more than
+// maxParseErrorRatio of root.
+func checkParseErrors(root *sitter.Node) error {
+	if root == nil {
+		return fmt.Errorf("%w: parser returned no tree", ErrParseFailed)
+	}
+	if !root.HasError() {
+		return nil
+	}
+	total := root.EndByte() - root.StartByte()
+	if total == 0 {
+		return nil
+	}
+
+	var broken uint32
+	var visit func(n *sitter.Node)
+	visit = func(n *sitter.Node) {
+		if n.IsError() {
+			broken += n.EndByte() - n.StartByte()
+			return
+		}
+		for i := 0; i < int(n.ChildCount()); i++ {
+			if child := n.Child(i); child.HasError() {
+				visit(child)
+			}
+		}
+	}
+	visit(root)
+
+	ratio := float64(broken) / float64(total)
+	if ratio > maxParseErrorRatio {
+		return fmt.Errorf("%w: %.0f%% of the file could not be parsed", ErrParseFailed, ratio*100)
+	}
+	return nil
+}
+
+// locFromNode returns the number of source lines spanned by node, counting
+// the first and last line.
+func locFromNode(node *sitter.Node) int {
+	if node == nil {
+		return 0
+	}
+	return int(node.EndPoint().Row-node.StartPoint().Row) + 1
+}
+
+// classifyComplexitySeverity is the severity scale of the grammar-based and
+// scanned analyzers, which is more lenient than DetermineSeverity: a
+// function only turns high halfway between the high and critical thresholds.
+// With DefaultComplexityThresholds it is critical above 20 cyclomatic, 25
+// cognitive or 5 nesting, high above 15, 20 or 4 and medium above 10, 15
+// or 3.
+func classifyComplexitySeverity(t models.ComplexityThresholds, cyclomatic, cognitive, nesting int) string {
+	if cyclomatic > t.CyclomaticCritical || cognitive > t.CognitiveCritical || nesting > t.NestingCritical-1 {
 		return "critical"
-	} else if cyclomatic > 15 || cognitive > 20 || nesting > 4 {
+	} else if cyclomatic > (t.CyclomaticHigh+t.CyclomaticCritical)/2 || cognitive > (t.CognitiveHigh+t.CognitiveCritical)/2 || nesting > t.NestingWarning {
 		return "high"
-	} else if cyclomatic > 10 || cognitive > 15 || nesting > 3 {
+	} else if cyclomatic > t.CyclomaticHigh || cognitive > t.CognitiveHigh || nesting > t.NestingWarning-1 {
 		return "medium"
 	}
 	return "low"
 }
 
+// returnScopes are the node types, across the JavaScript, TypeScript, Java
+// and Python grammars, that open a function or class of their own: the
+// returns inside them belong to it rather than to the enclosing function.
+var returnScopes = map[string]bool{
+	"function_declaration": true, "function_expression": true, "function": true,
+	"generator_function_declaration": true, "generator_function": true,
+	"arrow_function": true, "method_definition": true, "class_declaration": true, "class": true,
+	"lambda_expression": true, "class_body": true, "method_declaration": true, "constructor_declaration": true,
+	"function_definition": true, "lambda": true, "class_definition": true,
+}
+
+// countReturns counts the return statements of the function whose body is
+// body, and how many of them are guard clauses: the returns of the if
+// statements without an else that open the body, before any other statement.
+func countReturns(body *sitter.Node) (returns, guards int) {
+	if body == nil {
+		return 0, 0
+	}
+	returns = countReturnStatements(body)
+
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		statement := body.NamedChild(i)
+		if strings.Contains(statement.Type(), "comment") || isDocString(statement) {
+			continue
+		}
+		if statement.Type() != "if_statement" || statement.ChildByFieldName("alternative") != nil {
+			break
+		}
+		n := countReturnStatements(statement)
+		if n == 0 {
+			break
+		}
+		guards += n
+	}
+	return returns, guards
+}
+
+func countReturnStatements(node *sitter.Node) int {
+	count := 0
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch {
+		case returnScopes[child.Type()]:
+			continue
+		case child.Type() == "return_statement":
+			count++
+		}
+		count += countReturnStatements(child)
+	}
+	return count
+}
+
+// isDocString reports whether statement is a bare string, such as a Python
+// docstring or a "use strict" directive.
+func isDocString(statement *sitter.Node) bool {
+	return statement.Type() == "expression_statement" && statement.NamedChildCount() == 1 &&
+		statement.NamedChild(0).Type() == "string"
+}
+
 func truncateSnippet(code string, maxLen int) string {
 	if len(code) <= maxLen {
 		return code
@@ -90,3 +224,56 @@ func countParameterString(params string) int {
 	}
 	return count
 }
+
+// scannedFunction is a function located by one of the hand-written scanners
+// used for languages without a tree-sitter grammar, together with the
+// control flow found in its body (nested functions excluded).
+type scannedFunction struct {
+	functionInfo
+	nodes []Node
+}
+
+// buildScannedMetric scores a scannedFunction like the grammar-based analyzers
+// score a function node.
+func buildScannedMetric(language, filePath string, fn scannedFunction, thresholds models.ComplexityThresholds) models.ComplexityMetric {
+	cyclomatic, cognitive, nesting := CalculateComplexity(fn.nodes)
+	loc := fn.endLine - fn.line + 1
+
+	severity := classifyComplexitySeverity(thresholds, cyclomatic, cognitive, nesting)
+	debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
+
+	cognitivePtr := cognitive
+	snippetStr := truncateSnippet(fn.body, 10000)
+
+	return models.ComplexityMetric{
+		ID:                   uuid.New(),
+		FilePath:             filePath,
+		FunctionName:         fn.name,
+		StartLine:            fn.line,
+		EndLine:              fn.endLine,
+		CyclomaticComplexity: cyclomatic,
+		CognitiveComplexity:  &cognitivePtr,
+		NestingDepth:         nesting,
+		ParameterCount:       fn.paramCount,
+		LinesOfCode:          loc,
+		Severity:             severity,
+		TechnicalDebtMinutes: debtMinutes,
+		CodeSnippet:          &snippetStr,
+		Language:             language,
+	}
+}
+
+// sourceLines returns lines from through to (1-based, inclusive) of src.
+func sourceLines(src string, from, to int) string {
+	lines := strings.Split(src, "\n")
+	if from < 1 {
+		from = 1
+	}
+	if to > len(lines) {
+		to = len(lines)
+	}
+	if from > to {
+		return ""
+	}
+	return strings.Join(lines[from-1:to], "\n")
+}
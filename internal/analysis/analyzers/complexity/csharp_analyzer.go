@@ -29,8 +29,8 @@ func (a *CSharpAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 	var metrics []models.ComplexityMetric
 
 	ctx := context.Background()
-	parser := sitter.NewParser()
-	parser.SetLanguage(csharp.GetLanguage())
+	parser := csharpParsers.get()
+	defer csharpParsers.put(parser)
 
 	tree, err := parser.ParseCtx(ctx, nil, content)
 	if err != nil {
@@ -38,15 +38,23 @@ func (a *CSharpAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
-	functions := findCSharpFunctions(root, content)
+	functions, err := findCSharpFunctions(root, content)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, fn := range functions {
 		nodes := mapCSharpNodes(fn.Node, content)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.BodyContent, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
 
 		cognitivePtr := cognitive
 		snippetStr := truncateSnippet(fn.BodyContent, 10000)
@@ -63,9 +71,11 @@ func (a *CSharpAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 			ParameterCount:       fn.ParamCount,
 			LinesOfCode:          loc,
 			Severity:             severity,
+			TechnicalDebtMinutes: debtMinutes,
 			CodeSnippet:          &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -79,7 +89,7 @@ type cSharpFunctionInfo struct {
 	ParamCount  int
 }
 
-func findCSharpFunctions(root *sitter.Node, content []byte) []cSharpFunctionInfo {
+func findCSharpFunctions(root *sitter.Node, content []byte) ([]cSharpFunctionInfo, error) {
 	var functions []cSharpFunctionInfo
 	queryStr := `
 	(method_declaration name: (_) @name body: (_) @body)
@@ -90,8 +100,7 @@ func findCSharpFunctions(root *sitter.Node, content []byte) []cSharpFunctionInfo
 	`
 	q, err := sitter.NewQuery([]byte(queryStr), csharp.GetLanguage())
 	if err != nil {
-		fmt.Printf("Tree-Sitter query parse error: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("failed to compile C# function query: %w", err)
 	}
 	qc := sitter.NewQueryCursor()
 	defer qc.Close()
@@ -150,7 +159,7 @@ func findCSharpFunctions(root *sitter.Node, content []byte) []cSharpFunctionInfo
 		})
 	}
 
-	return functions
+	return functions, nil
 }
 
 func countCSharpParameters(paramList *sitter.Node) int {
@@ -0,0 +1,86 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzers_EmptyFunctions checks that every analyzer measures empty
+// functions, and functions holding nothing but a switch without cases, as
+// the simplest possible function, and skips declarations without a body.
+func TestAnalyzers_EmptyFunctions(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+
+	fixtures := []struct {
+		path string
+		code string
+		// want maps each function the analyzer reports to its lines of code.
+		want map[string]int
+	}{
+		{"main.go", "package main\n\nfunc f() {}\n\nfunc g(x int) {\n\tswitch x {\n\t}\n}\n\nfunc h() {\n\tswitch {\n\t}\n\tvar v interface{}\n\tswitch v.(type) {\n\t}\n}\n\nfunc asm(x int) int\n\ntype I interface {\n\tM()\n}\n",
+			map[string]int{"f": 1, "g": 4, "h": 7}},
+		{"app.js", "function f() {}\n\nfunction g(x) {\n  switch (x) {\n  }\n}\n\nconst h = () => {};\n\nclass A {\n  m() {}\n}\n",
+			map[string]int{"f": 1, "g": 4, "h": 1, "m": 1}},
+		{"app.ts", "function f(): void {}\n\nfunction g(x: number) {\n  switch (x) {\n  }\n}\n\ndeclare function d(x: number): void;\n\ninterface I {\n  m(): void;\n}\n\nabstract class A {\n  abstract m(): void;\n}\n",
+			map[string]int{"f": 1, "g": 4}},
+		{"app.py", "def f():\n    pass\n\ndef g(x):\n    ...\n\nclass A:\n    def m(self):\n        \"\"\"Doc.\"\"\"\n\nh = lambda: None\n",
+			map[string]int{"f": 2, "g": 2, "m": 2, "<lambda>": 1}},
+		{"App.java", "interface I {\n  void m();\n}\n\nabstract class App {\n  void f() {}\n  void g(int x) {\n    switch (x) {\n    }\n  }\n  abstract void a();\n}\n",
+			map[string]int{"f": 1, "g": 4}},
+		{"App.cs", "interface I {\n  void M();\n}\n\nabstract class App {\n  void F() {}\n  void G(int x) {\n    switch (x) {\n    }\n  }\n  public abstract void A();\n}\n",
+			map[string]int{"F": 1, "G": 4}},
+		{"app.php", "<?php\ninterface I {\n  function m();\n}\nfunction f() {}\nfunction g($x) {\n  switch ($x) {\n  }\n}\nabstract class A {\n  abstract function a();\n}\n",
+			map[string]int{"f": 1, "g": 4}},
+		{"app.rb", "def f\nend\n\ndef g(x)\n  case x\n  end\nend\n\ndef h; end\n",
+			map[string]int{"f": 2, "g": 4, "h": 1}},
+		{"main.rs", "fn f() {}\n\nfn g(x: i32) {\n    match x {\n    }\n}\n\ntrait T {\n    fn m(&self);\n}\n",
+			map[string]int{"f": 1, "g": 4}},
+		{"App.kt", "fun f() {}\n\nfun g(x: Int) {\n    when (x) {\n    }\n}\n\nfun h() = Unit\n\ninterface I {\n    fun m()\n}\n\nabstract class A {\n    abstract fun a()\n}\n",
+			map[string]int{"f": 1, "g": 4, "h": 1}},
+		{"App.swift", "func f() {}\n\nfunc g(x: Int) {\n    switch x {\n    }\n}\n\nprotocol P {\n    func m()\n}\n",
+			map[string]int{"f": 1, "g": 4}},
+		{"View.m", "@interface View\n- (void)m;\n@end\n\n@implementation View\n- (void)f {}\n- (void)g:(int)x {\n  switch (x) {\n  }\n}\n@end\n\nvoid c(void) {}\n",
+			map[string]int{"-[View f]": 1, "-[View g:]": 4, "c": 1}},
+		{"main.cpp", "void d();\n\nvoid f() {}\n\nvoid g(int x) {\n  switch (x) {\n  }\n}\n\nclass A {\n  virtual void m() = 0;\n  void n() {}\n};\n",
+			map[string]int{"f": 1, "g": 4, "n": 1}},
+		{"stats.R", "f <- function() {}\n\ng <- function(x) switch(x)\n",
+			map[string]int{"f": 1, "g": 1}},
+		{"f.m", "function f()\nend\n\nfunction g(x)\n  switch x\n  end\nend\n",
+			map[string]int{"f": 2, "g": 4}},
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.path, func(t *testing.T) {
+			analyzer, err := factory.GetAnalyzer(fixture.path)
+			require.NoError(t, err)
+
+			metrics, err := analyzer.AnalyzeFile(fixture.path, []byte(fixture.code))
+			require.NoError(t, err)
+
+			got := map[string]int{}
+			for _, m := range metrics {
+				assert.NotContains(t, got, m.FunctionName, "%s reported twice", m.FunctionName)
+				got[m.FunctionName] = m.LinesOfCode
+
+				assert.Equal(t, 1, m.CyclomaticComplexity, "cyclomatic complexity of %s", m.FunctionName)
+				require.NotNil(t, m.CognitiveComplexity, m.FunctionName)
+				assert.Zero(t, *m.CognitiveComplexity, "cognitive complexity of %s", m.FunctionName)
+				assert.Zero(t, m.NestingDepth, "nesting depth of %s", m.FunctionName)
+				assert.Equal(t, m.EndLine-m.StartLine+1, m.LinesOfCode, "lines of code of %s", m.FunctionName)
+			}
+			assert.Equal(t, fixture.want, got)
+
+			again, err := analyzer.AnalyzeFile(fixture.path, []byte(fixture.code))
+			require.NoError(t, err)
+			require.Len(t, again, len(metrics))
+			for i := range metrics {
+				assert.Equal(t, metrics[i].FunctionName, again[i].FunctionName)
+				assert.Equal(t, metrics[i].LinesOfCode, again[i].LinesOfCode)
+				assert.Equal(t, metrics[i].CyclomaticComplexity, again[i].CyclomaticComplexity)
+			}
+		})
+	}
+}
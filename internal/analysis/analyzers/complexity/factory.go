@@ -14,65 +14,228 @@ type Analyzer interface {
 	Language() string
 }
 
+// languageSpec registers an analyzer constructor for a set of extensions.
+type languageSpec struct {
+	extensions  []string
+	newAnalyzer func(models.ComplexityThresholds) Analyzer
+}
+
+// languageSpecs lists every analyzer the factory dispatches to, in the order
+// Languages reports them. Extensions are lower case with the leading dot.
+var languageSpecs = []languageSpec{
+	{[]string{".go"}, func(t models.ComplexityThresholds) Analyzer { return NewGoAnalyzer(t) }},
+	{[]string{".js", ".jsx"}, func(t models.ComplexityThresholds) Analyzer { return NewJavaScriptAnalyzer(t) }},
+	{[]string{".ts", ".tsx"}, func(t models.ComplexityThresholds) Analyzer { return NewTypeScriptAnalyzer(t) }},
+	{[]string{".py"}, func(t models.ComplexityThresholds) Analyzer { return NewPythonAnalyzer(t) }},
+	{[]string{".ipynb"}, func(t models.ComplexityThresholds) Analyzer { return NewNotebookAnalyzer(t) }},
+	{[]string{".cs"}, func(t models.ComplexityThresholds) Analyzer { return NewCSharpAnalyzer(t) }},
+	{[]string{".php"}, func(t models.ComplexityThresholds) Analyzer { return NewPHPAnalyzer(t) }},
+	{[]string{".java"}, func(t models.ComplexityThresholds) Analyzer { return NewJavaAnalyzer(t) }},
+	{[]string{".rb"}, func(t models.ComplexityThresholds) Analyzer { return NewRubyAnalyzer(t) }},
+	{[]string{".rs"}, func(t models.ComplexityThresholds) Analyzer { return NewRustAnalyzer(t) }},
+	{[]string{".kt", ".kts"}, func(t models.ComplexityThresholds) Analyzer { return NewKotlinAnalyzer(t) }},
+	{[]string{".swift"}, func(t models.ComplexityThresholds) Analyzer { return NewSwiftAnalyzer(t) }},
+	// .m is shared by Objective-C and MATLAB; MFileAnalyzer tells them apart.
+	{[]string{".m"}, func(t models.ComplexityThresholds) Analyzer { return NewMFileAnalyzer(t, t) }},
+	{[]string{".mm"}, func(t models.ComplexityThresholds) Analyzer { return NewObjCAnalyzer(t) }},
+	{[]string{".c", ".cpp", ".cc", ".cxx", ".c++", ".h", ".hpp", ".hxx", ".h++"}, func(t models.ComplexityThresholds) Analyzer { return NewCCppAnalyzer(t) }},
+	{[]string{".r"}, func(t models.ComplexityThresholds) Analyzer { return NewRAnalyzer(t) }},
+}
+
+// languageThresholder is implemented by the analyzers that dispatch to
+// several languages, so each language is scored with its own thresholds.
+type languageThresholder interface {
+	withLanguageThresholds(thresholdsOf func(language string) (models.ComplexityThresholds, bool)) Analyzer
+}
+
 // Factory creates the appropriate complexity analyzer based on file extension
 type Factory struct {
-	thresholds models.ComplexityThresholds
+	thresholds  models.ComplexityThresholds
+	specs       []languageSpec
+	byExtension map[string]languageSpec
+	// languageThresholds holds the thresholds of each extension whose
+	// language has its own.
+	languageThresholds map[string]models.ComplexityThresholds
+	// byLanguage holds the thresholds of each language that has its own,
+	// keyed by lower-case name, for the analyzers of several languages.
+	byLanguage map[string]models.ComplexityThresholds
 }
 
-// NewFactory creates a new analyzer factory
+// NewFactory creates a new analyzer factory. A misconfigured languageSpecs
+// table is a programming error and panics; see Validate.
 func NewFactory(thresholds models.ComplexityThresholds) *Factory {
+	return NewLanguageFactory(thresholds, nil)
+}
+
+// NewLanguageFactory is NewFactory with thresholds of their own for some
+// languages, keyed by language name as reported by Languages (e.g. "Go",
+// "Rust", "C/C++") and matched case-insensitively. Languages without an entry
+// use thresholds. An analyzer covering several languages scores each with its
+// own: .m files use those of Objective-C or MATLAB, whichever they are.
+func NewLanguageFactory(thresholds models.ComplexityThresholds, languages map[string]models.ComplexityThresholds) *Factory {
+	f := newFactory(thresholds, languageSpecs)
+	if err := f.Validate(); err != nil {
+		panic(fmt.Sprintf("complexity: %v", err))
+	}
+	f.setLanguageThresholds(languages)
+	return f
+}
+
+func newFactory(thresholds models.ComplexityThresholds, specs []languageSpec) *Factory {
+	byExtension := make(map[string]languageSpec)
+	for _, spec := range specs {
+		for _, ext := range spec.extensions {
+			if _, taken := byExtension[ext]; !taken {
+				byExtension[ext] = spec
+			}
+		}
+	}
 	return &Factory{
-		thresholds: thresholds,
+		thresholds:  thresholds,
+		specs:       specs,
+		byExtension: byExtension,
 	}
 }
 
+// setLanguageThresholds resolves languages to the extensions of the
+// analyzers it applies to, so GetAnalyzer does not look the language up for
+// every file. The analyzers of several languages look theirs up in byLanguage
+// instead.
+func (f *Factory) setLanguageThresholds(languages map[string]models.ComplexityThresholds) {
+	if len(languages) == 0 {
+		return
+	}
+	f.byLanguage = make(map[string]models.ComplexityThresholds, len(languages))
+	for name, thresholds := range languages {
+		f.byLanguage[strings.ToLower(name)] = thresholds
+	}
+	f.languageThresholds = map[string]models.ComplexityThresholds{}
+	for _, spec := range f.specs {
+		analyzer := spec.newAnalyzer(f.thresholds)
+		if _, multi := analyzer.(languageThresholder); multi {
+			continue
+		}
+		thresholds, ok := f.byLanguage[strings.ToLower(analyzer.Language())]
+		if !ok {
+			continue
+		}
+		for _, ext := range spec.extensions {
+			f.languageThresholds[ext] = thresholds
+		}
+	}
+}
+
+// DefaultLanguageThresholds returns the thresholds of every language that
+// NewLanguageFactory accepts, Languages' names included. They all start as
+// DefaultComplexityThresholds; a project overrides the ones whose idioms score
+// differently.
+func DefaultLanguageThresholds() map[string]models.ComplexityThresholds {
+	thresholds := map[string]models.ComplexityThresholds{}
+	for _, spec := range languageSpecs {
+		for _, language := range AnalyzerLanguages(spec.newAnalyzer(models.DefaultComplexityThresholds())) {
+			thresholds[language] = models.DefaultComplexityThresholds()
+		}
+	}
+	return thresholds
+}
+
+// ValidateLanguages reports whether the built-in analyzer table is
+// consistent, so callers can fail with an error at startup rather than
+// panicking in the first NewFactory.
+func ValidateLanguages() error {
+	return newFactory(models.DefaultComplexityThresholds(), languageSpecs).Validate()
+}
+
+// Validate reports every inconsistency in the factory's analyzer table: an
+// analyzer without extensions can never run, an extension registered twice
+// silently goes to whichever analyzer comes first, and an extension that is
+// not lower case with a leading dot never matches a file.
+func (f *Factory) Validate() error {
+	var problems []string
+	owners := map[string]string{}
+	for _, spec := range f.specs {
+		language := spec.newAnalyzer(f.thresholds).Language()
+		if len(spec.extensions) == 0 {
+			problems = append(problems, fmt.Sprintf("%s analyzer has no extensions", language))
+		}
+		for _, ext := range spec.extensions {
+			if !strings.HasPrefix(ext, ".") || ext != strings.ToLower(ext) || strings.ContainsAny(ext[1:], "./\\") {
+				problems = append(problems, fmt.Sprintf("%s analyzer has malformed extension %q", language, ext))
+				continue
+			}
+			if owner, taken := owners[ext]; taken {
+				problems = append(problems, fmt.Sprintf("extension %s registered by both %s and %s", ext, owner, language))
+				continue
+			}
+			owners[ext] = language
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid analyzer table: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 // GetAnalyzer returns the appropriate analyzer for the given file path
 func (f *Factory) GetAnalyzer(filePath string) (Analyzer, error) {
 	ext := strings.ToLower(filepath.Ext(filePath))
 
-	switch ext {
-	case ".go":
-		return NewGoAnalyzer(f.thresholds), nil
-	case ".js", ".jsx":
-		return NewJavaScriptAnalyzer(f.thresholds), nil
-	case ".ts", ".tsx":
-		return NewTypeScriptAnalyzer(f.thresholds), nil
-	case ".py":
-		return NewPythonAnalyzer(f.thresholds), nil
-	case ".cs":
-		return NewCSharpAnalyzer(f.thresholds), nil
-	case ".php":
-		return NewPHPAnalyzer(f.thresholds), nil
-	case ".java":
-		return NewJavaAnalyzer(f.thresholds), nil
-	case ".rb":
-		return NewRubyAnalyzer(f.thresholds), nil
-	case ".rs":
-		return NewRustAnalyzer(f.thresholds), nil
-	case ".kt", ".kts":
-		return NewKotlinAnalyzer(f.thresholds), nil
-	case ".swift":
-		return NewSwiftAnalyzer(f.thresholds), nil
-	case ".c", ".cpp", ".cc", ".cxx", ".c++", ".h", ".hpp", ".hxx", ".h++":
-		return NewCCppAnalyzer(f.thresholds), nil
-	default:
+	spec, ok := f.byExtension[ext]
+	if !ok {
 		return nil, fmt.Errorf("unsupported file extension: %s", ext)
 	}
+	thresholds, ok := f.languageThresholds[ext]
+	if !ok {
+		thresholds = f.thresholds
+	}
+	analyzer := spec.newAnalyzer(thresholds)
+	if multi, ok := analyzer.(languageThresholder); ok && len(f.byLanguage) > 0 {
+		return multi.withLanguageThresholds(func(language string) (models.ComplexityThresholds, bool) {
+			thresholds, ok := f.byLanguage[strings.ToLower(language)]
+			return thresholds, ok
+		}), nil
+	}
+	return analyzer, nil
 }
 
 // IsSupported returns true if the file extension is supported for complexity analysis
 func (f *Factory) IsSupported(filePath string) bool {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	supportedExts := []string{
-		".go", ".js", ".jsx", ".ts", ".tsx", ".py", ".java", ".cs", ".php",
-		".rb", ".rs", ".kt", ".kts", ".swift",
-		".c", ".cpp", ".cc", ".cxx", ".c++", ".h", ".hpp", ".hxx", ".h++",
-	}
+	_, ok := f.byExtension[strings.ToLower(filepath.Ext(filePath))]
+	return ok
+}
 
-	for _, supported := range supportedExts {
-		if ext == supported {
-			return true
+// LanguageSupport names a language and the file extensions analyzed as it.
+type LanguageSupport struct {
+	Language   string   `json:"language"`
+	Extensions []string `json:"extensions"`
+}
+
+// Languages reports the languages the factory analyzes, as named by the
+// analyzers themselves. Analyzers reporting the same language (Python source
+// and notebooks) are merged into one entry, and the extensions of an analyzer
+// that dispatches to several languages (.m) are listed under each of them.
+func (f *Factory) Languages() []LanguageSupport {
+	var languages []LanguageSupport
+	index := map[string]int{}
+	for _, spec := range f.specs {
+		for _, name := range AnalyzerLanguages(spec.newAnalyzer(f.thresholds)) {
+			i, ok := index[name]
+			if !ok {
+				i = len(languages)
+				index[name] = i
+				languages = append(languages, LanguageSupport{Language: name})
+			}
+			languages[i].Extensions = append(languages[i].Extensions, spec.extensions...)
 		}
 	}
-	return false
+	return languages
+}
+
+// AnalyzerLanguages returns the languages an analyzer reports metrics in:
+// its Languages() when it dispatches to several, otherwise its Language().
+func AnalyzerLanguages(a Analyzer) []string {
+	if multi, ok := a.(interface{ Languages() []string }); ok {
+		return multi.Languages()
+	}
+	return []string{a.Language()}
 }
@@ -32,7 +32,7 @@ func (a *GoAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Comp
 
 	file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Go file: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse Go file: %v", ErrParseFailed, err)
 	}
 
 	metrics := []models.ComplexityMetric{}
@@ -88,6 +88,7 @@ func (a *GoAnalyzer) analyzeFunction(
 	paramCount := countParameters(funcType)
 	loc := endPos.Line - startPos.Line + 1
 
+	returns, guards := countGoReturns(body)
 	codeSnippet := extractCodeSnippet(fset, node, content)
 
 	debtMinutes := models.CalculateTechnicalDebt(
@@ -112,6 +113,11 @@ func (a *GoAnalyzer) analyzeFunction(
 		paramCount,
 		loc,
 	)
+	suggestions = append(suggestions, models.GenerateSignatureSuggestions(
+		boolParameters(funcType),
+		countResults(funcType),
+		cyclomaticComplexity,
+	)...)
 
 	metric := &models.ComplexityMetric{
 		FilePath:               filePath,
@@ -130,6 +136,8 @@ func (a *GoAnalyzer) analyzeFunction(
 		CodeSnippet:            &codeSnippet,
 		RefactoringSuggestions: suggestions,
 		Language:               "Go",
+		ReturnCount:            returns,
+		GuardReturnCount:       guards,
 	}
 
 	return metric
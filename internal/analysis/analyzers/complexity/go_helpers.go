@@ -67,6 +67,79 @@ func countParameters(funcType *ast.FuncType) int {
 	return count
 }
 
+// boolParameters returns the names of the parameters declared as plain bool.
+// Unnamed bool parameters are reported as "bool".
+func boolParameters(funcType *ast.FuncType) []string {
+	if funcType.Params == nil {
+		return nil
+	}
+
+	var names []string
+	for _, field := range funcType.Params.List {
+		if ident, ok := field.Type.(*ast.Ident); !ok || ident.Name != "bool" {
+			continue
+		}
+		if len(field.Names) == 0 {
+			names = append(names, "bool")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// countResults returns the number of values the function returns.
+func countResults(funcType *ast.FuncType) int {
+	if funcType.Results == nil {
+		return 0
+	}
+
+	count := 0
+	for _, field := range funcType.Results.List {
+		count += len(field.Names)
+		if len(field.Names) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// countGoReturns counts the return statements of a function body, not those
+// of the function literals inside it, and how many of them are guard
+// clauses: the returns of the if statements without an else that open the
+// body, before any other statement.
+func countGoReturns(body *ast.BlockStmt) (returns, guards int) {
+	returns = countGoReturnStatements(body)
+
+	for _, statement := range body.List {
+		ifStmt, ok := statement.(*ast.IfStmt)
+		if !ok || ifStmt.Else != nil {
+			break
+		}
+		n := countGoReturnStatements(ifStmt)
+		if n == 0 {
+			break
+		}
+		guards += n
+	}
+	return returns, guards
+}
+
+func countGoReturnStatements(node ast.Node) int {
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 // extractReceiverType extracts the receiver type name
 func extractReceiverType(expr ast.Expr) string {
 	switch t := expr.(type) {
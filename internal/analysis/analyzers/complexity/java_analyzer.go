@@ -27,8 +27,8 @@ func (a *JavaAnalyzer) Language() string {
 func (a *JavaAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(java.GetLanguage())
+	parser := javaParsers.get()
+	defer javaParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -39,6 +39,10 @@ func (a *JavaAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 	functions, err := findJavaFunctions(root, content)
 	if err != nil {
@@ -48,8 +52,12 @@ func (a *JavaAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	for _, fn := range functions {
 		nodes := mapJavaNodes(fn.node, content)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
+		loc := locFromNode(fn.bodyNode)
+
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		returns, guards := countReturns(fn.bodyNode)
 
 		cognitivePtr := cognitive
 		snippetStr := truncateSnippet(fn.body, 10000)
@@ -64,10 +72,15 @@ func (a *JavaAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 			CognitiveComplexity:  &cognitivePtr,
 			NestingDepth:         nesting,
 			ParameterCount:       fn.paramCount,
+			LinesOfCode:          loc,
 			Severity:             severity,
+			TechnicalDebtMinutes: debtMinutes,
 			CodeSnippet:          &snippetStr,
+			ReturnCount:          returns,
+			GuardReturnCount:     guards,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -81,6 +94,7 @@ type javaFunctionInfo struct {
 	body       string
 	paramCount int
 	node       *sitter.Node
+	bodyNode   *sitter.Node
 }
 
 func findJavaFunctions(root *sitter.Node, content []byte) ([]javaFunctionInfo, error) {
@@ -155,6 +169,7 @@ func findJavaFunctions(root *sitter.Node, content []byte) ([]javaFunctionInfo, e
 				body:       fnBodyNode.Content(content),
 				paramCount: paramCount,
 				node:       fnNode,
+				bodyNode:   fnBodyNode,
 			})
 		}
 	}
@@ -2,7 +2,6 @@ package complexity
 
 import (
 	sitter "github.com/smacker/go-tree-sitter"
-	"github.com/smacker/go-tree-sitter/javascript"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/google/uuid"
@@ -25,8 +24,8 @@ func (a *JavaScriptAnalyzer) Language() string {
 func (a *JavaScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(javascript.GetLanguage())
+	parser := javascriptParsers.get()
+	defer javascriptParsers.put(parser)
 
 	tree := parser.Parse(nil, content)
 	if tree == nil {
@@ -34,6 +33,10 @@ func (a *JavaScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions := findJavaScriptFunctions(root, content)
@@ -41,8 +44,12 @@ func (a *JavaScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 	for _, fn := range functions {
 		nodes := mapJavaScriptNodes(fn.Node, content)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
+		loc := locFromNode(fn.Node)
+
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		returns, guards := countReturns(fn.Node.ChildByFieldName("body"))
 
 		cognitivePtr := cognitive
 		snippetStr := truncateSnippet(fn.body, 10000)
@@ -57,10 +64,15 @@ func (a *JavaScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 			CognitiveComplexity:  &cognitivePtr,
 			NestingDepth:         nesting,
 			ParameterCount:       fn.paramCount,
+			LinesOfCode:          loc,
 			Severity:             severity,
+			TechnicalDebtMinutes: debtMinutes,
 			CodeSnippet:          &snippetStr,
+			ReturnCount:          returns,
+			GuardReturnCount:     guards,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
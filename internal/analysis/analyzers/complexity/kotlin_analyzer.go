@@ -27,8 +27,8 @@ func (a *KotlinAnalyzer) Language() string {
 func (a *KotlinAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(kotlin.GetLanguage())
+	parser := kotlinParsers.get()
+	defer kotlinParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -39,6 +39,10 @@ func (a *KotlinAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions, err := findKotlinFunctions(root, content)
@@ -51,7 +55,7 @@ func (a *KotlinAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 		debtMinutes := estimateKotlinTechnicalDebt(cyclomatic, cognitive, loc)
 		suggestions := generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, fn.paramCount, loc)
 
@@ -75,6 +79,7 @@ func (a *KotlinAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 			CodeSnippet:            &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -123,11 +128,15 @@ func findKotlinFunctions(root *sitter.Node, content []byte) ([]kotlinFunctionInf
 		var fnBodyNode *sitter.Node
 		var fnNode *sitter.Node
 		var paramCount int
+		declaration := false
 
 		for _, c := range m.Captures {
 			captureName := q.CaptureNameForId(c.Index)
 			switch captureName {
-			case "function", "lambda":
+			case "function":
+				fnNode = c.Node
+				declaration = true
+			case "lambda":
 				fnNode = c.Node
 			case "name":
 				fnName = c.Node.Content(content)
@@ -136,6 +145,12 @@ func findKotlinFunctions(root *sitter.Node, content []byte) ([]kotlinFunctionInf
 			}
 		}
 
+		// Abstract and interface members declare a function without
+		// defining one, so there is nothing to measure.
+		if declaration && fnBodyNode == nil {
+			continue
+		}
+
 		if fnNode != nil {
 			if fnName == "" {
 				fnName = "<lambda>"
@@ -264,6 +279,7 @@ func generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, paramC
 
 	if cyclomatic > 15 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "extract_method",
 			Priority:    "high",
 			Title:       "Extract Functions",
 			Description: "Break down this function into smaller, focused functions. Consider using extension functions or sealed classes",
@@ -272,6 +288,7 @@ func generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, paramC
 
 	if nesting > 3 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "reduce_nesting",
 			Priority:    "high",
 			Title:       "Reduce Nesting Depth",
 			Description: "Use Kotlin's safe call operators (?.), let, also, or early returns to reduce nesting",
@@ -280,6 +297,7 @@ func generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, paramC
 
 	if paramCount > 4 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "introduce_parameter_object",
 			Priority:    "medium",
 			Title:       "Use Data Class or Builder Pattern",
 			Description: "Too many parameters. Consider using a data class with named parameters or default values",
@@ -288,6 +306,7 @@ func generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, paramC
 
 	if loc > 50 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "split_function",
 			Priority:    "high",
 			Title:       "Function Too Long",
 			Description: "Split this function into smaller functions. Consider using extension functions or separating concerns",
@@ -296,6 +315,7 @@ func generateKotlinRefactoringSuggestions(cyclomatic, cognitive, nesting, paramC
 
 	if cognitive > 20 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "simplify_logic",
 			Priority:    "medium",
 			Title:       "Simplify Logic",
 			Description: "Use Kotlin's scope functions (let, run, apply), when expressions, or sealed classes to simplify logic",
@@ -0,0 +1,188 @@
+package complexity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzers_SetLanguage(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+
+	fixtures := map[string]string{
+		"main.go":   "package main\n\nfunc f() {\n}\n",
+		"app.js":    "function f() {\n  return 1;\n}\n",
+		"app.ts":    "function f(): number {\n  return 1;\n}\n",
+		"app.py":    "def f():\n    return 1\n",
+		"App.java":  "class App {\n  void f() {\n  }\n}\n",
+		"App.cs":    "class App {\n  void F() {\n  }\n}\n",
+		"app.php":   "<?php\nfunction f() {\n  return 1;\n}\n",
+		"app.rb":    "def f\n  1\nend\n",
+		"main.rs":   "fn f() {\n}\n",
+		"App.kt":    "fun f() {\n}\n",
+		"App.swift": "func f() {\n}\n",
+		"View.m":    "@implementation View\n- (void)f {\n}\n@end\n",
+		"main.cpp":  "int f() {\n  return 1;\n}\n",
+		"stats.R":   "f <- function(x) {\n  x\n}\n",
+	}
+
+	for path, code := range fixtures {
+		t.Run(path, func(t *testing.T) {
+			analyzer, err := factory.GetAnalyzer(path)
+			require.NoError(t, err)
+
+			metrics, err := analyzer.AnalyzeFile(path, []byte(code))
+			require.NoError(t, err)
+			require.NotEmpty(t, metrics, "expected at least one function")
+
+			for _, m := range metrics {
+				assert.NotEmpty(t, m.Language, "Language for %s", m.FunctionName)
+				assert.Equal(t, analyzer.Language(), m.Language)
+			}
+		})
+	}
+}
+
+func TestFactory_Languages(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+
+	languages := factory.Languages()
+	require.NotEmpty(t, languages)
+
+	seen := map[string]bool{}
+	for _, language := range languages {
+		assert.False(t, seen[language.Language], "%s listed twice", language.Language)
+		seen[language.Language] = true
+		require.NotEmpty(t, language.Extensions, language.Language)
+		for _, ext := range language.Extensions {
+			analyzer, err := factory.GetAnalyzer("file" + ext)
+			require.NoError(t, err)
+			assert.Contains(t, AnalyzerLanguages(analyzer), language.Language, ext)
+		}
+	}
+	assert.Contains(t, languages, LanguageSupport{Language: "Python", Extensions: []string{".py", ".ipynb"}})
+	assert.Contains(t, languages, LanguageSupport{Language: "Objective-C", Extensions: []string{".m", ".mm"}})
+	assert.Contains(t, languages, LanguageSupport{Language: "MATLAB", Extensions: []string{".m"}})
+	assert.Contains(t, languages, LanguageSupport{Language: "R", Extensions: []string{".r"}})
+}
+
+func TestFactory_Validate(t *testing.T) {
+	require.NoError(t, ValidateLanguages())
+
+	// Every registered extension dispatches to the analyzer it is listed with.
+	factory := NewFactory(models.DefaultComplexityThresholds())
+	for _, spec := range languageSpecs {
+		want := spec.newAnalyzer(models.DefaultComplexityThresholds()).Language()
+		for _, ext := range spec.extensions {
+			analyzer, err := factory.GetAnalyzer("File" + strings.ToUpper(ext))
+			require.NoError(t, err, ext)
+			assert.IsType(t, spec.newAnalyzer(models.DefaultComplexityThresholds()), analyzer, ext)
+			assert.Equal(t, want, analyzer.Language(), ext)
+			assert.True(t, factory.IsSupported("file"+ext), ext)
+		}
+	}
+}
+
+func TestFactory_ValidateMisconfigured(t *testing.T) {
+	ruby := func(th models.ComplexityThresholds) Analyzer { return NewRubyAnalyzer(th) }
+	rust := func(th models.ComplexityThresholds) Analyzer { return NewRustAnalyzer(th) }
+
+	factory := newFactory(models.DefaultComplexityThresholds(), []languageSpec{
+		{[]string{".rb", "rs"}, ruby},
+		{[]string{".rb"}, rust},
+		{nil, rust},
+	})
+	err := factory.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Ruby analyzer has malformed extension "rs"`)
+	assert.Contains(t, err.Error(), "extension .rb registered by both Ruby and Rust")
+	assert.Contains(t, err.Error(), "Rust analyzer has no extensions")
+
+	// Until fixed, the first registration wins.
+	analyzer, err := factory.GetAnalyzer("app.rb")
+	require.NoError(t, err)
+	assert.Equal(t, "Ruby", analyzer.Language())
+}
+
+func TestFactory_LanguageThresholds(t *testing.T) {
+	var rust, golang strings.Builder
+	rust.WriteString("fn classify(code: u32) -> &'static str {\n    match code {\n")
+	golang.WriteString("package main\n\nfunc classify(code int) string {\n\tswitch code {\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&rust, "        %d => \"code %d\",\n", i, i)
+		fmt.Fprintf(&golang, "\tcase %d:\n\t\treturn \"code %d\"\n", i, i)
+	}
+	rust.WriteString("        _ => \"other\",\n    }\n}\n")
+	golang.WriteString("\t}\n\treturn \"other\"\n}\n")
+
+	severity := func(t *testing.T, factory *Factory, path, code string) string {
+		t.Helper()
+		analyzer, err := factory.GetAnalyzer(path)
+		require.NoError(t, err)
+		metrics, err := analyzer.AnalyzeFile(path, []byte(code))
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		return metrics[0].Severity
+	}
+
+	defaults := NewFactory(models.DefaultComplexityThresholds())
+	require.Equal(t, "high", severity(t, defaults, "main.rs", rust.String()))
+	require.Equal(t, "high", severity(t, defaults, "main.go", golang.String()))
+
+	rustThresholds := models.DefaultComplexityThresholds()
+	rustThresholds.CyclomaticHigh = 15
+	rustThresholds.CyclomaticCritical = 30
+	rustThresholds.CognitiveHigh = 20
+	rustThresholds.CognitiveCritical = 40
+	tuned := NewLanguageFactory(models.DefaultComplexityThresholds(), map[string]models.ComplexityThresholds{"rust": rustThresholds})
+	assert.Equal(t, "medium", severity(t, tuned, "main.rs", rust.String()), "Rust thresholds should accept a match-heavy function")
+	assert.Equal(t, "high", severity(t, tuned, "main.go", golang.String()), "Go should keep the default thresholds")
+
+	assert.Equal(t, models.DefaultComplexityThresholds(), DefaultLanguageThresholds()["Rust"])
+	assert.Contains(t, DefaultLanguageThresholds(), "MATLAB")
+	assert.Contains(t, DefaultLanguageThresholds(), "Objective-C")
+}
+
+func TestFactory_MFileLanguageThresholds(t *testing.T) {
+	var matlab, objc strings.Builder
+	matlab.WriteString("function r = classify(code)\n")
+	objc.WriteString("#import <Foundation/Foundation.h>\n\nint classify(int code) {\n")
+	for i := 0; i < 22; i++ {
+		fmt.Fprintf(&matlab, "    if code == %d\n        r = %d;\n    end\n", i, i)
+		fmt.Fprintf(&objc, "    if (code == %d) return %d;\n", i, i)
+	}
+	matlab.WriteString("end\n")
+	objc.WriteString("    return -1;\n}\n")
+
+	severity := func(t *testing.T, factory *Factory, code string) string {
+		t.Helper()
+		analyzer, err := factory.GetAnalyzer("classify.m")
+		require.NoError(t, err)
+		metrics, err := analyzer.AnalyzeFile("classify.m", []byte(code))
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		return metrics[0].Severity
+	}
+
+	lenient := models.DefaultComplexityThresholds()
+	lenient.CyclomaticHigh = 30
+	lenient.CyclomaticCritical = 60
+	lenient.CognitiveHigh = 30
+	lenient.CognitiveCritical = 60
+
+	defaults := NewFactory(models.DefaultComplexityThresholds())
+	require.Equal(t, "critical", severity(t, defaults, matlab.String()))
+	require.Equal(t, "critical", severity(t, defaults, objc.String()))
+
+	tuned := NewLanguageFactory(models.DefaultComplexityThresholds(), map[string]models.ComplexityThresholds{"matlab": lenient})
+	assert.NotEqual(t, "critical", severity(t, tuned, matlab.String()), "MATLAB thresholds should apply to MATLAB .m files")
+	assert.Equal(t, "critical", severity(t, tuned, objc.String()), "Objective-C should keep the default thresholds")
+
+	tuned = NewLanguageFactory(models.DefaultComplexityThresholds(), map[string]models.ComplexityThresholds{"Objective-C": lenient})
+	assert.Equal(t, "critical", severity(t, tuned, matlab.String()), "MATLAB should keep the default thresholds")
+	assert.NotEqual(t, "critical", severity(t, tuned, objc.String()), "Objective-C thresholds should apply to Objective-C .m files")
+}
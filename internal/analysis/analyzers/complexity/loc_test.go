@@ -0,0 +1,87 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fileAnalyzer interface {
+	AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error)
+}
+
+func TestAnalyzers_LinesOfCode(t *testing.T) {
+	thresholds := models.DefaultComplexityThresholds()
+
+	tests := []struct {
+		name     string
+		analyzer fileAnalyzer
+		file     string
+		code     string
+		function string
+		wantLOC  int
+	}{
+		{
+			name:     "JavaScript",
+			analyzer: NewJavaScriptAnalyzer(thresholds),
+			file:     "test.js",
+			code: `function total(items) {
+  let sum = 0;
+  for (const item of items) {
+    sum += item;
+  }
+  return sum;
+}
+`,
+			function: "total",
+			wantLOC:  7,
+		},
+		{
+			name:     "Java",
+			analyzer: NewJavaAnalyzer(thresholds),
+			file:     "Test.java",
+			code: `public class Test {
+    public int total(int[] items) {
+        int sum = 0;
+        for (int item : items) {
+            sum += item;
+        }
+        return sum;
+    }
+}
+`,
+			function: "total",
+			wantLOC:  7,
+		},
+		{
+			name:     "Python",
+			analyzer: NewPythonAnalyzer(thresholds),
+			file:     "test.py",
+			code: `def total(items):
+    total = 0
+    for item in items:
+        total += item
+    return total
+`,
+			function: "total",
+			wantLOC:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := tt.analyzer.AnalyzeFile(tt.file, []byte(tt.code))
+			assert.NoError(t, err)
+
+			var found bool
+			for _, m := range metrics {
+				if m.FunctionName == tt.function {
+					found = true
+					assert.Equal(t, tt.wantLOC, m.LinesOfCode, "LinesOfCode for %s", m.FunctionName)
+				}
+			}
+			assert.True(t, found, "Function %s not found", tt.function)
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package complexity
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// MATLABAnalyzer measures MATLAB (and Octave) .m files.
+//
+// There is no MATLAB grammar available, so the source is scanned: comments
+// and string literals are blanked out, then keywords are matched outside of
+// brackets (where `end` is an index) to track blocks. Every function is
+// measured, including nested and local functions, and the control flow of a
+// nested function counts only towards that function. Files whose functions
+// are not closed by `end` are supported too. Top-level script code is not
+// measured, like module-level code in the other languages.
+type MATLABAnalyzer struct {
+	thresholds models.ComplexityThresholds
+}
+
+func NewMATLABAnalyzer(thresholds models.ComplexityThresholds) *MATLABAnalyzer {
+	return &MATLABAnalyzer{
+		thresholds: thresholds,
+	}
+}
+
+func (a *MATLABAnalyzer) Language() string {
+	return "MATLAB"
+}
+
+func (a *MATLABAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
+	src := string(content)
+	sanitized := sanitizeMATLAB(src)
+
+	functions, ok := scanMATLABFunctions(src, sanitized, true)
+	if !ok {
+		// Functions are not closed by `end`: each one runs up to the next.
+		functions, _ = scanMATLABFunctions(src, sanitized, false)
+	}
+
+	var metrics []models.ComplexityMetric
+	for _, fn := range functions {
+		metrics = append(metrics, buildScannedMetric(a.Language(), filePath, fn, a.thresholds))
+	}
+	return metrics, nil
+}
+
+// matlabSignature matches a function declaration such as
+// `function [a, b] = name(x, y)`, `function out = get.Prop(obj)` or
+// `function name`.
+var matlabSignature = regexp.MustCompile(`^function\s+(?:(?:\[[^\]]*\]|[\w~]+)\s*=\s*)?([A-Za-z_][\w.]*)\s*(?:\(([^)]*)\))?`)
+
+// matlabEndKeywords close a block. Octave's specific forms are accepted too.
+var matlabEndKeywords = map[string]bool{
+	"end": true, "endfunction": true, "endif": true, "endfor": true, "endparfor": true,
+	"endwhile": true, "endswitch": true, "end_try_catch": true, "endclassdef": true,
+	"endmethods": true, "endproperties": true, "endevents": true, "endenumeration": true,
+}
+
+type matlabBlockKind int
+
+const (
+	matlabFunction matlabBlockKind = iota
+	matlabControl                  // if, for, parfor, while, switch: adds nesting
+	matlabPlain                    // try, spmd, classdef and its sections
+	matlabClassdef
+)
+
+type matlabBlock struct {
+	kind matlabBlockKind
+	fn   int // index in frames for matlabFunction
+}
+
+type matlabFrame struct {
+	fn    scannedFunction
+	depth int // stack height when the function was opened
+	open  bool
+}
+
+// scanMATLABFunctions scans the sanitized source once. With terminated set,
+// functions are expected to be closed by `end` and ok is false when some are
+// left open at the end of the file; otherwise a function ends where the next
+// one starts.
+func scanMATLABFunctions(src, sanitized string, terminated bool) (functions []scannedFunction, ok bool) {
+	var stack []matlabBlock
+	var frames []*matlabFrame
+
+	line := 1
+	brackets := 0
+	statementStart := true
+
+	// current returns the innermost open function and the nesting depth of
+	// the control blocks above it.
+	current := func() (*matlabFrame, int) {
+		for i := len(frames) - 1; i >= 0; i-- {
+			if frames[i].open {
+				depth := 0
+				for _, b := range stack[frames[i].depth:] {
+					if b.kind == matlabControl {
+						depth++
+					}
+				}
+				return frames[i], depth
+			}
+		}
+		return nil, 0
+	}
+	emit := func(t ComplexityNodeType, offset int) {
+		if frame, depth := current(); frame != nil {
+			frame.fn.nodes = append(frame.fn.nodes, Node{Type: t, Depth: depth + offset})
+		}
+	}
+	closeFrame := func(frame *matlabFrame, endLine int) {
+		frame.open = false
+		frame.fn.endLine = lastCodeLine(sanitized, frame.fn.line, endLine)
+		frame.fn.body = sourceLines(src, frame.fn.line, frame.fn.endLine)
+	}
+
+	for i := 0; i < len(sanitized); {
+		c := sanitized[i]
+		switch {
+		case c == '\n':
+			line++
+			if brackets == 0 {
+				statementStart = true
+			}
+			i++
+			continue
+		case c == ';' || c == ',':
+			if brackets == 0 {
+				statementStart = true
+			}
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '(' || c == '[' || c == '{':
+			brackets++
+		case c == ')' || c == ']' || c == '}':
+			if brackets > 0 {
+				brackets--
+			}
+		case (c == '&' || c == '|') && i+1 < len(sanitized) && sanitized[i+1] == c:
+			emit(Operator, 0)
+			i += 2
+			statementStart = false
+			continue
+		case isIdentStart(c):
+			j := i
+			for j < len(sanitized) && isIdentPart(sanitized[j]) {
+				j++
+			}
+			word := sanitized[i:j]
+			// Field names (s.end) and anything inside brackets (x(end)) are
+			// not keywords.
+			keyword := brackets == 0 && (i == 0 || sanitized[i-1] != '.')
+			wasStart := statementStart
+			statementStart = false
+			if keyword {
+				switch {
+				case word == "function":
+					if !terminated {
+						for _, frame := range frames {
+							if frame.open {
+								closeFrame(frame, line-1)
+							}
+						}
+						stack = stack[:0]
+					}
+					fn := scannedFunction{}
+					fn.line = line
+					fn.name = "<anonymous>"
+					signature := matlabSignatureAt(sanitized, i)
+					if m := matlabSignature.FindStringSubmatch(signature); m != nil {
+						fn.name = m[1]
+						fn.paramCount = countParameterString(m[2])
+					}
+					frames = append(frames, &matlabFrame{fn: fn, depth: len(stack) + 1, open: true})
+					stack = append(stack, matlabBlock{kind: matlabFunction, fn: len(frames) - 1})
+					// Skip the signature so parameter names are not scanned.
+					j = i + len(strings.TrimRight(signature, " \t"))
+					if !terminated {
+						// The function is not closed by `end`.
+						stack = stack[:len(stack)-1]
+						frames[len(frames)-1].depth = len(stack)
+					}
+				case word == "classdef":
+					stack = append(stack, matlabBlock{kind: matlabClassdef})
+				case (word == "methods" || word == "properties" || word == "events" || word == "enumeration") &&
+					wasStart && len(stack) > 0 && stack[len(stack)-1].kind == matlabClassdef:
+					stack = append(stack, matlabBlock{kind: matlabPlain})
+				case word == "if":
+					emit(Branch, 0)
+					stack = append(stack, matlabBlock{kind: matlabControl})
+				case word == "elseif":
+					// Same level as the if it continues.
+					emit(Branch, -1)
+				case word == "for" || word == "parfor" || word == "while":
+					emit(Loop, 0)
+					stack = append(stack, matlabBlock{kind: matlabControl})
+				case word == "switch":
+					emit(Nesting, 0)
+					stack = append(stack, matlabBlock{kind: matlabControl})
+				case word == "case":
+					emit(Branch, 0)
+				case word == "catch":
+					emit(Branch, 0)
+				case word == "try" || word == "spmd":
+					stack = append(stack, matlabBlock{kind: matlabPlain})
+				case matlabEndKeywords[word]:
+					if len(stack) > 0 {
+						top := stack[len(stack)-1]
+						stack = stack[:len(stack)-1]
+						if top.kind == matlabFunction {
+							closeFrame(frames[top.fn], line)
+						}
+					}
+				}
+			}
+			i = j
+			continue
+		}
+		statementStart = false
+		i++
+	}
+
+	ok = true
+	for _, frame := range frames {
+		if frame.open {
+			if terminated {
+				ok = false
+			}
+			closeFrame(frame, line)
+		}
+	}
+	for _, frame := range frames {
+		functions = append(functions, frame.fn)
+	}
+	return functions, ok
+}
+
+// matlabSignatureAt returns the function declaration starting at offset,
+// which may continue over several lines inside its parentheses.
+func matlabSignatureAt(sanitized string, offset int) string {
+	depth := 0
+	for i := offset; i < len(sanitized); i++ {
+		switch sanitized[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '\n', ';', ',':
+			if depth <= 0 {
+				return strings.ReplaceAll(sanitized[offset:i], "\n", " ")
+			}
+		}
+	}
+	return strings.ReplaceAll(sanitized[offset:], "\n", " ")
+}
+
+// lastCodeLine returns the last line in [from, to] of sanitized that holds
+// code, so a function ending before trailing comments or blank lines does
+// not claim them.
+func lastCodeLine(sanitized string, from, to int) int {
+	lines := strings.Split(sanitized, "\n")
+	for l := to; l > from; l-- {
+		if l <= len(lines) && strings.TrimSpace(lines[l-1]) != "" {
+			return l
+		}
+	}
+	return from
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// sanitizeMATLAB blanks out comments (% or #, %{ ... %} blocks and the text after
+// a `...` continuation) and the contents of string literals, keeping every
+// newline so offsets map to the same lines. A quote directly after a value
+// (x', a.', x(1)') is the transpose operator, not a string.
+func sanitizeMATLAB(src string) string {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for k := from; k < to && k < len(out); k++ {
+			if out[k] != '\n' {
+				out[k] = ' '
+			}
+		}
+	}
+
+	lines := strings.SplitAfter(src, "\n")
+	offset := 0
+	blockComment := 0
+	for _, line := range lines {
+		start := offset
+		offset += len(line)
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "%{" || trimmed == "#{" {
+			blockComment++
+			blank(start, offset)
+			continue
+		}
+		if blockComment > 0 {
+			if trimmed == "%}" || trimmed == "#}" {
+				blockComment--
+			}
+			blank(start, offset)
+			continue
+		}
+
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			switch {
+			case c == '%' || c == '#':
+				// # starts a comment in Octave and is not valid MATLAB.
+				blank(start+i, offset)
+				i = len(line)
+			case c == '.' && strings.HasPrefix(line[i:], "..."):
+				blank(start+i, offset)
+				i = len(line)
+			case c == '"' || (c == '\'' && !matlabTransposes(line, i)):
+				// Doubled quotes escape themselves.
+				j := i + 1
+				for j < len(line) && line[j] != '\n' {
+					if line[j] == c {
+						if j+1 < len(line) && line[j+1] == c {
+							j += 2
+							continue
+						}
+						break
+					}
+					j++
+				}
+				blank(start+i+1, start+j)
+				i = j
+			}
+		}
+	}
+	return string(out)
+}
+
+// matlabTransposes reports whether the quote at line[i] follows a value and
+// so is the transpose operator.
+func matlabTransposes(line string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev := line[i-1]
+	return isIdentPart(prev) || prev == ')' || prev == ']' || prev == '}' || prev == '.' || prev == '\''
+}
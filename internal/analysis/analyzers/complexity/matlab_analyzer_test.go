@@ -0,0 +1,163 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMATLABAnalyzer_AnalyzeFile(t *testing.T) {
+	analyzer := NewMATLABAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `function [total, count] = summarize(data, limit)
+% SUMMARIZE adds the values of data below limit.
+%{
+if this were code it would count
+%}
+total = 0;
+count = 0;
+for k = 1:numel(data)
+    if data(k) > 0 && data(k) < limit
+        total = total + data(k);
+    elseif data(end) == 0 || isempty(data)
+        count = count + 1; % if in a comment
+    end
+end
+msg = 'while in a string';
+x = data';
+    function out = scale(v)
+        switch v
+            case 1
+                out = v * 2;
+            case {2, 3}
+                out = v;
+            otherwise
+                out = 0;
+        end
+    end
+total = scale(total);
+end
+
+function r = helper(x)
+try
+    r = x.end + 1;
+catch err
+    r = 0;
+end
+end
+`
+
+	metrics, err := analyzer.AnalyzeFile("summarize.m", []byte(code))
+	require.NoError(t, err)
+
+	byName := map[string]models.ComplexityMetric{}
+	for _, m := range metrics {
+		byName[m.FunctionName] = m
+		assert.Equal(t, "MATLAB", m.Language)
+	}
+	require.Len(t, byName, 3, "found: %v", byName)
+
+	summarize := byName["summarize"]
+	assert.Equal(t, 6, summarize.CyclomaticComplexity, "for + if + && + elseif + ||; the nested function is measured on its own")
+	assert.Equal(t, 2, summarize.ParameterCount)
+	assert.Equal(t, 1, summarize.StartLine)
+	assert.Equal(t, 28, summarize.EndLine)
+	assert.Equal(t, 2, summarize.NestingDepth)
+
+	scale := byName["scale"]
+	assert.Equal(t, 3, scale.CyclomaticComplexity, "two cases")
+	assert.Equal(t, 17, scale.StartLine)
+	assert.Equal(t, 26, scale.EndLine)
+	assert.Equal(t, 1, scale.ParameterCount)
+
+	helper := byName["helper"]
+	assert.Equal(t, 2, helper.CyclomaticComplexity, "catch; s.end is a field")
+	assert.Equal(t, 30, helper.StartLine)
+	assert.Equal(t, 36, helper.EndLine)
+}
+
+func TestMATLABAnalyzer_UnterminatedFunctions(t *testing.T) {
+	analyzer := NewMATLABAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `function y = first(x)
+if x > 0
+    y = x;
+else
+    y = -x;
+end
+
+function second
+while true
+    break
+end
+`
+
+	metrics, err := analyzer.AnalyzeFile("first.m", []byte(code))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "first", metrics[0].FunctionName)
+	assert.Equal(t, 2, metrics[0].CyclomaticComplexity)
+	assert.Equal(t, 1, metrics[0].StartLine)
+	assert.Equal(t, 6, metrics[0].EndLine)
+
+	assert.Equal(t, "second", metrics[1].FunctionName)
+	assert.Equal(t, 2, metrics[1].CyclomaticComplexity)
+	assert.Equal(t, 0, metrics[1].ParameterCount)
+	assert.Equal(t, 8, metrics[1].StartLine)
+}
+
+func TestMATLABAnalyzer_Classdef(t *testing.T) {
+	analyzer := NewMATLABAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `classdef Account < handle
+    properties
+        Balance = 0
+    end
+    methods
+        function obj = deposit(obj, amount)
+            if amount <= 0
+                error('invalid amount');
+            end
+            obj.Balance = obj.Balance + amount;
+        end
+        function value = get.Balance(obj)
+            value = obj.Balance;
+        end
+    end
+end
+`
+
+	metrics, err := analyzer.AnalyzeFile("Account.m", []byte(code))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "deposit", metrics[0].FunctionName)
+	assert.Equal(t, 2, metrics[0].CyclomaticComplexity)
+	assert.Equal(t, 2, metrics[0].ParameterCount)
+	assert.Equal(t, 11, metrics[0].EndLine)
+	assert.Equal(t, "get.Balance", metrics[1].FunctionName)
+}
+
+func TestMFileAnalyzer_DispatchesByContent(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+	analyzer, err := factory.GetAnalyzer("model.m")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Objective-C", "MATLAB"}, AnalyzerLanguages(analyzer))
+
+	objc := "#import <Foundation/Foundation.h>\n\n@implementation View\n- (void)draw {\n  if (self.hidden) { return; }\n}\n@end\n"
+	metrics, err := analyzer.AnalyzeFile("View.m", []byte(objc))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "Objective-C", metrics[0].Language)
+	assert.Equal(t, "-[View draw]", metrics[0].FunctionName)
+
+	matlab := "% #import in a comment is still MATLAB\nfunction y = model(x)\nif x\n    y = 1;\nend\nend\n"
+	metrics, err = analyzer.AnalyzeFile("model.m", []byte(matlab))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "MATLAB", metrics[0].Language)
+	assert.Equal(t, "model", metrics[0].FunctionName)
+}
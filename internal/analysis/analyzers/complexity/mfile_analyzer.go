@@ -0,0 +1,64 @@
+package complexity
+
+import (
+	"regexp"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// objcMarkers matches preprocessor directives and @ keywords, which no
+// MATLAB file contains but virtually every Objective-C file does.
+var objcMarkers = regexp.MustCompile(`(?m)^[ \t]*(#(import|include|define|ifdef|ifndef|if|pragma)\b|@(interface|implementation|protocol|end|class|import)\b)`)
+
+// MFileAnalyzer handles the .m extension, which Objective-C and MATLAB share:
+// each file is sniffed and passed to the ObjCAnalyzer or the MATLABAnalyzer.
+// Its metrics carry the language of the analyzer that measured them.
+type MFileAnalyzer struct {
+	objc   *ObjCAnalyzer
+	matlab *MATLABAnalyzer
+}
+
+// NewMFileAnalyzer scores Objective-C files with objcThresholds and MATLAB
+// files with matlabThresholds.
+func NewMFileAnalyzer(objcThresholds, matlabThresholds models.ComplexityThresholds) *MFileAnalyzer {
+	return &MFileAnalyzer{
+		objc:   NewObjCAnalyzer(objcThresholds),
+		matlab: NewMATLABAnalyzer(matlabThresholds),
+	}
+}
+
+// withLanguageThresholds returns the analyzer with the thresholds thresholdsOf
+// reports for either language in place of its own.
+func (a *MFileAnalyzer) withLanguageThresholds(thresholdsOf func(language string) (models.ComplexityThresholds, bool)) Analyzer {
+	objcThresholds, matlabThresholds := a.objc.thresholds, a.matlab.thresholds
+	if t, ok := thresholdsOf(a.objc.Language()); ok {
+		objcThresholds = t
+	}
+	if t, ok := thresholdsOf(a.matlab.Language()); ok {
+		matlabThresholds = t
+	}
+	return NewMFileAnalyzer(objcThresholds, matlabThresholds)
+}
+
+// Language names the primary language of .m files; Languages lists both.
+func (a *MFileAnalyzer) Language() string {
+	return a.objc.Language()
+}
+
+// Languages returns every language an .m file may be analyzed as.
+func (a *MFileAnalyzer) Languages() []string {
+	return []string{a.objc.Language(), a.matlab.Language()}
+}
+
+func (a *MFileAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
+	if IsObjectiveC(content) {
+		return a.objc.AnalyzeFile(filePath, content)
+	}
+	return a.matlab.AnalyzeFile(filePath, content)
+}
+
+// IsObjectiveC reports whether the content of an .m file is Objective-C
+// rather than MATLAB.
+func IsObjectiveC(content []byte) bool {
+	return objcMarkers.Match(content)
+}
@@ -0,0 +1,135 @@
+package complexity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// ErrMalformedNotebook is returned by NotebookAnalyzer.AnalyzeFile when a
+// .ipynb file is not a notebook it can read. Unlike ErrParseFailed, which
+// covers broken code, callers warn about it since the whole file is skipped.
+var ErrMalformedNotebook = errors.New("malformed notebook")
+
+// NotebookAnalyzer analyzes the Python code cells of Jupyter notebooks with
+// the PythonAnalyzer. Each code cell is parsed on its own, so a syntax error
+// only costs the cell it is in; markdown and raw cells and cell outputs are
+// ignored. Metrics are reported against the notebook file with NotebookCell
+// set and lines counted from the start of the cell.
+type NotebookAnalyzer struct {
+	python *PythonAnalyzer
+}
+
+func NewNotebookAnalyzer(thresholds models.ComplexityThresholds) *NotebookAnalyzer {
+	return &NotebookAnalyzer{
+		python: NewPythonAnalyzer(thresholds),
+	}
+}
+
+func (a *NotebookAnalyzer) Language() string {
+	return a.python.Language()
+}
+
+// notebook is the subset of the nbformat 4 schema that is analyzed.
+type notebook struct {
+	NBFormat int            `json:"nbformat"`
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"`
+}
+
+func (a *NotebookAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedNotebook, err)
+	}
+	if nb.NBFormat < 4 {
+		return nil, fmt.Errorf("%w: unsupported nbformat %d (want 4 or later)", ErrMalformedNotebook, nb.NBFormat)
+	}
+
+	// Notebooks for other kernels (R, Julia, ...) have no Python to analyze.
+	language := nb.Metadata.LanguageInfo.Name
+	if language == "" {
+		language = nb.Metadata.KernelSpec.Language
+	}
+	if language != "" && !strings.EqualFold(language, "python") {
+		return nil, nil
+	}
+
+	var metrics []models.ComplexityMetric
+	for i, cell := range nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		source, err := notebookCellSource(cell.Source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cell %d: %v", ErrMalformedNotebook, i+1, err)
+		}
+		code, ok := pythonCellCode(source)
+		if !ok {
+			continue
+		}
+
+		cellMetrics, err := a.python.AnalyzeFile(filePath, []byte(code))
+		if err != nil {
+			continue
+		}
+		// Cells are numbered from 1 in notebook order, markdown included,
+		// so the number matches what a reader counts in the notebook UI.
+		for j := range cellMetrics {
+			cellNumber := i + 1
+			cellMetrics[j].NotebookCell = &cellNumber
+		}
+		metrics = append(metrics, cellMetrics...)
+	}
+	return metrics, nil
+}
+
+// notebookCellSource decodes a cell source, which nbformat allows to be a
+// single string or a list of lines.
+func notebookCellSource(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var source string
+	if err := json.Unmarshal(raw, &source); err == nil {
+		return source, nil
+	}
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return "", fmt.Errorf("source is neither a string nor a list of strings")
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// pythonCellCode turns a code cell into parseable Python. IPython line magics
+// (%time, %matplotlib) and shell escapes (!pip) become `pass` at the same
+// indentation so line numbers and blocks are preserved. Cells starting with a
+// cell magic (%%bash, %%sql) are not Python and report false.
+func pythonCellCode(source string) (string, bool) {
+	if strings.HasPrefix(strings.TrimSpace(source), "%%") {
+		return "", false
+	}
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "!") {
+			lines[i] = line[:len(line)-len(trimmed)] + "pass"
+		}
+	}
+	return strings.Join(lines, "\n"), true
+}
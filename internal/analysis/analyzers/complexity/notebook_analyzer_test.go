@@ -0,0 +1,63 @@
+package complexity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotebookAnalyzer(t *testing.T) {
+	notebook := `{
+ "nbformat": 4,
+ "metadata": {"language_info": {"name": "python"}},
+ "cells": [
+  {"cell_type": "markdown", "source": "def in_markdown():\n    pass\n"},
+  {"cell_type": "code", "source": ["%time x = 1\n", "def first(a):\n", "    if a:\n", "        !echo yes\n", "    return a\n"],
+   "outputs": [{"output_type": "stream", "text": "def in_output():\n    pass\n"}]},
+  {"cell_type": "code", "source": "}}} ))) ((( ::: ;;; ]]]\n"},
+  {"cell_type": "code", "source": ["%%sql\n", "SELECT 1\n"]},
+  {"cell_type": "code", "source": "\n\ndef second():\n    return 2\n"}
+ ]
+}`
+
+	metrics, err := NewNotebookAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("/nb.ipynb", []byte(notebook))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "first", metrics[0].FunctionName)
+	assert.Equal(t, "/nb.ipynb", metrics[0].FilePath)
+	require.NotNil(t, metrics[0].NotebookCell)
+	assert.Equal(t, 2, *metrics[0].NotebookCell)
+	assert.Equal(t, 2, metrics[0].StartLine)
+	assert.Equal(t, 2, metrics[0].CyclomaticComplexity)
+
+	assert.Equal(t, "second", metrics[1].FunctionName)
+	require.NotNil(t, metrics[1].NotebookCell)
+	assert.Equal(t, 5, *metrics[1].NotebookCell)
+	assert.Equal(t, 3, metrics[1].StartLine)
+	assert.Equal(t, "Python", metrics[1].Language)
+}
+
+func TestNotebookAnalyzer_OtherKernel(t *testing.T) {
+	notebook := `{"nbformat": 4, "metadata": {"kernelspec": {"language": "R"}}, "cells": [{"cell_type": "code", "source": "def f():\n    pass\n"}]}`
+
+	metrics, err := NewNotebookAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("/nb.ipynb", []byte(notebook))
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+}
+
+func TestNotebookAnalyzer_Malformed(t *testing.T) {
+	for name, notebook := range map[string]string{
+		"invalid JSON": `{"cells": [`,
+		"nbformat 3":   `{"nbformat": 3, "worksheets": []}`,
+		"bad source":   `{"nbformat": 4, "cells": [{"cell_type": "code", "source": 42}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewNotebookAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("/nb.ipynb", []byte(notebook))
+			assert.True(t, errors.Is(err, ErrMalformedNotebook), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package complexity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ObjCAnalyzer measures Objective-C (.m) and Objective-C++ (.mm) sources.
+//
+// There is no Objective-C grammar available, so method definitions are located
+// by a lightweight scanner over @implementation blocks, and each method body is
+// then parsed with the C++ grammar (a superset of the C statements an ObjC body
+// uses) after ObjC-only syntax has been blanked out. Plain C functions are
+// parsed the same way from the file with its @interface/@implementation
+// sections masked.
+type ObjCAnalyzer struct {
+	thresholds models.ComplexityThresholds
+}
+
+func NewObjCAnalyzer(thresholds models.ComplexityThresholds) *ObjCAnalyzer {
+	return &ObjCAnalyzer{
+		thresholds: thresholds,
+	}
+}
+
+func (a *ObjCAnalyzer) Language() string {
+	return "Objective-C"
+}
+
+func (a *ObjCAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
+	var metrics []models.ComplexityMetric
+
+	methods, masked := scanObjCMethods(content)
+
+	for _, m := range methods {
+		// Wrap the body in a dummy signature so it parses as a function.
+		wrapped := []byte("void objc_method(void) " + sanitizeObjC(m.body))
+		body, release, err := parseFunctionBody(wrapped)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, a.buildMetric(filePath, m.name, m.line, m.endLine, m.paramCount, m.body, mapObjCNodes(body, wrapped)))
+		release()
+	}
+
+	// C functions outside the ObjC sections.
+	sanitized := []byte(sanitizeObjC(string(masked)))
+	parser := cppParsers.get()
+	defer cppParsers.put(parser)
+	tree, err := parser.ParseCtx(context.Background(), nil, sanitized)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	functions, err := findCCppFunctions(tree.RootNode(), sanitized)
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range functions {
+		// Message sends such as [obj foo] can be misread as C++ lambdas.
+		if fn.Name == "<lambda>" {
+			continue
+		}
+		start := int(fn.Node.StartPoint().Row) + 1
+		end := int(fn.Node.EndPoint().Row) + 1
+		body := string(content[fn.Node.StartByte():fn.Node.EndByte()])
+		metrics = append(metrics, a.buildMetric(filePath, fn.Name, start, end, fn.ParamCount, body, mapObjCNodes(fn.Node, sanitized)))
+	}
+
+	return metrics, nil
+}
+
+func (a *ObjCAnalyzer) buildMetric(filePath, name string, line, endLine, paramCount int, body string, nodes []Node) models.ComplexityMetric {
+	cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
+	loc := strings.Count(body, "\n") + 1
+
+	severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+	debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
+
+	cognitivePtr := cognitive
+	snippetStr := truncateSnippet(body, 10000)
+
+	return models.ComplexityMetric{
+		ID:                   uuid.New(),
+		FilePath:             filePath,
+		FunctionName:         name,
+		StartLine:            line,
+		EndLine:              endLine,
+		CyclomaticComplexity: cyclomatic,
+		CognitiveComplexity:  &cognitivePtr,
+		NestingDepth:         nesting,
+		ParameterCount:       paramCount,
+		LinesOfCode:          loc,
+		Severity:             severity,
+		TechnicalDebtMinutes: debtMinutes,
+		CodeSnippet:          &snippetStr,
+		Language:             a.Language(),
+	}
+}
+
+// parseFunctionBody parses a single wrapped function and returns its body
+// node, or the root node when syntax the grammar does not know prevented a
+// clean function_definition. The caller must invoke release once done with
+// the node.
+func parseFunctionBody(source []byte) (*sitter.Node, func(), error) {
+	parser := cppParsers.get()
+	defer cppParsers.put(parser)
+	tree, err := parser.ParseCtx(context.Background(), nil, source)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		if child := root.NamedChild(i); child.Type() == "function_definition" {
+			if body := child.ChildByFieldName("body"); body != nil {
+				return body, tree.Close, nil
+			}
+		}
+	}
+	return root, tree.Close, nil
+}
+
+// mapObjCNodes classifies control flow like mapCCppNodes but ignores C++
+// lambdas, which is how the C++ grammar may misread ObjC message sends.
+func mapObjCNodes(node *sitter.Node, content []byte) []Node {
+	var nodes []Node
+	var visit func(*sitter.Node, int)
+	visit = func(n *sitter.Node, depth int) {
+		if n == nil {
+			return
+		}
+
+		newDepth := depth
+		switch n.Type() {
+		case "if_statement", "case_statement", "catch_clause", "conditional_expression", "goto_statement":
+			nodes = append(nodes, Node{Type: Branch, Depth: depth})
+			newDepth++
+		case "switch_statement":
+			nodes = append(nodes, Node{Type: Nesting, Depth: depth})
+			newDepth++
+		case "while_statement", "for_statement", "for_range_loop", "do_statement":
+			nodes = append(nodes, Node{Type: Loop, Depth: depth})
+			newDepth++
+		case "binary_expression":
+			if op := n.ChildByFieldName("operator"); op != nil {
+				if opStr := op.Content(content); opStr == "&&" || opStr == "||" {
+					nodes = append(nodes, Node{Type: Operator, Depth: depth})
+				}
+			}
+		}
+
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			visit(n.NamedChild(i), newDepth)
+		}
+	}
+
+	visit(node, 0)
+	return nodes
+}
+
+type objcMethodInfo struct {
+	name       string
+	line       int
+	endLine    int
+	body       string
+	paramCount int
+}
+
+var (
+	objcTypeGroup     = regexp.MustCompile(`\([^()]*\)`)
+	objcSelectorPart  = regexp.MustCompile(`(\w+)\s*:`)
+	objcSelectorBare  = regexp.MustCompile(`^\s*(\w+)`)
+	objcForIn         = regexp.MustCompile(`\bfor\s*\([^;()]*?\s(in)\s`)
+	objcContainerHead = regexp.MustCompile(`^@(implementation|interface|protocol)\s+(\w+)(\s*\(\s*(\w*)\s*\))?`)
+)
+
+// scanObjCMethods finds every method definition inside @implementation blocks.
+// It also returns a copy of content in which @interface, @protocol and
+// @implementation sections are replaced by spaces (newlines are kept so line
+// numbers do not move), leaving only plain C for the grammar to parse.
+func scanObjCMethods(content []byte) ([]objcMethodInfo, []byte) {
+	src := string(content)
+	masked := []byte(src)
+	var methods []objcMethodInfo
+
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(masked); i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	lineAt := func(offset int) int {
+		return strings.Count(src[:offset], "\n") + 1
+	}
+
+	i := 0
+	for i < len(src) {
+		if next, skipped := skipObjCNonCode(src, i); skipped {
+			i = next
+			continue
+		}
+		if src[i] != '@' {
+			i++
+			continue
+		}
+
+		head := objcContainerHead.FindStringSubmatch(src[i:])
+		if head == nil {
+			i++
+			continue
+		}
+		// Forward declarations (@protocol Foo;) have no @end.
+		if rest := strings.TrimLeft(src[i+len(head[0]):], " \t"); strings.HasPrefix(rest, ";") || strings.HasPrefix(rest, ",") {
+			i += len(head[0])
+			continue
+		}
+		start := i
+		end := findObjCEnd(src, i+len(head[0]))
+		if head[1] == "implementation" {
+			class := head[2]
+			if head[4] != "" {
+				class += "(" + head[4] + ")"
+			}
+			methods = append(methods, scanImplementation(src, i+len(head[0]), end, class, lineAt)...)
+		}
+		blank(start, end)
+		i = end
+	}
+
+	return methods, masked
+}
+
+// scanImplementation collects the method definitions between from and to.
+func scanImplementation(src string, from, to int, class string, lineAt func(int) int) []objcMethodInfo {
+	var methods []objcMethodInfo
+	depth := 0
+	lineStart := true
+
+	for i := from; i < to; {
+		if next, skipped := skipObjCNonCode(src, i); skipped {
+			i = next
+			continue
+		}
+		c := src[i]
+		switch {
+		case c == '\n':
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case depth == 0 && lineStart && (c == '-' || c == '+'):
+			if m, next, ok := scanMethod(src, i, to, class, lineAt); ok {
+				methods = append(methods, m)
+				i = next
+				lineStart = false
+				continue
+			}
+		}
+		lineStart = false
+		i++
+	}
+	return methods
+}
+
+// scanMethod reads one method starting at the -/+ marker at start. It returns
+// ok=false for declarations without a body.
+func scanMethod(src string, start, limit int, class string, lineAt func(int) int) (objcMethodInfo, int, bool) {
+	open := -1
+	for i := start; i < limit; {
+		if next, skipped := skipObjCNonCode(src, i); skipped {
+			i = next
+			continue
+		}
+		if src[i] == ';' {
+			return objcMethodInfo{}, i + 1, false
+		}
+		if src[i] == '{' {
+			open = i
+			break
+		}
+		i++
+	}
+	if open < 0 {
+		return objcMethodInfo{}, limit, false
+	}
+
+	depth := 0
+	closeIdx := -1
+	for i := open; i < limit; {
+		if next, skipped := skipObjCNonCode(src, i); skipped {
+			i = next
+			continue
+		}
+		if src[i] == '{' {
+			depth++
+		} else if src[i] == '}' {
+			depth--
+			if depth == 0 {
+				closeIdx = i
+				break
+			}
+		}
+		i++
+	}
+	if closeIdx < 0 {
+		closeIdx = limit - 1
+	}
+
+	selector, params := objcSelector(src[start+1 : open])
+	return objcMethodInfo{
+		name:       fmt.Sprintf("%c[%s %s]", src[start], class, selector),
+		line:       lineAt(start),
+		endLine:    lineAt(closeIdx),
+		body:       src[open : closeIdx+1],
+		paramCount: params,
+	}, closeIdx + 1, true
+}
+
+// objcSelector turns a method signature such as
+// "(id)initWithName:(NSString *)name age:(int)age" into "initWithName:age:".
+func objcSelector(signature string) (string, int) {
+	stripped := signature
+	for objcTypeGroup.MatchString(stripped) {
+		stripped = objcTypeGroup.ReplaceAllString(stripped, " ")
+	}
+
+	parts := objcSelectorPart.FindAllStringSubmatch(stripped, -1)
+	if len(parts) == 0 {
+		if bare := objcSelectorBare.FindStringSubmatch(stripped); bare != nil {
+			return bare[1], 0
+		}
+		return "unknown_selector", 0
+	}
+
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p[1])
+		b.WriteByte(':')
+	}
+	return b.String(), len(parts)
+}
+
+// findObjCEnd returns the offset just past the @end that closes the section
+// starting at from.
+func findObjCEnd(src string, from int) int {
+	for i := from; i < len(src); {
+		if next, skipped := skipObjCNonCode(src, i); skipped {
+			i = next
+			continue
+		}
+		if strings.HasPrefix(src[i:], "@end") {
+			return i + len("@end")
+		}
+		i++
+	}
+	return len(src)
+}
+
+// skipObjCNonCode skips a comment or string/char literal starting at i.
+func skipObjCNonCode(src string, i int) (int, bool) {
+	switch {
+	case strings.HasPrefix(src[i:], "//"):
+		if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+			return i + end, true
+		}
+		return len(src), true
+	case strings.HasPrefix(src[i:], "/*"):
+		if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2, true
+		}
+		return len(src), true
+	case src[i] == '"' || src[i] == '\'':
+		quote := src[i]
+		for j := i + 1; j < len(src); j++ {
+			if src[j] == '\\' {
+				j++
+				continue
+			}
+			if src[j] == quote || src[j] == '\n' {
+				return j + 1, true
+			}
+		}
+		return len(src), true
+	}
+	return i, false
+}
+
+// sanitizeObjC blanks ObjC-only syntax so the C++ grammar sees plain
+// statements: @keywords and literal prefixes (@"", @[], @{}) and block carets
+// are replaced by spaces, except @catch which becomes an if so it still counts
+// as a branch, and fast enumeration (for x in y) becomes a range-for. Offsets
+// are preserved.
+func sanitizeObjC(src string) string {
+	b := []byte(src)
+	for _, m := range objcForIn.FindAllSubmatchIndex(b, -1) {
+		copy(b[m[2]:m[3]], ": ")
+	}
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '@':
+			j := i + 1
+			for j < len(b) && (b[j] == '_' || b[j] >= 'a' && b[j] <= 'z' || b[j] >= 'A' && b[j] <= 'Z') {
+				j++
+			}
+			if string(b[i+1:j]) == "catch" {
+				copy(b[i:j], "if    ")
+			} else {
+				for k := i; k < j; k++ {
+					b[k] = ' '
+				}
+			}
+			i = j - 1
+		case '^':
+			if i+1 < len(b) && (b[i+1] == '{' || b[i+1] == '(') {
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,103 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjCAnalyzer_AnalyzeFile(t *testing.T) {
+	analyzer := NewObjCAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `#import <Foundation/Foundation.h>
+
+@protocol Greeter;
+
+@interface Person : NSObject
+- (instancetype)initWithName:(NSString *)name age:(int)age;
+@end
+
+static int clamp(int v, int lo, int hi) {
+    if (v < lo) {
+        return lo;
+    }
+    return v > hi ? hi : v;
+}
+
+@implementation Person {
+    NSString *_name;
+}
+
+- (instancetype)initWithName:(NSString *)name age:(int)age {
+    self = [super init];
+    if (self && age > 0) {
+        _name = [name copy];
+    }
+    return self;
+}
+
+- (void)greet {
+    NSLog(@"Hello, %@", _name);
+    [[NSNotificationCenter defaultCenter] postNotificationName:@"greet" object:self];
+    [self.delegate personDidGreet:self withMessage:@"hi"];
+}
+
++ (NSArray *)process:(NSArray *)items {
+    NSMutableArray *out = [NSMutableArray array];
+    for (id item in items) {
+        @try {
+            switch ([item intValue]) {
+                case 1:
+                    [out addObject:item];
+                    break;
+                default:
+                    break;
+            }
+        } @catch (NSException *e) {
+            NSLog(@"%@", e);
+        }
+    }
+    return out;
+}
+@end
+`
+
+	metrics, err := analyzer.AnalyzeFile("Person.m", []byte(code))
+	require.NoError(t, err)
+
+	byName := map[string]models.ComplexityMetric{}
+	for _, m := range metrics {
+		byName[m.FunctionName] = m
+	}
+	require.Len(t, byName, 4, "found: %v", byName)
+
+	init := byName["-[Person initWithName:age:]"]
+	assert.Equal(t, 3, init.CyclomaticComplexity, "if + &&")
+	assert.Equal(t, 2, init.ParameterCount)
+	assert.Equal(t, 20, init.StartLine)
+
+	greet := byName["-[Person greet]"]
+	assert.Equal(t, 1, greet.CyclomaticComplexity, "message sends are not branches")
+	assert.Equal(t, 0, greet.ParameterCount)
+
+	process := byName["+[Person process:]"]
+	assert.Equal(t, 4, process.CyclomaticComplexity, "for + case + @catch")
+	assert.Equal(t, 1, process.ParameterCount)
+
+	clamp := byName["clamp"]
+	assert.Equal(t, 3, clamp.CyclomaticComplexity, "if + ternary")
+	assert.Equal(t, 3, clamp.ParameterCount)
+}
+
+func TestFactory_ObjectiveCExtensions(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+
+	for _, path := range []string{"View.m", "Bridge.mm"} {
+		analyzer, err := factory.GetAnalyzer(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Objective-C", analyzer.Language())
+		assert.True(t, factory.IsSupported(path))
+	}
+}
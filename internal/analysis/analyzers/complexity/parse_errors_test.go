@@ -0,0 +1,88 @@
+package complexity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenSources are syntactically invalid files for every extension the
+// factory dispatches; each mixes an unterminated function with stray tokens.
+var brokenSources = map[string]string{
+	"broken.go":    "package main\n\nfunc run( {\n\tif x > {\n\t\treturn\n}}}\n",
+	"broken.js":    "function run(a, {\n  if (a > ) { return }\n}}} ===\n",
+	"broken.ts":    "function run(a: number, {\n  if (a > ) { return }\n}}} : =>\n",
+	"broken.py":    "def run(a:\n    if a >:\n        return\n  )))\n",
+	"broken.cs":    "class A { void Run( { if (x > ) { return; } }}} ; }\n",
+	"broken.php":   "<?php\nfunction run($a {\n  if ($a > ) { return; }\n}}}\n",
+	"broken.java":  "class A { void run( { if (x > ) { return; } }}} }\n",
+	"broken.rb":    "def run(a\n  if a >\n    return\nend end end\n",
+	"broken.rs":    "fn run(a: i32 {\n    if a > { return }\n}}}\n",
+	"broken.kt":    "fun run(a: Int {\n  if (a > ) { return }\n}}}\n",
+	"broken.swift": "func run(a: Int {\n  if a > { return }\n}}}\n",
+	"broken.m":     "@implementation A\n- (void)run:(int)a {\n  if (a > ) { return; }\n}}}\n@end\n",
+	"broken.cpp":   "int run(int a {\n  if (a > ) { return 0; }\n}}}\n",
+	"broken.ipynb": "{\"nbformat\": 4, \"cells\": [{\"cell_type\": \"code\", \"source\": ",
+}
+
+func TestAnalyzers_BrokenSourceDoesNotPanic(t *testing.T) {
+	factory := NewFactory(models.DefaultComplexityThresholds())
+
+	for file, source := range brokenSources {
+		t.Run(file, func(t *testing.T) {
+			analyzer, err := factory.GetAnalyzer(file)
+			require.NoError(t, err)
+
+			assert.NotPanics(t, func() {
+				metrics, err := analyzer.AnalyzeFile(file, []byte(source))
+				if err == nil {
+					for _, m := range metrics {
+						assert.GreaterOrEqual(t, m.EndLine, m.StartLine)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestAnalyzers_MostlyGarbageIsSkipped(t *testing.T) {
+	garbage := []byte("}}} ))) ((( ::: ;;; ]]] }}} ))) ((( ::: ;;; ]]]\n")
+
+	analyzers := map[string]fileAnalyzer{
+		"JavaScript": NewJavaScriptAnalyzer(models.DefaultComplexityThresholds()),
+		"Python":     NewPythonAnalyzer(models.DefaultComplexityThresholds()),
+		"Java":       NewJavaAnalyzer(models.DefaultComplexityThresholds()),
+		"C/C++":      NewCCppAnalyzer(models.DefaultComplexityThresholds()),
+		"Go":         NewGoAnalyzer(models.DefaultComplexityThresholds()),
+	}
+	for name, analyzer := range analyzers {
+		t.Run(name, func(t *testing.T) {
+			_, err := analyzer.AnalyzeFile("garbage", garbage)
+			assert.True(t, errors.Is(err, ErrParseFailed), "got %v", err)
+		})
+	}
+}
+
+func TestCheckParseErrors_ToleratesLocalMistakes(t *testing.T) {
+	source := []byte(`function ok(a) {
+  if (a > 1) {
+    return a;
+  }
+  return 0;
+}
+
+function alsoOk(b) {
+  for (const x of b) {
+    console.log(x);
+  }
+}
+
+function broken( {
+`)
+	metrics, err := NewJavaScriptAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("partial.js", source)
+	require.NoError(t, err)
+	assert.NotEmpty(t, metrics)
+}
@@ -0,0 +1,65 @@
+package complexity
+
+import (
+	"sync"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/cpp"
+	"github.com/smacker/go-tree-sitter/csharp"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"github.com/smacker/go-tree-sitter/php"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/ruby"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/swift"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// parserPool reuses tree-sitter parsers for a single grammar, so each file
+// does not pay for creating a C parser and loading its language. Idle parsers
+// are held by a sync.Pool and released by the garbage collector, which keeps
+// the pool's memory bounded by the number of files parsed concurrently.
+type parserPool struct {
+	pool sync.Pool
+}
+
+func newParserPool(language *sitter.Language) *parserPool {
+	return &parserPool{pool: sync.Pool{
+		New: func() interface{} {
+			parser := sitter.NewParser()
+			parser.SetLanguage(language)
+			return parser
+		},
+	}}
+}
+
+// get returns a parser with the pool's language set. The caller must hand it
+// back with put once parsing is done, typically via defer.
+func (p *parserPool) get() *sitter.Parser {
+	return p.pool.Get().(*sitter.Parser)
+}
+
+// put resets parser and returns it to the pool. Trees it produced remain
+// valid after the parser is returned.
+func (p *parserPool) put(parser *sitter.Parser) {
+	parser.Reset()
+	p.pool.Put(parser)
+}
+
+// One pool per grammar. Analyzers that share a grammar (C/C++ and
+// Objective-C) share its pool.
+var (
+	cppParsers        = newParserPool(cpp.GetLanguage())
+	csharpParsers     = newParserPool(csharp.GetLanguage())
+	javaParsers       = newParserPool(java.GetLanguage())
+	javascriptParsers = newParserPool(javascript.GetLanguage())
+	kotlinParsers     = newParserPool(kotlin.GetLanguage())
+	phpParsers        = newParserPool(php.GetLanguage())
+	pythonParsers     = newParserPool(python.GetLanguage())
+	rubyParsers       = newParserPool(ruby.GetLanguage())
+	rustParsers       = newParserPool(rust.GetLanguage())
+	swiftParsers      = newParserPool(swift.GetLanguage())
+	typescriptParsers = newParserPool(typescript.GetLanguage())
+)
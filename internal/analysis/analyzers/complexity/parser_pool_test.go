@@ -0,0 +1,83 @@
+package complexity
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pooledJavaScript = `function classify(items) {
+  let total = 0;
+  for (const item of items) {
+    if (item > 10 && item < 100) {
+      total += item;
+    } else if (item < 0) {
+      total -= item;
+    }
+  }
+  return total;
+}
+`
+
+func TestParserPool_ReuseGivesSameResults(t *testing.T) {
+	analyzer := NewJavaScriptAnalyzer(models.DefaultComplexityThresholds())
+
+	first, err := analyzer.AnalyzeFile("a.js", []byte(pooledJavaScript))
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	// A failed parse in between must not leave state behind in the pooled parser.
+	_, _ = analyzer.AnalyzeFile("broken.js", []byte("}}} ))) ((( ;;;"))
+
+	second, err := analyzer.AnalyzeFile("a.js", []byte(pooledJavaScript))
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, first[0].CyclomaticComplexity, second[0].CyclomaticComplexity)
+	assert.Equal(t, first[0].StartLine, second[0].StartLine)
+	assert.Equal(t, first[0].EndLine, second[0].EndLine)
+}
+
+// The two benchmarks isolate the cost the pool removes. Compare with
+//
+//	go test -run '^$' -bench 'Parse' -benchmem ./internal/analysis/analyzers/complexity/
+func BenchmarkParse_NewParserPerFile(b *testing.B) {
+	content := []byte(strings.Repeat(pooledJavaScript, 20))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parser := sitter.NewParser()
+		parser.SetLanguage(javascript.GetLanguage())
+		tree, _ := parser.ParseCtx(context.Background(), nil, content)
+		tree.Close()
+		parser.Close()
+	}
+}
+
+func BenchmarkParse_Pooled(b *testing.B) {
+	content := []byte(strings.Repeat(pooledJavaScript, 20))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parser := javascriptParsers.get()
+		tree, _ := parser.ParseCtx(context.Background(), nil, content)
+		tree.Close()
+		javascriptParsers.put(parser)
+	}
+}
+
+func BenchmarkAnalyzeFile_JavaScript(b *testing.B) {
+	analyzer := NewJavaScriptAnalyzer(models.DefaultComplexityThresholds())
+	content := []byte(strings.Repeat(pooledJavaScript, 20))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := analyzer.AnalyzeFile("bench.js", content); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
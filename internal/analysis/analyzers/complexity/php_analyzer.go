@@ -28,8 +28,8 @@ func (a *PHPAnalyzer) Language() string {
 func (a *PHPAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(php.GetLanguage())
+	parser := phpParsers.get()
+	defer phpParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -40,6 +40,10 @@ func (a *PHPAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Com
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 	functions, err := findPHPFunctions(root, content)
 	if err != nil {
@@ -51,7 +55,7 @@ func (a *PHPAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Com
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 
 		cognitivePtr := cognitive
 		snippetStr := truncateSnippet(fn.body, 10000)
@@ -71,6 +75,7 @@ func (a *PHPAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Com
 			CodeSnippet:          &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
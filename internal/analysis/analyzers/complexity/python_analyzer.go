@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
-	"github.com/smacker/go-tree-sitter/python"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/google/uuid"
@@ -27,15 +26,22 @@ func (a *PythonAnalyzer) Language() string {
 func (a *PythonAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(python.GetLanguage())
+	parser := pythonParsers.get()
+	defer pythonParsers.put(parser)
 
-	tree, _ := parser.ParseCtx(context.Background(), nil, content)
+	tree, err := parser.ParseCtx(context.Background(), nil, content)
+	if err != nil {
+		return nil, err
+	}
 	if tree == nil {
 		return metrics, nil
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions := findPythonFunctions(root, content)
@@ -43,8 +49,10 @@ func (a *PythonAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 	for _, fn := range functions {
 		nodes := mapPythonNodes(fn.Node)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
+		loc := locFromNode(fn.Node)
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
 
 		cognitivePtr := cognitive
 		// Use full function code for AI fixes - extract up to 10000 chars
@@ -60,10 +68,18 @@ func (a *PythonAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.
 			CognitiveComplexity:  &cognitivePtr,
 			NestingDepth:         nesting,
 			ParameterCount:       fn.paramCount,
+			LinesOfCode:          loc,
 			Severity:             severity,
+			TechnicalDebtMinutes: debtMinutes,
 			CodeSnippet:          &snippetStr,
+			ReturnCount:          fn.returns,
+			GuardReturnCount:     fn.guardReturns,
+		}
+		if suggestions := models.GenerateSignatureSuggestions(fn.flagParams, fn.returnValues, cyclomatic); len(suggestions) > 0 {
+			metric.RefactoringSuggestions = suggestions
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -90,7 +106,9 @@ func traverseForFunctions(node *sitter.Node, content []byte, functions *[]functi
 		if fn.name != "" {
 			*functions = append(*functions, fn)
 		}
-	} else if nodeType == "lambda" {
+	} else if nodeType == "lambda" && node.IsNamed() {
+		// The lambda keyword is an anonymous node of the same type as the
+		// expression it starts.
 		fn := extractPythonLambda(node, content)
 		*functions = append(*functions, fn)
 	}
@@ -122,13 +140,80 @@ func extractPythonFunction(node *sitter.Node, content []byte) functionInfo {
 		child := node.Child(i)
 		if child.Type() == "parameters" {
 			fn.paramCount = countPythonParameters(child, content)
+			fn.flagParams = pythonFlagParameters(child, content)
 			break
 		}
 	}
+	if body := node.ChildByFieldName("body"); body != nil {
+		fn.returnValues = maxPythonReturnValues(body)
+		fn.returns, fn.guardReturns = countReturns(body)
+	}
 
 	return fn
 }
 
+// pythonFlagParameters returns the parameters annotated as bool or defaulting
+// to True/False.
+func pythonFlagParameters(paramsNode *sitter.Node, content []byte) []string {
+	var names []string
+	for i := 0; i < int(paramsNode.NamedChildCount()); i++ {
+		param := paramsNode.NamedChild(i)
+		switch param.Type() {
+		case "typed_parameter", "default_parameter", "typed_default_parameter":
+		default:
+			continue
+		}
+
+		isFlag := false
+		if typ := param.ChildByFieldName("type"); typ != nil && typ.Content(content) == "bool" {
+			isFlag = true
+		}
+		if value := param.ChildByFieldName("value"); value != nil && (value.Type() == "true" || value.Type() == "false") {
+			isFlag = true
+		}
+		if !isFlag {
+			continue
+		}
+
+		name := param.ChildByFieldName("name")
+		if name == nil && param.NamedChildCount() > 0 {
+			name = param.NamedChild(0)
+		}
+		if name != nil {
+			names = append(names, name.Content(content))
+		}
+	}
+	return names
+}
+
+// maxPythonReturnValues returns the largest tuple returned by a return
+// statement of the function, ignoring nested functions and lambdas.
+func maxPythonReturnValues(node *sitter.Node) int {
+	most := 0
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "function_definition", "lambda", "class_definition":
+			continue
+		case "return_statement":
+			if child.NamedChildCount() > 0 {
+				values := 1
+				if value := child.NamedChild(0); value.Type() == "expression_list" {
+					values = int(value.NamedChildCount())
+				}
+				if values > most {
+					most = values
+				}
+			}
+			continue
+		}
+		if n := maxPythonReturnValues(child); n > most {
+			most = n
+		}
+	}
+	return most
+}
+
 func extractPythonLambda(node *sitter.Node, content []byte) functionInfo {
 	var fn functionInfo
 
@@ -0,0 +1,425 @@
+package complexity
+
+import (
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// RAnalyzer measures R source files.
+//
+// There is no R grammar available, so the source is scanned: comments and
+// string literals are blanked out, then brackets and the bodies of if, else,
+// for, while, repeat and function are tracked, whether they are braced or a
+// single expression. Every function literal is measured, named after the
+// variable it is assigned to (`f <- function(x)`) or "<anonymous>", and the
+// control flow of a nested function counts only towards that function.
+// Each alternative of switch() counts as a branch.
+type RAnalyzer struct {
+	thresholds models.ComplexityThresholds
+}
+
+func NewRAnalyzer(thresholds models.ComplexityThresholds) *RAnalyzer {
+	return &RAnalyzer{
+		thresholds: thresholds,
+	}
+}
+
+func (a *RAnalyzer) Language() string {
+	return "R"
+}
+
+func (a *RAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
+	src := string(content)
+
+	var metrics []models.ComplexityMetric
+	for _, fn := range scanRFunctions(src, sanitizeR(src)) {
+		metrics = append(metrics, buildScannedMetric(a.Language(), filePath, fn, a.thresholds))
+	}
+	return metrics, nil
+}
+
+type rScopeKind int
+
+const (
+	rParen     rScopeKind = iota // ( or [
+	rBrace                       // {
+	rStatement                   // an unbraced body, up to the end of its expression
+)
+
+// rBody describes the body that follows an if, for, function, ... header.
+type rBody struct {
+	nests bool // control flow body: adds a nesting level
+	fn    int  // index in frames for a function body, or -1
+}
+
+type rFrame struct {
+	fn   scannedFunction
+	open bool
+}
+
+type rScope struct {
+	kind   rScopeKind
+	nests  bool
+	fn     int    // function whose body this scope is, or -1
+	header *rBody // set on the (...) of a header: the body that follows it
+	// commas counts top-level commas, for the parameters of a function and
+	// the alternatives of switch().
+	commas   int
+	empty    bool
+	isSwitch bool
+}
+
+// scanRFunctions walks the sanitized source once and returns every function
+// literal with its control flow.
+func scanRFunctions(src, sanitized string) []scannedFunction {
+	var stack []rScope
+	var frames []*rFrame
+	var pending *rBody // header seen, body not started yet
+
+	line := 1
+	// The two previous tokens, newlines excluded, for naming functions.
+	var prev, prev2 string
+
+	current := func() (*rFrame, int) {
+		depth := 0
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].fn >= 0 {
+				return frames[stack[i].fn], depth
+			}
+			if stack[i].nests {
+				depth++
+			}
+		}
+		return nil, 0
+	}
+	emit := func(t ComplexityNodeType, offset int) {
+		if frame, depth := current(); frame != nil {
+			frame.fn.nodes = append(frame.fn.nodes, Node{Type: t, Depth: depth + offset})
+		}
+	}
+	closeFrame := func(frame *rFrame, endLine int) {
+		if !frame.open {
+			return
+		}
+		frame.open = false
+		frame.fn.endLine = endLine
+		frame.fn.body = sourceLines(src, frame.fn.line, endLine)
+	}
+	pop := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if top.fn >= 0 {
+			closeFrame(frames[top.fn], line)
+		}
+	}
+	// endStatements closes the unbraced bodies ended by a newline, `;`, `,`
+	// or a closing bracket.
+	endStatements := func() {
+		for len(stack) > 0 && stack[len(stack)-1].kind == rStatement {
+			pop()
+		}
+	}
+	// startBody opens the pending body when the token at hand is not `{`.
+	startBody := func(braced bool) {
+		if pending == nil {
+			return
+		}
+		body := *pending
+		pending = nil
+		kind := rStatement
+		if braced {
+			kind = rBrace
+		}
+		stack = append(stack, rScope{kind: kind, nests: body.nests, fn: body.fn})
+	}
+	// header opens the (...) of a header, whose body follows it.
+	header := func(body rBody) {
+		pending = nil
+		stack = append(stack, rScope{kind: rParen, fn: -1, header: &body, empty: true})
+	}
+	expectHeader := false
+	var headerBody rBody
+
+	for i := 0; i < len(sanitized); {
+		c := sanitized[i]
+		if c == '\n' {
+			if pending == nil {
+				endStatements()
+			}
+			line++
+			i++
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\r' {
+			i++
+			continue
+		}
+
+		if expectHeader {
+			expectHeader = false
+			if c == '(' {
+				header(headerBody)
+				prev2, prev = prev, "("
+				i++
+				continue
+			}
+			// A keyword without its parentheses: nothing to track.
+			if headerBody.fn >= 0 {
+				closeFrame(frames[headerBody.fn], line)
+			}
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].kind == rParen && c != ')' && c != ']' {
+			stack[len(stack)-1].empty = false
+		}
+
+		token := string(c)
+		switch {
+		case c == '{':
+			if pending != nil {
+				startBody(true)
+			} else {
+				stack = append(stack, rScope{kind: rBrace, fn: -1})
+			}
+			i++
+		case c == '(' || c == '[':
+			startBody(false)
+			isSwitch := prev == "switch"
+			if isSwitch {
+				emit(Nesting, 0)
+			}
+			stack = append(stack, rScope{kind: rParen, fn: -1, nests: isSwitch, isSwitch: isSwitch, empty: true})
+			i++
+		case c == ')' || c == ']' || c == '}':
+			endStatements()
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.header != nil && top.header.fn >= 0 {
+					params := 0
+					if !top.empty {
+						params = top.commas + 1
+					}
+					frames[top.header.fn].fn.paramCount = params
+				}
+				pop()
+				if top.header != nil {
+					pending = top.header
+				}
+			}
+			i++
+		case c == ';':
+			endStatements()
+			i++
+		case c == ',':
+			endStatements()
+			if len(stack) > 0 {
+				top := &stack[len(stack)-1]
+				top.commas++
+				if top.isSwitch {
+					emit(Branch, 0)
+				}
+			}
+			i++
+		case (c == '&' || c == '|') && i+1 < len(sanitized) && sanitized[i+1] == c:
+			startBody(false)
+			emit(Operator, 0)
+			token = sanitized[i : i+2]
+			i += 2
+		case c == '<' && strings.HasPrefix(sanitized[i:], "<<-"):
+			token = "<-"
+			i += 3
+		case c == '<' && strings.HasPrefix(sanitized[i:], "<-"):
+			token = "<-"
+			i += 2
+		case (c == '=' || c == '!' || c == '<' || c == '>') && i+1 < len(sanitized) && sanitized[i+1] == '=':
+			token = sanitized[i : i+2]
+			i += 2
+		case c == '\\' && i+1 < len(sanitized) && sanitized[i+1] == '(':
+			// \(x) is shorthand for function(x).
+			startBody(false)
+			frames = append(frames, &rFrame{fn: newRFunction(line, prev, prev2), open: true})
+			headerBody = rBody{fn: len(frames) - 1}
+			expectHeader = true
+			token = "function"
+			i++
+		case isRIdentStart(sanitized, i):
+			j := i
+			for j < len(sanitized) && (isIdentPart(sanitized[j]) || sanitized[j] == '.') {
+				j++
+			}
+			token = sanitized[i:j]
+			// Names after $ or @ are fields, not keywords.
+			field := prev == "$" || prev == "@"
+			if field {
+				startBody(false)
+				break
+			}
+			switch token {
+			case "if", "for", "while":
+				startBody(false)
+				if token == "if" {
+					emit(Branch, 0)
+				} else {
+					emit(Loop, 0)
+				}
+				headerBody = rBody{nests: true, fn: -1}
+				expectHeader = true
+			case "function":
+				startBody(false)
+				frames = append(frames, &rFrame{fn: newRFunction(line, prev, prev2), open: true})
+				headerBody = rBody{fn: len(frames) - 1}
+				expectHeader = true
+			case "repeat":
+				startBody(false)
+				emit(Loop, 0)
+				pending = &rBody{nests: true, fn: -1}
+			case "else":
+				// The unbraced body of the if ends here, not the bodies
+				// around it.
+				if n := len(stack); n > 0 && stack[n-1].kind == rStatement && stack[n-1].fn < 0 {
+					pop()
+				}
+				if nextRWord(sanitized, j) != "if" {
+					pending = &rBody{nests: true, fn: -1}
+				}
+			default:
+				startBody(false)
+			}
+			i = j
+		case c >= '0' && c <= '9':
+			startBody(false)
+			j := i
+			for j < len(sanitized) && (isIdentPart(sanitized[j]) || sanitized[j] == '.') {
+				j++
+			}
+			token = sanitized[i:j]
+			i = j
+		default:
+			startBody(false)
+			i++
+		}
+		prev2, prev = prev, token
+	}
+
+	for len(stack) > 0 {
+		pop()
+	}
+	var functions []scannedFunction
+	for _, frame := range frames {
+		closeFrame(frame, line)
+		functions = append(functions, frame.fn)
+	}
+	return functions
+}
+
+// newRFunction starts a function literal at line, named after the variable
+// it is assigned to when the tokens before it are `name <-` or `name =`.
+func newRFunction(line int, prev, prev2 string) scannedFunction {
+	fn := scannedFunction{}
+	fn.line = line
+	fn.name = "<anonymous>"
+	if (prev == "<-" || prev == "=") && prev2 != "" && isRIdentStart(prev2, 0) {
+		fn.name = prev2
+	}
+	return fn
+}
+
+// isRIdentStart reports whether an R name starts at s[i]: a letter, or a dot
+// not followed by a digit (.5 is a number).
+func isRIdentStart(s string, i int) bool {
+	c := s[i]
+	if c == '.' {
+		return i+1 >= len(s) || !(s[i+1] >= '0' && s[i+1] <= '9')
+	}
+	return isIdentStart(c) && c != '_'
+}
+
+// nextRWord returns the name starting at the first non-blank character from
+// offset, newlines included.
+func nextRWord(sanitized string, offset int) string {
+	i := offset
+	for i < len(sanitized) && strings.ContainsRune(" \t\r\n", rune(sanitized[i])) {
+		i++
+	}
+	j := i
+	for j < len(sanitized) && (isIdentPart(sanitized[j]) || sanitized[j] == '.') {
+		j++
+	}
+	return sanitized[i:j]
+}
+
+// sanitizeR blanks out comments and the contents of string literals, raw
+// strings (r"(...)") and backquoted names, keeping every newline so offsets
+// map to the same lines.
+func sanitizeR(src string) string {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for k := from; k < to && k < len(out); k++ {
+			if out[k] != '\n' {
+				out[k] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '#':
+			j := strings.IndexByte(src[i:], '\n')
+			if j < 0 {
+				j = len(src) - i
+			}
+			blank(i, i+j)
+			i += j - 1
+		case (c == 'r' || c == 'R') && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '\'') &&
+			(i == 0 || !(isIdentPart(src[i-1]) || src[i-1] == '.')):
+			if end, ok := rawStringEnd(src, i+1); ok {
+				blank(i+2, end)
+				i = end
+			}
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			blank(i+1, j)
+			i = j
+		}
+	}
+	return string(out)
+}
+
+// rawStringEnd returns the offset of the closing quote of the raw string whose
+// opening quote is at src[quote], e.g. r"-(...)-".
+func rawStringEnd(src string, quote int) (int, bool) {
+	i := quote + 1
+	dashes := 0
+	for i < len(src) && src[i] == '-' {
+		dashes++
+		i++
+	}
+	if i >= len(src) {
+		return 0, false
+	}
+	var closer byte
+	switch src[i] {
+	case '(':
+		closer = ')'
+	case '[':
+		closer = ']'
+	case '{':
+		closer = '}'
+	default:
+		return 0, false
+	}
+	terminator := string(closer) + strings.Repeat("-", dashes) + string(src[quote])
+	end := strings.Index(src[i+1:], terminator)
+	if end < 0 {
+		return len(src), true
+	}
+	return i + 1 + end + len(terminator) - 1, true
+}
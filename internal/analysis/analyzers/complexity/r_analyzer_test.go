@@ -0,0 +1,119 @@
+package complexity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRAnalyzer_AnalyzeFile(t *testing.T) {
+	analyzer := NewRAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `# if in a comment
+summarize <- function(data, limit = c(1, 2)) {
+  total <- 0
+  for (x in data) {
+    if (x > 0 && x < limit[1]) {
+      total <- total + x
+    } else if (is.na(x) || x == 0) {
+      message("while in a string")
+    } else {
+      next
+    }
+  }
+  scale <- function(v) switch(v,
+    small = v * 2,
+    large = v,
+    0)
+  total
+}
+
+label = function(x) if (x) "yes" else "no"
+
+squares <- sapply(1:3, \(i) i^2)
+
+retry <- function() {
+  n <- 0
+  repeat {
+    n <- n + 1
+    if (n > 3) break
+  }
+  while (TRUE) break
+  n
+}
+`
+
+	metrics, err := analyzer.AnalyzeFile("summarize.R", []byte(code))
+	require.NoError(t, err)
+
+	byName := map[string]models.ComplexityMetric{}
+	for _, m := range metrics {
+		byName[m.FunctionName] = m
+		assert.Equal(t, "R", m.Language)
+	}
+	require.Len(t, byName, 5, "found: %v", byName)
+
+	summarize := byName["summarize"]
+	assert.Equal(t, 6, summarize.CyclomaticComplexity, "for + if + && + else if + ||; scale is measured on its own")
+	assert.Equal(t, 2, summarize.ParameterCount)
+	assert.Equal(t, 2, summarize.StartLine)
+	assert.Equal(t, 18, summarize.EndLine)
+	assert.Equal(t, 1, summarize.NestingDepth)
+
+	scale := byName["scale"]
+	assert.Equal(t, 4, scale.CyclomaticComplexity, "three switch alternatives")
+	assert.Equal(t, 13, scale.StartLine)
+	assert.Equal(t, 16, scale.EndLine)
+	assert.Equal(t, 1, scale.NestingDepth)
+
+	label := byName["label"]
+	assert.Equal(t, 2, label.CyclomaticComplexity)
+	assert.Equal(t, 20, label.StartLine)
+	assert.Equal(t, 20, label.EndLine)
+
+	lambda := byName["<anonymous>"]
+	assert.Equal(t, 1, lambda.CyclomaticComplexity)
+	assert.Equal(t, 1, lambda.ParameterCount)
+	assert.Equal(t, 22, lambda.StartLine)
+
+	retry := byName["retry"]
+	assert.Equal(t, 4, retry.CyclomaticComplexity, "repeat + if + while")
+	assert.Equal(t, 0, retry.ParameterCount)
+	assert.Equal(t, 32, retry.EndLine)
+	assert.Equal(t, 1, retry.NestingDepth)
+}
+
+func TestRAnalyzer_UnbracedBodies(t *testing.T) {
+	analyzer := NewRAnalyzer(models.DefaultComplexityThresholds())
+
+	code := `sign <- function(x)
+  if (x > 0) 1 else if (x < 0) -1 else {
+    for (i in 1:2) if (i) x
+    0
+  }
+after <- function() NULL
+`
+
+	metrics, err := analyzer.AnalyzeFile("sign.r", []byte(code))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "sign", metrics[0].FunctionName)
+	assert.Equal(t, 5, metrics[0].CyclomaticComplexity, "if + else if + for + if")
+	assert.Equal(t, 5, metrics[0].EndLine)
+	assert.Equal(t, 2, metrics[0].NestingDepth)
+	assert.Equal(t, "after", metrics[1].FunctionName)
+	assert.Equal(t, 6, metrics[1].StartLine)
+}
+
+func TestSanitizeR(t *testing.T) {
+	src := "x <- \"a # b\" # note\ny <- r\"(if (x) \")\" \n`if` <- 'it\\'s'\n"
+	sanitized := sanitizeR(src)
+	require.Equal(t, len(src), len(sanitized))
+	assert.NotContains(t, sanitized, "#")
+	assert.NotContains(t, sanitized, "if")
+	assert.Equal(t, 3, strings.Count(sanitized, "\n"))
+}
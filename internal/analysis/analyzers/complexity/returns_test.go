@@ -0,0 +1,164 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Each sample has two guard clauses, three returns further down and a
+// nested function whose return is not counted.
+func TestAnalyzers_ReturnCount(t *testing.T) {
+	thresholds := models.DefaultComplexityThresholds()
+	tests := []struct {
+		name     string
+		analyzer Analyzer
+		path     string
+		function string
+		code     string
+	}{
+		{
+			name: "Go", analyzer: NewGoAnalyzer(thresholds), path: "p.go", function: "classify",
+			code: `package p
+
+func classify(n int, s string) string {
+	if n < 0 {
+		return "negative"
+	}
+	if s == "" {
+		return "empty"
+	}
+	f := func() int { return n }
+	for i := 0; i < f(); i++ {
+		if i == 3 {
+			return "three"
+		}
+	}
+	if n > 100 {
+		return "large"
+	}
+	return "small"
+}
+`,
+		},
+		{
+			name: "JavaScript", analyzer: NewJavaScriptAnalyzer(thresholds), path: "p.js", function: "classify",
+			code: `function classify(n, s) {
+  if (n < 0) {
+    return "negative";
+  }
+  if (!s) return "empty";
+  const f = () => { return n; };
+  for (let i = 0; i < f(); i++) {
+    if (i === 3) {
+      return "three";
+    }
+  }
+  if (n > 100) {
+    return "large";
+  }
+  return "small";
+}
+`,
+		},
+		{
+			name: "TypeScript", analyzer: NewTypeScriptAnalyzer(thresholds), path: "p.ts", function: "classify",
+			code: `function classify(n: number, s: string): string {
+  if (n < 0) {
+    return "negative";
+  }
+  if (!s) return "empty";
+  const f = function (): number { return n; };
+  for (let i = 0; i < f(); i++) {
+    if (i === 3) {
+      return "three";
+    }
+  }
+  if (n > 100) {
+    return "large";
+  }
+  return "small";
+}
+`,
+		},
+		{
+			name: "Java", analyzer: NewJavaAnalyzer(thresholds), path: "P.java", function: "classify",
+			code: `class P {
+    String classify(int n, String s) {
+        // Reject bad input first.
+        if (n < 0) {
+            return "negative";
+        }
+        if (s.isEmpty()) return "empty";
+        java.util.function.IntSupplier f = () -> { return n; };
+        for (int i = 0; i < f.getAsInt(); i++) {
+            if (i == 3) {
+                return "three";
+            }
+        }
+        if (n > 100) {
+            return "large";
+        }
+        return "small";
+    }
+}
+`,
+		},
+		{
+			name: "Python", analyzer: NewPythonAnalyzer(thresholds), path: "p.py", function: "classify",
+			code: `def classify(n, s):
+    """Name the size of n."""
+    if n < 0:
+        return "negative"
+    if not s:
+        return "empty"
+    def f():
+        return n
+    for i in range(f()):
+        if i == 3:
+            return "three"
+    if n > 100:
+        return "large"
+    return "small"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := tt.analyzer.AnalyzeFile(tt.path, []byte(tt.code))
+			require.NoError(t, err)
+
+			var found *models.ComplexityMetric
+			for i := range metrics {
+				if metrics[i].FunctionName == tt.function {
+					found = &metrics[i]
+				}
+			}
+			require.NotNil(t, found, "function %s not found", tt.function)
+			assert.Equal(t, 5, found.ReturnCount)
+			assert.Equal(t, 2, found.GuardReturnCount)
+		})
+	}
+}
+
+func TestGenerateReturnSuggestions(t *testing.T) {
+	// Guard clauses count half: six guards and two other returns weigh 5.
+	assert.Empty(t, models.GenerateReturnSuggestions(8, 6, models.DefaultMaxReturns))
+
+	suggestions := models.GenerateReturnSuggestions(8, 0, models.DefaultMaxReturns)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "too_many_returns", suggestions[0].Type)
+	assert.Equal(t, "medium", suggestions[0].Priority)
+
+	suggestions = models.GenerateReturnSuggestions(12, 2, models.DefaultMaxReturns)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "high", suggestions[0].Priority)
+
+	metadata := models.ComplexityMetric{ReturnCount: 5, GuardReturnCount: 2}.ReturnMetadata()
+	require.NotNil(t, metadata)
+	assert.JSONEq(t, `{"return_count":5,"guard_return_count":2,"weighted_return_count":4}`, *metadata)
+	assert.Nil(t, models.ComplexityMetric{}.ReturnMetadata())
+}
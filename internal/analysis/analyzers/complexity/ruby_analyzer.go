@@ -28,8 +28,8 @@ func (a *RubyAnalyzer) Language() string {
 func (a *RubyAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(ruby.GetLanguage())
+	parser := rubyParsers.get()
+	defer rubyParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -40,6 +40,10 @@ func (a *RubyAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions, err := findRubyMethods(root, content)
@@ -52,7 +56,7 @@ func (a *RubyAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
 		suggestions := generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, fn.paramCount, loc)
 
@@ -76,6 +80,7 @@ func (a *RubyAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 			CodeSnippet:            &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -253,6 +258,7 @@ func generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cyclomatic > 15 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "extract_method",
 			Priority:    "high",
 			Title:       "Extract Methods",
 			Description: "Break down this method into smaller, focused methods using Ruby's expressive syntax",
@@ -261,6 +267,7 @@ func generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if nesting > 3 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "reduce_nesting",
 			Priority:    "high",
 			Title:       "Reduce Nesting Depth",
 			Description: "Use Ruby's guard clauses, early returns, or extract nested logic into separate methods",
@@ -269,6 +276,7 @@ func generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if paramCount > 4 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "introduce_parameter_object",
 			Priority:    "medium",
 			Title:       "Introduce Parameter Object",
 			Description: "Consider using a hash or creating a parameter object to group related parameters",
@@ -277,6 +285,7 @@ func generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if loc > 50 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "split_function",
 			Priority:    "high",
 			Title:       "Method Too Long",
 			Description: "Split this method into smaller methods. Consider using Ruby modules or service objects",
@@ -285,6 +294,7 @@ func generateRubyRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cognitive > 20 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "simplify_logic",
 			Priority:    "medium",
 			Title:       "Simplify Logic",
 			Description: "Use Ruby idioms like safe navigation (&.), try, or early returns to simplify logic",
@@ -27,8 +27,8 @@ func (a *RustAnalyzer) Language() string {
 func (a *RustAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(rust.GetLanguage())
+	parser := rustParsers.get()
+	defer rustParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -39,6 +39,10 @@ func (a *RustAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions, err := findRustFunctions(root, content)
@@ -51,7 +55,7 @@ func (a *RustAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 		debtMinutes := estimateRustTechnicalDebt(cyclomatic, cognitive, loc)
 		suggestions := generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, fn.paramCount, loc)
 
@@ -75,6 +79,7 @@ func (a *RustAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.Co
 			CodeSnippet:            &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -225,6 +230,7 @@ func generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cyclomatic > 15 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "extract_method",
 			Priority:    "high",
 			Title:       "Extract Functions",
 			Description: "Break down this function into smaller, focused functions. Consider using private helper functions or modules",
@@ -233,6 +239,7 @@ func generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if nesting > 3 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "reduce_nesting",
 			Priority:    "high",
 			Title:       "Reduce Nesting Depth",
 			Description: "Use early returns with ? operator, if let, or match guards to reduce nesting",
@@ -241,6 +248,7 @@ func generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if paramCount > 4 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "introduce_parameter_object",
 			Priority:    "medium",
 			Title:       "Consider Builder Pattern or Struct",
 			Description: "Too many parameters. Consider using a builder pattern or passing a configuration struct",
@@ -249,6 +257,7 @@ func generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if loc > 50 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "split_function",
 			Priority:    "high",
 			Title:       "Function Too Long",
 			Description: "Split this function into smaller functions. Consider extracting logic into separate modules or traits",
@@ -257,6 +266,7 @@ func generateRustRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCou
 
 	if cognitive > 20 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "simplify_logic",
 			Priority:    "medium",
 			Title:       "Simplify Logic",
 			Description: "Use Rust's Result and Option combinators (map, and_then, unwrap_or) to simplify control flow",
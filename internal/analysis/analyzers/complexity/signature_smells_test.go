@@ -0,0 +1,89 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func suggestionTypes(metric models.ComplexityMetric) map[string]string {
+	types := make(map[string]string)
+	for _, s := range metric.RefactoringSuggestions {
+		types[s.Type] = s.Priority
+	}
+	return types
+}
+
+func TestGoAnalyzer_SignatureSmells(t *testing.T) {
+	code := `package p
+
+func Render(name string, verbose bool) string {
+	if verbose {
+		return "long " + name
+	}
+	return name
+}
+
+func Stats(xs []int) (min, max, sum int, err error) {
+	return 0, 0, 0, nil
+}
+
+func Plain(a, b int) (int, error) {
+	return a + b, nil
+}
+`
+	metrics, err := NewGoAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("p.go", []byte(code))
+	require.NoError(t, err)
+	require.Len(t, metrics, 3)
+
+	byName := map[string]models.ComplexityMetric{}
+	for _, m := range metrics {
+		byName[m.FunctionName] = m
+	}
+
+	assert.Equal(t, "info", suggestionTypes(byName["Render"])["replace_flag_argument"])
+	assert.Contains(t, suggestionTypes(byName["Stats"]), "split_return")
+	assert.NotContains(t, suggestionTypes(byName["Plain"]), "split_return")
+	assert.NotContains(t, suggestionTypes(byName["Plain"]), "replace_flag_argument")
+}
+
+func TestPythonAnalyzer_SignatureSmells(t *testing.T) {
+	code := `def export(rows, compress=False, header: bool = True):
+    return rows
+
+def bounds(points):
+    def inner():
+        return 1, 2, 3, 4, 5
+    return 0, 0, 1, 1
+
+def simple(a, b=2):
+    return a, b
+`
+	metrics, err := NewPythonAnalyzer(models.DefaultComplexityThresholds()).AnalyzeFile("p.py", []byte(code))
+	require.NoError(t, err)
+
+	byName := map[string]models.ComplexityMetric{}
+	for _, m := range metrics {
+		byName[m.FunctionName] = m
+	}
+
+	export := byName["export"]
+	require.Len(t, export.RefactoringSuggestions, 1)
+	assert.Equal(t, "replace_flag_argument", export.RefactoringSuggestions[0].Type)
+	assert.Contains(t, export.RefactoringSuggestions[0].Reason, "compress, header")
+
+	assert.Contains(t, suggestionTypes(byName["bounds"]), "split_return")
+	assert.Empty(t, byName["simple"].RefactoringSuggestions)
+}
+
+func TestGenerateSignatureSuggestions_PriorityRisesWithComplexity(t *testing.T) {
+	low := models.GenerateSignatureSuggestions([]string{"force"}, 0, 3)
+	high := models.GenerateSignatureSuggestions([]string{"force"}, 0, 14)
+
+	require.Len(t, low, 1)
+	require.Len(t, high, 1)
+	assert.Equal(t, "info", low[0].Priority)
+	assert.Equal(t, "medium", high[0].Priority)
+}
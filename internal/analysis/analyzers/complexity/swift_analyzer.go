@@ -27,8 +27,8 @@ func (a *SwiftAnalyzer) Language() string {
 func (a *SwiftAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(swift.GetLanguage())
+	parser := swiftParsers.get()
+	defer swiftParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -39,6 +39,10 @@ func (a *SwiftAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.C
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions, err := findSwiftFunctions(root, content)
@@ -51,7 +55,7 @@ func (a *SwiftAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.C
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
 
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
 		debtMinutes := estimateSwiftTechnicalDebt(cyclomatic, cognitive, loc)
 		suggestions := generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, fn.paramCount, loc)
 
@@ -75,6 +79,7 @@ func (a *SwiftAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.C
 			CodeSnippet:            &snippetStr,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
@@ -261,6 +266,7 @@ func generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCo
 
 	if cyclomatic > 15 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "extract_method",
 			Priority:    "high",
 			Title:       "Extract Methods",
 			Description: "Break down this function into smaller, focused methods. Consider using extension methods or protocols",
@@ -269,6 +275,7 @@ func generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCo
 
 	if nesting > 3 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "reduce_nesting",
 			Priority:    "high",
 			Title:       "Reduce Nesting Depth",
 			Description: "Use guard statements, optional chaining (?.), or early returns to reduce nesting",
@@ -277,6 +284,7 @@ func generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCo
 
 	if paramCount > 4 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "introduce_parameter_object",
 			Priority:    "medium",
 			Title:       "Use Struct or Builder Pattern",
 			Description: "Too many parameters. Consider using a struct with default values or a builder pattern",
@@ -285,6 +293,7 @@ func generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCo
 
 	if loc > 50 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "split_function",
 			Priority:    "high",
 			Title:       "Function Too Long",
 			Description: "Split this function into smaller methods. Consider extracting logic into extensions or separate types",
@@ -293,6 +302,7 @@ func generateSwiftRefactoringSuggestions(cyclomatic, cognitive, nesting, paramCo
 
 	if cognitive > 20 {
 		suggestions = append(suggestions, models.RefactoringSuggestion{
+			Type:        "simplify_logic",
 			Priority:    "medium",
 			Title:       "Simplify Logic",
 			Description: "Use Swift's Result type, optional chaining, guard statements, or functional methods (map, flatMap) to simplify logic",
@@ -28,8 +28,8 @@ func (a *TypeScriptAnalyzer) Language() string {
 func (a *TypeScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]models.ComplexityMetric, error) {
 	var metrics []models.ComplexityMetric
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(typescript.GetLanguage())
+	parser := typescriptParsers.get()
+	defer typescriptParsers.put(parser)
 
 	tree, err := parser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
@@ -40,6 +40,10 @@ func (a *TypeScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 	}
 	defer tree.Close()
 
+	if err := checkParseErrors(tree.RootNode()); err != nil {
+		return nil, err
+	}
+
 	root := tree.RootNode()
 
 	functions, err := findTypeScriptFunctions(root, content)
@@ -51,7 +55,9 @@ func (a *TypeScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 		nodes := mapTypeScriptNodes(fn.node, content)
 		cyclomatic, cognitive, nesting := CalculateComplexity(nodes)
 		loc := strings.Count(fn.body, "\n") + 1
-		severity := classifyComplexitySeverity(cyclomatic, cognitive, nesting)
+		severity := classifyComplexitySeverity(a.thresholds, cyclomatic, cognitive, nesting)
+		debtMinutes := estimateTechnicalDebt(cyclomatic, cognitive, loc)
+		returns, guards := countReturns(fn.node.ChildByFieldName("body"))
 		cognitivePtr := cognitive
 		snippetStr := truncateSnippet(fn.body, 10000)
 
@@ -67,9 +73,13 @@ func (a *TypeScriptAnalyzer) AnalyzeFile(filePath string, content []byte) ([]mod
 			ParameterCount:       fn.paramCount,
 			LinesOfCode:          loc,
 			Severity:             severity,
+			TechnicalDebtMinutes: debtMinutes,
 			CodeSnippet:          &snippetStr,
+			ReturnCount:          returns,
+			GuardReturnCount:     guards,
 		}
 
+		metric.Language = a.Language()
 		metrics = append(metrics, metric)
 	}
 
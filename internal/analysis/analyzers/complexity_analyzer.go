@@ -2,7 +2,10 @@ package analyzers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
@@ -21,17 +24,70 @@ import (
 type ComplexityAnalyzer struct {
 	factory         *complexity.Factory
 	complexityStore store.ComplexityStoreInterface
+	thresholds      models.ComplexityThresholds
+	thresholdsHash  string
 }
 
 // NewComplexityAnalyzer creates a new complexity analyzer
 func NewComplexityAnalyzer(complexityStore store.ComplexityStoreInterface) *ComplexityAnalyzer {
-	thresholds := models.DefaultComplexityThresholds()
-	factory := complexity.NewFactory(thresholds)
+	return NewLanguageComplexityAnalyzer(complexityStore, models.DefaultComplexityThresholds(), nil)
+}
 
+// NewLanguageComplexityAnalyzer creates a complexity analyzer that scores
+// functions with thresholds, except in the languages that have their own in
+// languages (see complexity.NewLanguageFactory). The ComplexityConfig of a
+// scan can add per-language thresholds too.
+func NewLanguageComplexityAnalyzer(complexityStore store.ComplexityStoreInterface, thresholds models.ComplexityThresholds, languages map[string]models.ComplexityThresholds) *ComplexityAnalyzer {
+	factory := complexity.NewLanguageFactory(thresholds, languages)
+
+	// Without per-language thresholds the key is the one earlier releases
+	// used, so their cache stays valid.
+	encoded, _ := json.Marshal(thresholds)
+	if len(languages) > 0 {
+		encoded, _ = json.Marshal(struct {
+			Thresholds models.ComplexityThresholds
+			Languages  map[string]models.ComplexityThresholds
+		}{thresholds, languages})
+	}
 	return &ComplexityAnalyzer{
 		factory:         factory,
 		complexityStore: complexityStore,
+		thresholds:      thresholds,
+		thresholdsHash:  analysis.HashFileContent(encoded)[:12],
+	}
+}
+
+// cacheKey identifies a file's raw metrics in the FileCache: the language
+// analyzer (chosen by extension), the thresholds it scores with and the file
+// content. Changing any of them misses the cache.
+func (a *ComplexityAnalyzer) cacheKey(path string, content []byte) string {
+	return fmt.Sprintf("complexity:%s:%s:%s", strings.ToLower(filepath.Ext(path)), a.thresholdsHash, analysis.HashFileContent(content))
+}
+
+// excludedDirs are never descended into by the complexity analysis: version
+// control metadata, dependency trees and interpreter caches.
+var excludedDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true,
+	".venv": true, "venv": true, "__pycache__": true,
+}
+
+// estimatedDebtLanguages are the languages whose analyzers estimate the debt
+// minutes of a function themselves. The other languages are scored on
+// cyclomatic complexity alone.
+var estimatedDebtLanguages = map[string]bool{
+	"JavaScript": true, "TypeScript": true, "Java": true, "C#": true, "Python": true,
+}
+
+// skippedDirReason returns why the complexity analysis skips the directory at
+// path, or "" when it is walked.
+func skippedDirReason(path string, ignore *git.IgnoreMatcher) string {
+	if excludedDirs[filepath.Base(path)] {
+		return "excluded by default"
+	}
+	if source := ignore.IgnoredBy(path, true); source != "" {
+		return "ignored by " + source
 	}
+	return ""
 }
 
 // Name returns the analyzer name
@@ -41,52 +97,81 @@ func (a *ComplexityAnalyzer) Name() string {
 
 // Analyze performs complexity analysis on the repository
 func (a *ComplexityAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
-	analysisRunID, ok := ctx.Value("analysisRunID").(uuid.UUID)
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("analysisRunID not found in context")
 	}
 
-	repositoryID, ok := ctx.Value("repositoryID").(uuid.UUID)
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("repositoryID not found in context")
 	}
 
-	userID, ok := ctx.Value("userID").(uuid.UUID)
+	userID, ok := analysis.UserIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("userID not found in context")
 	}
 
-	var targetFilesMap map[string]bool
-	if targetFiles, ok := ctx.Value("targetFiles").([]string); ok && len(targetFiles) > 0 {
-		targetFilesMap = make(map[string]bool)
-		for _, f := range targetFiles {
-			targetFilesMap[f] = true
-		}
+	if targetFiles, ok := analysis.TargetFilesFromContext(ctx); ok && len(targetFiles) > 0 && !analysis.IsCLI(ctx) {
 		log.Printf("🔬 Incremental analysis: targeting %d changed files", len(targetFiles))
 	}
 
-	config, ok := ctx.Value("complexityConfig").(models.ComplexityConfig)
+	config, ok := analysis.ComplexityConfigFromContext(ctx)
 	if !ok {
 		config = models.DefaultComplexityConfig()
 	}
+	if len(config.LanguageThresholds) > 0 {
+		a = NewLanguageComplexityAnalyzer(a.complexityStore, a.thresholds, config.LanguageThresholds)
+	}
+	maxFunctions := config.MaxFunctionsPerFile
+	if maxFunctions <= 0 {
+		maxFunctions = models.DefaultMaxFunctionsPerFile
+	}
+	maxReturns := config.MaxReturns
+	if maxReturns <= 0 {
+		maxReturns = models.DefaultMaxReturns
+	}
+	docs := config.RefactoringDocs
+	if docs == nil {
+		docs = models.DefaultRefactoringDocs
+	}
+	var outliers *outlierDetector
+	if config.OutlierDetection {
+		outliers = &outlierDetector{}
+	}
 
 	allMetrics := []models.ComplexityMetric{}
-
-	err := filepath.Walk(repo.Path, func(path string, info os.FileInfo, err error) error {
+	var minifiedIssues []models.TechnicalDebtIssue
+	var largeFileIssues []models.TechnicalDebtIssue
+	parseErrors := 0
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	cache := analysis.FileCacheFromContext(ctx)
+	baseContents, changedOnly := analysis.BaseContentsFromContext(ctx)
+	unchangedFunctions := 0
+	// skippedFiles counts the files left out of the analysis by reason.
+	skippedFiles := map[string]int{}
+
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info == nil {
+		// Checked per entry so a deadline stops the walk between files
+		// rather than after the whole tree has been parsed.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d == nil {
 			return nil
 		}
-		if info.IsDir() {
-			dirName := filepath.Base(path)
-			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" ||
-				dirName == ".venv" || dirName == "venv" || dirName == "__pycache__" {
+		if d.IsDir() {
+			if skippedDirReason(path, ignore) != "" {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
 
 		relPath, err := filepath.Rel(repo.Path, path)
 		if err != nil {
@@ -97,61 +182,137 @@ func (a *ComplexityAnalyzer) Analyze(ctx context.Context, repo *git.Repository)
 			relPath = "/" + relPath
 		}
 
-		if targetFilesMap != nil && !targetFilesMap[relPath] {
-			if ctx.Value("isCLI") != true {
-				log.Printf("🔍 Skipping %s - not in target files", relPath)
-			}
-			return nil
-		}
-
 		if !a.factory.IsSupported(path) {
-			if ctx.Value("isCLI") != true {
+			if !analysis.IsCLI(ctx) {
 				log.Printf("🔍 Skipping %s - unsupported file type", relPath)
 			}
+			skippedFiles["unsupported"]++
 			return nil
 		}
 
-		if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+		info, statErr := os.Stat(path)
+		if os.IsNotExist(statErr) {
+			return nil
+		}
+		if statErr == nil && info.Size() > analysis.MaxFileSize {
+			if !analysis.IsCLI(ctx) {
+				log.Printf("🔍 Skipping %s - larger than %d bytes", relPath, analysis.MaxFileSize)
+			}
+			skippedFiles["too_large"]++
 			return nil
 		}
 
 		content, err := ioutil.ReadFile(path)
 		if err != nil {
-			if ctx.Value("isCLI") != true {
+			if !analysis.IsCLI(ctx) {
 				log.Printf("⚠️  Failed to read file %s: %v", path, err)
 			}
 			return nil
 		}
 
+		// Minified bundles yield meaningless metrics and cost the most to
+		// parse, so they are reported as committed artifacts instead.
+		if file, ok := detectMinified(path, content, config.Minified); ok {
+			if !analysis.IsCLI(ctx) {
+				log.Printf("🔍 Skipping %s - minified", relPath)
+			}
+			minifiedIssues = append(minifiedIssues, minifiedIssue(userID, repositoryID, analysisRunID, relPath, file))
+			skippedFiles["minified"]++
+			return nil
+		}
+
+		// Nobody edits generated code, so its issues could not be fixed.
+		if !config.IncludeGenerated && isGenerated(relPath, content, config.GeneratedPatterns) {
+			if !analysis.IsCLI(ctx) {
+				log.Printf("🔍 Skipping %s - generated", relPath)
+			}
+			skippedFiles["generated"]++
+			return nil
+		}
+
 		analyzer, err := a.factory.GetAnalyzer(path)
 		if err != nil {
 			return nil
 		}
 
-		metrics, err := analyzer.AnalyzeFile(relPath, content)
-		if err != nil {
-			if ctx.Value("isCLI") != true {
-				log.Printf("⚠️  Failed to analyze file %s: %v", relPath, err)
+		// Cached metrics are the language analyzer's raw output, so the
+		// per-run stamping and debt recalculation below still apply to them.
+		var metrics []models.ComplexityMetric
+		cacheKey := a.cacheKey(path, content)
+		if cache.Get(cacheKey, &metrics) {
+			for i := range metrics {
+				metrics[i].FilePath = relPath
+			}
+		} else {
+			metrics, err = analyzer.AnalyzeFile(relPath, content)
+			if err != nil {
+				if errors.Is(err, complexity.ErrParseFailed) {
+					parseErrors++
+				}
+				skippedFiles["parse_error"]++
+				// A malformed notebook is skipped whole, so it is worth a
+				// warning even in CLI mode.
+				if errors.Is(err, complexity.ErrMalformedNotebook) {
+					log.Printf("⚠️  Skipping notebook %s: %v", relPath, err)
+					return nil
+				}
+				if !analysis.IsCLI(ctx) {
+					log.Printf("⚠️  Failed to analyze file %s: %v", relPath, err)
+				}
+				return nil
+			}
+			cache.Put(cacheKey, metrics)
+		}
+
+		// Counted before the changed-functions filter below, which would
+		// hide the functions a change left alone.
+		if summary := fileSummary(relPath, analyzer.Language(), metrics); summary.FunctionCount > maxFunctions {
+			largeFileIssues = append(largeFileIssues, tooManyFunctionsIssue(userID, repositoryID, analysisRunID, summary, maxFunctions, docs))
+		}
+		if outliers != nil {
+			outliers.add(metrics)
+		}
+
+		// Functions a change since the base ref left alone are not
+		// reported; a file that is new since then has nothing to compare.
+		if changedOnly {
+			if base, ok := baseContents[strings.TrimPrefix(filepath.ToSlash(relPath), "/")]; ok {
+				var skipped int
+				metrics, skipped = a.changedFunctions(path, relPath, content, metrics, base, analyzer, cache)
+				unchangedFunctions += skipped
 			}
-			return nil
 		}
 
 		if len(metrics) > 0 {
 			// Only log in non-CLI mode to avoid polluting TUI output
-			if ctx.Value("isCLI") != true {
+			if !analysis.IsCLI(ctx) {
 				log.Printf("🔍 Analyzed %s - found %d functions", relPath, len(metrics))
 			}
 		}
 
 		for i := range metrics {
+			if metrics[i].Language == "" {
+				metrics[i].Language = analyzer.Language()
+			}
 			metrics[i].ID = uuid.New()
 			metrics[i].UserID = userID
 			metrics[i].RepositoryID = repositoryID
 			metrics[i].AnalysisRunID = analysisRunID
-
-			// Recalculate debt based on dynamic configuration
+			metrics[i].Metadata = metrics[i].ReturnMetadata()
+			metrics[i].RefactoringSuggestions = append(metrics[i].RefactoringSuggestions,
+				models.GenerateReturnSuggestions(metrics[i].ReturnCount, metrics[i].GuardReturnCount, maxReturns)...)
+			models.AttachDocURLs(metrics[i].RefactoringSuggestions, docs)
+
+			// Recalculate debt based on dynamic configuration. Functions of the
+			// languages in estimatedDebtLanguages that are reported for
+			// cognitive load or nesting rather than cyclomatic complexity keep
+			// the language analyzer's own estimate; functions that are not
+			// reported carry no estimate.
 			debtHours := a.CalculateDebt(metrics[i].CyclomaticComplexity, config)
-			metrics[i].TechnicalDebtMinutes = int(debtHours * 60)
+			reported := metrics[i].Severity == "high" || metrics[i].Severity == "critical"
+			if debtHours > 0 || !reported || !estimatedDebtLanguages[metrics[i].Language] {
+				metrics[i].TechnicalDebtMinutes = int(debtHours * 60)
+			}
 		}
 
 		allMetrics = append(allMetrics, metrics...)
@@ -160,7 +321,7 @@ func (a *ComplexityAnalyzer) Analyze(ctx context.Context, repo *git.Repository)
 	})
 
 	if err != nil {
-		if ctx.Value("isCLI") != true {
+		if !analysis.IsCLI(ctx) {
 			log.Printf("❌ Error walking repository: %v", err)
 		}
 		return &analysis.Result{
@@ -169,27 +330,53 @@ func (a *ComplexityAnalyzer) Analyze(ctx context.Context, repo *git.Repository)
 		}, err
 	}
 
-	if ctx.Value("isCLI") != true {
-		log.Printf("✅ Analyzed %d functions across repository", len(allMetrics))
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Analyzed %d functions across repository (%d files skipped for parse errors)", len(allMetrics), parseErrors)
 	}
 
 	if config.AnalysisMode == "legacy" {
 		filtered := allMetrics[:0]
 		for _, m := range allMetrics {
-			switch m.FunctionName {
-			case "<anonymous>", "<lambda>", "<closure>", "<block>":
+			if anonymousFunction(m.FunctionName) {
 				continue
 			}
 			filtered = append(filtered, m)
 		}
-		if ctx.Value("isCLI") != true {
+		if !analysis.IsCLI(ctx) {
 			log.Printf("🔀 Legacy mode: filtered %d anonymous constructs from scoring", len(allMetrics)-len(filtered))
 		}
 		allMetrics = filtered
 	}
 
-	issues := a.convertToIssues(allMetrics)
+	issues := append(a.convertToIssues(repo.Path, allMetrics), minifiedIssues...)
+	issues = append(issues, largeFileIssues...)
+	var outlierIssues []models.TechnicalDebtIssue
+	if outliers != nil {
+		outlierIssues = outliers.issues(allMetrics)
+		issues = append(issues, outlierIssues...)
+	}
 	summary := a.calculateSummary(allMetrics)
+	summary["parse_errors"] = parseErrors
+	if changedOnly {
+		summary["complexity_unchanged_functions"] = unchangedFunctions
+	}
+	if len(minifiedIssues) > 0 {
+		summary["complexity_minified_files"] = len(minifiedIssues)
+	}
+	if len(largeFileIssues) > 0 {
+		summary["complexity_large_files"] = len(largeFileIssues)
+	}
+	if outliers != nil {
+		summary["complexity_outliers"] = len(outlierIssues)
+	}
+	if len(skippedFiles) > 0 {
+		summary["complexity_skipped_files"] = skippedFiles
+	}
+	if cache != nil {
+		hits, misses := cache.Stats()
+		summary["cache_hits"] = hits
+		summary["cache_misses"] = misses
+	}
 
 	return &analysis.Result{
 		Issues:  issues,
@@ -204,7 +391,7 @@ func (a *ComplexityAnalyzer) CalculateDebt(complexity int, config models.Complex
 	return float64((complexity-config.CyclomaticThreshold)*config.CostPerPoint) / 60.0
 }
 
-func (a *ComplexityAnalyzer) convertToIssues(metrics []models.ComplexityMetric) []models.TechnicalDebtIssue {
+func (a *ComplexityAnalyzer) convertToIssues(repoPath string, metrics []models.ComplexityMetric) []models.TechnicalDebtIssue {
 	issues := []models.TechnicalDebtIssue{}
 
 	for _, metric := range metrics {
@@ -222,7 +409,7 @@ func (a *ComplexityAnalyzer) convertToIssues(metrics []models.ComplexityMetric)
 			IssueType:          "complexity",
 			Severity:           metric.Severity,
 			Category:           "maintainability",
-			Message:            a.formatIssueMessage(metric),
+			Message:            a.formatIssueMessage(metric) + notebookLocation(metric),
 			Description:        a.formatIssueDescription(metric),
 			ToolName:           "complexity_analyzer",
 			ConfidenceScore:    1.0,
@@ -230,6 +417,16 @@ func (a *ComplexityAnalyzer) convertToIssues(metrics []models.ComplexityMetric)
 			EffortMultiplier:   1.0,
 			Status:             "open",
 			CodeSnippet:        metric.CodeSnippet,
+			Metadata:           a.issueMetadata(metric),
+		}
+
+		// Notebook lines count from the start of a cell, not of the file.
+		if metric.NotebookCell == nil {
+			surrounding := extractSurroundingContext(filepath.Join(repoPath, metric.FilePath),
+				metric.StartLine, metric.EndLine, surroundingContextPadding)
+			if surrounding != "" {
+				issue.SurroundingContext = &surrounding
+			}
 		}
 
 		issues = append(issues, issue)
@@ -238,6 +435,65 @@ func (a *ComplexityAnalyzer) convertToIssues(metrics []models.ComplexityMetric)
 	return issues
 }
 
+// issueMetadata exposes the raw metrics behind a complexity issue in
+// machine-readable form alongside the human-readable description.
+func (a *ComplexityAnalyzer) issueMetadata(metric models.ComplexityMetric) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"function_name":         metric.FunctionName,
+		"cyclomatic_complexity": metric.CyclomaticComplexity,
+		"nesting_depth":         metric.NestingDepth,
+		"parameter_count":       metric.ParameterCount,
+		"lines_of_code":         metric.LinesOfCode,
+		"start_line":            metric.StartLine,
+		"end_line":              metric.EndLine,
+	}
+	if metric.CognitiveComplexity != nil {
+		metadata["cognitive_complexity"] = *metric.CognitiveComplexity
+	}
+	if metric.NotebookCell != nil {
+		metadata["notebook_cell"] = *metric.NotebookCell
+	}
+	if metric.ReturnCount > 0 {
+		metadata["return_count"] = metric.ReturnCount
+		metadata["guard_return_count"] = metric.GuardReturnCount
+	}
+	if metric.Language != "" {
+		metadata["language"] = metric.Language
+		metadata["snippet_language"] = snippetLanguageTag(metric.Language)
+	}
+	if len(metric.RefactoringSuggestions) > 0 {
+		metadata["refactoring_suggestions"] = metric.RefactoringSuggestions
+	}
+	return metadata
+}
+
+// snippetLanguageTags maps analyzer language names to the identifiers that
+// markdown fences (```go) and highlighter classes (language-go) expect.
+var snippetLanguageTags = map[string]string{
+	"Go":          "go",
+	"JavaScript":  "javascript",
+	"TypeScript":  "typescript",
+	"Python":      "python",
+	"Java":        "java",
+	"C#":          "csharp",
+	"PHP":         "php",
+	"Ruby":        "ruby",
+	"Rust":        "rust",
+	"Kotlin":      "kotlin",
+	"Swift":       "swift",
+	"Objective-C": "objectivec",
+	"C/C++":       "cpp",
+}
+
+// snippetLanguageTag returns the highlight identifier for language, falling
+// back to its lower-cased name.
+func snippetLanguageTag(language string) string {
+	if tag, ok := snippetLanguageTags[language]; ok {
+		return tag
+	}
+	return strings.ToLower(language)
+}
+
 func (a *ComplexityAnalyzer) formatIssueMessage(metric models.ComplexityMetric) string {
 	if metric.CyclomaticComplexity > 20 {
 		return fmt.Sprintf("Function '%s' has critical cyclomatic complexity of %d (threshold: 20)",
@@ -254,6 +510,15 @@ func (a *ComplexityAnalyzer) formatIssueMessage(metric models.ComplexityMetric)
 	return fmt.Sprintf("Function '%s' has complexity issues", metric.FunctionName)
 }
 
+// notebookLocation locates a function found in a Jupyter notebook, whose
+// line numbers are only meaningful within the cell.
+func notebookLocation(metric models.ComplexityMetric) string {
+	if metric.NotebookCell == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (cell %d, line %d)", *metric.NotebookCell, metric.StartLine)
+}
+
 func (a *ComplexityAnalyzer) formatIssueDescription(metric models.ComplexityMetric) *string {
 	var parts []string
 
@@ -274,6 +539,9 @@ func (a *ComplexityAnalyzer) formatIssueDescription(metric models.ComplexityMetr
 		for _, suggestion := range metric.RefactoringSuggestions {
 			parts = append(parts, fmt.Sprintf("- [%s] %s: %s",
 				strings.ToUpper(suggestion.Priority), suggestion.Title, suggestion.Description))
+			if suggestion.DocURL != "" {
+				parts = append(parts, "  See: "+suggestion.DocURL)
+			}
 		}
 	}
 
@@ -285,6 +553,7 @@ func (a *ComplexityAnalyzer) calculateSummary(metrics []models.ComplexityMetric)
 	if len(metrics) == 0 {
 		return map[string]interface{}{
 			"complexity_functions_analyzed": 0,
+			"complexity_histogram":          models.NewComplexityHistogram(nil),
 		}
 	}
 
@@ -315,5 +584,6 @@ func (a *ComplexityAnalyzer) calculateSummary(metrics []models.ComplexityMetric)
 		"complexity_critical_functions": criticalCount,
 		"complexity_high_functions":     highCount,
 		"complexity_total_debt_hours":   float64(totalDebtMinutes) / 60.0,
+		"complexity_histogram":          models.NewComplexityHistogram(metrics),
 	}
 }
@@ -0,0 +1,262 @@
+package analyzers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnippetLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"Go":          "go",
+		"C#":          "csharp",
+		"C/C++":       "cpp",
+		"Objective-C": "objectivec",
+		"Elixir":      "elixir",
+	}
+	for language, want := range tests {
+		assert.Equal(t, want, snippetLanguageTag(language), language)
+	}
+}
+
+func TestIssueMetadata_Language(t *testing.T) {
+	a := NewComplexityAnalyzer(nil)
+
+	metadata := a.issueMetadata(models.ComplexityMetric{FunctionName: "handler", Language: "TypeScript"})
+	assert.Equal(t, "TypeScript", metadata["language"])
+	assert.Equal(t, "typescript", metadata["snippet_language"])
+
+	metadata = a.issueMetadata(models.ComplexityMetric{FunctionName: "handler"})
+	assert.NotContains(t, metadata, "snippet_language")
+}
+
+func complexityTestContext(t testing.TB, dir string) (context.Context, *git.Repository) {
+	absPath, err := filepath.Abs(dir)
+	require.NoError(t, err)
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+	return ctx, &git.Repository{FS: osfs.New(absPath), Path: absPath}
+}
+
+func TestComplexityAnalyzer_FileCache(t *testing.T) {
+	ctx, repo := complexityTestContext(t, "testdata/go/dirty")
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	run := func() *analysis.Result {
+		t.Helper()
+		cache, err := analysis.OpenFileCache(cachePath, "test")
+		require.NoError(t, err)
+		result, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithFileCache(ctx, cache), repo)
+		require.NoError(t, err)
+		require.NoError(t, cache.Save())
+		return result
+	}
+	messages := func(result *analysis.Result) []string {
+		var out []string
+		for _, issue := range result.Issues {
+			out = append(out, issue.FilePath+":"+issue.Message)
+		}
+		return out
+	}
+
+	cold := run()
+	require.NotEmpty(t, cold.Issues)
+	assert.Equal(t, 0, cold.Metrics["cache_hits"])
+
+	warm := run()
+	assert.Equal(t, cold.Metrics["cache_misses"], warm.Metrics["cache_hits"], "every file should be served from the cache")
+	assert.Equal(t, 0, warm.Metrics["cache_misses"])
+	assert.Equal(t, messages(cold), messages(warm))
+}
+
+func TestComplexityAnalyzer_ChangedFunctions(t *testing.T) {
+	source, err := os.ReadFile("testdata/go/dirty/complex.go")
+	require.NoError(t, err)
+	second := strings.Replace(string(source[strings.Index(string(source), "func "):]), "ComplexFunction", "OtherFunction", 1)
+	current := string(source) + "\n" + second
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "complex.go"), []byte(current), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "added.go"), []byte(current), 0644))
+	ctx, repo := complexityTestContext(t, dir)
+
+	// At the base OtherFunction printed something else; the copy in
+	// added.go did not exist.
+	base := strings.Replace(current, second, strings.Replace(second, `"fizz"`, `"buzz"`, 1), 1)
+	result, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithBaseContents(ctx, map[string][]byte{"complex.go": []byte(base)}), repo)
+	require.NoError(t, err)
+
+	var reported []string
+	for _, issue := range result.Issues {
+		reported = append(reported, issue.FilePath+":"+issue.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		"/complex.go:Function 'OtherFunction' has complexity issues",
+		"/added.go:Function 'ComplexFunction' has complexity issues",
+		"/added.go:Function 'OtherFunction' has complexity issues",
+	}, reported)
+	assert.Equal(t, 1, result.Metrics["complexity_unchanged_functions"])
+}
+
+// BenchmarkComplexityAnalyzer_FileCache compares a run that parses every file
+// with one where nothing changed since the cache was written.
+func BenchmarkComplexityAnalyzer_FileCache(b *testing.B) {
+	ctx, repo := complexityTestContext(b, "testdata")
+	cachePath := filepath.Join(b.TempDir(), "cache.json")
+
+	warmup, _ := analysis.OpenFileCache(cachePath, "bench")
+	if _, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithFileCache(ctx, warmup), repo); err != nil {
+		b.Fatal(err)
+	}
+	if err := warmup.Save(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("no-cache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unchanged", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cache, _ := analysis.OpenFileCache(cachePath, "bench")
+			if _, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithFileCache(ctx, cache), repo); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestComplexityAnalyzer_Notebook(t *testing.T) {
+	ctx, repo := complexityTestContext(t, "testdata/notebook")
+
+	result, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	// broken.ipynb is skipped with a warning.
+	require.Len(t, result.Issues, 1)
+	issue := result.Issues[0]
+	assert.Equal(t, "/analysis.ipynb", issue.FilePath)
+	assert.Contains(t, issue.Message, "Function 'classify'")
+	assert.Contains(t, issue.Message, "(cell 3, line 2)")
+	require.NotNil(t, issue.LineNumber)
+	assert.Equal(t, 2, *issue.LineNumber)
+	assert.Equal(t, 3, issue.Metadata["notebook_cell"])
+	assert.Nil(t, issue.SurroundingContext)
+}
+
+func TestComplexityAnalyzer_TooManyFunctions(t *testing.T) {
+	ctx, repo := complexityTestContext(t, "testdata/manyfunctions")
+
+	largeFiles := func(ctx context.Context) []models.TechnicalDebtIssue {
+		t.Helper()
+		result, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo)
+		require.NoError(t, err)
+		var issues []models.TechnicalDebtIssue
+		for _, issue := range result.Issues {
+			if issue.IssueType == "large_file" {
+				issues = append(issues, issue)
+			}
+		}
+		return issues
+	}
+
+	t.Run("default threshold", func(t *testing.T) {
+		issues := largeFiles(ctx)
+		require.Len(t, issues, 1)
+
+		issue := issues[0]
+		assert.Equal(t, "/handlers.py", issue.FilePath)
+		require.NotNil(t, issue.LineNumber)
+		assert.Equal(t, 1, *issue.LineNumber)
+		assert.Equal(t, "too_many_functions", *issue.ToolRuleID)
+		assert.Equal(t, "low", issue.Severity)
+		assert.Equal(t, "File defines 32 functions (threshold: 30); consider splitting it", issue.Message)
+		assert.Equal(t, 32, issue.Metadata["function_count"])
+
+		suggestions, ok := issue.Metadata["refactoring_suggestions"].([]models.RefactoringSuggestion)
+		require.True(t, ok)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, "split_file", suggestions[0].Type)
+		assert.Equal(t, "medium", suggestions[0].Priority)
+	})
+
+	t.Run("configured threshold", func(t *testing.T) {
+		issues := largeFiles(analysis.WithComplexityConfig(ctx, models.ComplexityConfig{CyclomaticThreshold: 10, MaxFunctionsPerFile: 10}))
+		require.Len(t, issues, 1)
+		assert.Equal(t, "medium", issues[0].Severity)
+		assert.Equal(t, "File defines 32 functions (threshold: 10); consider splitting it", issues[0].Message)
+
+		assert.Empty(t, largeFiles(analysis.WithComplexityConfig(ctx, models.ComplexityConfig{CyclomaticThreshold: 10, MaxFunctionsPerFile: 40})))
+	})
+}
+
+func TestComplexityAnalyzer_TooManyReturns(t *testing.T) {
+	ctx, repo := complexityTestContext(t, "testdata/returns")
+
+	statusIssue := func(ctx context.Context) models.TechnicalDebtIssue {
+		t.Helper()
+		result, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		return result.Issues[0]
+	}
+
+	issue := statusIssue(ctx)
+	assert.Equal(t, 23, issue.Metadata["return_count"])
+	assert.Equal(t, 0, issue.Metadata["guard_return_count"])
+	require.NotNil(t, issue.Description)
+	assert.Contains(t, *issue.Description, "- [HIGH] Reduce Return Points")
+
+	issue = statusIssue(analysis.WithComplexityConfig(ctx, models.ComplexityConfig{CyclomaticThreshold: 10, MaxReturns: 30}))
+	assert.NotContains(t, *issue.Description, "Reduce Return Points")
+}
+
+func TestComplexityAnalyzer_RefactoringDocs(t *testing.T) {
+	ctx, repo := complexityTestContext(t, "testdata/returns")
+
+	suggestion := func(ctx context.Context) (models.RefactoringSuggestion, string) {
+		t.Helper()
+		result, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		issue := result.Issues[0]
+		suggestions, ok := issue.Metadata["refactoring_suggestions"].([]models.RefactoringSuggestion)
+		require.True(t, ok)
+		for _, s := range suggestions {
+			if s.Type == "too_many_returns" {
+				require.NotNil(t, issue.Description)
+				return s, *issue.Description
+			}
+		}
+		t.Fatalf("no too_many_returns suggestion in %+v", suggestions)
+		return models.RefactoringSuggestion{}, ""
+	}
+
+	s, description := suggestion(ctx)
+	assert.Equal(t, models.DefaultRefactoringDocs["too_many_returns"], s.DocURL)
+	assert.Contains(t, description, "  See: "+s.DocURL)
+
+	docs := models.RefactoringDocs(map[string]string{"too_many_returns": "https://wiki.example.com/returns"})
+	s, description = suggestion(analysis.WithComplexityConfig(ctx, models.ComplexityConfig{CyclomaticThreshold: 10, RefactoringDocs: docs}))
+	assert.Equal(t, "https://wiki.example.com/returns", s.DocURL)
+	assert.Contains(t, description, "  See: https://wiki.example.com/returns")
+
+	docs = models.RefactoringDocs(map[string]string{"too_many_returns": ""})
+	s, description = suggestion(analysis.WithComplexityConfig(ctx, models.ComplexityConfig{CyclomaticThreshold: 10, RefactoringDocs: docs}))
+	assert.Empty(t, s.DocURL)
+	assert.NotContains(t, description, "See:")
+}
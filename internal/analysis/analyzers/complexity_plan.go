@@ -0,0 +1,88 @@
+package analyzers
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers/complexity"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+)
+
+// ComplexityPlan is the scope an Analyze call would cover, found by the same
+// walk without parsing anything.
+type ComplexityPlan struct {
+	// Languages counts the files to analyze per language.
+	Languages map[string]int `json:"languages"`
+	Files     int            `json:"files"`
+	// SkippedDirs lists the directories the walk does not enter, relative to
+	// the repository root.
+	SkippedDirs []SkippedDir `json:"skipped_dirs"`
+}
+
+// SkippedDir is a directory left out of the analysis and why.
+type SkippedDir struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// Plan walks repo as Analyze does, honoring the target files and ignore rules
+// in ctx, and reports which files would be analyzed. Only .m files are read,
+// to tell Objective-C from MATLAB. The .git directory is not listed.
+func (a *ComplexityAnalyzer) Plan(ctx context.Context, repo *git.Repository) (*ComplexityPlan, error) {
+	plan := &ComplexityPlan{Languages: map[string]int{}, SkippedDirs: []SkippedDir{}}
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d == nil {
+			return nil
+		}
+		if d.IsDir() {
+			reason := skippedDirReason(path, ignore)
+			if reason == "" {
+				return nil
+			}
+			if d.Name() != ".git" {
+				relPath, err := filepath.Rel(repo.Path, path)
+				if err != nil {
+					relPath = path
+				}
+				plan.SkippedDirs = append(plan.SkippedDirs, SkippedDir{Path: filepath.ToSlash(relPath), Reason: reason})
+			}
+			return filepath.SkipDir
+		}
+		if ignore.Ignored(path, false) || !a.factory.IsSupported(path) {
+			return nil
+		}
+
+		analyzer, err := a.factory.GetAnalyzer(path)
+		if err != nil {
+			return nil
+		}
+		language := analyzer.Language()
+		if _, ok := analyzer.(*complexity.MFileAnalyzer); ok {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			if !complexity.IsObjectiveC(content) {
+				language = "MATLAB"
+			}
+		}
+		plan.Languages[language]++
+		plan.Files++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
@@ -0,0 +1,46 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexityAnalyzer_Plan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".gitignore":             "build/\n",
+		"main.go":                "package main\n",
+		"lib/util.py":            "def f():\n    pass\n",
+		"lib/view.m":             "@interface View : NSObject\n@end\n",
+		"lib/solve.m":            "function x = solve(a)\n  x = a;\nend\n",
+		"README.md":              "# docs\n",
+		"build/out.go":           "package out\n",
+		"node_modules/dep/a.js":  "module.exports = 1;\n",
+		".git/hooks/pre-push.py": "print(1)\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	ctx, repo := complexityTestContext(t, dir)
+	matcher, err := git.NewIgnoreMatcher(repo.Path)
+	require.NoError(t, err)
+	ctx = analysis.WithIgnoreMatcher(ctx, matcher)
+
+	plan, err := NewComplexityAnalyzer(nil).Plan(ctx, repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"Go": 1, "Python": 1, "Objective-C": 1, "MATLAB": 1}, plan.Languages)
+	assert.Equal(t, 4, plan.Files)
+	assert.Equal(t, []SkippedDir{
+		{Path: "build", Reason: "ignored by .gitignore"},
+		{Path: "node_modules", Reason: "excluded by default"},
+	}, plan.SkippedDirs)
+}
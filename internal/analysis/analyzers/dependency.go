@@ -0,0 +1,389 @@
+package analyzers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// outdatedDependencyDebtHours is the estimated effort to pin or upgrade
+	// one dependency and verify the build.
+	outdatedDependencyDebtHours = 0.5
+	// stalePseudoVersionAge is how old the commit behind a Go pseudo-version
+	// may be before the dependency is reported as likely abandoned.
+	stalePseudoVersionAge = 3 * 365 * 24 * time.Hour
+)
+
+// pseudoVersionPattern captures the commit timestamp of a Go pseudo-version
+// such as v0.0.0-20190311183353-d8887717615a or v1.2.4-0.20190311183353-d8887717615a.
+var pseudoVersionPattern = regexp.MustCompile(`[.-](\d{14})-[0-9a-f]{12}$`)
+
+// requirementNamePattern matches the project name at the start of a
+// requirements.txt line, including an optional [extras] list.
+var requirementNamePattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?`)
+
+// unpinnedNpmRanges are package.json version ranges that accept any release.
+var unpinnedNpmRanges = map[string]bool{
+	"":       true,
+	"*":      true,
+	"x":      true,
+	"X":      true,
+	"latest": true,
+}
+
+// DependencyAnalyzer inspects package.json, go.mod and requirements.txt
+// manifests without network access. It reports dependencies with no version
+// constraint, Go modules pinned to pseudo-versions of commits older than three
+// years, and +incompatible Go modules that predate module support. A manifest
+// that cannot be parsed is skipped.
+type DependencyAnalyzer struct {
+	now func() time.Time
+}
+
+func NewDependencyAnalyzer() *DependencyAnalyzer {
+	return &DependencyAnalyzer{now: time.Now}
+}
+
+func (a *DependencyAnalyzer) Name() string {
+	return "DependencyAnalyzer"
+}
+
+// dependencyFinding is one reportable dependency of a manifest.
+type dependencyFinding struct {
+	line       int
+	name       string
+	version    string
+	rule       string
+	severity   string
+	confidence float64
+	message    string
+	detail     string
+}
+
+func (a *DependencyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	var manifests []string
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch d.Name() {
+		case "package.json", "go.mod", "requirements.txt":
+			if !ignore.Ignored(path, false) {
+				manifests = append(manifests, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(manifests)
+
+	issues := []models.TechnicalDebtIssue{}
+	skipped := 0
+	for _, path := range manifests {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		relPath, err := filepath.Rel(repo.Path, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = "/" + filepath.ToSlash(relPath)
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			skipped++
+			continue
+		}
+
+		var ecosystem string
+		var findings []dependencyFinding
+		switch filepath.Base(path) {
+		case "package.json":
+			ecosystem = "npm"
+			findings, err = checkPackageJSON(content)
+		case "go.mod":
+			ecosystem = "go"
+			findings = checkGoMod(content, a.now())
+		case "requirements.txt":
+			ecosystem = "pip"
+			findings = checkRequirements(content)
+		}
+		if err != nil {
+			skipped++
+			if !analysis.IsCLI(ctx) {
+				log.Printf("⚠️ [DependencyAnalyzer] Skipping malformed manifest %s: %v", relPath, err)
+			}
+			continue
+		}
+
+		for _, finding := range findings {
+			line := finding.line
+			ruleID := finding.rule
+			description := finding.detail
+			issues = append(issues, models.TechnicalDebtIssue{
+				ID:                 uuid.New(),
+				UserID:             userID,
+				RepositoryID:       repositoryID,
+				AnalysisRunID:      analysisRunID,
+				FilePath:           relPath,
+				LineNumber:         &line,
+				IssueType:          "outdated_dependency",
+				Severity:           finding.severity,
+				Category:           "maintenance",
+				Message:            finding.message,
+				Description:        &description,
+				ToolName:           "dependency",
+				ToolRuleID:         &ruleID,
+				ConfidenceScore:    finding.confidence,
+				TechnicalDebtHours: outdatedDependencyDebtHours,
+				EffortMultiplier:   1.0,
+				Status:             "open",
+				Metadata: map[string]interface{}{
+					"ecosystem":  ecosystem,
+					"dependency": finding.name,
+					"version":    finding.version,
+				},
+			})
+		}
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Dependency analysis checked %d manifests, found %d issues", len(manifests)-skipped, len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"manifests_scanned":           len(manifests) - skipped,
+			"manifests_skipped":           skipped,
+			"outdated_dependencies_count": len(issues),
+		},
+	}, nil
+}
+
+func unpinnedFinding(line int, name, version string) dependencyFinding {
+	shown := version
+	if shown == "" {
+		shown = "no version"
+	}
+	return dependencyFinding{
+		line:       line,
+		name:       name,
+		version:    version,
+		rule:       "unpinned-dependency",
+		severity:   "medium",
+		confidence: 0.9,
+		message:    fmt.Sprintf("Dependency %s is not pinned (%s)", name, shown),
+		detail:     fmt.Sprintf("%s accepts any release, so builds are not reproducible and a breaking upgrade can land unnoticed. Pin it to a version range.", name),
+	}
+}
+
+// checkPackageJSON reports dependencies of every package.json section whose
+// range accepts any version. Git, file and URL specifiers are left alone.
+func checkPackageJSON(content []byte) ([]dependencyFinding, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var findings []dependencyFinding
+	for _, section := range []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"} {
+		raw, ok := manifest[section]
+		if !ok {
+			continue
+		}
+		var deps map[string]string
+		if err := json.Unmarshal(raw, &deps); err != nil {
+			return nil, fmt.Errorf("%s: %w", section, err)
+		}
+
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sectionLine := findJSONKeyLine(lines, section, 0)
+		for _, name := range names {
+			version := strings.TrimSpace(deps[name])
+			if !unpinnedNpmRanges[version] {
+				continue
+			}
+			findings = append(findings, unpinnedFinding(findJSONKeyLine(lines, name, sectionLine), name, version))
+		}
+	}
+	return findings, nil
+}
+
+// findJSONKeyLine returns the 1-based line of the first "key": at or after
+// line from, falling back to from (or 1) when it cannot be located.
+func findJSONKeyLine(lines []string, key string, from int) int {
+	pattern := regexp.MustCompile(`"` + regexp.QuoteMeta(key) + `"\s*:`)
+	start := from - 1
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(lines); i++ {
+		if pattern.MatchString(lines[i]) {
+			return i + 1
+		}
+	}
+	if from > 0 {
+		return from
+	}
+	return 1
+}
+
+// checkGoMod reports require directives pinned to pseudo-versions of old
+// commits and +incompatible modules. go.mod always carries exact versions, so
+// there is no unpinned case.
+func checkGoMod(content []byte, now time.Time) []dependencyFinding {
+	var findings []dependencyFinding
+	inRequire := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) >= 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require" && len(fields) >= 3:
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) < 2 {
+			continue
+		}
+
+		module, version := fields[0], fields[1]
+		if finding, ok := checkGoModule(module, version, now); ok {
+			finding.line = lineNumber
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func checkGoModule(module, version string, now time.Time) (dependencyFinding, bool) {
+	if m := pseudoVersionPattern.FindStringSubmatch(version); m != nil {
+		committed, err := time.Parse("20060102150405", m[1])
+		if err == nil && now.Sub(committed) > stalePseudoVersionAge {
+			return dependencyFinding{
+				name:       module,
+				version:    version,
+				rule:       "stale-pseudo-version",
+				severity:   "low",
+				confidence: 0.7,
+				message:    fmt.Sprintf("Dependency %s is pinned to a commit from %s", module, committed.Format("2006-01-02")),
+				detail:     fmt.Sprintf("%s points at an untagged commit more than three years old, which usually means the module is abandoned or was never upgraded. Move to a tagged release or replace the module.", module),
+			}, true
+		}
+	}
+	if strings.HasSuffix(version, "+incompatible") {
+		return dependencyFinding{
+			name:       module,
+			version:    version,
+			rule:       "incompatible-module",
+			severity:   "low",
+			confidence: 0.6,
+			message:    fmt.Sprintf("Dependency %s predates Go modules (%s)", module, version),
+			detail:     fmt.Sprintf("%s is imported through a +incompatible version, so it has no go.mod of its own at that release. Upgrade to a module-aware major version if one exists.", module),
+		}, true
+	}
+	return dependencyFinding{}, false
+}
+
+// checkRequirements reports requirements.txt entries without a version
+// specifier. Options (-r, -e, --index-url), URLs and local paths are skipped.
+func checkRequirements(content []byte) []dependencyFinding {
+	var findings []dependencyFinding
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		// Environment markers do not constrain the version.
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		m := requirementNamePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		rest := strings.TrimSpace(line[len(m[0]):])
+		if rest == "" {
+			findings = append(findings, unpinnedFinding(lineNumber, m[1], ""))
+		}
+	}
+	return findings
+}
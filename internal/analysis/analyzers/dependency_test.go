@@ -0,0 +1,70 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyAnalyzer(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/dependencies")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	analyzer := NewDependencyAnalyzer()
+	analyzer.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	result, err := analyzer.Analyze(ctx, repo)
+	require.NoError(t, err, "a malformed manifest must not fail the analysis")
+
+	type found struct {
+		file string
+		line int
+		rule string
+		dep  string
+	}
+	var got []found
+	for _, issue := range result.Issues {
+		assert.Equal(t, "outdated_dependency", issue.IssueType)
+		assert.Equal(t, "maintenance", issue.Category)
+		require.NotNil(t, issue.LineNumber)
+		require.NotNil(t, issue.ToolRuleID)
+		got = append(got, found{issue.FilePath, *issue.LineNumber, *issue.ToolRuleID, issue.Metadata["dependency"].(string)})
+	}
+
+	assert.Equal(t, []found{
+		{"/svc/go.mod", 8, "stale-pseudo-version", "github.com/old/abandoned"},
+		{"/svc/go.mod", 10, "incompatible-module", "github.com/legacy/lib"},
+		{"/svc/requirements.txt", 3, "unpinned-dependency", "requests"},
+		{"/svc/requirements.txt", 5, "unpinned-dependency", "numpy"},
+		{"/web/package.json", 7, "unpinned-dependency", "left-pad"},
+		{"/web/package.json", 6, "unpinned-dependency", "lodash"},
+		{"/web/package.json", 10, "unpinned-dependency", "jest"},
+	}, got)
+	assert.Equal(t, 3, result.Metrics["manifests_scanned"])
+	assert.Equal(t, 1, result.Metrics["manifests_skipped"])
+}
+
+func TestCheckGoModule_RecentPseudoVersion(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	_, flagged := checkGoModule("example.com/m", "v1.2.4-0.20250101000000-abcdefabcdef", now)
+	assert.False(t, flagged)
+
+	finding, flagged := checkGoModule("example.com/m", "v1.2.4-0.20200101000000-abcdefabcdef", now)
+	require.True(t, flagged)
+	assert.Equal(t, "stale-pseudo-version", finding.rule)
+}
@@ -0,0 +1,105 @@
+package analyzers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// tooManyFunctionsRuleID is the tool rule of the large_file issues raised for
+// files holding too many functions.
+const tooManyFunctionsRuleID = "too_many_functions"
+
+// anonymousFunction reports whether name is one of the placeholders the
+// language analyzers give to unnamed constructs such as lambdas.
+func anonymousFunction(name string) bool {
+	switch name {
+	case "<anonymous>", "<lambda>", "<closure>", "<block>":
+		return true
+	}
+	return false
+}
+
+// fileSummary aggregates the metrics of the file at relPath. Only named
+// functions are counted: callbacks and lambdas are part of the function that
+// declares them, and do not make a file any harder to navigate.
+func fileSummary(relPath, language string, metrics []models.ComplexityMetric) models.FileComplexitySummary {
+	summary := models.FileComplexitySummary{FilePath: relPath, Language: language}
+	totalComplexity := 0
+	for _, m := range metrics {
+		if anonymousFunction(m.FunctionName) {
+			continue
+		}
+		summary.FunctionCount++
+		totalComplexity += m.CyclomaticComplexity
+		summary.MaxCyclomaticComplexity = max(summary.MaxCyclomaticComplexity, m.CyclomaticComplexity)
+		summary.TotalLinesOfCode += m.LinesOfCode
+	}
+	if summary.FunctionCount > 0 {
+		summary.AvgCyclomaticComplexity = float64(totalComplexity) / float64(summary.FunctionCount)
+	}
+	return summary
+}
+
+// tooManyFunctionsIssue flags a file that holds more than maxFunctions
+// functions, a file-level smell distinct from a long function: each function
+// can be simple while the file as a whole has too many responsibilities. It
+// is attributed to the first line of the file. Its suggestions link to the
+// pages docs maps their types to.
+func tooManyFunctionsIssue(userID, repositoryID, analysisRunID uuid.UUID, summary models.FileComplexitySummary, maxFunctions int, docs map[string]string) models.TechnicalDebtIssue {
+	ruleID := tooManyFunctionsRuleID
+	line := 1
+	suggestions := models.GenerateFileSuggestions(summary, maxFunctions)
+	models.AttachDocURLs(suggestions, docs)
+	severity := "low"
+	if len(suggestions) > 0 && suggestions[0].Priority == "high" {
+		severity = "medium"
+	}
+
+	parts := []string{
+		fmt.Sprintf("Functions: %d (threshold: %d)", summary.FunctionCount, maxFunctions),
+		fmt.Sprintf("Average Cyclomatic Complexity: %.1f", summary.AvgCyclomaticComplexity),
+		fmt.Sprintf("Lines of Code in Functions: %d", summary.TotalLinesOfCode),
+		"\nRefactoring Suggestions:",
+	}
+	for _, suggestion := range suggestions {
+		parts = append(parts, fmt.Sprintf("- [%s] %s: %s",
+			strings.ToUpper(suggestion.Priority), suggestion.Title, suggestion.Description))
+		if suggestion.DocURL != "" {
+			parts = append(parts, "  See: "+suggestion.DocURL)
+		}
+	}
+	description := strings.Join(parts, "\n")
+
+	// Splitting a file mostly means moving code and fixing imports, so the
+	// effort grows slowly with the number of functions over the threshold.
+	excess := summary.FunctionCount - maxFunctions
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           summary.FilePath,
+		LineNumber:         &line,
+		IssueType:          "large_file",
+		Severity:           severity,
+		Category:           "maintainability",
+		Message:            fmt.Sprintf("File defines %d functions (threshold: %d); consider splitting it", summary.FunctionCount, maxFunctions),
+		Description:        &description,
+		ToolName:           "complexity_analyzer",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    0.7,
+		TechnicalDebtHours: 1.0 + 0.1*float64(excess),
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata: map[string]interface{}{
+			"function_count":            summary.FunctionCount,
+			"max_functions_per_file":    maxFunctions,
+			"avg_cyclomatic_complexity": summary.AvgCyclomaticComplexity,
+			"language":                  summary.Language,
+			"refactoring_suggestions":   suggestions,
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package analyzers
+
+import (
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/go-enry/go-enry/v2"
+)
+
+// isGenerated reports whether the file at relPath is generated code: go-enry
+// recognizes it by its name or content (e.g. a "Code generated ... DO NOT
+// EDIT." header), or it matches DefaultGeneratedPatterns or one of patterns.
+func isGenerated(relPath string, content []byte, patterns []string) bool {
+	relPath = strings.TrimLeft(relPath, "/")
+	if enry.IsGenerated(relPath, content) {
+		return true
+	}
+	for _, list := range [][]string{models.DefaultGeneratedPatterns, patterns} {
+		for _, pattern := range list {
+			if analysis.MatchPathPattern(pattern, relPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package analyzers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// branchyGoSource is a Go file with one function of cyclomatic complexity 16.
+func branchyGoSource(header string) string {
+	var b strings.Builder
+	b.WriteString(header + "package api\n\nfunc Code(kind int) string {\n\tswitch kind {\n")
+	for i := 0; i < 15; i++ {
+		fmt.Fprintf(&b, "\tcase %d:\n\t\treturn \"k%d\"\n", i, i)
+	}
+	b.WriteString("\t}\n\treturn \"\"\n}\n")
+	return b.String()
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		patterns []string
+		want     bool
+	}{
+		{"hand-written", "/api/handler.go", branchyGoSource(""), nil, false},
+		{"generated marker", "/api/queries.go", branchyGoSource("// Code generated by sqlc. DO NOT EDIT.\n\n"), nil, true},
+		{"protobuf", "/api/api.pb.go", branchyGoSource(""), nil, true},
+		{"go generate", "/api/enum_gen.go", branchyGoSource(""), nil, true},
+		{"dart build_runner", "/lib/user.g.dart", "class User {}\n", nil, true},
+		{"generated infix", "/web/client.generated.ts", "export {}\n", nil, true},
+		{"configured pattern", "/internal/schema/tables.go", branchyGoSource(""), []string{"internal/schema/**"}, true},
+		{"configured pattern elsewhere", "/api/tables.go", branchyGoSource(""), []string{"internal/schema/**"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isGenerated(tt.path, []byte(tt.content), tt.patterns))
+		})
+	}
+}
+
+func TestComplexityAnalyzer_SkipsGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"handler.go": branchyGoSource(""),
+		"queries.go": branchyGoSource("// Code generated by sqlc. DO NOT EDIT.\n\n"),
+		"api.pb.go":  branchyGoSource(""),
+		"tables.go":  branchyGoSource(""),
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	ctx, repo := complexityTestContext(t, dir)
+	reported := func(result *analysis.Result) []string {
+		var paths []string
+		for _, issue := range result.Issues {
+			if !slices.Contains(paths, issue.FilePath) {
+				paths = append(paths, issue.FilePath)
+			}
+		}
+		return paths
+	}
+
+	config := models.DefaultComplexityConfig()
+	config.GeneratedPatterns = []string{"tables.go"}
+	result, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithComplexityConfig(ctx, config), repo)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/handler.go"}, reported(result))
+	assert.Equal(t, map[string]int{"generated": 3}, result.Metrics["complexity_skipped_files"])
+
+	config.IncludeGenerated = true
+	result, err = NewComplexityAnalyzer(nil).Analyze(analysis.WithComplexityConfig(ctx, config), repo)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/handler.go", "/queries.go", "/api.pb.go", "/tables.go"}, reported(result))
+
+	// Generated files still count in the language statistics.
+	lines, err := NewLineCounter().Analyze(ctx, repo)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, lines.Metrics["languages"].(map[string]LanguageLineStats)["Go"].Files)
+}
@@ -0,0 +1,348 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// deadCodeDebtHours is the estimated effort to confirm and delete one
+	// unused function.
+	deadCodeDebtHours = 0.25
+	// deadCodeConfidence stays below 1.0: calls through reflection, code
+	// generated at build time or files excluded by build tags elsewhere in
+	// the module cannot be seen.
+	deadCodeConfidence = 0.7
+)
+
+var (
+	// linknamePattern matches //go:linkname directives; the first name is the
+	// local function that may be called from another package.
+	linknamePattern = regexp.MustCompile(`^//go:linkname\s+(\S+)`)
+	// cgoExportPattern matches cgo //export directives, which expose a
+	// function to C.
+	cgoExportPattern = regexp.MustCompile(`^//export\s+(\S+)`)
+	// generatedPattern is the standard marker of generated Go files.
+	generatedPattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+)
+
+// GoDeadCodeAnalyzer flags unexported Go functions and methods that nothing
+// in their package refers to. An unexported identifier can only be used from
+// its own package, so each package is resolved on its own; in-package test
+// files count as callers but are not reported themselves.
+//
+// References are matched by name from go/ast rather than through go/types,
+// so files for every build configuration are considered and a name used
+// anywhere else in the package (a call, a method value, an interface method
+// the method may satisfy) keeps the function alive. This errs on the side of
+// missing dead code rather than reporting live code, and the analyzer is
+// opt-in because reflection and assembly can still use what it reports.
+type GoDeadCodeAnalyzer struct{}
+
+func NewGoDeadCodeAnalyzer() *GoDeadCodeAnalyzer {
+	return &GoDeadCodeAnalyzer{}
+}
+
+func (a *GoDeadCodeAnalyzer) Name() string {
+	return "GoDeadCodeAnalyzer"
+}
+
+func (a *GoDeadCodeAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	packageDirs := map[string]bool{}
+	reported := map[string]bool{}
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "testdata":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") && !ignore.Ignored(path, false) {
+			packageDirs[filepath.Dir(path)] = true
+			reported[path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(packageDirs))
+	for dir := range packageDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	issues := []models.TechnicalDebtIssue{}
+	for _, dir := range dirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// The whole directory is read even when only some files are
+		// targeted, since callers may live in any file of the package.
+		for _, fn := range findDeadFunctions(goPackageSources(dir, ignore)) {
+			if !reported[fn.file] {
+				continue
+			}
+			relPath, err := filepath.Rel(repo.Path, fn.file)
+			if err != nil {
+				relPath = fn.file
+			}
+			if !strings.HasPrefix(relPath, "/") {
+				relPath = "/" + relPath
+			}
+
+			line := fn.line
+			ruleID := "deadcode"
+			kind := "Function"
+			if strings.Contains(fn.name, ".") {
+				kind = "Method"
+			}
+			description := fmt.Sprintf("%s %s is unexported and nothing in its package refers to it. Delete it, or suppress the finding if it is used through reflection, assembly or a build configuration that was not scanned.", kind, fn.name)
+			issues = append(issues, models.TechnicalDebtIssue{
+				ID:                 uuid.New(),
+				UserID:             userID,
+				RepositoryID:       repositoryID,
+				AnalysisRunID:      analysisRunID,
+				FilePath:           filepath.ToSlash(relPath),
+				LineNumber:         &line,
+				IssueType:          "dead_code",
+				Severity:           "low",
+				Category:           "maintainability",
+				Message:            fmt.Sprintf("%s %s is never used", kind, fn.name),
+				Description:        &description,
+				ToolName:           "go_deadcode",
+				ToolRuleID:         &ruleID,
+				ConfidenceScore:    deadCodeConfidence,
+				TechnicalDebtHours: deadCodeDebtHours,
+				EffortMultiplier:   1.0,
+				Status:             "open",
+			})
+		}
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Go dead code check found %d unused functions", len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"dead_functions_count": len(issues),
+		},
+	}, nil
+}
+
+// goPackageSources lists the Go files of the package in dir, tests included.
+func goPackageSources(dir string, ignore *git.IgnoreMatcher) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type().IsRegular() && strings.HasSuffix(path, ".go") && !ignore.Ignored(path, false) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+type deadFunction struct {
+	file string
+	line int
+	// name is "fn" for functions and "T.fn" for methods.
+	name string
+}
+
+type deadCodeCandidate struct {
+	deadFunction
+	ident *ast.Ident
+	decl  *ast.FuncDecl
+}
+
+// findDeadFunctions parses the Go files of one directory and returns the
+// unexported functions and methods of its non-test files whose name is not
+// used anywhere in the package outside their own body. Files of an external
+// test package (package x_test) cannot use unexported names and are skipped;
+// files that do not parse are skipped too, which can only hide findings in
+// the files that do not parse.
+func findDeadFunctions(paths []string) []deadFunction {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	pkgName := ""
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		name := file.Name.Name
+		if pkgName == "" && !strings.HasSuffix(name, "_test") {
+			pkgName = name
+		}
+		files = append(files, file)
+	}
+	if pkgName == "" {
+		return nil
+	}
+
+	var candidates []deadCodeCandidate
+	uses := map[string][]token.Pos{}
+	for _, file := range files {
+		if file.Name.Name != pkgName {
+			continue
+		}
+		filename := fset.Position(file.Pos()).Filename
+		reportable := !strings.HasSuffix(filename, "_test.go") && !isGeneratedFile(file)
+		keep := directiveNames(file)
+
+		declared := map[*ast.Ident]bool{}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			declared[fn.Name] = true
+			if !reportable || !isDeadCodeCandidate(pkgName, fn) || keep[fn.Name.Name] {
+				continue
+			}
+			name := fn.Name.Name
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				name = receiverTypeName(fn.Recv.List[0].Type) + "." + name
+			}
+			candidates = append(candidates, deadCodeCandidate{
+				deadFunction: deadFunction{file: filename, line: fset.Position(fn.Pos()).Line, name: name},
+				ident:        fn.Name,
+				decl:         fn,
+			})
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && !declared[ident] {
+				uses[ident.Name] = append(uses[ident.Name], ident.Pos())
+			}
+			return true
+		})
+	}
+
+	var dead []deadFunction
+	for _, candidate := range candidates {
+		used := false
+		for _, pos := range uses[candidate.ident.Name] {
+			// Recursive calls do not keep a function alive.
+			if pos < candidate.decl.Pos() || pos >= candidate.decl.End() {
+				used = true
+				break
+			}
+		}
+		if !used {
+			dead = append(dead, candidate.deadFunction)
+		}
+	}
+	return dead
+}
+
+// isDeadCodeCandidate excludes exported API, init, main in package main,
+// blank functions and functions implemented outside Go (no body).
+func isDeadCodeCandidate(pkgName string, fn *ast.FuncDecl) bool {
+	name := fn.Name.Name
+	switch {
+	case fn.Body == nil, ast.IsExported(name), name == "_":
+		return false
+	case fn.Recv == nil && name == "init":
+		return false
+	case fn.Recv == nil && name == "main" && pkgName == "main":
+		return false
+	}
+	return true
+}
+
+// receiverTypeName returns the name of a method's receiver type, without
+// pointer or type parameters.
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return "?"
+}
+
+// directiveNames returns the local names exposed to other packages or to C
+// by //go:linkname and //export directives in file.
+func directiveNames(file *ast.File) map[string]bool {
+	names := map[string]bool{}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if m := linknamePattern.FindStringSubmatch(c.Text); m != nil {
+				names[m[1]] = true
+			}
+			if m := cgoExportPattern.FindStringSubmatch(c.Text); m != nil {
+				names[m[1]] = true
+			}
+		}
+	}
+	return names
+}
+
+// isGeneratedFile reports whether file carries the standard "Code generated
+// ... DO NOT EDIT." comment before its package clause.
+func isGeneratedFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if generatedPattern.MatchString(c.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoDeadCodeAnalyzer(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/deadcode")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	result, err := NewGoDeadCodeAnalyzer().Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, issue := range result.Issues {
+		assert.Equal(t, "dead_code", issue.IssueType)
+		assert.Equal(t, "low", issue.Severity)
+		assert.Less(t, issue.ConfidenceScore, 1.0)
+		require.NotNil(t, issue.LineNumber)
+		messages = append(messages, issue.FilePath+": "+issue.Message)
+	}
+	sort.Strings(messages)
+
+	// main, init, exported, linknamed, interface and test-only callees, test
+	// helpers and generated files are all excluded.
+	assert.Equal(t, []string{
+		"/lib/lib.go: Method Stack.reset is never used",
+		"/main.go: Function recursive is never used",
+		"/main.go: Function unused is never used",
+		"/main.go: Method english.unusedMethod is never used",
+	}, messages)
+	assert.Equal(t, 4, result.Metrics["dead_functions_count"])
+}
+
+func TestGoDeadCodeAnalyzer_TargetFiles(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/deadcode")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+	ctx = analysis.WithTargetFiles(ctx, []string{"lib/lib.go"})
+
+	result, err := NewGoDeadCodeAnalyzer().Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "/lib/lib.go", result.Issues[0].FilePath)
+}
@@ -0,0 +1,387 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// ignoredErrorDebtHours is the estimated effort to handle one ignored error.
+const ignoredErrorDebtHours = 0.25
+
+// nolintPattern matches golangci-lint style suppressions that apply to
+// errcheck: a bare //nolint or a //nolint list naming errcheck.
+var nolintPattern = regexp.MustCompile(`^//\s*nolint(?::([\w,\s-]+))?\b`)
+
+// errcheckExclusions are calls whose error result is conventionally ignored
+// because it can never be non-nil or is not actionable.
+var errcheckExclusions = map[string]bool{
+	"fmt.Print":                      true,
+	"fmt.Printf":                     true,
+	"fmt.Println":                    true,
+	"(*bytes.Buffer).Write":          true,
+	"(*bytes.Buffer).WriteByte":      true,
+	"(*bytes.Buffer).WriteRune":      true,
+	"(*bytes.Buffer).WriteString":    true,
+	"(*strings.Builder).Write":       true,
+	"(*strings.Builder).WriteByte":   true,
+	"(*strings.Builder).WriteRune":   true,
+	"(*strings.Builder).WriteString": true,
+}
+
+// GoErrorCheckAnalyzer flags Go call sites that discard an error result:
+// `_ = f()`, `x, _ := f()` and error-returning calls used as statements.
+// Calls are resolved with go/types, so only functions whose signature is
+// known to return an error are reported. Imports of the repository's own
+// packages resolve within their go.mod module or go.work workspace.
+type GoErrorCheckAnalyzer struct{}
+
+func NewGoErrorCheckAnalyzer() *GoErrorCheckAnalyzer {
+	return &GoErrorCheckAnalyzer{}
+}
+
+func (a *GoErrorCheckAnalyzer) Name() string {
+	return "GoErrorCheckAnalyzer"
+}
+
+func (a *GoErrorCheckAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	packageDirs := map[string][]string{}
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "testdata":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") && !ignore.Ignored(path, false) {
+			dir := filepath.Dir(path)
+			packageDirs[dir] = append(packageDirs[dir], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// When only some files are targeted, their packages are still
+	// type-checked whole so calls into the rest of the package resolve, but
+	// only the targeted files are reported.
+	var reported map[string]bool
+	if targets, ok := analysis.TargetFilesFromContext(ctx); ok && len(targets) > 0 {
+		reported = map[string]bool{}
+		for dir, paths := range packageDirs {
+			for _, path := range paths {
+				reported[path] = true
+			}
+			packageDirs[dir] = goPackageFiles(dir, ignore)
+		}
+	}
+
+	dirs := make([]string, 0, len(packageDirs))
+	for dir := range packageDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	fset := token.NewFileSet()
+	// One standard importer for the whole scan so imported packages are
+	// type-checked once. Packages of the repository's own modules resolve
+	// through one importer per module, so a multi-module tree or go.work
+	// workspace never mixes up two modules that share import paths.
+	std := importer.ForCompiler(fset, "source", nil)
+	modules := findGoModules(repo.Path, ignore)
+	importers := map[string]types.Importer{}
+	importerFor := func(dir string) types.Importer {
+		module := modules.moduleFor(dir)
+		if module == nil {
+			return std
+		}
+		imp, ok := importers[module.root]
+		if !ok {
+			imp = newModuleImporter(fset, modules, module, ignore, std)
+			importers[module.root] = imp
+		}
+		return imp
+	}
+
+	issues := []models.TechnicalDebtIssue{}
+	for _, dir := range dirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, site := range checkPackageErrors(fset, importerFor(dir), packageDirs[dir]) {
+			if reported != nil && !reported[site.file] {
+				continue
+			}
+			relPath, err := filepath.Rel(repo.Path, site.file)
+			if err != nil {
+				relPath = site.file
+			}
+			if !strings.HasPrefix(relPath, "/") {
+				relPath = "/" + relPath
+			}
+
+			line := site.line
+			ruleID := "errcheck"
+			description := fmt.Sprintf("The error returned by %s is discarded. Handle it, return it, or annotate the call with //nolint:errcheck if ignoring it is deliberate.", site.callee)
+			issues = append(issues, models.TechnicalDebtIssue{
+				ID:                 uuid.New(),
+				UserID:             userID,
+				RepositoryID:       repositoryID,
+				AnalysisRunID:      analysisRunID,
+				FilePath:           filepath.ToSlash(relPath),
+				LineNumber:         &line,
+				IssueType:          "ignored_error",
+				Severity:           "medium",
+				Category:           "reliability",
+				Message:            fmt.Sprintf("Error returned by %s is ignored", site.callee),
+				Description:        &description,
+				ToolName:           "go_errcheck",
+				ToolRuleID:         &ruleID,
+				ConfidenceScore:    1.0,
+				TechnicalDebtHours: ignoredErrorDebtHours,
+				EffortMultiplier:   1.0,
+				Status:             "open",
+			})
+		}
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Go error check found %d ignored errors", len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"ignored_errors_count": len(issues),
+		},
+	}, nil
+}
+
+type ignoredErrorSite struct {
+	file   string
+	line   int
+	callee string
+}
+
+// goPackageFiles lists the non-test Go files of the package in dir.
+func goPackageFiles(dir string, ignore *git.IgnoreMatcher) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type().IsRegular() && strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") && !ignore.Ignored(path, false) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// checkPackageErrors type-checks the files of one package directory and
+// returns every call site that discards an error. Type errors (e.g. imports
+// that cannot be resolved) are tolerated: unresolved calls are simply not
+// reported, so partial type information never yields false positives.
+func checkPackageErrors(fset *token.FileSet, imp types.Importer, paths []string) []ignoredErrorSite {
+	var files []*ast.File
+	pkgName := ""
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		if pkgName == "" {
+			pkgName = file.Name.Name
+		}
+		if file.Name.Name != pkgName {
+			continue
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{
+		Importer:    imp,
+		Error:       func(error) {},
+		FakeImportC: true,
+	}
+	_, _ = conf.Check(pkgName, fset, files, info)
+
+	var sites []ignoredErrorSite
+	for _, file := range files {
+		suppressed := nolintLines(fset, file)
+
+		report := func(call *ast.CallExpr) {
+			pos := fset.Position(call.Pos())
+			if suppressed[pos.Line] {
+				return
+			}
+			callee := calleeName(info, call)
+			if errcheckExclusions[callee] {
+				return
+			}
+			sites = append(sites, ignoredErrorSite{file: pos.Filename, line: pos.Line, callee: callee})
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch stmt := n.(type) {
+			case *ast.ExprStmt:
+				call, ok := stmt.X.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				results := callResults(info, call)
+				if results != nil && results.Len() > 0 && isErrorType(results.At(results.Len()-1).Type()) {
+					report(call)
+				}
+			case *ast.AssignStmt:
+				if len(stmt.Rhs) != 1 {
+					// a, b = f(), g(): each RHS is single-valued.
+					for i, rhs := range stmt.Rhs {
+						call, ok := rhs.(*ast.CallExpr)
+						if !ok || i >= len(stmt.Lhs) || !isBlank(stmt.Lhs[i]) {
+							continue
+						}
+						if results := callResults(info, call); results != nil && results.Len() == 1 && isErrorType(results.At(0).Type()) {
+							report(call)
+						}
+					}
+					return true
+				}
+				call, ok := stmt.Rhs[0].(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				results := callResults(info, call)
+				if results == nil || results.Len() != len(stmt.Lhs) {
+					return true
+				}
+				for i, lhs := range stmt.Lhs {
+					if isBlank(lhs) && isErrorType(results.At(i).Type()) {
+						report(call)
+						break
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return sites
+}
+
+// callResults returns the result tuple of a call, or nil when the callee's
+// type is unknown or the expression is a conversion.
+func callResults(info *types.Info, call *ast.CallExpr) *types.Tuple {
+	tv, ok := info.Types[call.Fun]
+	if !ok || tv.IsType() || tv.IsBuiltin() {
+		return nil
+	}
+	sig, ok := tv.Type.Underlying().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	return sig.Results()
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// calleeName renders the called function as "pkg.Func" or "(*pkg.T).Method".
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	var ident *ast.Ident
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fn
+	case *ast.SelectorExpr:
+		ident = fn.Sel
+	}
+	if ident != nil {
+		if f, ok := info.Uses[ident].(*types.Func); ok {
+			return f.FullName()
+		}
+		return ident.Name
+	}
+	return "function call"
+}
+
+// nolintLines returns the lines carrying a //nolint comment that applies to
+// errcheck.
+func nolintLines(fset *token.FileSet, file *ast.File) map[int]bool {
+	lines := map[int]bool{}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			m := nolintPattern.FindStringSubmatch(c.Text)
+			if m == nil {
+				continue
+			}
+			if m[1] != "" && !nolintNamesErrcheck(m[1]) {
+				continue
+			}
+			lines[fset.Position(c.Pos()).Line] = true
+		}
+	}
+	return lines
+}
+
+func nolintNamesErrcheck(linters string) bool {
+	for _, name := range strings.Split(linters, ",") {
+		if strings.TrimSpace(name) == "errcheck" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoErrorCheckAnalyzer(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/errcheck")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	result, err := NewGoErrorCheckAnalyzer().Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	var lines []int
+	callees := map[int]string{}
+	for _, issue := range result.Issues {
+		assert.Equal(t, "ignored_error", issue.IssueType)
+		assert.Equal(t, "reliability", issue.Category)
+		assert.Equal(t, "medium", issue.Severity)
+		assert.Equal(t, "/main.go", issue.FilePath)
+		require.NotNil(t, issue.LineNumber)
+		lines = append(lines, *issue.LineNumber)
+		callees[*issue.LineNumber] = issue.Message
+	}
+	sort.Ints(lines)
+
+	// Lines 19-23 discard errors; line 46 uses a //nolint for a different linter.
+	assert.Equal(t, []int{19, 20, 21, 22, 23, 46}, lines)
+	assert.Equal(t, "Error returned by os.Remove is ignored", callees[23])
+	assert.Equal(t, 6, result.Metrics["ignored_errors_count"])
+}
+
+func TestGoErrorCheckAnalyzer_Modules(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/gomodules")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	result, err := NewGoErrorCheckAnalyzer().Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	messages := map[string]string{}
+	for _, issue := range result.Issues {
+		messages[issue.FilePath] = issue.Message
+	}
+	// a and b both declare example.com/app, but only a's lib.Load returns
+	// an error; svc resolves example.com/shared through the go.work.
+	assert.Equal(t, map[string]string{
+		"/a/main.go":        "Error returned by example.com/app/lib.Load is ignored",
+		"/work/svc/main.go": "Error returned by example.com/shared.Sync is ignored",
+		"/loose.go":         "Error returned by os.Remove is ignored",
+	}, messages)
+}
+
+func TestFindGoModules(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/gomodules")
+	require.NoError(t, err)
+
+	modules := findGoModules(absPath, nil)
+	require.Len(t, modules, 4)
+	assert.Equal(t, "example.com/shared", modules[2].path)
+	assert.Equal(t, filepath.Join(absPath, "work"), modules[2].workspace)
+	assert.Empty(t, modules[0].workspace)
+
+	assert.Equal(t, filepath.Join(absPath, "a"), modules.moduleFor(filepath.Join(absPath, "a", "lib")).root)
+	assert.Nil(t, modules.moduleFor(absPath))
+	assert.Len(t, modules.visibleFrom(&modules[3]), 2)
+}
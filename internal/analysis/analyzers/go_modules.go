@@ -0,0 +1,242 @@
+package analyzers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+)
+
+// goModule is a directory with a go.mod file and the module path it declares.
+type goModule struct {
+	root string
+	path string
+	// workspace is the directory of the go.work file that uses the module,
+	// or "" when no scanned go.work names it.
+	workspace string
+}
+
+// goModules are the modules found in a repository.
+type goModules []goModule
+
+// findGoModules discovers the go.mod and go.work files under root, skipping
+// the directories the Go analyzers skip. A go.mod without a module directive
+// is ignored, as the go command would reject it.
+func findGoModules(root string, ignore *git.IgnoreMatcher) goModules {
+	var modules goModules
+	var workspaces []string
+	_ = analysis.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "testdata":
+				if path != root {
+					return filepath.SkipDir
+				}
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch d.Name() {
+		case "go.mod":
+			if modulePath := readModulePath(path); modulePath != "" {
+				modules = append(modules, goModule{root: filepath.Dir(path), path: modulePath})
+			}
+		case "go.work":
+			workspaces = append(workspaces, path)
+		}
+		return nil
+	})
+
+	for _, work := range workspaces {
+		dir := filepath.Dir(work)
+		for _, use := range readWorkspaceUses(work) {
+			useDir := filepath.Clean(filepath.Join(dir, filepath.FromSlash(use)))
+			for i := range modules {
+				if modules[i].root == useDir && modules[i].workspace == "" {
+					modules[i].workspace = dir
+				}
+			}
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].root < modules[j].root })
+	return modules
+}
+
+// readModulePath returns the module path declared by the go.mod at path.
+func readModulePath(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripGoModComment(scanner.Text()))
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// readWorkspaceUses returns the directories named by the use directives of
+// the go.work at path, in both the single-line and the block form.
+func readWorkspaceUses(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var uses []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripGoModComment(scanner.Text()))
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	}
+	return uses
+}
+
+func stripGoModComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// moduleFor returns the innermost module containing dir, or nil when dir is
+// in no module.
+func (m goModules) moduleFor(dir string) *goModule {
+	var found *goModule
+	for i := range m {
+		if dir == m[i].root || strings.HasPrefix(dir, m[i].root+string(filepath.Separator)) {
+			if found == nil || len(m[i].root) > len(found.root) {
+				found = &m[i]
+			}
+		}
+	}
+	return found
+}
+
+// visibleFrom returns the modules whose packages code in module can import
+// from the repository: the module itself and, inside a workspace, the other
+// modules the go.work uses.
+func (m goModules) visibleFrom(module *goModule) goModules {
+	if module == nil {
+		return nil
+	}
+	visible := goModules{*module}
+	if module.workspace == "" {
+		return visible
+	}
+	for _, other := range m {
+		if other.workspace == module.workspace && other.root != module.root {
+			visible = append(visible, other)
+		}
+	}
+	return visible
+}
+
+// moduleImporter type-checks the packages of a set of modules from source,
+// so calls into other packages of the same module (or workspace) resolve.
+// Every other import, the standard library included, goes to fallback. Each
+// module gets its own importer: two modules declaring the same import path
+// never see each other's packages.
+type moduleImporter struct {
+	fset     *token.FileSet
+	all      goModules
+	visible  goModules
+	ignore   *git.IgnoreMatcher
+	fallback types.Importer
+	packages map[string]*types.Package
+	checking map[string]bool
+}
+
+func newModuleImporter(fset *token.FileSet, all goModules, module *goModule, ignore *git.IgnoreMatcher, fallback types.Importer) *moduleImporter {
+	return &moduleImporter{
+		fset:     fset,
+		all:      all,
+		visible:  all.visibleFrom(module),
+		ignore:   ignore,
+		fallback: fallback,
+		packages: map[string]*types.Package{},
+		checking: map[string]bool{},
+	}
+}
+
+func (imp *moduleImporter) Import(path string) (*types.Package, error) {
+	dir, ok := imp.resolve(path)
+	if !ok {
+		return imp.fallback.Import(path)
+	}
+	if pkg, ok := imp.packages[path]; ok {
+		return pkg, nil
+	}
+	if imp.checking[path] {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+	imp.checking[path] = true
+	defer delete(imp.checking, path)
+
+	var files []*ast.File
+	for _, file := range goPackageFiles(dir, imp.ignore) {
+		parsed, err := parser.ParseFile(imp.fset, file, nil, 0)
+		if err != nil {
+			continue
+		}
+		if len(files) > 0 && parsed.Name.Name != files[0].Name.Name {
+			continue
+		}
+		files = append(files, parsed)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files for %s in %s", path, dir)
+	}
+
+	conf := types.Config{Importer: imp, Error: func(error) {}, FakeImportC: true}
+	pkg, _ := conf.Check(path, imp.fset, files, nil)
+	imp.packages[path] = pkg
+	return pkg, nil
+}
+
+// resolve maps an import path to the directory of one of the visible
+// modules. A directory that belongs to a nested module is not part of the
+// outer one.
+func (imp *moduleImporter) resolve(path string) (string, bool) {
+	for i := range imp.visible {
+		module := &imp.visible[i]
+		rest, ok := strings.CutPrefix(path, module.path)
+		if !ok || (rest != "" && rest[0] != '/') {
+			continue
+		}
+		dir := filepath.Join(module.root, filepath.FromSlash(rest))
+		if owner := imp.all.moduleFor(dir); owner == nil || owner.root != module.root {
+			continue
+		}
+		return dir, true
+	}
+	return "", false
+}
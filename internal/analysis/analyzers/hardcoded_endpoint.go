@@ -0,0 +1,468 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// endpointDebtHours is the estimated effort to move one hardcoded endpoint
+// into configuration.
+const endpointDebtHours = 0.25
+
+// The tool rules of hardcoded_endpoint issues.
+const (
+	hardcodedURLRuleID = "hardcoded-url"
+	hardcodedIPRuleID  = "hardcoded-ip"
+)
+
+// endpointURLPattern matches absolute URLs of the schemes services are
+// reached by. Relative URLs and bare hostnames are not endpoints on their own.
+var endpointURLPattern = regexp.MustCompile(`(?i)\b(?:https?|wss?|ftps?|grpcs?|amqps?|mqtts?|rediss?|mongodb(?:\+srv)?|postgres(?:ql)?|mysql|ldaps?|smtps?)://(?:\[[0-9a-f:.]+\])?[^\s"'<>` + "`" + `(){}\[\]\\]*`)
+
+// hostnamePattern is what is left of a host once templates and format verbs
+// ("%s", "${host}") are ruled out.
+var hostnamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+// namespaceHosts serve XML and JSON schema identifiers, which look like URLs
+// but are never requested.
+var namespaceHosts = []string{
+	"www.w3.org", "w3.org", "json-schema.org", "schemas.xmlsoap.org",
+	"schemas.microsoft.com", "schemas.android.com", "xmlns.com", "purl.org", "ns.adobe.com",
+}
+
+// documentationNetworks are the address blocks reserved for examples by
+// RFC 5737 and RFC 3849.
+var documentationNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// EndpointAnalyzer flags IP addresses and absolute URLs written into string
+// literals, which tie the code to one deployment and belong in
+// configuration. Local and example addresses are not reported, nor are hosts
+// on the allowlist of the models.EndpointPolicy in the context. Test files
+// and comments are skipped unless the policy includes them; documentation
+// comments are always skipped.
+type EndpointAnalyzer struct{}
+
+func NewEndpointAnalyzer() *EndpointAnalyzer {
+	return &EndpointAnalyzer{}
+}
+
+func (a *EndpointAnalyzer) Name() string {
+	return "EndpointAnalyzer"
+}
+
+func (a *EndpointAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	policy := analysis.EndpointPolicyFromContext(ctx)
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	issues := []models.TechnicalDebtIssue{}
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "dist", "build":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if !isCodeFile(ext) {
+			return nil
+		}
+		relPath, err := filepath.Rel(repo.Path, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = "/" + filepath.ToSlash(relPath)
+		if !policy.IncludeTests && isTestPath(relPath) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		for _, endpoint := range findEndpoints(content, ext, policy) {
+			issues = append(issues, endpointIssue(userID, repositoryID, analysisRunID, relPath, endpoint))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Hardcoded endpoint check found %d issues", len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"hardcoded_endpoints_count": len(issues),
+		},
+	}, nil
+}
+
+// isTestPath reports whether the repository path relPath is a test file or
+// lies in a test directory, by the naming conventions of the languages the
+// analyzers support.
+func isTestPath(relPath string) bool {
+	dir, name := path.Split(relPath)
+	for _, part := range strings.Split(strings.ToLower(dir), "/") {
+		switch part {
+		case "test", "tests", "__tests__", "testdata", "spec", "fixtures":
+			return true
+		}
+	}
+	base := strings.TrimSuffix(name, path.Ext(name))
+	lower := strings.ToLower(base)
+	if strings.HasSuffix(lower, "_test") || strings.HasSuffix(lower, ".test") ||
+		strings.HasSuffix(lower, ".spec") || strings.HasPrefix(lower, "test_") {
+		return true
+	}
+	// JUnit and xUnit name test classes FooTest or FooTests.
+	return strings.HasSuffix(base, "Test") || strings.HasSuffix(base, "Tests")
+}
+
+// literalKind tells string literals from the comments sourceLiterals finds.
+type literalKind int
+
+const (
+	stringLiteral literalKind = iota
+	commentLiteral
+	// docLiteral is a documentation comment: a /** block, a /// or //! line,
+	// a Go comment directly above a declaration or a Python docstring.
+	docLiteral
+)
+
+// literal is the text of a string literal or comment starting at line.
+type literal struct {
+	text string
+	line int
+	kind literalKind
+}
+
+// sourceLiterals splits content into its string literals and comments, using
+// the comment syntax of ext. It is a lexer, not a parser: code between the
+// literals is skipped, and constructs it does not know, such as regular
+// expression literals, may be taken for the start of a string.
+func sourceLiterals(content []byte, ext string) []literal {
+	s := string(content)
+	syntax := commentSyntaxFor(ext)
+	python := ext == ".py"
+
+	var literals []literal
+	line := 1
+	add := func(text string, kind literalKind) {
+		literals = append(literals, literal{text: text, line: line, kind: kind})
+		line += strings.Count(text, "\n")
+	}
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case s[i] == '\n':
+			line++
+			i++
+		case syntax.blockStart != "" && strings.HasPrefix(rest, syntax.blockStart):
+			body := rest[len(syntax.blockStart):]
+			end := strings.Index(body, syntax.blockEnd)
+			if end < 0 {
+				end = len(body)
+			}
+			kind := commentLiteral
+			if syntax.blockStart == "/*" && strings.HasPrefix(body, "*") {
+				kind = docLiteral
+			}
+			add(body[:end], kind)
+			i += len(syntax.blockStart) + min(end+len(syntax.blockEnd), len(body))
+		case hasAnyPrefix(rest, syntax.linePrefixes):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			kind := commentLiteral
+			if strings.HasPrefix(rest, "///") || strings.HasPrefix(rest, "//!") ||
+				ext == ".go" && startsStatement(s, i) && precedesGoDeclaration(s[i+end:]) {
+				kind = docLiteral
+			}
+			add(rest[:end], kind)
+			i += end
+		case python && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''")):
+			body := rest[3:]
+			end := strings.Index(body, rest[:3])
+			if end < 0 {
+				end = len(body)
+			}
+			kind := stringLiteral
+			if startsStatement(s, i) {
+				kind = docLiteral
+			}
+			add(body[:end], kind)
+			i += 3 + min(end+3, len(body))
+		case s[i] == '"' || s[i] == '\'' || s[i] == '`':
+			quote := s[i]
+			j := i + 1
+			for j < len(s) && s[j] != quote && (s[j] != '\n' || quote == '`') {
+				if s[j] == '\\' && quote != '`' {
+					j++
+				}
+				j++
+			}
+			add(s[i+1:min(j, len(s))], stringLiteral)
+			i = j
+			if j < len(s) && s[j] == quote {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return literals
+}
+
+// precedesGoDeclaration reports whether the Go comment line ending where rest
+// begins is part of a comment block directly above a declaration.
+func precedesGoDeclaration(rest string) bool {
+	for _, line := range strings.Split(rest, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "//"):
+			continue
+		}
+		for _, keyword := range []string{"func ", "type ", "var ", "const ", "package "} {
+			if strings.HasPrefix(trimmed, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// startsStatement reports whether only indentation precedes offset i on its
+// line, as for a Python docstring or a comment of its own.
+func startsStatement(s string, i int) bool {
+	start := strings.LastIndexByte(s[:i], '\n') + 1
+	return strings.TrimSpace(s[start:i]) == ""
+}
+
+// endpoint is a hardcoded URL or IP address found at line.
+type endpoint struct {
+	line int
+	// value is the URL or address as written.
+	value string
+	host  string
+	url   bool
+	// ip is set when the host is an IP address.
+	ip bool
+}
+
+// findEndpoints returns the URLs and IP addresses in the string literals of
+// content, and in its comments when policy includes them, leaving out local,
+// example and allowed hosts. An address inside a URL is reported as the URL.
+func findEndpoints(content []byte, ext string, policy models.EndpointPolicy) []endpoint {
+	var endpoints []endpoint
+	for _, lit := range sourceLiterals(content, ext) {
+		if lit.kind == docLiteral || lit.kind == commentLiteral && !policy.IncludeComments {
+			continue
+		}
+		endpoints = append(endpoints, literalEndpoints(lit, policy)...)
+	}
+	return endpoints
+}
+
+func literalEndpoints(lit literal, policy models.EndpointPolicy) []endpoint {
+	var endpoints []endpoint
+	lineOf := func(offset int) int {
+		return lit.line + strings.Count(lit.text[:offset], "\n")
+	}
+
+	// URLs are blanked out once matched so their hosts are not reported a
+	// second time as addresses.
+	masked := []byte(lit.text)
+	for _, loc := range endpointURLPattern.FindAllStringIndex(lit.text, -1) {
+		for i := loc[0]; i < loc[1]; i++ {
+			masked[i] = ' '
+		}
+		raw := strings.TrimRight(lit.text[loc[0]:loc[1]], ".,;:!?")
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		ip := net.ParseIP(host)
+		if ip == nil && !hostnamePattern.MatchString(host) || !reportableHost(host, ip, policy) {
+			continue
+		}
+		endpoints = append(endpoints, endpoint{line: lineOf(loc[0]), value: raw, host: host, url: true, ip: ip != nil})
+	}
+
+	text := string(masked)
+	offset := 0
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".:[]%", r)
+	}) {
+		offset += strings.Index(text[offset:], token)
+		start := offset
+		offset += len(token)
+
+		value := strings.TrimRight(token, ".:")
+		ip := parseEndpointIP(value)
+		if ip == nil || !reportableHost(ip.String(), ip, policy) {
+			continue
+		}
+		endpoints = append(endpoints, endpoint{line: lineOf(start), value: value, host: ip.String(), ip: true})
+	}
+	return endpoints
+}
+
+// parseEndpointIP returns the address token denotes, with or without a port.
+// IPv6 addresses need a digit, so that "::" scope operators and words such
+// as "add::bad" are not taken for addresses.
+func parseEndpointIP(token string) net.IP {
+	ip := net.ParseIP(strings.Trim(token, "[]"))
+	if ip == nil {
+		if host, _, err := net.SplitHostPort(token); err == nil {
+			ip = net.ParseIP(host)
+		}
+	}
+	if ip == nil || ip.To4() == nil && !strings.ContainsAny(token, "0123456789") {
+		return nil
+	}
+	return ip
+}
+
+// reportableHost reports whether host (with ip set for an address) is worth
+// flagging: neither local, reserved for documentation, a schema namespace,
+// nor allowed by policy.
+func reportableHost(host string, ip net.IP, policy models.EndpointPolicy) bool {
+	if ip != nil {
+		if ip.IsLoopback() || ip.IsUnspecified() {
+			return false
+		}
+		// Netmasks and the broadcast address.
+		if v4 := ip.To4(); v4 != nil && v4[0] == 255 {
+			return false
+		}
+		for _, network := range documentationNetworks {
+			if network.Contains(ip) {
+				return false
+			}
+		}
+		return !policy.Allows(host)
+	}
+
+	if host == "localhost" {
+		return false
+	}
+	for _, suffix := range []string{".localhost", ".example", ".test", ".invalid"} {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+	for _, domain := range []string{"example.com", "example.net", "example.org"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	}
+	for _, namespace := range namespaceHosts {
+		if host == namespace {
+			return false
+		}
+	}
+	return !policy.Allows(host)
+}
+
+// endpointIssue reports e in the file at relPath. A raw address is the more
+// brittle of the two, so it is of medium severity and a URL by name is low.
+func endpointIssue(userID, repositoryID, analysisRunID uuid.UUID, relPath string, e endpoint) models.TechnicalDebtIssue {
+	line := e.line
+	ruleID := hardcodedURLRuleID
+	message := fmt.Sprintf("Hardcoded URL %s", e.value)
+	confidence := 0.7
+	if !e.url {
+		ruleID = hardcodedIPRuleID
+		message = fmt.Sprintf("Hardcoded IP address %s", e.value)
+		confidence = 0.6
+	}
+	severity := "low"
+	if e.ip {
+		severity = "medium"
+	}
+	description := fmt.Sprintf("%s is compiled into the code, so pointing it at another environment means editing "+
+		"and redeploying it. Read it from configuration or the environment instead, or add %s to "+
+		"endpoints.allow_hosts in .debtdrone.yaml if it is meant to be fixed.", e.host, e.host)
+
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           relPath,
+		LineNumber:         &line,
+		IssueType:          "hardcoded_endpoint",
+		Severity:           severity,
+		Category:           "configuration",
+		Message:            message,
+		Description:        &description,
+		ToolName:           "hardcoded_endpoint",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    confidence,
+		TechnicalDebtHours: endpointDebtHours,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata: map[string]interface{}{
+			"endpoint": e.value,
+			"host":     e.host,
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointAnalyzer(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/endpoints")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	found := func(t *testing.T, policy models.EndpointPolicy) []string {
+		t.Helper()
+		result, err := NewEndpointAnalyzer().Analyze(analysis.WithEndpointPolicy(ctx, policy), repo)
+		require.NoError(t, err)
+		assert.Equal(t, len(result.Issues), result.Metrics["hardcoded_endpoints_count"])
+
+		var found []string
+		for _, issue := range result.Issues {
+			assert.Equal(t, "hardcoded_endpoint", issue.IssueType)
+			assert.Equal(t, "configuration", issue.Category)
+			require.NotNil(t, issue.LineNumber)
+			found = append(found, fmt.Sprintf("%s:%d %s %s", issue.FilePath, *issue.LineNumber, issue.Severity, issue.Message))
+		}
+		sort.Strings(found)
+		return found
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		// Local, example, namespace, netmask and templated hosts, comments,
+		// the doc comment and the test directory are left out.
+		assert.Equal(t, []string{
+			"/client.go:13 low Hardcoded URL https://status.partner.net/ping",
+			"/client.go:18 medium Hardcoded URL wss://[2001:4860::8888]:443/stream",
+			"/client.go:4 low Hardcoded URL https://api.payments.io/v2",
+			"/client.go:7 medium Hardcoded IP address 10.0.4.12:6379",
+			"/worker.py:3 low Hardcoded URL amqp://guest@queue.internal.corp:5672/",
+			"/worker.py:4 medium Hardcoded IP address 192.168.1.40",
+		}, found(t, models.EndpointPolicy{}))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		issues := found(t, models.EndpointPolicy{AllowedHosts: []string{"partner.net", "192.168.0.0/16", "*.corp"}})
+		assert.NotContains(t, issues, "/client.go:13 low Hardcoded URL https://status.partner.net/ping")
+		assert.Len(t, issues, 3)
+	})
+
+	t.Run("comments and tests", func(t *testing.T) {
+		issues := found(t, models.EndpointPolicy{IncludeComments: true, IncludeTests: true})
+		assert.Contains(t, issues, "/client.go:17 low Hardcoded URL http://legacy.payments.io")
+		assert.Contains(t, issues, "/worker.py:3 low Hardcoded URL http://wiki.corp/queues")
+		assert.Contains(t, issues, "/tests/client_test.go:3 medium Hardcoded URL http://10.1.1.1:9000")
+		assert.NotContains(t, issues, "/client.go:3 low Hardcoded URL https://api.payments.io/v2.", "doc comments are never checked")
+		assert.NotContains(t, issues, "/worker.py:1 low Hardcoded URL http://queue.internal.corp:5672", "docstrings are never checked")
+		assert.Len(t, issues, 9)
+	})
+}
+
+func TestEndpointPolicy_Allows(t *testing.T) {
+	policy := models.EndpointPolicy{AllowedHosts: []string{"api.stripe.com", "*.corp.example", "10.0.0.0/8", "2001:4860::8888"}}
+
+	assert.True(t, policy.Allows("api.stripe.com"))
+	assert.True(t, policy.Allows("eu.api.stripe.com"), "subdomains of an entry")
+	assert.True(t, policy.Allows("API.Stripe.com."))
+	assert.False(t, policy.Allows("stripe.com"))
+	assert.True(t, policy.Allows("db.corp.example"))
+	assert.False(t, policy.Allows("corp.example"), "a wildcard matches subdomains only")
+	assert.True(t, policy.Allows("10.20.30.40"))
+	assert.False(t, policy.Allows("11.0.0.1"))
+	assert.True(t, policy.Allows("2001:4860:0:0:0:0:0:8888"))
+}
+
+func TestIsTestPath(t *testing.T) {
+	for _, p := range []string{"/pkg/client_test.go", "/src/__tests__/app.js", "/app.spec.ts", "/test_worker.py", "/src/ClientTest.java", "/testdata/a.go"} {
+		assert.True(t, isTestPath(p), p)
+	}
+	for _, p := range []string{"/pkg/client.go", "/src/Latest.java", "/contest.py"} {
+		assert.False(t, isTestPath(p), p)
+	}
+}
@@ -0,0 +1,435 @@
+package analyzers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// indentationRuleID is the tool rule of inconsistent_indentation issues.
+const indentationRuleID = "mixed-indentation"
+
+// Without an .editorconfig the minority style must cover at least
+// indentationMinLines lines and indentationMinRatio of the indented lines, so
+// that a stray tab in a space-indented file is not reported.
+const (
+	indentationMinLines = 3
+	indentationMinRatio = 0.1
+)
+
+// IndentationAnalyzer flags source files that indent some lines with tabs and
+// others with spaces. The style a file should use comes from the
+// indent_style of the .editorconfig sections matching it, or else from the
+// style most of its lines use. Files indented consistently are never
+// reported, even when their style differs from the rest of the repository or
+// from the .editorconfig. Go files are skipped since gofmt owns their
+// indentation.
+type IndentationAnalyzer struct{}
+
+func NewIndentationAnalyzer() *IndentationAnalyzer {
+	return &IndentationAnalyzer{}
+}
+
+func (a *IndentationAnalyzer) Name() string {
+	return "IndentationAnalyzer"
+}
+
+func (a *IndentationAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	editorConfig := newEditorConfigResolver(repo.Path)
+	issues := []models.TechnicalDebtIssue{}
+	filesChecked := 0
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "dist", "build":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".go" || !isCodeFile(ext) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		filesChecked++
+
+		stats := countIndentation(content)
+		style := editorConfig.properties(path)
+		use, ok := stats.inconsistent(style)
+		if !ok {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(repo.Path, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = "/" + filepath.ToSlash(relPath)
+		issues = append(issues, indentationIssue(userID, repositoryID, analysisRunID, relPath, stats, use, style))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ Indentation check found %d issues", len(issues))
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"indentation_issues_count":  len(issues),
+			"indentation_files_checked": filesChecked,
+		},
+	}, nil
+}
+
+// indentationStats counts the indented lines of a file by the character
+// their indentation starts with.
+type indentationStats struct {
+	tabLines   int
+	spaceLines int
+	// firstTab and firstSpace are the first line indented each way.
+	firstTab   int
+	firstSpace int
+}
+
+// countIndentation classifies the indented lines of content. Blank lines are
+// skipped, and so are lines starting with "*" once indented, the
+// continuation lines of block comments, which are aligned with a space after
+// the comment's own indentation. Spaces after leading tabs are alignment, so
+// such a line counts as tab-indented.
+func countIndentation(content []byte) indentationStats {
+	var stats indentationStats
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		rest := strings.TrimLeft(text, " \t")
+		if rest == "" || len(rest) == len(text) || strings.HasPrefix(rest, "*") {
+			continue
+		}
+		if text[0] == '\t' {
+			if stats.tabLines == 0 {
+				stats.firstTab = line
+			}
+			stats.tabLines++
+		} else {
+			if stats.spaceLines == 0 {
+				stats.firstSpace = line
+			}
+			stats.spaceLines++
+		}
+	}
+	return stats
+}
+
+// inconsistent reports whether the file mixes tab and space indentation and,
+// if so, the style it should use: "tab" or "space". With an indent_style
+// from the .editorconfig every line indented the other way counts; without
+// one the style of most lines is expected and the rest must clear
+// indentationMinLines and indentationMinRatio.
+func (s indentationStats) inconsistent(style editorConfigStyle) (string, bool) {
+	if s.tabLines == 0 || s.spaceLines == 0 {
+		return "", false
+	}
+	if style.indentStyle == "tab" || style.indentStyle == "space" {
+		return style.indentStyle, true
+	}
+	use, minority := "space", s.tabLines
+	if s.tabLines > s.spaceLines {
+		use, minority = "tab", s.spaceLines
+	}
+	if minority < indentationMinLines || float64(minority) < indentationMinRatio*float64(s.tabLines+s.spaceLines) {
+		return "", false
+	}
+	return use, true
+}
+
+// indentationIssue reports a file whose lines mix tab and space indentation.
+// use is the style the file should be converted to.
+func indentationIssue(userID, repositoryID, analysisRunID uuid.UUID, relPath string, stats indentationStats, use string, style editorConfigStyle) models.TechnicalDebtIssue {
+	ruleID := indentationRuleID
+	offending, firstOffending, source := stats.spaceLines, stats.firstSpace, "most lines"
+	if use == "space" {
+		offending, firstOffending = stats.tabLines, stats.firstTab
+	}
+	if style.indentStyle == use {
+		source = ".editorconfig"
+	}
+	expected := "tabs"
+	if use == "space" {
+		expected = "spaces"
+		if style.indentSize > 0 {
+			expected = fmt.Sprintf("%d spaces", style.indentSize)
+		}
+	}
+
+	description := fmt.Sprintf("%d lines are indented with tabs and %d with spaces; the first line that does not use %s is line %d. "+
+		"Mixed indentation renders differently across editors and makes diffs noisy. Reindent the file with %s (expected per %s), "+
+		"or declare the style in .editorconfig so editors apply it.", stats.tabLines, stats.spaceLines, expected, firstOffending, expected, source)
+	metadata := map[string]interface{}{
+		"tab_lines":       stats.tabLines,
+		"space_lines":     stats.spaceLines,
+		"expected_style":  use,
+		"expected_source": source,
+		"first_offending": firstOffending,
+		"offending_lines": offending,
+	}
+	if style.indentSize > 0 {
+		metadata["indent_size"] = style.indentSize
+	}
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           relPath,
+		IssueType:          "inconsistent_indentation",
+		Severity:           "low",
+		Category:           "maintainability",
+		Message:            fmt.Sprintf("Mixed tab and space indentation (%d lines not indented with %s)", offending, expected),
+		Description:        &description,
+		ToolName:           "indentation",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    0.7,
+		TechnicalDebtHours: 0.25,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata:           metadata,
+	}
+}
+
+// editorConfigStyle holds the indentation properties .editorconfig sets for
+// a file. indentStyle is empty when unset.
+type editorConfigStyle struct {
+	indentStyle string
+	indentSize  int
+}
+
+// editorConfigSection is one [glob] section of an .editorconfig file.
+type editorConfigSection struct {
+	pattern    *regexp.Regexp
+	properties map[string]string
+}
+
+type editorConfigFile struct {
+	dir      string
+	root     bool
+	sections []editorConfigSection
+}
+
+// editorConfigResolver resolves .editorconfig properties for the files of a
+// repository, parsing each directory's file at most once. Files above the
+// repository root are not read.
+type editorConfigResolver struct {
+	root  string
+	files map[string]*editorConfigFile
+}
+
+func newEditorConfigResolver(root string) *editorConfigResolver {
+	return &editorConfigResolver{root: filepath.Clean(root), files: map[string]*editorConfigFile{}}
+}
+
+// properties returns the indentation properties for path. As the
+// EditorConfig specification requires, files closer to path and later
+// sections of a file take precedence, and the search stops at a file
+// declaring root = true.
+func (r *editorConfigResolver) properties(path string) editorConfigStyle {
+	var chain []*editorConfigFile
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if file := r.load(dir); file != nil {
+			chain = append(chain, file)
+			if file.root {
+				break
+			}
+		}
+		if dir == r.root || !strings.HasPrefix(dir, r.root) || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	properties := map[string]string{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		file := chain[i]
+		rel, err := filepath.Rel(file.dir, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, section := range file.sections {
+			if !section.pattern.MatchString(rel) {
+				continue
+			}
+			for key, value := range section.properties {
+				properties[key] = value
+			}
+		}
+	}
+
+	var style editorConfigStyle
+	if value := properties["indent_style"]; value == "tab" || value == "space" {
+		style.indentStyle = value
+	}
+	size := properties["indent_size"]
+	if size == "tab" {
+		size = properties["tab_width"]
+	}
+	if n, err := strconv.Atoi(size); err == nil && n > 0 {
+		style.indentSize = n
+	}
+	return style
+}
+
+func (r *editorConfigResolver) load(dir string) *editorConfigFile {
+	if file, ok := r.files[dir]; ok {
+		return file
+	}
+	file := parseEditorConfig(dir)
+	r.files[dir] = file
+	return file
+}
+
+// parseEditorConfig reads dir/.editorconfig, returning nil when there is none.
+// Keys and values are lowercased, and sections whose glob cannot be
+// compiled are dropped.
+func parseEditorConfig(dir string) *editorConfigFile {
+	content, err := os.ReadFile(filepath.Join(dir, ".editorconfig"))
+	if err != nil {
+		return nil
+	}
+	file := &editorConfigFile{dir: dir}
+	var section *editorConfigSection
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = nil
+			if pattern, err := editorConfigGlob(line[1 : len(line)-1]); err == nil {
+				file.sections = append(file.sections, editorConfigSection{pattern: pattern, properties: map[string]string{}})
+				section = &file.sections[len(file.sections)-1]
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if section == nil {
+			// Only root is allowed before the first section.
+			if key == "root" {
+				file.root = value == "true"
+			}
+			continue
+		}
+		section.properties[key] = value
+	}
+	return file
+}
+
+// editorConfigGlob compiles an .editorconfig section glob into a regular
+// expression matched against a slash-separated path relative to the
+// .editorconfig's directory. A glob without a slash matches file names at
+// any depth; "*" stops at slashes, "**" does not, and "{a,b}" matches either
+// alternative.
+func editorConfigGlob(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	depth := 0
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		case c == '{':
+			b.WriteString("(?:")
+			depth++
+		case c == '}' && depth > 0:
+			b.WriteString(")")
+			depth--
+		case c == ',' && depth > 0:
+			b.WriteString("|")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,144 @@
+package analyzers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIndentationRepo(t *testing.T, files map[string]string) *git.Repository {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return &git.Repository{FS: osfs.New(dir), Path: dir}
+}
+
+func indentationContext() context.Context {
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	return analysis.WithCLI(ctx)
+}
+
+// indented builds a file of n lines indented with prefix.
+func indented(prefix string, n int) string {
+	return strings.Repeat("if (x) {\n"+prefix+"call();\n}\n", n)
+}
+
+func TestIndentationAnalyzer(t *testing.T) {
+	ctx := indentationContext()
+
+	t.Run("mixed files are reported, consistent ones are not", func(t *testing.T) {
+		repo := writeIndentationRepo(t, map[string]string{
+			"mixed.js":  indented("    ", 10) + indented("\t", 4),
+			"spaces.js": indented("  ", 10),
+			"tabs.py":   "def f():\n\tif x:\n\t\treturn 1\n",
+			"stray.js":  indented("    ", 30) + indented("\t", 1),
+			// A block comment's continuation lines and alignment after
+			// tabs do not make a tab-indented file mixed.
+			"comment.java": "class A {\n\t/**\n\t * Doc.\n\t */\n\tint call(int a,\n\t         int b) {\n\t\treturn a;\n\t}\n}\n",
+			// gofmt owns the indentation of Go files.
+			"main.go": indented("    ", 10) + indented("\t", 10),
+		})
+
+		result, err := NewIndentationAnalyzer().Analyze(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+
+		issue := result.Issues[0]
+		assert.Equal(t, "/mixed.js", issue.FilePath)
+		assert.Equal(t, "inconsistent_indentation", issue.IssueType)
+		assert.Equal(t, "low", issue.Severity)
+		assert.Nil(t, issue.LineNumber)
+		assert.Equal(t, "Mixed tab and space indentation (4 lines not indented with spaces)", issue.Message)
+		assert.Equal(t, "space", issue.Metadata["expected_style"])
+		assert.Equal(t, "most lines", issue.Metadata["expected_source"])
+		assert.Equal(t, 32, issue.Metadata["first_offending"])
+		assert.Equal(t, 1, result.Metrics["indentation_issues_count"])
+		assert.Equal(t, 5, result.Metrics["indentation_files_checked"])
+	})
+
+	t.Run("editorconfig decides the expected style", func(t *testing.T) {
+		repo := writeIndentationRepo(t, map[string]string{
+			".editorconfig": "root = true\n\n[*]\nindent_style = space\nindent_size = 2\n\n[{lib,src}/**.js]\nindent_style = tab\n",
+			// The configured style wins over the majority, and a single
+			// line indented the other way is enough.
+			"src/app/mixed.js": indented("  ", 30) + indented("\t", 1),
+			"other.js":         indented("\t", 30) + indented("  ", 1),
+			// Consistent files are left alone even when they break the
+			// configured style.
+			"src/tabs.js": indented("    ", 5),
+		})
+
+		result, err := NewIndentationAnalyzer().Analyze(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 2)
+
+		byPath := map[string]string{}
+		for _, issue := range result.Issues {
+			assert.Equal(t, ".editorconfig", issue.Metadata["expected_source"])
+			byPath[issue.FilePath] = issue.Message
+		}
+		assert.Equal(t, map[string]string{
+			"/src/app/mixed.js": "Mixed tab and space indentation (30 lines not indented with tabs)",
+			"/other.js":         "Mixed tab and space indentation (30 lines not indented with 2 spaces)",
+		}, byPath)
+	})
+
+	t.Run("nested editorconfig overrides its parent", func(t *testing.T) {
+		repo := writeIndentationRepo(t, map[string]string{
+			".editorconfig":     "root = true\n[*.py]\nindent_style = tab\n",
+			"pkg/.editorconfig": "[*.py]\nindent_style = space\nindent_size = 4\n",
+			"pkg/mod.py":        "def f():\n    pass\n\tpass\n",
+			"top.py":            "def f():\n    pass\n\tpass\n",
+		})
+
+		result, err := NewIndentationAnalyzer().Analyze(ctx, repo)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 2)
+
+		expected := map[string]interface{}{}
+		for _, issue := range result.Issues {
+			expected[issue.FilePath] = issue.Metadata["expected_style"]
+		}
+		assert.Equal(t, map[string]interface{}{"/pkg/mod.py": "space", "/top.py": "tab"}, expected)
+	})
+}
+
+func TestEditorConfigGlob(t *testing.T) {
+	tests := []struct {
+		glob  string
+		path  string
+		match bool
+	}{
+		{"*", "a/b/c.js", true},
+		{"*.js", "a/b/c.js", true},
+		{"*.js", "c.ts", false},
+		{"*.{js,ts}", "dir/c.ts", true},
+		{"/src/*.js", "src/a.js", true},
+		{"src/*.js", "src/lib/a.js", false},
+		{"src/**.js", "src/lib/a.js", true},
+		{"file?.py", "file1.py", true},
+		{"[!a]*.py", "abc.py", false},
+		{"[!a]*.py", "bcd.py", true},
+		{"Makefile", "sub/Makefile", true},
+	}
+	for _, tt := range tests {
+		pattern, err := editorConfigGlob(tt.glob)
+		require.NoError(t, err, tt.glob)
+		assert.Equal(t, tt.match, pattern.MatchString(tt.path), "%s vs %s", tt.glob, tt.path)
+	}
+}
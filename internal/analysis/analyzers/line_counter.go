@@ -2,16 +2,27 @@ package analyzers
 
 import (
 	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
 	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-enry/go-enry/v2"
 )
 
 type LineCounter struct{}
 
+// LanguageLineStats is the cloc-style line breakdown for a single language.
+type LanguageLineStats struct {
+	Files        int64 `json:"files"`
+	TotalLines   int64 `json:"total_lines"`
+	CodeLines    int64 `json:"code_lines"`
+	CommentLines int64 `json:"comment_lines"`
+	BlankLines   int64 `json:"blank_lines"`
+}
+
 func NewLineCounter() *LineCounter {
 	return &LineCounter{}
 }
@@ -23,17 +34,26 @@ func (a *LineCounter) Name() string {
 func (a *LineCounter) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
 	var totalLines int64
 	var fileCount int64
+	var totals LanguageLineStats
+	languages := make(map[string]*LanguageLineStats)
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
 
-	err := filepath.Walk(repo.Path, func(path string, info os.FileInfo, err error) error {
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
-			if info.Name() == ".git" {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || ignore.Ignored(path, true) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
 
 		ext := strings.ToLower(filepath.Ext(path))
 		if !isCodeFile(ext) {
@@ -49,6 +69,19 @@ func (a *LineCounter) Analyze(ctx context.Context, repo *git.Repository) (*analy
 		totalLines += int64(lines)
 		fileCount++
 
+		language := enry.GetLanguage(filepath.Base(path), content)
+		if language == "" {
+			language = "Other"
+		}
+		stats := countLines(content, commentSyntaxFor(ext))
+		stats.Files = 1
+
+		if languages[language] == nil {
+			languages[language] = &LanguageLineStats{}
+		}
+		languages[language].add(stats)
+		totals.add(stats)
+
 		return nil
 	})
 
@@ -56,15 +89,110 @@ func (a *LineCounter) Analyze(ctx context.Context, repo *git.Repository) (*analy
 		return nil, err
 	}
 
+	breakdown := make(map[string]LanguageLineStats, len(languages))
+	for lang, stats := range languages {
+		breakdown[lang] = *stats
+	}
+
 	return &analysis.Result{
 		Issues: nil,
 		Metrics: map[string]interface{}{
-			"loc":        totalLines,
-			"file_count": fileCount,
+			"loc":           totalLines,
+			"file_count":    fileCount,
+			"total_lines":   totals.TotalLines,
+			"code_lines":    totals.CodeLines,
+			"comment_lines": totals.CommentLines,
+			"blank_lines":   totals.BlankLines,
+			"languages":     breakdown,
 		},
 	}, nil
 }
 
+func (s *LanguageLineStats) add(other LanguageLineStats) {
+	s.Files += other.Files
+	s.TotalLines += other.TotalLines
+	s.CodeLines += other.CodeLines
+	s.CommentLines += other.CommentLines
+	s.BlankLines += other.BlankLines
+}
+
+// commentSyntax describes how a language marks comments. Detection is line
+// prefix based: a line counts as a comment only when it starts with a marker
+// (or sits inside a block comment), so trailing comments count as code.
+type commentSyntax struct {
+	linePrefixes []string
+	blockStart   string
+	blockEnd     string
+}
+
+var (
+	cStyleComments  = commentSyntax{linePrefixes: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashComments    = commentSyntax{linePrefixes: []string{"#"}}
+	phpComments     = commentSyntax{linePrefixes: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"}
+	rubyComments    = commentSyntax{linePrefixes: []string{"#"}, blockStart: "=begin", blockEnd: "=end"}
+	noCommentSyntax = commentSyntax{}
+)
+
+func commentSyntaxFor(ext string) commentSyntax {
+	switch ext {
+	case ".go", ".js", ".ts", ".tsx", ".jsx", ".java", ".cs", ".c", ".cpp", ".h":
+		return cStyleComments
+	case ".py":
+		return hashComments
+	case ".rb":
+		return rubyComments
+	case ".php":
+		return phpComments
+	default:
+		return noCommentSyntax
+	}
+}
+
+// countLines classifies every line of content as code, comment or blank.
+func countLines(content []byte, syntax commentSyntax) LanguageLineStats {
+	var stats LanguageLineStats
+
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return stats
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(text, "\n") {
+		stats.TotalLines++
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inBlock:
+			stats.CommentLines++
+			if strings.Contains(trimmed, syntax.blockEnd) {
+				inBlock = false
+			}
+		case trimmed == "":
+			stats.BlankLines++
+		case syntax.blockStart != "" && strings.HasPrefix(trimmed, syntax.blockStart):
+			stats.CommentLines++
+			rest := strings.TrimPrefix(trimmed, syntax.blockStart)
+			inBlock = !strings.Contains(rest, syntax.blockEnd)
+		case hasAnyPrefix(trimmed, syntax.linePrefixes):
+			stats.CommentLines++
+		default:
+			stats.CodeLines++
+		}
+	}
+
+	return stats
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
 func isCodeFile(ext string) bool {
 	switch ext {
 	case ".go", ".js", ".ts", ".tsx", ".jsx", ".py", ".java", ".cs", ".c", ".cpp", ".h", ".rb", ".php":
@@ -0,0 +1,56 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineCounter_Breakdown(t *testing.T) {
+	absPath, err := filepath.Abs("testdata/lines")
+	require.NoError(t, err)
+
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	result, err := NewLineCounter().Analyze(context.Background(), repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(21), result.Metrics["total_lines"])
+	assert.Equal(t, int64(8), result.Metrics["code_lines"])
+	assert.Equal(t, int64(8), result.Metrics["comment_lines"])
+	assert.Equal(t, int64(5), result.Metrics["blank_lines"])
+	assert.Equal(t, int64(2), result.Metrics["file_count"])
+
+	languages, ok := result.Metrics["languages"].(map[string]LanguageLineStats)
+	require.True(t, ok, "languages metric should be a per-language map")
+
+	assert.Equal(t, LanguageLineStats{Files: 1, TotalLines: 13, CodeLines: 5, CommentLines: 6, BlankLines: 2}, languages["Go"])
+	assert.Equal(t, LanguageLineStats{Files: 1, TotalLines: 8, CodeLines: 3, CommentLines: 2, BlankLines: 3}, languages["Python"])
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		syntax  commentSyntax
+		want    LanguageLineStats
+	}{
+		{"empty file", "", cStyleComments, LanguageLineStats{}},
+		{"no trailing newline", "a := 1\nb := 2", cStyleComments, LanguageLineStats{TotalLines: 2, CodeLines: 2}},
+		{"single-line block comment", "/* one */\nx()\n", cStyleComments, LanguageLineStats{TotalLines: 2, CodeLines: 1, CommentLines: 1}},
+		{"whitespace-only lines are blank", "x\n \t \n", hashComments, LanguageLineStats{TotalLines: 2, CodeLines: 1, BlankLines: 1}},
+		{"ruby block comment", "=begin\ndoc\n=end\nputs 1\n", rubyComments, LanguageLineStats{TotalLines: 4, CodeLines: 1, CommentLines: 3}},
+		{"unknown syntax is all code", "# not a comment\n", noCommentSyntax, LanguageLineStats{TotalLines: 1, CodeLines: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, countLines([]byte(tt.content), tt.syntax))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package analyzers
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// minifiedRuleID is the tool rule of the committed_artifact issues raised for
+// minified files.
+const minifiedRuleID = "minified-file"
+
+// minifiedFile describes a file detectMinified matched.
+type minifiedFile struct {
+	avgLineLength int
+	size          int
+	// byName is set when the file name (app.min.js) marks it as minified.
+	byName bool
+}
+
+// detectMinified reports whether content looks like minified or bundled
+// output rather than source. It only looks at files with one of the
+// thresholds' extensions: a ".min." infix in the name is enough, otherwise
+// the file must be at least MinBytes long with lines averaging at least
+// AvgLineLength bytes, the newline ratio minifiers leave behind.
+func detectMinified(path string, content []byte, thresholds models.MinifiedThresholds) (minifiedFile, bool) {
+	thresholds = thresholds.WithDefaults()
+	name := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(name)
+	matched := false
+	for _, candidate := range thresholds.Extensions {
+		if strings.EqualFold(ext, candidate) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return minifiedFile{}, false
+	}
+
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	file := minifiedFile{size: len(content), byName: strings.Contains(name, ".min.")}
+	if lines > 0 {
+		file.avgLineLength = len(content) / lines
+	}
+	if file.byName {
+		return file, true
+	}
+	return file, file.size >= thresholds.MinBytes && file.avgLineLength >= thresholds.AvgLineLength
+}
+
+// minifiedIssue flags a minified file committed to the repository. Such
+// files are build output that belongs in an artifact store, not in review.
+func minifiedIssue(userID, repositoryID, analysisRunID uuid.UUID, relPath string, file minifiedFile) models.TechnicalDebtIssue {
+	ruleID := minifiedRuleID
+	reason := fmt.Sprintf("its lines average %d characters", file.avgLineLength)
+	if file.byName {
+		reason = "its name marks it as minified"
+	}
+	description := fmt.Sprintf("This file looks like minified or bundled build output (%d bytes; %s), so it was skipped by the complexity analysis. "+
+		"Generate it during the build instead of committing it, or add it to .gitignore.", file.size, reason)
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           relPath,
+		IssueType:          "committed_artifact",
+		Severity:           "low",
+		Category:           "maintainability",
+		Message:            "Minified file committed to the repository; skipped by complexity analysis",
+		Description:        &description,
+		ToolName:           "complexity_analyzer",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    0.8,
+		TechnicalDebtHours: 0.5,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata: map[string]interface{}{
+			"size_bytes":      file.size,
+			"avg_line_length": file.avgLineLength,
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minifiedSource is one long line of JavaScript, as a minifier leaves it.
+var minifiedSource = strings.Repeat("function a(b){if(b>1){return b*2}else{return b+1}};", 80) + "\n"
+
+func TestDetectMinified(t *testing.T) {
+	readable := strings.Repeat("function double(value) {\n  return value * 2;\n}\n", 80)
+	dataTable := "package table\n\nvar Lookup = []int{" + strings.Repeat("1, 2, 3, 4, 5, 6, 7, 8, ", 300) + "}\n"
+
+	tests := []struct {
+		name       string
+		path       string
+		content    string
+		thresholds models.MinifiedThresholds
+		want       bool
+	}{
+		{"long lines in a large bundle", "dist/app.js", minifiedSource, models.MinifiedThresholds{}, true},
+		{"readable source", "src/app.js", readable, models.MinifiedThresholds{}, false},
+		{"small files are left alone", "src/tiny.js", minifiedSource[:600], models.MinifiedThresholds{}, false},
+		{"min infix is enough", "vendor/jquery.min.js", "var a=1;\n", models.MinifiedThresholds{}, true},
+		{"data tables in other languages never match", "table.go", dataTable, models.MinifiedThresholds{}, false},
+		{"configured extensions", "table.go", dataTable, models.MinifiedThresholds{Extensions: []string{".go"}}, true},
+		{"configured line length", "dist/app.js", minifiedSource, models.MinifiedThresholds{AvgLineLength: 10000}, false},
+		{"configured size", "src/tiny.js", minifiedSource[:600], models.MinifiedThresholds{MinBytes: 100}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := detectMinified(tt.path, []byte(tt.content), tt.thresholds)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestComplexityAnalyzer_SkipsMinifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dist"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dist", "bundle.js"), []byte(minifiedSource), 0644))
+	ctx, repo := complexityTestContext(t, dir)
+
+	result, err := NewComplexityAnalyzer(nil).Analyze(ctx, repo)
+	require.NoError(t, err)
+
+	require.Len(t, result.Issues, 1)
+	issue := result.Issues[0]
+	assert.Equal(t, "/dist/bundle.js", issue.FilePath)
+	assert.Equal(t, "committed_artifact", issue.IssueType)
+	assert.Equal(t, "low", issue.Severity)
+	assert.Equal(t, minifiedRuleID, *issue.ToolRuleID)
+	assert.Equal(t, len(minifiedSource), issue.Metadata["avg_line_length"])
+	assert.Equal(t, 1, result.Metrics["complexity_minified_files"])
+	assert.Equal(t, 0, result.Metrics["complexity_functions_analyzed"], "the bundle's functions are not parsed")
+}
@@ -0,0 +1,176 @@
+package analyzers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// outlierRuleID is the tool rule of the complexity_outlier issues.
+const outlierRuleID = "statistical_outlier"
+
+// outlierStdDevs is how many standard deviations above the repository's mean
+// a function's length or cyclomatic complexity must lie to be an outlier.
+const outlierStdDevs = 2.0
+
+// minOutlierFunctions is the number of named functions from which their mean
+// and standard deviation say something about the norms of a codebase.
+const minOutlierFunctions = 30
+
+// distribution is the mean and population standard deviation of a measure
+// across the functions of a run.
+type distribution struct {
+	mean   float64
+	stddev float64
+}
+
+func newDistribution(values []float64) distribution {
+	if len(values) == 0 {
+		return distribution{}
+	}
+	var d distribution
+	for _, v := range values {
+		d.mean += v
+	}
+	d.mean /= float64(len(values))
+	for _, v := range values {
+		d.stddev += (v - d.mean) * (v - d.mean)
+	}
+	d.stddev = math.Sqrt(d.stddev / float64(len(values)))
+	return d
+}
+
+// score is the number of standard deviations v lies above the mean; it is
+// zero when every function measures the same.
+func (d distribution) score(v float64) float64 {
+	if d.stddev == 0 {
+		return 0
+	}
+	return (v - d.mean) / d.stddev
+}
+
+// outlierDetector flags functions that are much longer or more complex than
+// the rest of the repository, instead of comparing them with fixed
+// thresholds. The distributions are built from every named function a run
+// analyzes, including those a changed-only scan then leaves out of the
+// report, so the norms do not shrink to the functions of one change.
+type outlierDetector struct {
+	loc        []float64
+	cyclomatic []float64
+}
+
+// add records the named functions of metrics in the distributions.
+func (o *outlierDetector) add(metrics []models.ComplexityMetric) {
+	for _, m := range metrics {
+		if anonymousFunction(m.FunctionName) {
+			continue
+		}
+		o.loc = append(o.loc, float64(m.LinesOfCode))
+		o.cyclomatic = append(o.cyclomatic, float64(m.CyclomaticComplexity))
+	}
+}
+
+// enough reports whether enough functions were recorded for the
+// distributions to be meaningful.
+func (o *outlierDetector) enough() bool {
+	return len(o.loc) >= minOutlierFunctions
+}
+
+// issues returns a complexity_outlier issue for each named function of
+// metrics lying more than outlierStdDevs standard deviations above the mean
+// length or cyclomatic complexity. It returns none until enough functions
+// were recorded.
+func (o *outlierDetector) issues(metrics []models.ComplexityMetric) []models.TechnicalDebtIssue {
+	if !o.enough() {
+		return nil
+	}
+	loc, cyclomatic := newDistribution(o.loc), newDistribution(o.cyclomatic)
+
+	var issues []models.TechnicalDebtIssue
+	for _, m := range metrics {
+		if anonymousFunction(m.FunctionName) {
+			continue
+		}
+		locScore := loc.score(float64(m.LinesOfCode))
+		cyclomaticScore := cyclomatic.score(float64(m.CyclomaticComplexity))
+		if locScore <= outlierStdDevs && cyclomaticScore <= outlierStdDevs {
+			continue
+		}
+		issues = append(issues, outlierIssue(m, loc, cyclomatic, locScore, cyclomaticScore))
+	}
+	return issues
+}
+
+// outlierIssue reports the function m as an outlier. The further it lies
+// beyond the threshold, the higher the confidence that it really stands out
+// rather than sitting at the tail of a wide distribution.
+func outlierIssue(m models.ComplexityMetric, loc, cyclomatic distribution, locScore, cyclomaticScore float64) models.TechnicalDebtIssue {
+	ruleID := outlierRuleID
+	line := m.StartLine
+	score := math.Max(locScore, cyclomaticScore)
+	excess := score - outlierStdDevs
+
+	var measures []string
+	if locScore > outlierStdDevs {
+		measures = append(measures, fmt.Sprintf("%d lines (%.1fσ above the mean of %.1f)", m.LinesOfCode, locScore, loc.mean))
+	}
+	if cyclomaticScore > outlierStdDevs {
+		measures = append(measures, fmt.Sprintf("cyclomatic complexity %d (%.1fσ above the mean of %.1f)", m.CyclomaticComplexity, cyclomaticScore, cyclomatic.mean))
+	}
+	description := fmt.Sprintf("Function: %s\n%s.\n\nCompared with the other functions of this repository it is unusually %s; "+
+		"splitting it brings it in line with the rest of the codebase.",
+		m.FunctionName, strings.Join(measures, "\n"), outlierTraits(locScore, cyclomaticScore))
+
+	severity := "low"
+	if excess > 1 {
+		severity = "medium"
+	}
+
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             m.UserID,
+		RepositoryID:       m.RepositoryID,
+		AnalysisRunID:      m.AnalysisRunID,
+		FilePath:           m.FilePath,
+		LineNumber:         &line,
+		IssueType:          "complexity_outlier",
+		Severity:           severity,
+		Category:           "maintainability",
+		Message:            fmt.Sprintf("Function '%s' is a statistical outlier: %s", m.FunctionName, strings.Join(measures, ", ")),
+		Description:        &description,
+		ToolName:           "complexity_analyzer",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    math.Min(0.95, 0.5+0.1*excess),
+		TechnicalDebtHours: 0.5 + 0.25*excess,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		CodeSnippet:        m.CodeSnippet,
+		Metadata: map[string]interface{}{
+			"function_name":         m.FunctionName,
+			"lines_of_code":         m.LinesOfCode,
+			"cyclomatic_complexity": m.CyclomaticComplexity,
+			"loc_mean":              loc.mean,
+			"loc_stddev":            loc.stddev,
+			"loc_score":             locScore,
+			"cyclomatic_mean":       cyclomatic.mean,
+			"cyclomatic_stddev":     cyclomatic.stddev,
+			"cyclomatic_score":      cyclomaticScore,
+			"threshold_stddevs":     outlierStdDevs,
+			"language":              m.Language,
+		},
+	}
+}
+
+func outlierTraits(locScore, cyclomaticScore float64) string {
+	switch {
+	case locScore > outlierStdDevs && cyclomaticScore > outlierStdDevs:
+		return "long and branchy"
+	case locScore > outlierStdDevs:
+		return "long"
+	default:
+		return "branchy"
+	}
+}
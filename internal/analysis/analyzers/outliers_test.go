@@ -0,0 +1,111 @@
+package analyzers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistribution(t *testing.T) {
+	d := newDistribution([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	assert.InDelta(t, 5.0, d.mean, 1e-9)
+	assert.InDelta(t, 2.0, d.stddev, 1e-9)
+	assert.InDelta(t, 2.0, d.score(9), 1e-9)
+	assert.Zero(t, newDistribution([]float64{3, 3, 3}).score(3))
+}
+
+func outlierMetrics(n int) []models.ComplexityMetric {
+	metrics := make([]models.ComplexityMetric, n)
+	for i := range metrics {
+		metrics[i] = models.ComplexityMetric{
+			FilePath:             "/service.go",
+			FunctionName:         fmt.Sprintf("f%d", i),
+			StartLine:            10 * i,
+			LinesOfCode:          10 + i%5,
+			CyclomaticComplexity: 2 + i%3,
+		}
+	}
+	return metrics
+}
+
+func TestOutlierDetector(t *testing.T) {
+	t.Run("flags functions far above the mean", func(t *testing.T) {
+		metrics := outlierMetrics(40)
+		metrics[7].LinesOfCode = 20
+		metrics[20].CyclomaticComplexity = 30
+		// Lambdas neither count nor get flagged.
+		metrics = append(metrics, models.ComplexityMetric{FunctionName: "<lambda>", LinesOfCode: 500})
+
+		detector := &outlierDetector{}
+		detector.add(metrics)
+		issues := detector.issues(metrics)
+		require.Len(t, issues, 2)
+
+		long, branchy := issues[0], issues[1]
+		assert.Equal(t, "complexity_outlier", long.IssueType)
+		assert.Equal(t, outlierRuleID, *long.ToolRuleID)
+		assert.Equal(t, "f7", long.Metadata["function_name"])
+		assert.Contains(t, long.Message, "Function 'f7' is a statistical outlier: 20 lines")
+		assert.NotContains(t, long.Message, "cyclomatic")
+
+		assert.Equal(t, "f20", branchy.Metadata["function_name"])
+		assert.Contains(t, branchy.Message, "cyclomatic complexity 30")
+		assert.Contains(t, *branchy.Description, "unusually branchy")
+		assert.Equal(t, "medium", branchy.Severity)
+		assert.Greater(t, branchy.ConfidenceScore, long.ConfidenceScore, "the more extreme function is the more certain outlier")
+		assert.LessOrEqual(t, branchy.ConfidenceScore, 0.95)
+	})
+
+	t.Run("needs enough functions", func(t *testing.T) {
+		metrics := outlierMetrics(minOutlierFunctions - 1)
+		metrics[0].LinesOfCode = 1000
+		detector := &outlierDetector{}
+		detector.add(metrics)
+		assert.Empty(t, detector.issues(metrics))
+	})
+}
+
+func TestComplexityAnalyzer_OutlierDetection(t *testing.T) {
+	dir := t.TempDir()
+	var src strings.Builder
+	for i := 0; i < 35; i++ {
+		fmt.Fprintf(&src, "def handler_%d(x):\n    return x + %d\n\n", i, i)
+	}
+	src.WriteString("def migrate(rows):\n    total = 0\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&src, "    total += rows[%d]\n", i)
+	}
+	src.WriteString("    return total\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "handlers.py"), []byte(src.String()), 0644))
+
+	ctx, repo := complexityTestContext(t, dir)
+	outlierIssues := func(config models.ComplexityConfig) (*analysis.Result, []models.TechnicalDebtIssue) {
+		t.Helper()
+		result, err := NewComplexityAnalyzer(nil).Analyze(analysis.WithComplexityConfig(ctx, config), repo)
+		require.NoError(t, err)
+		var issues []models.TechnicalDebtIssue
+		for _, issue := range result.Issues {
+			if issue.IssueType == "complexity_outlier" {
+				issues = append(issues, issue)
+			}
+		}
+		return result, issues
+	}
+
+	result, issues := outlierIssues(models.ComplexityConfig{CyclomaticThreshold: 10})
+	assert.Empty(t, issues, "outlier detection is opt-in")
+	assert.NotContains(t, result.Metrics, "complexity_outliers")
+
+	result, issues = outlierIssues(models.ComplexityConfig{CyclomaticThreshold: 10, OutlierDetection: true})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "/handlers.py", issues[0].FilePath)
+	assert.Equal(t, "migrate", issues[0].Metadata["function_name"])
+	assert.Equal(t, 1, result.Metrics["complexity_outliers"])
+}
@@ -34,6 +34,7 @@ type TrivyResult struct {
 	Target          string               `json:"Target"`
 	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities"`
 	Secrets         []TrivySecret        `json:"Secrets"`
+	Licenses        []TrivyLicense       `json:"Licenses"`
 }
 
 type TrivyVulnerability struct {
@@ -57,18 +58,31 @@ type TrivySecret struct {
 	Match     string `json:"Match"`
 }
 
+// TrivyLicense is a finding of Trivy's license scanner: the license of a
+// package, or one detected in a file (LICENSE, a source header) when
+// PkgName is empty. Category is Trivy's risk classification of the license.
+type TrivyLicense struct {
+	Severity   string  `json:"Severity"`
+	Category   string  `json:"Category"`
+	PkgName    string  `json:"PkgName"`
+	FilePath   string  `json:"FilePath"`
+	Name       string  `json:"Name"`
+	Confidence float64 `json:"Confidence"`
+	Link       string  `json:"Link"`
+}
+
 func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
-	analysisRunID, ok := ctx.Value("analysisRunID").(uuid.UUID)
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("analysisRunID not found in context")
 	}
 
-	repositoryID, ok := ctx.Value("repositoryID").(uuid.UUID)
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("repositoryID not found in context")
 	}
 
-	userID, ok := ctx.Value("userID").(uuid.UUID)
+	userID, ok := analysis.UserIDFromContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("userID not found in context")
 	}
@@ -97,8 +111,13 @@ func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*ana
 		}, nil
 	}
 
-	cmd := exec.CommandContext(ctx, "trivy", "fs",
-		"--scanners", "vuln,secret",
+	scanners := "vuln,secret"
+	licenses := analysis.LicensePolicyFromContext(ctx)
+	if licenses.Enabled {
+		scanners += ",license"
+	}
+	cmd := analysis.CommandContext(ctx, "trivy", "fs",
+		"--scanners", scanners,
 		"--format", "json",
 		"--quiet",
 		repo.Path)
@@ -117,39 +136,12 @@ func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*ana
 
 	var issues []models.TechnicalDebtIssue
 	now := time.Now()
+	costs := analysis.SecurityDebtCostsFromContext(ctx)
+	allowedLicenses := 0
 
 	for _, result := range trivyResult.Results {
 		for _, vuln := range result.Vulnerabilities {
-			message := fmt.Sprintf("%s: %s (%s)", vuln.VulnerabilityID, vuln.Title, vuln.PkgName)
-
-			description := vuln.Description
-			if vuln.FixedVersion != "" {
-				description += fmt.Sprintf("\n\nFixed Version: %s", vuln.FixedVersion)
-			}
-			if vuln.PrimaryURL != "" {
-				description += fmt.Sprintf("\nMore info: %s", vuln.PrimaryURL)
-			}
-
-			issues = append(issues, models.TechnicalDebtIssue{
-				ID:                 uuid.New(),
-				UserID:             userID,
-				RepositoryID:       repositoryID,
-				AnalysisRunID:      analysisRunID,
-				FilePath:           result.Target,
-				IssueType:          "security",
-				Category:           "vulnerability",
-				Severity:           mapSeverity(vuln.Severity),
-				Message:            message,
-				Description:        &description,
-				ToolName:           "trivy",
-				ToolRuleID:         &vuln.VulnerabilityID,
-				ConfidenceScore:    1.0,
-				TechnicalDebtHours: calculateSecurityDebt(vuln.Severity),
-				EffortMultiplier:   1.0,
-				Status:             "open",
-				CreatedAt:          now,
-				UpdatedAt:          now,
-			})
+			issues = append(issues, newVulnerabilityIssue(userID, repositoryID, analysisRunID, result.Target, "vulnerability", vuln, costs, now))
 		}
 
 		for _, secret := range result.Secrets {
@@ -173,13 +165,25 @@ func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*ana
 				ToolName:           "trivy",
 				ToolRuleID:         &ruleID,
 				ConfidenceScore:    1.0,
-				TechnicalDebtHours: 4.0,
+				TechnicalDebtHours: costs.SecretCost(secret.Category),
 				EffortMultiplier:   1.0,
 				Status:             "open",
-				CreatedAt:          now,
-				UpdatedAt:          now,
+				Metadata: map[string]interface{}{
+					"secret_category": secret.Category,
+					"end_line":        secret.EndLine,
+				},
+				CreatedAt: now,
+				UpdatedAt: now,
 			})
 		}
+
+		for _, license := range result.Licenses {
+			if licenses.Allows(license.Name) {
+				allowedLicenses++
+				continue
+			}
+			issues = append(issues, newLicenseIssue(userID, repositoryID, analysisRunID, result.Target, license, costs, now))
+		}
 	}
 
 	metrics := map[string]interface{}{
@@ -192,6 +196,10 @@ func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*ana
 		"low_issues_count":      countBySeverity(issues, "low"),
 		"trivy_available":       true,
 	}
+	if licenses.Enabled {
+		metrics["licenses_count"] = countByCategory(issues, "license")
+		metrics["licenses_allowed_count"] = allowedLicenses
+	}
 
 	return &analysis.Result{
 		Issues:  issues,
@@ -199,31 +207,145 @@ func (a *TrivyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*ana
 	}, nil
 }
 
-func mapSeverity(trivySeverity string) string {
-	switch strings.ToUpper(trivySeverity) {
-	case "CRITICAL":
+// newVulnerabilityIssue converts a Trivy vulnerability found in target into
+// a security issue of the given category, costed by costs.
+func newVulnerabilityIssue(userID, repositoryID, analysisRunID uuid.UUID, target, category string, vuln TrivyVulnerability, costs models.SecurityDebtCosts, now time.Time) models.TechnicalDebtIssue {
+	message := fmt.Sprintf("%s: %s (%s)", vuln.VulnerabilityID, vuln.Title, vuln.PkgName)
+
+	description := vuln.Description
+	if vuln.FixedVersion != "" {
+		description += fmt.Sprintf("\n\nFixed Version: %s", vuln.FixedVersion)
+	}
+	if vuln.PrimaryURL != "" {
+		description += fmt.Sprintf("\nMore info: %s", vuln.PrimaryURL)
+	}
+
+	ruleID := vuln.VulnerabilityID
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           target,
+		IssueType:          "security",
+		Category:           category,
+		Severity:           mapSeverity(vuln.Severity),
+		Message:            message,
+		Description:        &description,
+		ToolName:           "trivy",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    1.0,
+		TechnicalDebtHours: costs.VulnerabilityHours(mapSeverity(vuln.Severity)),
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata: map[string]interface{}{
+			"pkg_name":          vuln.PkgName,
+			"installed_version": vuln.InstalledVersion,
+			"fixed_version":     vuln.FixedVersion,
+			"primary_url":       vuln.PrimaryURL,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// newLicenseIssue converts a Trivy license finding in target into a
+// compliance issue. Its severity is Trivy's, which follows the license's
+// risk category, and it is costed like a vulnerability of that severity:
+// replacing a dependency takes about as long as upgrading one.
+func newLicenseIssue(userID, repositoryID, analysisRunID uuid.UUID, target string, license TrivyLicense, costs models.SecurityDebtCosts, now time.Time) models.TechnicalDebtIssue {
+	severity := mapLicenseSeverity(license)
+	filePath := target
+	subject := fmt.Sprintf("package %s", license.PkgName)
+	if license.PkgName == "" {
+		if license.FilePath != "" {
+			filePath = license.FilePath
+		}
+		subject = filePath
+	}
+	category := strings.ToLower(license.Category)
+	if category == "" {
+		category = "unknown"
+	}
+
+	description := fmt.Sprintf("%s is distributed under %s, which Trivy classifies as %s. "+
+		"Check that the license is compatible with how this project is distributed, replace the dependency, "+
+		"or add the license to licenses.allow in .debtdrone.yaml once it has been approved.", subject, license.Name, category)
+	if license.Link != "" {
+		description += fmt.Sprintf("\nMore info: %s", license.Link)
+	}
+
+	ruleID := license.Name
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           filePath,
+		IssueType:          "compliance",
+		Category:           "license",
+		Severity:           severity,
+		Message:            fmt.Sprintf("License %s (%s) in %s", license.Name, category, subject),
+		Description:        &description,
+		ToolName:           "trivy",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    licenseConfidence(license),
+		TechnicalDebtHours: costs.VulnerabilityHours(severity),
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata: map[string]interface{}{
+			"license":          license.Name,
+			"license_category": category,
+			"pkg_name":         license.PkgName,
+			"link":             license.Link,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// mapLicenseSeverity returns the severity of a license finding. Trivy sets
+// it from the license category; older releases leave it empty, in which
+// case the category decides the same way.
+func mapLicenseSeverity(license TrivyLicense) string {
+	if license.Severity != "" {
+		return mapSeverity(license.Severity)
+	}
+	switch strings.ToLower(license.Category) {
+	case "forbidden":
 		return "critical"
-	case "HIGH":
+	case "restricted":
 		return "high"
-	case "MEDIUM":
+	case "reciprocal":
 		return "medium"
-	case "LOW":
+	case "notice", "permissive", "unencumbered":
 		return "low"
 	default:
 		return "info"
 	}
 }
 
-func calculateSecurityDebt(severity string) float64 {
-	switch strings.ToUpper(severity) {
+// licenseConfidence is Trivy's confidence in a license detected from file
+// contents; licenses declared in package metadata are certain.
+func licenseConfidence(license TrivyLicense) float64 {
+	if license.Confidence > 0 && license.Confidence <= 1 {
+		return license.Confidence
+	}
+	return 1.0
+}
+
+func mapSeverity(trivySeverity string) string {
+	switch strings.ToUpper(trivySeverity) {
 	case "CRITICAL":
-		return 8.0
+		return "critical"
 	case "HIGH":
-		return 4.0
+		return "high"
 	case "MEDIUM":
-		return 2.0
+		return "medium"
+	case "LOW":
+		return "low"
 	default:
-		return 1.0
+		return "info"
 	}
 }
 
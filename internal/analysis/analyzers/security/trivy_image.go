@@ -0,0 +1,131 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// TrivyImageAnalyzer scans the container image set with
+// analysis.WithContainerImage using `trivy image`. The reference is always
+// explicit so a scan never pulls an image nobody asked for.
+type TrivyImageAnalyzer struct{}
+
+func NewTrivyImageAnalyzer() *TrivyImageAnalyzer {
+	return &TrivyImageAnalyzer{}
+}
+
+func (a *TrivyImageAnalyzer) Name() string {
+	return "Trivy Container Image Scanner"
+}
+
+func (a *TrivyImageAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	image := analysis.ContainerImageFromContext(ctx)
+	if image == "" {
+		return skippedImageScan(image, "no container image given"), nil
+	}
+
+	if _, err := exec.LookPath("trivy"); err != nil {
+		log.Println("⚠️  Trivy not installed - skipping container image scan. Install with: brew install aquasec/trivy/trivy")
+		return skippedImageScan(image, "trivy not installed"), nil
+	}
+
+	cmd := analysis.CommandContext(ctx, "trivy", "image",
+		"--scanners", "vuln",
+		"--format", "json",
+		"--quiet",
+		image)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// Trivy reports an image it cannot pull or find on stderr with an empty
+	// stdout; that only skips the scan, like a missing binary does.
+	output, err := cmd.Output()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || len(bytes.TrimSpace(output)) > 0 {
+			return nil, fmt.Errorf("trivy image execution failed: %w, output: %s", err, stderr.String())
+		}
+		detail := lastLine(stderr.String())
+		if detail == "" {
+			detail = err.Error()
+		}
+		reason := fmt.Sprintf("image %s could not be scanned: %s", image, detail)
+		log.Printf("⚠️  %s - skipping container image scan", reason)
+		return skippedImageScan(image, reason), nil
+	}
+
+	var trivyResult TrivyOutput
+	if err := json.Unmarshal(output, &trivyResult); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy image output: %w", err)
+	}
+
+	var issues []models.TechnicalDebtIssue
+	now := time.Now()
+	costs := analysis.SecurityDebtCostsFromContext(ctx)
+	for _, result := range trivyResult.Results {
+		for _, vuln := range result.Vulnerabilities {
+			issue := newVulnerabilityIssue(userID, repositoryID, analysisRunID, result.Target, "container_vulnerability", vuln, costs, now)
+			issue.Metadata["image"] = image
+			issues = append(issues, issue)
+		}
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"container_image":                 image,
+			"container_vulnerabilities_count": len(issues),
+			"container_critical_issues_count": countBySeverity(issues, "critical"),
+			"container_high_issues_count":     countBySeverity(issues, "high"),
+		},
+	}, nil
+}
+
+// skippedImageScan is the empty result of a scan that could not run. Its
+// metrics are prefixed so they never overwrite the filesystem scan's.
+func skippedImageScan(image, reason string) *analysis.Result {
+	return &analysis.Result{
+		Issues: []models.TechnicalDebtIssue{},
+		Metrics: map[string]interface{}{
+			"container_image":                 image,
+			"container_vulnerabilities_count": 0,
+			"container_skip_reason":           reason,
+		},
+	}
+}
+
+// lastLine returns the last non-empty line of s, which is where Trivy puts
+// the reason of a fatal error.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,94 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrivy puts a trivy script running body on PATH.
+func fakeTrivy(t *testing.T, body string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stand-in for trivy")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "trivy"), []byte("#!/bin/sh\n"+body+"\n"), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func imageScanContext(image string) context.Context {
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	return analysis.WithContainerImage(ctx, image)
+}
+
+func TestTrivyImageAnalyzer_Analyze(t *testing.T) {
+	fakeTrivy(t, `[ "$1" = image ] && [ "$7" = "alpine:3.18" ] || exit 9
+cat <<'JSON'
+{"Results":[{"Target":"alpine:3.18 (alpine 3.18.4)","Vulnerabilities":[
+ {"VulnerabilityID":"CVE-2023-5363","PkgName":"libcrypto3","InstalledVersion":"3.1.3-r0","FixedVersion":"3.1.4-r0","Title":"openssl: Incorrect cipher key","Severity":"HIGH"},
+ {"VulnerabilityID":"CVE-2023-5678","PkgName":"libssl3","Severity":"MEDIUM"}]}]}
+JSON`)
+
+	result, err := NewTrivyImageAnalyzer().Analyze(imageScanContext("alpine:3.18"), &git.Repository{})
+	require.NoError(t, err)
+	require.Len(t, result.Issues, 2)
+	assert.Empty(t, models.ValidateIssues(result.Issues), "image issues must be storable")
+
+	issue := result.Issues[0]
+	assert.Equal(t, "security", issue.IssueType)
+	assert.Equal(t, "container_vulnerability", issue.Category)
+	assert.Equal(t, "high", issue.Severity)
+	assert.Equal(t, "alpine:3.18 (alpine 3.18.4)", issue.FilePath)
+	assert.Equal(t, "CVE-2023-5363", *issue.ToolRuleID)
+	assert.Equal(t, "alpine:3.18", issue.Metadata["image"])
+	assert.Contains(t, *issue.Description, "Fixed Version: 3.1.4-r0")
+	assert.Equal(t, "CVE-2023-5678", *result.Issues[1].ToolRuleID)
+
+	assert.Equal(t, 2, result.Metrics["container_vulnerabilities_count"])
+	assert.Equal(t, 1, result.Metrics["container_high_issues_count"])
+	assert.NotContains(t, result.Metrics, "container_skip_reason")
+}
+
+func TestTrivyImageAnalyzer_Skips(t *testing.T) {
+	t.Run("image cannot be pulled", func(t *testing.T) {
+		fakeTrivy(t, `echo "2024-01-01T00:00:00Z	INFO	Need to update DB" >&2
+echo "2024-01-01T00:00:00Z	FATAL	image scan error: unable to find the specified image" >&2
+exit 1`)
+		result, err := NewTrivyImageAnalyzer().Analyze(imageScanContext("acme/missing:1"), &git.Repository{})
+		require.NoError(t, err)
+		assert.Empty(t, result.Issues)
+		assert.Contains(t, result.Metrics["container_skip_reason"], "image acme/missing:1 could not be scanned")
+		assert.Contains(t, result.Metrics["container_skip_reason"], "unable to find the specified image")
+	})
+
+	t.Run("trivy not installed", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		result, err := NewTrivyImageAnalyzer().Analyze(imageScanContext("alpine:3.18"), &git.Repository{})
+		require.NoError(t, err)
+		assert.Equal(t, "trivy not installed", result.Metrics["container_skip_reason"])
+	})
+
+	t.Run("no image given", func(t *testing.T) {
+		fakeTrivy(t, "exit 9")
+		result, err := NewTrivyImageAnalyzer().Analyze(imageScanContext(""), &git.Repository{})
+		require.NoError(t, err)
+		assert.Equal(t, "no container image given", result.Metrics["container_skip_reason"])
+	})
+
+	t.Run("unparseable output is an error", func(t *testing.T) {
+		fakeTrivy(t, "echo not json")
+		_, err := NewTrivyImageAnalyzer().Analyze(imageScanContext("alpine:3.18"), &git.Repository{})
+		assert.ErrorContains(t, err, "failed to parse trivy image output")
+	})
+}
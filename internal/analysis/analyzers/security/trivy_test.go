@@ -0,0 +1,134 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trivyFSReport = `[ "$1" = fs ] || exit 9
+cat <<'JSON'
+{"Results":[{"Target":"go.sum","Vulnerabilities":[
+ {"VulnerabilityID":"CVE-1","PkgName":"a","Severity":"CRITICAL"},
+ {"VulnerabilityID":"CVE-2","PkgName":"b","Severity":"HIGH"},
+ {"VulnerabilityID":"CVE-3","PkgName":"c","Severity":"MEDIUM"},
+ {"VulnerabilityID":"CVE-4","PkgName":"d","Severity":"LOW"},
+ {"VulnerabilityID":"CVE-5","PkgName":"e","Severity":"UNKNOWN"}]},
+ {"Target":"deploy/key.pem","Secrets":[
+ {"RuleID":"private-key","Category":"AsymmetricPrivateKey","Severity":"HIGH","Title":"Asymmetric Private Key","StartLine":1},
+ {"RuleID":"slack-web-hook","Category":"Slack","Severity":"MEDIUM","Title":"Slack Webhook","StartLine":4}]}]}
+JSON`
+
+// debtHours maps each issue's rule ID to its debt hours.
+func debtHours(issues []models.TechnicalDebtIssue) map[string]float64 {
+	hours := map[string]float64{}
+	for _, issue := range issues {
+		hours[*issue.ToolRuleID] = issue.TechnicalDebtHours
+	}
+	return hours
+}
+
+func TestTrivyAnalyzer_DefaultDebtCosts(t *testing.T) {
+	fakeTrivy(t, trivyFSReport)
+
+	result, err := NewTrivyAnalyzer().Analyze(imageScanContext(""), &git.Repository{Path: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"CVE-1":          8,
+		"CVE-2":          4,
+		"CVE-3":          2,
+		"CVE-4":          1,
+		"CVE-5":          1,
+		"private-key":    4,
+		"slack-web-hook": 4,
+	}, debtHours(result.Issues))
+}
+
+func TestTrivyAnalyzer_ConfiguredDebtCosts(t *testing.T) {
+	fakeTrivy(t, trivyFSReport)
+
+	ctx := analysis.WithSecurityDebtCosts(imageScanContext(""), models.SecurityDebtCosts{
+		SeverityHours:       map[string]float64{"Critical": 16, "info": 0.5},
+		SecretHours:         1,
+		SecretCategoryHours: map[string]float64{"asymmetricprivatekey": 12},
+	})
+	result, err := NewTrivyAnalyzer().Analyze(ctx, &git.Repository{Path: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"CVE-1":          16,
+		"CVE-2":          4,
+		"CVE-3":          2,
+		"CVE-4":          1,
+		"CVE-5":          0.5,
+		"private-key":    12,
+		"slack-web-hook": 1,
+	}, debtHours(result.Issues))
+}
+
+const trivyLicenseReport = `[ "$1" = fs ] && [ "$3" = vuln,secret,license ] || exit 9
+cat <<'JSON'
+{"Results":[{"Target":"go.sum","Class":"lang-pkgs","Vulnerabilities":[
+ {"VulnerabilityID":"CVE-1","PkgName":"a","Severity":"CRITICAL"}]},
+ {"Target":"Go","Class":"license","Licenses":[
+ {"Severity":"HIGH","Category":"restricted","PkgName":"github.com/example/gpl","Name":"GPL-3.0","Confidence":1,"Link":"https://spdx.org/licenses/GPL-3.0.html"},
+ {"Severity":"LOW","Category":"notice","PkgName":"github.com/example/mit","Name":"MIT","Confidence":1},
+ {"Category":"reciprocal","PkgName":"github.com/example/mpl","Name":"MPL-2.0"}]},
+ {"Target":"Loose File License(s)","Class":"license-file","Licenses":[
+ {"Severity":"CRITICAL","Category":"forbidden","FilePath":"vendor/x/LICENSE","Name":"AGPL-3.0","Confidence":0.9}]}]}
+JSON`
+
+func TestTrivyAnalyzer_Licenses(t *testing.T) {
+	fakeTrivy(t, trivyLicenseReport)
+
+	ctx := analysis.WithLicensePolicy(imageScanContext(""), models.LicensePolicy{
+		Enabled: true,
+		Allowed: []string{"mit"},
+	})
+	result, err := NewTrivyAnalyzer().Analyze(ctx, &git.Repository{Path: t.TempDir()})
+	require.NoError(t, err)
+	require.Len(t, result.Issues, 4)
+	assert.Empty(t, models.ValidateIssues(result.Issues), "license issues must be storable")
+	assert.Equal(t, "vulnerability", result.Issues[0].Category)
+
+	gpl := result.Issues[1]
+	assert.Equal(t, "compliance", gpl.IssueType)
+	assert.Equal(t, "license", gpl.Category)
+	assert.Equal(t, "high", gpl.Severity)
+	assert.Equal(t, "Go", gpl.FilePath)
+	assert.Equal(t, "GPL-3.0", *gpl.ToolRuleID)
+	assert.Equal(t, "License GPL-3.0 (restricted) in package github.com/example/gpl", gpl.Message)
+	assert.Contains(t, *gpl.Description, "https://spdx.org/licenses/GPL-3.0.html")
+	assert.Equal(t, 4.0, gpl.TechnicalDebtHours)
+
+	mpl := result.Issues[2]
+	assert.Equal(t, "medium", mpl.Severity, "severity falls back to the category")
+	assert.Equal(t, 1.0, mpl.ConfidenceScore)
+
+	agpl := result.Issues[3]
+	assert.Equal(t, "critical", agpl.Severity)
+	assert.Equal(t, "vendor/x/LICENSE", agpl.FilePath)
+	assert.Equal(t, "License AGPL-3.0 (forbidden) in vendor/x/LICENSE", agpl.Message)
+	assert.Equal(t, 0.9, agpl.ConfidenceScore)
+
+	assert.Equal(t, 3, result.Metrics["licenses_count"])
+	assert.Equal(t, 1, result.Metrics["licenses_allowed_count"])
+}
+
+func TestTrivyAnalyzer_LicenseScanOptIn(t *testing.T) {
+	fakeTrivy(t, `[ "$3" = vuln,secret ] || exit 9
+cat <<'JSON'
+{"Results":[{"Target":"go.sum","Vulnerabilities":[{"VulnerabilityID":"CVE-1","PkgName":"a","Severity":"HIGH"}]},
+ {"Target":"deploy/key.pem","Secrets":[{"RuleID":"private-key","Category":"AsymmetricPrivateKey","Severity":"HIGH","Title":"Asymmetric Private Key","StartLine":1}]}]}
+JSON`)
+
+	result, err := NewTrivyAnalyzer().Analyze(imageScanContext(""), &git.Repository{Path: t.TempDir()})
+	require.NoError(t, err)
+	require.Len(t, result.Issues, 2)
+	assert.Equal(t, "vulnerability", result.Issues[0].Category)
+	assert.Equal(t, "secret", result.Issues[1].Category)
+	assert.NotContains(t, result.Metrics, "licenses_count")
+}
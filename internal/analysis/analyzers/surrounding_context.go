@@ -0,0 +1,48 @@
+package analyzers
+
+import (
+	"os"
+	"strings"
+)
+
+// surroundingContextPadding is the number of lines shown above and below a
+// flagged function in the issue's SurroundingContext.
+const surroundingContextPadding = 3
+
+// extractSurroundingContext returns lines startLine-pad through endLine+pad
+// (1-based, inclusive) of the file at filePath. The range is clamped to the
+// file, so a file that shrank between parsing and extraction yields whatever
+// lines remain instead of panicking. An unreadable file or a range that lies
+// entirely past the end of the file yields "".
+func extractSurroundingContext(filePath string, startLine, endLine, pad int) string {
+	if startLine <= 0 {
+		return ""
+	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	if pad < 0 {
+		pad = 0
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil || len(content) == 0 {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	from := startLine - pad
+	if from < 1 {
+		from = 1
+	}
+	to := endLine + pad
+	if to > len(lines) {
+		to = len(lines)
+	}
+	if from > to {
+		return ""
+	}
+
+	return strings.Join(lines[from-1:to], "\n")
+}
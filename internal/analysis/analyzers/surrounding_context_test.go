@@ -0,0 +1,36 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSurroundingContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sample.txt")
+	require.NoError(t, os.WriteFile(path, []byte("l1\nl2\nl3\nl4\nl5\nl6\nl7\n"), 0644))
+
+	tests := []struct {
+		name            string
+		start, end, pad int
+		expected        string
+	}{
+		{"padded range", 3, 4, 1, "l2\nl3\nl4\nl5"},
+		{"clamped at file start", 1, 2, 3, "l1\nl2\nl3\nl4\nl5"},
+		{"file shorter than expected", 6, 12, 1, "l5\nl6\nl7"},
+		{"range entirely past end of file", 20, 25, 2, ""},
+		{"end before start", 4, 2, 0, "l4"},
+		{"unknown start line", 0, 3, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractSurroundingContext(path, tt.start, tt.end, tt.pad))
+		})
+	}
+
+	assert.Empty(t, extractSurroundingContext(filepath.Join(t.TempDir(), "missing.go"), 1, 2, 1))
+}
@@ -0,0 +1,29 @@
+const fs = require('fs');
+const { execSync } = require('child_process');
+const express = require('express');
+
+// Startup code may block: nothing is being served yet.
+const config = JSON.parse(fs.readFileSync('config.json', 'utf8'));
+
+const app = express();
+
+app.get('/report', (req, res) => {
+  const data = fs.readFileSync('report.txt');
+  res.send(data);
+});
+
+async function loadUsers() {
+  const raw = await fs.promises.readFile('users.json');
+  const rev = execSync('git rev-parse HEAD');
+  return [raw, rev];
+}
+
+async function loadAll(paths) {
+  return paths.map((p) => fs.statSync(p));
+}
+
+function helper(paths) {
+  return fs.readdirSync(paths);
+}
+
+app.listen(config.port);
@@ -0,0 +1,12 @@
+import * as fs from 'fs';
+
+export class Worker {
+  async run(path: string): Promise<string> {
+    fs.writeFileSync(path, 'started');
+    return path;
+  }
+
+  sync(path: string): void {
+    fs.writeFileSync(path, 'done');
+  }
+}
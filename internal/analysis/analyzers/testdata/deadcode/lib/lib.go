@@ -0,0 +1,13 @@
+package lib
+
+type Stack[T any] struct{ items []T }
+
+func (s *Stack[T]) peek() T { return s.items[len(s.items)-1] }
+
+func (s *Stack[T]) Pop() T {
+	item := s.peek()
+	s.items = s.items[:len(s.items)-1]
+	return item
+}
+
+func (s *Stack[T]) reset() { s.items = nil }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	_ "unsafe"
+)
+
+type greeter interface {
+	greet() string
+}
+
+type english struct{}
+
+func (english) greet() string { return "hello" }
+
+func (e *english) unusedMethod() {}
+
+func main() {
+	var g greeter = english{}
+	fmt.Println(g.greet(), helper())
+}
+
+func init() {}
+
+func helper() int { return 1 }
+
+func unused() {}
+
+func recursive(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return recursive(n - 1)
+}
+
+func testedOnly() {}
+
+//go:linkname linked runtime.nanotime
+func linked() int64 { return 0 }
+
+func Exported() {}
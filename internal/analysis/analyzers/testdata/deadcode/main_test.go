@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func TestTestedOnly(t *testing.T) { testedOnly() }
+
+func unusedTestHelper() {}
@@ -0,0 +1,11 @@
+module example.com/svc
+
+go 1.21
+
+require github.com/davecgh/go-spew v1.1.1
+
+require (
+	github.com/old/abandoned v0.0.0-20190311183353-d8887717615a // indirect
+	github.com/fresh/module v0.0.0-20260101120000-abcdefabcdef
+	github.com/legacy/lib v2.3.0+incompatible
+)
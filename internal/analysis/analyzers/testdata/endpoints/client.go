@@ -0,0 +1,19 @@
+package client
+
+// DefaultBaseURL is the production API, e.g. https://api.payments.io/v2.
+const DefaultBaseURL = "https://api.payments.io/v2"
+
+var (
+	cacheAddr   = "10.0.4.12:6379"
+	localAddr   = "http://localhost:8080/health"
+	docsAddr    = "https://docs.example.com/setup"
+	schema      = "http://www.w3.org/2001/XMLSchema"
+	netmask     = "255.255.255.0"
+	template    = "http://%s:%d/metrics"
+	allowedHost = "https://status.partner.net/ping"
+)
+
+func dial() string {
+	// The fallback used to be http://legacy.payments.io.
+	return `wss://[2001:4860::8888]:443/stream`
+}
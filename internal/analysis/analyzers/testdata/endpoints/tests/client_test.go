@@ -0,0 +1,3 @@
+package tests
+
+var server = "http://10.1.1.1:9000"
@@ -0,0 +1,4 @@
+"""Worker reading from http://queue.internal.corp:5672."""
+
+BROKER = "amqp://guest@queue.internal.corp:5672/"  # see http://wiki.corp/queues
+FALLBACK = '192.168.1.40'
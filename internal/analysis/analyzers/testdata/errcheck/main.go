@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func save(path string) error {
+	return os.WriteFile(path, []byte("data"), 0644)
+}
+
+func parse(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func ignored() {
+	save("a.txt")       // ignored: statement context
+	_ = save("b.txt")   // ignored: blank assignment
+	n, _ := parse("42") // ignored: blank error in tuple
+	_, _ = parse("7")   // ignored: both discarded
+	os.Remove("tmp")    // ignored: stdlib call
+	fmt.Println(n)
+}
+
+func handled() error {
+	if err := save("c.txt"); err != nil {
+		return err
+	}
+	n, err := parse("1")
+	if err != nil {
+		return err
+	}
+	_ = n
+	var sb strings.Builder
+	sb.WriteString("excluded by convention")
+	fmt.Println(sb.String())
+	defer os.Remove("cleanup")
+	return nil
+}
+
+func suppressed() {
+	save("d.txt") //nolint:errcheck // best effort
+	save("e.txt") //nolint
+	save("f.txt") //nolint:govet
+}
+
+func main() {
+	ignored()
+	if err := handled(); err != nil {
+		os.Exit(1)
+	}
+	suppressed()
+}
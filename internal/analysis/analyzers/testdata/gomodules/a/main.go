@@ -0,0 +1,7 @@
+package main
+
+import "example.com/app/lib"
+
+func main() {
+	lib.Load()
+}
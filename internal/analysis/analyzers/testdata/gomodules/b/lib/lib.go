@@ -0,0 +1,3 @@
+package lib
+
+func Load() int { return 0 }
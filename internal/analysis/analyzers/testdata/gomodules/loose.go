@@ -0,0 +1,7 @@
+package loose
+
+import "os"
+
+func cleanup() {
+	os.Remove("tmp")
+}
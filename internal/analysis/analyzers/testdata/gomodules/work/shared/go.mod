@@ -0,0 +1,3 @@
+module example.com/shared // shared helpers
+
+go 1.22
@@ -0,0 +1,13 @@
+// Package main is a line-counter fixture.
+package main
+
+import "fmt"
+
+/*
+Block comment spanning
+three lines.
+*/
+func main() {
+	// say hello
+	fmt.Println("hello") // trailing comments count as code
+}
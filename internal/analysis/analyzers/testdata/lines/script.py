@@ -0,0 +1,8 @@
+# A tiny fixture
+
+def greet(name):
+    # inline comment
+    return "hi " + name
+
+
+print(greet("drone"))
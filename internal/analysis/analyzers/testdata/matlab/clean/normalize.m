@@ -0,0 +1,10 @@
+function out = normalize(values)
+% NORMALIZE scales values to the range [0, 1].
+lo = min(values);
+hi = max(values);
+if hi == lo
+    out = zeros(size(values));
+    return
+end
+out = (values - lo) / (hi - lo);
+end
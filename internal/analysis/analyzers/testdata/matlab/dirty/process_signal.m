@@ -0,0 +1,43 @@
+function result = process_signal(signal, mode, threshold, window, verbose)
+% PROCESS_SIGNAL filters and classifies a signal with too many branches.
+result = zeros(size(signal));
+for k = 1:numel(signal)
+    if signal(k) > threshold && mode == 1
+        for w = 1:window
+            if k + w <= numel(signal)
+                if signal(k + w) > signal(k) || verbose
+                    result(k) = result(k) + 1;
+                elseif signal(k + w) < 0
+                    result(k) = result(k) - 1;
+                else
+                    switch mode
+                        case 1
+                            result(k) = 0;
+                        case 2
+                            result(k) = -1;
+                        otherwise
+                            result(k) = NaN;
+                    end
+                end
+            end
+        end
+    elseif signal(k) < -threshold
+        while result(k) > -threshold && k > 1
+            result(k) = result(k) - 1;
+            if verbose
+                disp(k);
+            end
+        end
+    end
+end
+result = smooth(result);
+
+    function s = smooth(r)
+        s = r;
+        for j = 2:numel(r) - 1
+            if isnan(r(j))
+                s(j) = 0;
+            end
+        end
+    end
+end
@@ -0,0 +1,48 @@
+def status_text(code):
+    """Describe an HTTP status code."""
+    text = str(code)
+    if code == 200:
+        return text + " 200"
+    if code == 201:
+        return text + " 201"
+    if code == 202:
+        return text + " 202"
+    if code == 203:
+        return text + " 203"
+    if code == 204:
+        return text + " 204"
+    if code == 205:
+        return text + " 205"
+    if code == 206:
+        return text + " 206"
+    if code == 207:
+        return text + " 207"
+    if code == 208:
+        return text + " 208"
+    if code == 209:
+        return text + " 209"
+    if code == 210:
+        return text + " 210"
+    if code == 211:
+        return text + " 211"
+    if code == 212:
+        return text + " 212"
+    if code == 213:
+        return text + " 213"
+    if code == 214:
+        return text + " 214"
+    if code == 215:
+        return text + " 215"
+    if code == 216:
+        return text + " 216"
+    if code == 217:
+        return text + " 217"
+    if code == 218:
+        return text + " 218"
+    if code == 219:
+        return text + " 219"
+    if code == 220:
+        return text + " 220"
+    if code == 221:
+        return text + " 221"
+    return text
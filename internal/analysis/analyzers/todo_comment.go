@@ -0,0 +1,275 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// todoDebtHours is the estimated effort to resolve the work one marker
+// defers.
+const todoDebtHours = 0.5
+
+// The tool rules of todo_comment issues.
+const (
+	todoRuleID        = "todo"
+	overdueTodoRuleID = "overdue-todo"
+)
+
+// todoMarkerPattern matches a debt marker opening a comment line, after the
+// comment syntax and any indentation: the marker, an optional parenthesized
+// note such as "(JIRA-123)" or "(alice, 2024-06-01)" and the text after it.
+// Markers must be upper case, so prose such as "a todo list" is left alone.
+var todoMarkerPattern = regexp.MustCompile(`(?m)^[ \t/*#!;-]*(TODO|FIXME|HACK|XXX)\b(?:\(([^)\n]*)\))?[ \t]*:?[ \t]*(.*)$`)
+
+// ticketPattern matches an issue tracker reference: a Jira-style key
+// (PROJ-123) or a GitHub-style number, optionally qualified by its repository
+// (#123, acme/api#123).
+var ticketPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b|(?:\b[\w.-]+/[\w.-]+)?#[0-9]+\b`)
+
+// dueDatePattern matches a deadline in the text of a marker: an ISO date
+// after "before", "by", "until", "due" or "deadline".
+var dueDatePattern = regexp.MustCompile(`(?i)\b(?:before|by|until|due(?:\s+(?:by|on))?|deadline)\s*:?\s*([0-9]{4}-[0-9]{2}-[0-9]{2})\b`)
+
+// isoDatePattern matches a date the note of a marker holds on its own, as in
+// TODO(2024-06-01).
+var isoDatePattern = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+
+// TodoAnalyzer reports the TODO, FIXME, HACK and XXX markers in comments as
+// deferred work. A marker may reference a ticket and carry a due date, both
+// recorded in the issue metadata; one whose due date has passed is escalated
+// to high severity, so a quality gate can fail on forgotten promises.
+type TodoAnalyzer struct {
+	now func() time.Time
+}
+
+func NewTodoAnalyzer() *TodoAnalyzer {
+	return &TodoAnalyzer{now: time.Now}
+}
+
+func (a *TodoAnalyzer) Name() string {
+	return "TodoAnalyzer"
+}
+
+func (a *TodoAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	analysisRunID, ok := analysis.RunIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("analysisRunID not found in context")
+	}
+
+	repositoryID, ok := analysis.RepositoryIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("repositoryID not found in context")
+	}
+
+	userID, ok := analysis.UserIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("userID not found in context")
+	}
+
+	now := a.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	ignore := analysis.IgnoreMatcherFromContext(ctx)
+	issues := []models.TechnicalDebtIssue{}
+	overdue := 0
+	err := walkRepository(ctx, repo.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "dist", "build":
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if !isCodeFile(ext) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		relPath, err := filepath.Rel(repo.Path, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = "/" + filepath.ToSlash(relPath)
+
+		for _, marker := range findTodos(content, ext) {
+			issue := todoIssue(userID, repositoryID, analysisRunID, relPath, marker, today)
+			if *issue.ToolRuleID == overdueTodoRuleID {
+				overdue++
+			}
+			issues = append(issues, issue)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !analysis.IsCLI(ctx) {
+		log.Printf("✅ TODO comment check found %d issues (%d overdue)", len(issues), overdue)
+	}
+
+	return &analysis.Result{
+		Issues: issues,
+		Metrics: map[string]interface{}{
+			"todo_comments_count":         len(issues),
+			"overdue_todo_comments_count": overdue,
+		},
+	}, nil
+}
+
+// todo is a debt marker found at line.
+type todo struct {
+	line int
+	// marker is TODO, FIXME, HACK or XXX.
+	marker string
+	// text is what the comment says after the marker and its note.
+	text   string
+	ticket string
+	// due is the deadline of the marker; it is zero when there is none.
+	due   time.Time
+	owner string
+}
+
+// findTodos returns the debt markers opening a line of the comments of
+// content, using the comment syntax of ext. Markers in string literals are
+// not reported.
+func findTodos(content []byte, ext string) []todo {
+	var todos []todo
+	for _, lit := range sourceLiterals(content, ext) {
+		if lit.kind == stringLiteral {
+			continue
+		}
+		for _, loc := range todoMarkerPattern.FindAllStringSubmatchIndex(lit.text, -1) {
+			note := ""
+			if loc[4] >= 0 {
+				note = lit.text[loc[4]:loc[5]]
+			}
+			t := parseTodo(lit.text[loc[2]:loc[3]], note, lit.text[loc[6]:loc[7]])
+			t.line = lit.line + strings.Count(lit.text[:loc[2]], "\n")
+			todos = append(todos, t)
+		}
+	}
+	return todos
+}
+
+// parseTodo reads the ticket, due date and owner of a marker. The note in
+// parentheses is a comma-separated list of tickets, dates and an owner; a
+// ticket or a due date ("before 2024-06-01") may also appear in the text.
+func parseTodo(marker, note, text string) todo {
+	t := todo{marker: marker, text: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "*/"))}
+	for _, part := range strings.Split(note, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case isoDatePattern.MatchString(part):
+			if due, err := time.Parse(time.DateOnly, part); err == nil && t.due.IsZero() {
+				t.due = due
+			}
+		case ticketPattern.FindString(part) == part:
+			if t.ticket == "" {
+				t.ticket = part
+			}
+		case t.owner == "":
+			t.owner = part
+		}
+	}
+	if t.ticket == "" {
+		t.ticket = ticketPattern.FindString(t.text)
+	}
+	if t.due.IsZero() {
+		if m := dueDatePattern.FindStringSubmatch(t.text); m != nil {
+			if due, err := time.Parse(time.DateOnly, m[1]); err == nil {
+				t.due = due
+			}
+		}
+	}
+	return t
+}
+
+// todoIssue reports t in the file at relPath. FIXME, HACK and XXX mark code
+// known to be wrong, so they are of medium severity and a TODO is low; a
+// marker due before today is high whatever its kind.
+func todoIssue(userID, repositoryID, analysisRunID uuid.UUID, relPath string, t todo, today time.Time) models.TechnicalDebtIssue {
+	line := t.line
+	ruleID := todoRuleID
+	severity := "low"
+	if t.marker != "TODO" {
+		severity = "medium"
+	}
+	summary := t.text
+	if len(summary) > 100 {
+		summary = summary[:97] + "..."
+	}
+	message := fmt.Sprintf("%s comment: %s", t.marker, summary)
+	metadata := map[string]interface{}{
+		"marker": t.marker,
+	}
+	if t.ticket != "" {
+		metadata["ticket"] = t.ticket
+	}
+	if t.owner != "" {
+		metadata["owner"] = t.owner
+	}
+	if !t.due.IsZero() {
+		due := t.due.Format(time.DateOnly)
+		overdue := t.due.Before(today)
+		metadata["due_date"] = due
+		metadata["overdue"] = overdue
+		if overdue {
+			ruleID = overdueTodoRuleID
+			severity = "high"
+			message = fmt.Sprintf("Overdue %s comment (due %s): %s", t.marker, due, summary)
+		}
+	}
+	description := fmt.Sprintf("The comment defers work with a %s marker. Do the work, or track it in the "+
+		"issue tracker and give the marker a ticket reference, e.g. %s(PROJ-123), and a deadline, "+
+		"e.g. \"before 2025-01-31\"; a marker past its deadline is reported as high severity.", t.marker, t.marker)
+
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		RepositoryID:       repositoryID,
+		AnalysisRunID:      analysisRunID,
+		FilePath:           relPath,
+		LineNumber:         &line,
+		IssueType:          "todo_comment",
+		Severity:           severity,
+		Category:           "maintainability",
+		Message:            message,
+		Description:        &description,
+		ToolName:           "todo_comment",
+		ToolRuleID:         &ruleID,
+		ConfidenceScore:    0.9,
+		TechnicalDebtHours: todoDebtHours,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata:           metadata,
+	}
+}
@@ -0,0 +1,88 @@
+package analyzers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTodo(t *testing.T) {
+	date := func(value string) time.Time {
+		due, err := time.Parse(time.DateOnly, value)
+		require.NoError(t, err)
+		return due
+	}
+	tests := []struct {
+		name   string
+		source string
+		ext    string
+		want   []todo
+	}{
+		{"plain", "// TODO: handle retries\n", ".go", []todo{{line: 1, marker: "TODO", text: "handle retries"}}},
+		{"jira note", "x := 1 // TODO(JIRA-123): drop the fallback\n", ".go",
+			[]todo{{line: 1, marker: "TODO", text: "drop the fallback", ticket: "JIRA-123"}}},
+		{"owner and date note", "\n# FIXME(alice, 2024-06-01) remove once v2 ships\n", ".py",
+			[]todo{{line: 2, marker: "FIXME", text: "remove once v2 ships", owner: "alice", due: date("2024-06-01")}}},
+		{"deadline in the text", "// FIXME before 2024-06-01: migrate callers\n", ".go",
+			[]todo{{line: 1, marker: "FIXME", text: "before 2024-06-01: migrate callers", due: date("2024-06-01")}}},
+		{"github ticket in a block comment", "/*\n * HACK: works around acme/api#42,\n * due by 2030-01-31\n */\n", ".ts",
+			[]todo{{line: 2, marker: "HACK", text: "works around acme/api#42,", ticket: "acme/api#42"}}},
+		{"ruby", "# XXX until 2025-12-31 (#7)\n", ".rb",
+			[]todo{{line: 1, marker: "XXX", text: "until 2025-12-31 (#7)", ticket: "#7", due: date("2025-12-31")}}},
+		{"prose and strings are not markers", "// keep the todo list short\nlog(\"TODO: not a comment\")\n// see TODO below\n", ".js", nil},
+		{"invalid date", "// TODO by 2024-13-45\n", ".go", []todo{{line: 1, marker: "TODO", text: "by 2024-13-45"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findTodos([]byte(tt.source), tt.ext))
+		})
+	}
+}
+
+func TestTodoAnalyzer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n\n// TODO(PAY-9): support refunds\nfunc main() {}\n\n// FIXME before 2024-06-01: remove the v1 client\n",
+		"worker.py": "# TODO(2031-01-01): batch the writes\n# HACK: sleep until the queue drains\n",
+		"README.md": "TODO: document the flags\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	ctx, repo := complexityTestContext(t, dir)
+	analyzer := NewTodoAnalyzer()
+	analyzer.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	result, err := analyzer.Analyze(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Metrics["todo_comments_count"])
+	assert.Equal(t, 1, result.Metrics["overdue_todo_comments_count"])
+
+	var found []string
+	for _, issue := range result.Issues {
+		assert.Equal(t, "todo_comment", issue.IssueType)
+		assert.Equal(t, "maintainability", issue.Category)
+		require.NotNil(t, issue.LineNumber)
+		found = append(found, fmt.Sprintf("%s:%d %s %s %s", issue.FilePath, *issue.LineNumber, issue.Severity, *issue.ToolRuleID, issue.Message))
+
+		switch *issue.LineNumber {
+		case 3:
+			assert.Equal(t, map[string]interface{}{"marker": "TODO", "ticket": "PAY-9"}, issue.Metadata)
+		case 6:
+			assert.Equal(t, map[string]interface{}{"marker": "FIXME", "due_date": "2024-06-01", "overdue": true}, issue.Metadata)
+		}
+	}
+	sort.Strings(found)
+	assert.Equal(t, []string{
+		"/main.go:3 low todo TODO comment: support refunds",
+		"/main.go:6 high overdue-todo Overdue FIXME comment (due 2024-06-01): before 2024-06-01: remove the v1 client",
+		"/worker.py:1 low todo TODO comment: batch the writes",
+		"/worker.py:2 medium todo HACK comment: sleep until the queue drains",
+	}, found)
+}
@@ -0,0 +1,18 @@
+package analyzers
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+)
+
+// walkRepository walks the files of root selected by analysis.WithTargetFiles,
+// or the whole tree when ctx selects none. Symbolic links are never visited,
+// so no analyzer reads a file outside root.
+func walkRepository(ctx context.Context, root string, fn fs.WalkDirFunc) error {
+	if walked, err := analysis.WalkTargetFiles(ctx, root, fn); walked {
+		return err
+	}
+	return analysis.WalkDir(root, fn)
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"sync"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// AnalyzerOutcome is what one analyzer of a run reported to a Collector.
+type AnalyzerOutcome struct {
+	// Analyzer is the name the analyzer was registered under.
+	Analyzer string
+	Result   *Result
+	Err      error
+	// Ran is false for an analyzer that never reported, e.g. because the
+	// run was cancelled before it started.
+	Ran bool
+}
+
+// Collector gathers the results of analyzers that run concurrently. Each
+// analyzer reports under its position in the run, so the merged issues and
+// metrics come out in the order of the analyzers, whichever finishes first.
+// It is safe for concurrent use.
+type Collector struct {
+	mu       sync.Mutex
+	outcomes []AnalyzerOutcome
+}
+
+// NewCollector returns a Collector for the analyzers of a run, named in the
+// order they were selected in.
+func NewCollector(analyzers []string) *Collector {
+	outcomes := make([]AnalyzerOutcome, len(analyzers))
+	for i, name := range analyzers {
+		outcomes[i].Analyzer = name
+	}
+	return &Collector{outcomes: outcomes}
+}
+
+// Add records the result or error of the analyzer at index. An analyzer
+// reporting twice replaces its first outcome.
+func (c *Collector) Add(index int, result *Result, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outcomes[index].Result = result
+	c.outcomes[index].Err = err
+	c.outcomes[index].Ran = true
+}
+
+// Outcomes returns a copy of the outcome of every analyzer, in the order
+// they were passed to NewCollector.
+func (c *Collector) Outcomes() []AnalyzerOutcome {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	outcomes := make([]AnalyzerOutcome, len(c.outcomes))
+	copy(outcomes, c.outcomes)
+	return outcomes
+}
+
+// Merge concatenates the issues and merges the metrics of the analyzers that
+// succeeded, in analyzer order. When two analyzers report the same metric,
+// the later analyzer's value is kept.
+func (c *Collector) Merge() ([]models.TechnicalDebtIssue, map[string]interface{}) {
+	var issues []models.TechnicalDebtIssue
+	metrics := make(map[string]interface{})
+	for _, outcome := range c.Outcomes() {
+		if !outcome.Ran || outcome.Err != nil || outcome.Result == nil {
+			continue
+		}
+		issues = append(issues, outcome.Result.Issues...)
+		for k, v := range outcome.Result.Metrics {
+			metrics[k] = v
+		}
+	}
+	return issues, metrics
+}
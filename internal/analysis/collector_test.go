@@ -0,0 +1,99 @@
+package analysis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// busyAnalyzer reports issues and metrics once every analyzer of the test
+// has started, so they all write into the collector at the same time.
+type busyAnalyzer struct {
+	name    string
+	issues  int
+	started *sync.WaitGroup
+}
+
+func (a *busyAnalyzer) Name() string { return a.name }
+
+func (a *busyAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	a.started.Done()
+	a.started.Wait()
+	result := &analysis.Result{Metrics: map[string]interface{}{
+		a.name + "_issues": a.issues,
+		"shared":           a.name,
+	}}
+	for i := 0; i < a.issues; i++ {
+		result.Issues = append(result.Issues, models.TechnicalDebtIssue{ToolName: a.name, Message: fmt.Sprintf("%s #%d", a.name, i)})
+	}
+	return result, nil
+}
+
+func TestCollector_ConcurrentAnalyzers(t *testing.T) {
+	var started sync.WaitGroup
+	analyzersList := []analysis.Analyzer{
+		&busyAnalyzer{name: "first", issues: 300, started: &started},
+		&busyAnalyzer{name: "second", issues: 200, started: &started},
+	}
+	started.Add(len(analyzersList))
+
+	collector := analysis.NewCollector([]string{"first", "second"})
+	var wg sync.WaitGroup
+	for i, analyzer := range analyzersList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := analyzer.Analyze(context.Background(), nil)
+			collector.Add(i, result, err)
+		}()
+	}
+	wg.Wait()
+
+	issues, metrics := collector.Merge()
+	require.Len(t, issues, 500)
+	assert.Equal(t, "first #0", issues[0].Message, "issues are merged in analyzer order")
+	assert.Equal(t, "second #0", issues[300].Message)
+	assert.Equal(t, 300, metrics["first_issues"])
+	assert.Equal(t, 200, metrics["second_issues"])
+	assert.Equal(t, "second", metrics["shared"], "the later analyzer's metric wins")
+
+	outcomes := collector.Outcomes()
+	require.Len(t, outcomes, 2)
+	for i, outcome := range outcomes {
+		assert.True(t, outcome.Ran)
+		require.NoError(t, outcome.Err)
+		assert.Equal(t, analyzersList[i].Name(), outcome.Analyzer)
+		for _, issue := range outcome.Result.Issues {
+			assert.Equal(t, outcome.Analyzer, issue.ToolName, "each outcome keeps the issues of its analyzer")
+		}
+	}
+}
+
+func TestCollector_FailedAndPendingAnalyzers(t *testing.T) {
+	collector := analysis.NewCollector([]string{"lines", "security", "complexity"})
+	collector.Add(1, nil, errors.New("trivy failed"))
+	collector.Add(0, &analysis.Result{
+		Issues:  []models.TechnicalDebtIssue{{Message: "counted"}},
+		Metrics: map[string]interface{}{"total_lines": 10},
+	}, nil)
+
+	issues, metrics := collector.Merge()
+	require.Len(t, issues, 1)
+	assert.Equal(t, map[string]interface{}{"total_lines": 10}, metrics)
+
+	outcomes := collector.Outcomes()
+	assert.EqualError(t, outcomes[1].Err, "trivy failed")
+	assert.False(t, outcomes[2].Ran, "an analyzer that never reported did not run")
+
+	issues, metrics = analysis.NewCollector(nil).Merge()
+	assert.Empty(t, issues)
+	assert.NotNil(t, metrics)
+}
@@ -0,0 +1,31 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// ValidateMinConfidence reports an error when min is outside [0, 1].
+func ValidateMinConfidence(min float64) error {
+	if min < 0 || min > 1 {
+		return fmt.Errorf("invalid minimum confidence %v (must be between 0.0 and 1.0)", min)
+	}
+	return nil
+}
+
+// FilterByConfidence returns the issues whose ConfidenceScore is at least min.
+// Issues exactly at the threshold are kept.
+func FilterByConfidence(issues []models.TechnicalDebtIssue, min float64) []models.TechnicalDebtIssue {
+	if min <= 0 {
+		return issues
+	}
+
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		if issue.ConfidenceScore >= min {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
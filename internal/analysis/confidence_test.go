@@ -0,0 +1,50 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterByConfidence(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{Message: "certain", ConfidenceScore: 1.0},
+		{Message: "heuristic", ConfidenceScore: 0.8},
+		{Message: "guess", ConfidenceScore: 0.5},
+	}
+
+	messages := func(issues []models.TechnicalDebtIssue) []string {
+		var out []string
+		for _, issue := range issues {
+			out = append(out, issue.Message)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		min  float64
+		want []string
+	}{
+		{"zero keeps everything", 0.0, []string{"certain", "heuristic", "guess"}},
+		{"threshold equal to a score keeps it", 0.8, []string{"certain", "heuristic"}},
+		{"just above a score drops it", 0.81, []string{"certain"}},
+		{"one keeps only certain issues", 1.0, []string{"certain"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, messages(analysis.FilterByConfidence(issues, tt.min)))
+		})
+	}
+	assert.Len(t, issues, 3, "input slice must not be modified")
+}
+
+func TestValidateMinConfidence(t *testing.T) {
+	assert.NoError(t, analysis.ValidateMinConfidence(0))
+	assert.NoError(t, analysis.ValidateMinConfidence(1))
+	assert.Error(t, analysis.ValidateMinConfidence(-0.1))
+	assert.Error(t, analysis.ValidateMinConfidence(1.01))
+}
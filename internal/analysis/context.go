@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	"context"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// contextKey is unexported so no other package can read or overwrite the
+// values below except through the accessors.
+type contextKey int
+
+const (
+	runIDKey contextKey = iota
+	repositoryIDKey
+	userIDKey
+	cliKey
+	complexityConfigKey
+	targetFilesKey
+	blockingAPIsKey
+	ignoreMatcherKey
+	fileCacheKey
+	containerImageKey
+	securityDebtCostsKey
+	subprocessLimitsKey
+	baseContentsKey
+	licensePolicyKey
+	endpointPolicyKey
+)
+
+// WithRunID returns a copy of ctx carrying the analysis run ID.
+func WithRunID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, runIDKey, id)
+}
+
+// RunIDFromContext returns the analysis run ID set by WithRunID.
+func RunIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(runIDKey).(uuid.UUID)
+	return id, ok
+}
+
+// WithRepositoryID returns a copy of ctx carrying the repository ID.
+func WithRepositoryID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, repositoryIDKey, id)
+}
+
+// RepositoryIDFromContext returns the repository ID set by WithRepositoryID.
+func RepositoryIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(repositoryIDKey).(uuid.UUID)
+	return id, ok
+}
+
+// WithUserID returns a copy of ctx carrying the ID of the user the run is for.
+func WithUserID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserIDFromContext returns the user ID set by WithUserID.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(userIDKey).(uuid.UUID)
+	return id, ok
+}
+
+// WithCLI marks ctx as belonging to a CLI or TUI run, which silences the
+// analyzers' progress logging.
+func WithCLI(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cliKey, true)
+}
+
+// IsCLI reports whether ctx was marked by WithCLI.
+func IsCLI(ctx context.Context) bool {
+	cli, _ := ctx.Value(cliKey).(bool)
+	return cli
+}
+
+// WithComplexityConfig returns a copy of ctx carrying the complexity thresholds.
+func WithComplexityConfig(ctx context.Context, config models.ComplexityConfig) context.Context {
+	return context.WithValue(ctx, complexityConfigKey, config)
+}
+
+// ComplexityConfigFromContext returns the thresholds set by WithComplexityConfig.
+func ComplexityConfigFromContext(ctx context.Context) (models.ComplexityConfig, bool) {
+	config, ok := ctx.Value(complexityConfigKey).(models.ComplexityConfig)
+	return config, ok
+}
+
+// WithTargetFiles restricts file-level analyzers to the given repository
+// relative paths, as used by delta scans.
+func WithTargetFiles(ctx context.Context, files []string) context.Context {
+	return context.WithValue(ctx, targetFilesKey, files)
+}
+
+// TargetFilesFromContext returns the paths set by WithTargetFiles.
+func TargetFilesFromContext(ctx context.Context) ([]string, bool) {
+	files, ok := ctx.Value(targetFilesKey).([]string)
+	return files, ok
+}
+
+// WithBlockingAPIs overrides the API list of the blocking call analyzer.
+func WithBlockingAPIs(ctx context.Context, apis []string) context.Context {
+	return context.WithValue(ctx, blockingAPIsKey, apis)
+}
+
+// BlockingAPIsFromContext returns the list set by WithBlockingAPIs.
+func BlockingAPIsFromContext(ctx context.Context) []string {
+	apis, _ := ctx.Value(blockingAPIsKey).([]string)
+	return apis
+}
+
+// WithIgnoreMatcher makes file walks skip the paths matcher ignores.
+func WithIgnoreMatcher(ctx context.Context, matcher *git.IgnoreMatcher) context.Context {
+	return context.WithValue(ctx, ignoreMatcherKey, matcher)
+}
+
+// IgnoreMatcherFromContext returns the matcher set by WithIgnoreMatcher. The
+// result is nil, which ignores nothing, when none was set.
+func IgnoreMatcherFromContext(ctx context.Context) *git.IgnoreMatcher {
+	matcher, _ := ctx.Value(ignoreMatcherKey).(*git.IgnoreMatcher)
+	return matcher
+}
+
+// WithFileCache lets file-level analyzers reuse results cached by earlier runs.
+func WithFileCache(ctx context.Context, cache *FileCache) context.Context {
+	return context.WithValue(ctx, fileCacheKey, cache)
+}
+
+// FileCacheFromContext returns the cache set by WithFileCache, or nil, which
+// caches nothing.
+func FileCacheFromContext(ctx context.Context) *FileCache {
+	cache, _ := ctx.Value(fileCacheKey).(*FileCache)
+	return cache
+}
+
+// WithContainerImage sets the image reference the container image analyzer
+// scans.
+func WithContainerImage(ctx context.Context, image string) context.Context {
+	return context.WithValue(ctx, containerImageKey, image)
+}
+
+// ContainerImageFromContext returns the reference set by WithContainerImage,
+// or "" when none was set.
+func ContainerImageFromContext(ctx context.Context) string {
+	image, _ := ctx.Value(containerImageKey).(string)
+	return image
+}
+
+// WithSecurityDebtCosts sets the cost model of the security analyzers.
+func WithSecurityDebtCosts(ctx context.Context, costs models.SecurityDebtCosts) context.Context {
+	return context.WithValue(ctx, securityDebtCostsKey, costs)
+}
+
+// SecurityDebtCostsFromContext returns the cost model set by
+// WithSecurityDebtCosts, with the defaults filled in.
+func SecurityDebtCostsFromContext(ctx context.Context) models.SecurityDebtCosts {
+	costs, _ := ctx.Value(securityDebtCostsKey).(models.SecurityDebtCosts)
+	return costs.WithDefaults()
+}
+
+// WithLicensePolicy enables the license scan of the security analyzer and
+// sets the licenses it accepts.
+func WithLicensePolicy(ctx context.Context, policy models.LicensePolicy) context.Context {
+	return context.WithValue(ctx, licensePolicyKey, policy)
+}
+
+// LicensePolicyFromContext returns the policy set by WithLicensePolicy, or a
+// disabled one.
+func LicensePolicyFromContext(ctx context.Context) models.LicensePolicy {
+	policy, _ := ctx.Value(licensePolicyKey).(models.LicensePolicy)
+	return policy
+}
+
+// WithEndpointPolicy sets the allowlist of the hardcoded endpoint analyzer
+// and whether it checks test files and comments.
+func WithEndpointPolicy(ctx context.Context, policy models.EndpointPolicy) context.Context {
+	return context.WithValue(ctx, endpointPolicyKey, policy)
+}
+
+// EndpointPolicyFromContext returns the policy set by WithEndpointPolicy, or
+// one that allows no host and skips test files and comments.
+func EndpointPolicyFromContext(ctx context.Context) models.EndpointPolicy {
+	policy, _ := ctx.Value(endpointPolicyKey).(models.EndpointPolicy)
+	return policy
+}
+
+// WithSubprocessLimits sets the resource limits CommandContext applies to
+// analyzer subprocesses.
+func WithSubprocessLimits(ctx context.Context, limits SubprocessLimits) context.Context {
+	return context.WithValue(ctx, subprocessLimitsKey, limits)
+}
+
+// SubprocessLimitsFromContext returns the limits set by
+// WithSubprocessLimits, or no limits.
+func SubprocessLimitsFromContext(ctx context.Context) SubprocessLimits {
+	limits, _ := ctx.Value(subprocessLimitsKey).(SubprocessLimits)
+	return limits
+}
+
+// WithBaseContents makes the complexity analyzer report only the functions
+// that changed since a base ref. contents holds the base content of each
+// changed file, keyed by its slash-separated path relative to the
+// repository; a file missing from it did not exist at the base, so all its
+// functions are new.
+func WithBaseContents(ctx context.Context, contents map[string][]byte) context.Context {
+	return context.WithValue(ctx, baseContentsKey, contents)
+}
+
+// BaseContentsFromContext returns the contents set by WithBaseContents.
+func BaseContentsFromContext(ctx context.Context) (map[string][]byte, bool) {
+	contents, ok := ctx.Value(baseContentsKey).(map[string][]byte)
+	return contents, ok
+}
@@ -0,0 +1,34 @@
+package analysis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextAccessors(t *testing.T) {
+	ctx := context.Background()
+
+	_, ok := analysis.RunIDFromContext(ctx)
+	assert.False(t, ok)
+	assert.False(t, analysis.IsCLI(ctx))
+	assert.Nil(t, analysis.BlockingAPIsFromContext(ctx))
+
+	runID, repoID, userID := uuid.New(), uuid.New(), uuid.New()
+	ctx = analysis.WithRunID(ctx, runID)
+	ctx = analysis.WithRepositoryID(ctx, repoID)
+	ctx = analysis.WithUserID(ctx, userID)
+	ctx = analysis.WithCLI(ctx)
+
+	got, ok := analysis.RunIDFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, runID, got)
+	got, _ = analysis.RepositoryIDFromContext(ctx)
+	assert.Equal(t, repoID, got)
+	got, _ = analysis.UserIDFromContext(ctx)
+	assert.Equal(t, userID, got)
+	assert.True(t, analysis.IsCLI(ctx))
+}
@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// DefaultSeverityWeights are the points an issue of each severity adds to the
+// debt score when no weights are configured. Severities without a weight add
+// nothing.
+var DefaultSeverityWeights = map[string]int{
+	"critical": 4,
+	"high":     2,
+	"medium":   1,
+	"low":      0,
+}
+
+// ValidateSeverityWeights reports the first weight given for an unknown
+// severity or below zero.
+func ValidateSeverityWeights(weights map[string]int) error {
+	for severity, weight := range weights {
+		if !validSeverities[strings.ToLower(severity)] {
+			return fmt.Errorf("unknown severity %q (valid: critical, high, medium, low, info)", severity)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight of %s must not be negative, got %d", severity, weight)
+		}
+	}
+	return nil
+}
+
+// DebtScoreTerm is what the issues of one severity add to a debt score.
+type DebtScoreTerm struct {
+	Severity string
+	Issues   int
+	Weight   int
+}
+
+// Points is the term's contribution to the score.
+func (t DebtScoreTerm) Points() int { return t.Issues * t.Weight }
+
+// DebtScore is the severity-weighted sum of a run's issues, which a debt
+// budget gate compares with the budget instead of failing on the first issue
+// at a severity.
+type DebtScore struct {
+	Score int
+	// Terms lists the weighted severities that issues were found at, from
+	// the most to the least severe.
+	Terms []DebtScoreTerm
+}
+
+// ComputeDebtScore weighs every issue by the weight of its severity.
+func ComputeDebtScore(issues []models.TechnicalDebtIssue, weights map[string]int) DebtScore {
+	byWeight := make(map[string]int, len(weights))
+	for severity, weight := range weights {
+		byWeight[strings.ToLower(severity)] = weight
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		severity := strings.ToLower(issue.Severity)
+		if byWeight[severity] > 0 {
+			counts[severity]++
+		}
+	}
+
+	var score DebtScore
+	for severity, n := range counts {
+		term := DebtScoreTerm{Severity: severity, Issues: n, Weight: byWeight[severity]}
+		score.Terms = append(score.Terms, term)
+		score.Score += term.Points()
+	}
+	sort.Slice(score.Terms, func(i, j int) bool {
+		return severityRanks[score.Terms[i].Severity] > severityRanks[score.Terms[j].Severity]
+	})
+	return score
+}
+
+// Breakdown renders the terms of the score, e.g. "2 critical × 4 + 1 high × 2".
+func (s DebtScore) Breakdown() string {
+	if len(s.Terms) == 0 {
+		return "no weighted issues"
+	}
+	parts := make([]string, len(s.Terms))
+	for i, term := range s.Terms {
+		parts[i] = fmt.Sprintf("%d %s × %d", term.Issues, term.Severity, term.Weight)
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,42 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDebtScore(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{Severity: "critical"}, {Severity: "high"}, {Severity: "Critical"},
+		{Severity: "medium"}, {Severity: "low"}, {Severity: "info"},
+	}
+
+	score := analysis.ComputeDebtScore(issues, analysis.DefaultSeverityWeights)
+	assert.Equal(t, 11, score.Score)
+	assert.Equal(t, []analysis.DebtScoreTerm{
+		{Severity: "critical", Issues: 2, Weight: 4},
+		{Severity: "high", Issues: 1, Weight: 2},
+		{Severity: "medium", Issues: 1, Weight: 1},
+	}, score.Terms, "severities without weight are left out")
+	assert.Equal(t, "2 critical × 4 + 1 high × 2 + 1 medium × 1", score.Breakdown())
+
+	score = analysis.ComputeDebtScore(issues, map[string]int{"LOW": 5, "info": 1})
+	assert.Equal(t, 6, score.Score)
+	assert.Equal(t, "1 low × 5 + 1 info × 1", score.Breakdown())
+}
+
+func TestComputeDebtScore_Empty(t *testing.T) {
+	score := analysis.ComputeDebtScore(nil, analysis.DefaultSeverityWeights)
+	assert.Zero(t, score.Score)
+	assert.Equal(t, "no weighted issues", score.Breakdown())
+}
+
+func TestValidateSeverityWeights(t *testing.T) {
+	assert.NoError(t, analysis.ValidateSeverityWeights(nil))
+	assert.NoError(t, analysis.ValidateSeverityWeights(map[string]int{"Critical": 10, "info": 0}))
+	assert.ErrorContains(t, analysis.ValidateSeverityWeights(map[string]int{"blocker": 1}), `unknown severity "blocker"`)
+	assert.ErrorContains(t, analysis.ValidateSeverityWeights(map[string]int{"high": -1}), "must not be negative")
+}
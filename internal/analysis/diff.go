@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// SeverityChange is an issue present in both runs whose severity differs.
+// Issue is the head run's copy.
+type SeverityChange struct {
+	Issue       models.TechnicalDebtIssue `json:"issue"`
+	OldSeverity string                    `json:"old_severity"`
+	NewSeverity string                    `json:"new_severity"`
+}
+
+// RunDiff describes how the issues of a head run differ from a base run.
+type RunDiff struct {
+	BaseRunID       uuid.UUID                   `json:"base_run_id"`
+	HeadRunID       uuid.UUID                   `json:"head_run_id"`
+	Added           []models.TechnicalDebtIssue `json:"added"`
+	Resolved        []models.TechnicalDebtIssue `json:"resolved"`
+	SeverityChanged []SeverityChange            `json:"severity_changed"`
+	// DebtHoursDelta is the head run's total debt hours minus the base run's.
+	DebtHoursDelta float64 `json:"debt_hours_delta"`
+	// BaseRunAt is when the base run happened, set when it was read from a
+	// LocalState rather than a stored run.
+	BaseRunAt *time.Time `json:"base_run_at,omitempty"`
+}
+
+// DiffIssues compares the issues of two runs of the same repository. Issues are
+// matched by their canonical fingerprint, so an issue that only moved within
+// its file is neither added nor resolved. The repository ID is left out of the
+// match because both sides describe one repository, which lets a local scan be
+// diffed against a stored run. The run IDs of the result are left for the
+// caller to fill in.
+func DiffIssues(base, head []models.TechnicalDebtIssue) RunDiff {
+	diff := RunDiff{
+		Added:           []models.TechnicalDebtIssue{},
+		Resolved:        []models.TechnicalDebtIssue{},
+		SeverityChanged: []SeverityChange{},
+	}
+
+	baseByKey := make(map[string]models.TechnicalDebtIssue, len(base))
+	for _, issue := range base {
+		baseByKey[DiffKey(issue)] = issue
+		diff.DebtHoursDelta -= issue.TechnicalDebtHours
+	}
+
+	seen := make(map[string]bool, len(head))
+	for _, issue := range head {
+		diff.DebtHoursDelta += issue.TechnicalDebtHours
+
+		key := DiffKey(issue)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		previous, ok := baseByKey[key]
+		if !ok {
+			diff.Added = append(diff.Added, issue)
+			continue
+		}
+		if previous.Severity != issue.Severity {
+			diff.SeverityChanged = append(diff.SeverityChanged, SeverityChange{
+				Issue:       issue,
+				OldSeverity: previous.Severity,
+				NewSeverity: issue.Severity,
+			})
+		}
+	}
+
+	for key, issue := range baseByKey {
+		if !seen[key] {
+			diff.Resolved = append(diff.Resolved, issue)
+		}
+	}
+	// Map iteration order is random; keep the report stable.
+	sort.SliceStable(diff.Resolved, func(i, j int) bool {
+		if diff.Resolved[i].FilePath != diff.Resolved[j].FilePath {
+			return diff.Resolved[i].FilePath < diff.Resolved[j].FilePath
+		}
+		return diff.Resolved[i].Message < diff.Resolved[j].Message
+	})
+
+	return diff
+}
+
+// DiffKey identifies issue across scans of one repository: its fingerprint
+// without the repository ID, which differs between a local checkout (see
+// service.RepositoryID) and the repository the server stores runs under.
+func DiffKey(issue models.TechnicalDebtIssue) string {
+	issue.RepositoryID = uuid.Nil
+	return issue.Fingerprint()
+}
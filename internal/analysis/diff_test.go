@@ -0,0 +1,64 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffIssue(path string, line int, message, severity string, hours float64) models.TechnicalDebtIssue {
+	rule := "cyclomatic-complexity"
+	return models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		FilePath:           path,
+		LineNumber:         &line,
+		IssueType:          "complexity",
+		ToolRuleID:         &rule,
+		Message:            message,
+		Severity:           severity,
+		TechnicalDebtHours: hours,
+	}
+}
+
+func TestDiffIssues(t *testing.T) {
+	base := []models.TechnicalDebtIssue{
+		diffIssue("/a.go", 10, "Function 'parse' has high complexity (21)", "medium", 1.0),
+		diffIssue("/a.go", 40, "Function 'render' has high complexity (18)", "medium", 0.5),
+		diffIssue("/b.go", 5, "Function 'old' has high complexity (30)", "high", 2.0),
+	}
+	head := []models.TechnicalDebtIssue{
+		// Moved down the file and its complexity grew: same issue.
+		diffIssue("/a.go", 25, "Function 'parse' has high complexity (23)", "medium", 1.25),
+		// Same issue, escalated.
+		diffIssue("/a.go", 40, "Function 'render' has high complexity (26)", "high", 1.0),
+		diffIssue("/c.go", 1, "Function 'fresh' has high complexity (16)", "low", 0.25),
+	}
+
+	diff := analysis.DiffIssues(base, head)
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "/c.go", diff.Added[0].FilePath)
+	require.Len(t, diff.Resolved, 1)
+	assert.Equal(t, "/b.go", diff.Resolved[0].FilePath)
+	require.Len(t, diff.SeverityChanged, 1)
+	assert.Equal(t, "medium", diff.SeverityChanged[0].OldSeverity)
+	assert.Equal(t, "high", diff.SeverityChanged[0].NewSeverity)
+	assert.InDelta(t, -1.0, diff.DebtHoursDelta, 1e-9)
+}
+
+func TestDiffIssues_IgnoresRepositoryID(t *testing.T) {
+	stored := diffIssue("/a.go", 10, "Function 'parse' has high complexity (21)", "medium", 1.0)
+	stored.RepositoryID = uuid.New()
+	local := diffIssue("/a.go", 12, "Function 'parse' has high complexity (21)", "medium", 1.0)
+
+	diff := analysis.DiffIssues([]models.TechnicalDebtIssue{stored}, []models.TechnicalDebtIssue{local})
+
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Resolved)
+	assert.Empty(t, diff.SeverityChanged)
+	assert.Zero(t, diff.DebtHoursDelta)
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// EffortRule scales the debt of issues in files matching Pattern, a path
+// pattern as MatchPathPattern takes.
+type EffortRule struct {
+	Pattern    string
+	Multiplier float64
+}
+
+// ValidateEffortRules reports the first rule with a malformed pattern or a
+// negative multiplier.
+func ValidateEffortRules(rules []EffortRule) error {
+	for _, rule := range rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("invalid effort multiplier rule: empty path pattern")
+		}
+		if err := ValidatePathPattern(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid effort multiplier pattern %q: %w", rule.Pattern, err)
+		}
+		if rule.Multiplier < 0 {
+			return fmt.Errorf("invalid effort multiplier for %q: %v (must be 0 or greater)", rule.Pattern, rule.Multiplier)
+		}
+	}
+	return nil
+}
+
+// ApplyEffortMultipliers sets EffortMultiplier in place from the first rule
+// whose pattern matches each issue's file and returns the number of issues
+// matched. Issues no rule matches keep their multiplier. A multiplier of 0
+// removes an issue's contribution to effective debt without hiding it.
+func ApplyEffortMultipliers(issues []models.TechnicalDebtIssue, rules []EffortRule) int {
+	if len(rules) == 0 {
+		return 0
+	}
+
+	matched := 0
+	for i := range issues {
+		filePath := strings.TrimLeft(strings.ReplaceAll(issues[i].FilePath, "\\", "/"), "/")
+		for _, rule := range rules {
+			if MatchPathPattern(rule.Pattern, filePath) {
+				issues[i].EffortMultiplier = rule.Multiplier
+				matched++
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// ValidatePathPattern reports whether pattern is malformed, such as one
+// with an unclosed "[".
+func ValidatePathPattern(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchPathPattern reports whether filePath, slash-separated and relative to
+// the scanned root, matches pattern. Patterns are globs where "**" matches
+// any number of directories (e.g. "legacy/**"); a pattern without a slash
+// matches the file name in any directory (e.g. "*.generated.*").
+func MatchPathPattern(pattern, filePath string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(filePath))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, letting "**"
+// consume zero or more segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
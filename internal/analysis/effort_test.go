@@ -0,0 +1,51 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEffortMultipliers(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{FilePath: "/legacy/billing/invoice.go", EffortMultiplier: 1.0},
+		{FilePath: "/api/user.generated.go", EffortMultiplier: 1.0},
+		{FilePath: "/legacy/api.generated.go", EffortMultiplier: 1.0},
+		{FilePath: "/api/user.go", EffortMultiplier: 1.0},
+		{FilePath: "/src/legacy.go", EffortMultiplier: 1.0},
+	}
+	rules := []analysis.EffortRule{
+		{Pattern: "legacy/**", Multiplier: 2.0},
+		{Pattern: "**/*.generated.*", Multiplier: 0.0},
+	}
+
+	matched := analysis.ApplyEffortMultipliers(issues, rules)
+
+	assert.Equal(t, 3, matched)
+	assert.Equal(t, 2.0, issues[0].EffortMultiplier)
+	assert.Equal(t, 0.0, issues[1].EffortMultiplier)
+	assert.Equal(t, 2.0, issues[2].EffortMultiplier, "the first matching rule wins")
+	assert.Equal(t, 1.0, issues[3].EffortMultiplier)
+	assert.Equal(t, 1.0, issues[4].EffortMultiplier)
+}
+
+func TestApplyEffortMultipliers_BareNameMatchesAnyDirectory(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{FilePath: "/a/b/c/schema.pb.go", EffortMultiplier: 1.0},
+		{FilePath: "/schema.go", EffortMultiplier: 1.0},
+	}
+
+	analysis.ApplyEffortMultipliers(issues, []analysis.EffortRule{{Pattern: "*.pb.go", Multiplier: 0.5}})
+
+	assert.Equal(t, 0.5, issues[0].EffortMultiplier)
+	assert.Equal(t, 1.0, issues[1].EffortMultiplier)
+}
+
+func TestValidateEffortRules(t *testing.T) {
+	assert.NoError(t, analysis.ValidateEffortRules([]analysis.EffortRule{{Pattern: "legacy/**", Multiplier: 0}}))
+	assert.Error(t, analysis.ValidateEffortRules([]analysis.EffortRule{{Pattern: "legacy/**", Multiplier: -1}}))
+	assert.Error(t, analysis.ValidateEffortRules([]analysis.EffortRule{{Pattern: "legacy/[", Multiplier: 1}}))
+	assert.Error(t, analysis.ValidateEffortRules([]analysis.EffortRule{{Pattern: "", Multiplier: 1}}))
+}
@@ -51,6 +51,22 @@ func TestEngine_Golden(t *testing.T) {
 			name:     "ts_dirty",
 			repoPath: "analyzers/testdata/ts/dirty",
 		},
+		{
+			name:     "matlab_clean",
+			repoPath: "analyzers/testdata/matlab/clean",
+		},
+		{
+			name:     "matlab_dirty",
+			repoPath: "analyzers/testdata/matlab/dirty",
+		},
+		{
+			name:     "r_clean",
+			repoPath: "analyzers/testdata/r/clean",
+		},
+		{
+			name:     "r_dirty",
+			repoPath: "analyzers/testdata/r/dirty",
+		},
 	}
 
 	for _, tt := range tests {
@@ -74,9 +90,9 @@ func TestEngine_Golden(t *testing.T) {
 			repoID, _ := uuid.Parse("00000000-0000-0000-0000-000000000000")
 			userID, _ := uuid.Parse("00000000-0000-0000-0000-000000000000")
 
-			ctx = context.WithValue(ctx, "analysisRunID", runID)
-			ctx = context.WithValue(ctx, "repositoryID", repoID)
-			ctx = context.WithValue(ctx, "userID", userID)
+			ctx = analysis.WithRunID(ctx, runID)
+			ctx = analysis.WithRepositoryID(ctx, repoID)
+			ctx = analysis.WithUserID(ctx, userID)
 
 			// 4. Run Analysis
 			finalReport := map[string]interface{}{}
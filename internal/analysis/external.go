@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// externalReportVersion is the only supported value of an import file's
+// version key.
+const externalReportVersion = 1
+
+// Defaults of optional external finding fields.
+const (
+	defaultExternalIssueType  = "lint"
+	defaultExternalCategory   = "maintainability"
+	defaultExternalConfidence = 1.0
+	defaultExternalDebtHours  = 0.5
+)
+
+// externalReport is the import file schema documented in
+// docs/headless-usage.md. The same keys are used for JSON and YAML.
+type externalReport struct {
+	Version  int               `yaml:"version"`
+	Tool     string            `yaml:"tool"`
+	Findings []externalFinding `yaml:"findings"`
+}
+
+type externalFinding struct {
+	Tool        string   `yaml:"tool"`
+	File        string   `yaml:"file"`
+	Line        int      `yaml:"line"`
+	Column      int      `yaml:"column"`
+	Rule        string   `yaml:"rule"`
+	Type        string   `yaml:"type"`
+	Severity    string   `yaml:"severity"`
+	Category    string   `yaml:"category"`
+	Message     string   `yaml:"message"`
+	Description string   `yaml:"description"`
+	Confidence  *float64 `yaml:"confidence"`
+	DebtHours   *float64 `yaml:"debt_hours"`
+}
+
+// LoadExternalIssues reads a file of findings produced by another tool and
+// converts them into open issues. Files ending in .yaml or .yml are read as
+// YAML, anything else as JSON. Errors name the file and, where known, the line
+// of the offending finding, and no issue is returned unless every finding is
+// valid. FilePath is slash-separated with a leading "/", like the analyzers';
+// repository and run IDs are left to the caller.
+func LoadExternalIssues(filename string) ([]models.TechnicalDebtIssue, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file %s: %w", filename, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".yaml" && ext != ".yml" {
+		// YAML accepts a superset of JSON, so syntax errors are reported by
+		// the stricter JSON parser first.
+		if err := checkJSONSyntax(filename, data); err != nil {
+			return nil, err
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("%s: import file is empty", filename)
+	}
+	doc := root.Content[0]
+
+	var report externalReport
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&report); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if report.Version != externalReportVersion {
+		return nil, fmt.Errorf("%s:%d: missing or unsupported import version %d (want %d)", filename, doc.Line, report.Version, externalReportVersion)
+	}
+
+	// Line numbers of the findings, for the validation errors below.
+	var findingLines []int
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == "findings" {
+			for _, item := range doc.Content[i+1].Content {
+				findingLines = append(findingLines, item.Line)
+			}
+		}
+	}
+
+	issues := make([]models.TechnicalDebtIssue, 0, len(report.Findings))
+	for i, finding := range report.Findings {
+		issue, err := finding.toIssue(report.Tool)
+		if err != nil {
+			line := doc.Line
+			if i < len(findingLines) {
+				line = findingLines[i]
+			}
+			return nil, fmt.Errorf("%s:%d: finding %d: %w", filename, line, i, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func (f externalFinding) toIssue(defaultTool string) (models.TechnicalDebtIssue, error) {
+	tool := f.Tool
+	if tool == "" {
+		tool = defaultTool
+	}
+	if tool == "" {
+		return models.TechnicalDebtIssue{}, errors.New("tool is required (set it on the finding or at the top level)")
+	}
+	if f.Line < 0 || f.Column < 0 {
+		return models.TechnicalDebtIssue{}, fmt.Errorf("invalid position %d:%d (must not be negative)", f.Line, f.Column)
+	}
+
+	issue := models.TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		FilePath:           externalFilePath(f.File),
+		IssueType:          f.Type,
+		Severity:           strings.ToLower(f.Severity),
+		Category:           f.Category,
+		Message:            f.Message,
+		ToolName:           tool,
+		ConfidenceScore:    defaultExternalConfidence,
+		TechnicalDebtHours: defaultExternalDebtHours,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = defaultExternalIssueType
+	}
+	if issue.Category == "" {
+		issue.Category = defaultExternalCategory
+	}
+	if f.Line > 0 {
+		line := f.Line
+		issue.LineNumber = &line
+	}
+	if f.Column > 0 {
+		column := f.Column
+		issue.ColumnNumber = &column
+	}
+	if f.Rule != "" {
+		rule := f.Rule
+		issue.ToolRuleID = &rule
+	}
+	if f.Description != "" {
+		description := f.Description
+		issue.Description = &description
+	}
+	if f.Confidence != nil {
+		issue.ConfidenceScore = *f.Confidence
+	}
+	if f.DebtHours != nil {
+		issue.TechnicalDebtHours = *f.DebtHours
+	}
+
+	if err := issue.Validate(); err != nil {
+		return models.TechnicalDebtIssue{}, err
+	}
+	return issue, nil
+}
+
+// externalFilePath normalizes a finding's file to the analyzers' form. An
+// empty file stays empty so that Validate rejects it.
+func externalFilePath(file string) string {
+	file = strings.TrimSpace(file)
+	if file == "" {
+		return ""
+	}
+	return path.Clean("/" + filepath.ToSlash(file))
+}
+
+// checkJSONSyntax reports a JSON syntax error with the line it occurred on.
+func checkJSONSyntax(filename string, data []byte) error {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%s:%d: %w", filename, 1+bytes.Count(data[:syntaxErr.Offset], []byte("\n")), err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
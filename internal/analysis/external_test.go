@@ -0,0 +1,138 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeImportFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadExternalIssues(t *testing.T) {
+	yamlReport := `version: 1
+tool: eslint
+findings:
+  - file: src/app.js
+    line: 12
+    column: 5
+    rule: no-unused-vars
+    severity: Medium
+    message: "'x' is assigned a value but never used"
+  - tool: pylint
+    file: ./scripts/build.py
+    severity: low
+    category: style
+    message: Missing module docstring
+    confidence: 0.8
+    debt_hours: 0.1
+`
+	jsonReport := `{
+	"version": 1,
+	"tool": "eslint",
+	"findings": [
+		{"file": "src/app.js", "line": 12, "column": 5, "rule": "no-unused-vars", "severity": "Medium", "message": "'x' is assigned a value but never used"},
+		{"tool": "pylint", "file": "./scripts/build.py", "severity": "low", "category": "style", "message": "Missing module docstring", "confidence": 0.8, "debt_hours": 0.1}
+	]
+}`
+
+	for name, path := range map[string]string{
+		"yaml": writeImportFile(t, "findings.yaml", yamlReport),
+		"json": writeImportFile(t, "findings.json", jsonReport),
+	} {
+		t.Run(name, func(t *testing.T) {
+			issues, err := analysis.LoadExternalIssues(path)
+			require.NoError(t, err)
+			require.Len(t, issues, 2)
+
+			first := issues[0]
+			assert.Equal(t, "/src/app.js", first.FilePath)
+			assert.Equal(t, "eslint", first.ToolName)
+			assert.Equal(t, "lint", first.IssueType)
+			assert.Equal(t, "medium", first.Severity)
+			assert.Equal(t, "maintainability", first.Category)
+			assert.Equal(t, "open", first.Status)
+			assert.Equal(t, 1.0, first.ConfidenceScore)
+			require.NotNil(t, first.LineNumber)
+			assert.Equal(t, 12, *first.LineNumber)
+			require.NotNil(t, first.ToolRuleID)
+			assert.Equal(t, "no-unused-vars", *first.ToolRuleID)
+
+			second := issues[1]
+			assert.Equal(t, "/scripts/build.py", second.FilePath)
+			assert.Equal(t, "pylint", second.ToolName, "a finding's tool overrides the report's")
+			assert.Nil(t, second.LineNumber)
+			assert.Nil(t, second.ToolRuleID)
+			assert.Equal(t, 0.8, second.ConfidenceScore)
+			assert.Equal(t, 0.1, second.TechnicalDebtHours)
+		})
+	}
+}
+
+func TestLoadExternalIssues_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "JSON syntax error reports its line",
+			file:    "findings.json",
+			content: "{\n  \"version\": 1,\n  \"findings\": [\n    {\"file\": \"a.go\",}\n  ]\n}",
+			wantErr: "findings.json:4: invalid character '}'",
+		},
+		{
+			name:    "YAML syntax error reports its line",
+			file:    "findings.yaml",
+			content: "version: 1\nfindings:\n  - file: a.go\n    message: 'unterminated\n",
+			wantErr: "findings.yaml: yaml: line 4",
+		},
+		{
+			name:    "invalid finding reports its line and index",
+			file:    "findings.yaml",
+			content: "version: 1\ntool: eslint\nfindings:\n  - file: a.js\n    severity: low\n    message: ok\n  - file: b.js\n    severity: blocker\n    message: bad\n",
+			wantErr: "findings.yaml:7: finding 1: invalid severity \"blocker\"",
+		},
+		{
+			name:    "missing tool",
+			file:    "findings.yaml",
+			content: "version: 1\nfindings:\n  - file: a.js\n    severity: low\n    message: ok\n",
+			wantErr: "findings.yaml:3: finding 0: tool is required",
+		},
+		{
+			name:    "unknown key",
+			file:    "findings.yaml",
+			content: "version: 1\ntool: eslint\nfindings:\n  - file: a.js\n    severity: low\n    msg: typo\n",
+			wantErr: "line 6: field msg not found",
+		},
+		{
+			name:    "missing version",
+			file:    "findings.json",
+			content: `{"tool": "eslint", "findings": []}`,
+			wantErr: "missing or unsupported import version 0",
+		},
+		{
+			name:    "empty file",
+			file:    "findings.yaml",
+			content: "",
+			wantErr: "import file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := analysis.LoadExternalIssues(writeImportFile(t, tt.file, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
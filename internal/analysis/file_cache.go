@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileCacheFormat is bumped whenever the on-disk layout of FileCache changes.
+const fileCacheFormat = 1
+
+type fileCacheDocument struct {
+	Format  int                        `json:"format"`
+	Key     string                     `json:"key"`
+	Entries map[string]json.RawMessage `json:"entries"`
+}
+
+// FileCache is a local, JSON file backed cache of per-file analysis results,
+// keyed by the caller (typically by a hash of the file content). Unlike
+// AnalysisCache it needs no server and is meant for repeated CLI runs over the
+// same checkout.
+//
+// A nil *FileCache is valid and caches nothing.
+type FileCache struct {
+	path string
+	key  string
+
+	mu      sync.Mutex
+	entries map[string]json.RawMessage
+	used    map[string]bool
+	hits    int
+	misses  int
+}
+
+// OpenFileCache loads the cache stored at path. key names everything besides
+// the entry keys that cached results depend on, such as the tool version: a
+// file written under another key, a missing file and a corrupt file all yield
+// an empty cache that Save then overwrites. The returned error reports a
+// corrupt file and is informational only; the cache is usable either way.
+func OpenFileCache(path, key string) (*FileCache, error) {
+	c := &FileCache{
+		path:    path,
+		key:     key,
+		entries: map[string]json.RawMessage{},
+		used:    map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, nil
+	}
+	var doc fileCacheDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return c, fmt.Errorf("discarding corrupt cache %s: %w", path, err)
+	}
+	if doc.Format == fileCacheFormat && doc.Key == key && doc.Entries != nil {
+		c.entries = doc.Entries
+	}
+	return c, nil
+}
+
+// Get decodes the entry stored under entryKey into v and reports whether it
+// was found. An entry that no longer decodes into v counts as a miss.
+func (c *FileCache) Get(entryKey string, v interface{}) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.entries[entryKey]
+	if !ok || json.Unmarshal(data, v) != nil {
+		c.misses++
+		return false
+	}
+	c.used[entryKey] = true
+	c.hits++
+	return true
+}
+
+// Put stores v under entryKey. Values that cannot be encoded are not cached.
+func (c *FileCache) Put(entryKey string, v interface{}) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[entryKey] = data
+	c.used[entryKey] = true
+}
+
+// Stats returns the number of Get hits and misses since the cache was opened.
+func (c *FileCache) Stats() (hits, misses int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the entries read or written since the cache was opened back to
+// its file, dropping the rest so results for deleted or changed files do not
+// accumulate. The file is replaced atomically.
+func (c *FileCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	doc := fileCacheDocument{Format: fileCacheFormat, Key: c.key, Entries: make(map[string]json.RawMessage, len(c.used))}
+	for entryKey := range c.used {
+		doc.Entries[entryKey] = c.entries[entryKey]
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
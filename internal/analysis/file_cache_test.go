@@ -0,0 +1,78 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "cache.json")
+
+	cache, err := analysis.OpenFileCache(path, "v1")
+	require.NoError(t, err)
+	var got []int
+	assert.False(t, cache.Get("a", &got))
+	cache.Put("a", []int{1, 2})
+	cache.Put("b", []int{3})
+	require.NoError(t, cache.Save())
+
+	reopened, err := analysis.OpenFileCache(path, "v1")
+	require.NoError(t, err)
+	require.True(t, reopened.Get("a", &got))
+	assert.Equal(t, []int{1, 2}, got)
+	hits, misses := reopened.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 0, misses)
+
+	// Only "a" was used by this run, so "b" is dropped on save.
+	require.NoError(t, reopened.Save())
+	pruned, err := analysis.OpenFileCache(path, "v1")
+	require.NoError(t, err)
+	assert.True(t, pruned.Get("a", &got))
+	assert.False(t, pruned.Get("b", &got))
+}
+
+func TestFileCache_InvalidatedByKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := analysis.OpenFileCache(path, "v1")
+	require.NoError(t, err)
+	cache.Put("a", 1)
+	require.NoError(t, cache.Save())
+
+	other, err := analysis.OpenFileCache(path, "v2")
+	require.NoError(t, err)
+	var got int
+	assert.False(t, other.Get("a", &got))
+}
+
+func TestFileCache_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"format": 1, "entries": {`), 0644))
+
+	cache, err := analysis.OpenFileCache(path, "v1")
+	assert.ErrorContains(t, err, "corrupt cache")
+	require.NotNil(t, cache, "a corrupt cache still yields a usable empty cache")
+
+	var got int
+	assert.False(t, cache.Get("a", &got))
+	cache.Put("a", 7)
+	require.NoError(t, cache.Save())
+
+	regenerated, err := analysis.OpenFileCache(path, "v1")
+	require.NoError(t, err)
+	require.True(t, regenerated.Get("a", &got))
+	assert.Equal(t, 7, got)
+}
+
+func TestFileCache_Nil(t *testing.T) {
+	var cache *analysis.FileCache
+	var got int
+	cache.Put("a", 1)
+	assert.False(t, cache.Get("a", &got))
+	assert.NoError(t, cache.Save())
+}
@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// golangciSeverities maps golangci-lint severities, which are free-form
+// strings set by the linter or the user's severity rules, onto DebtDrone's
+// scale. Empty and unknown severities become medium.
+var golangciSeverities = map[string]string{
+	"blocker":  "critical",
+	"critical": "critical",
+	"error":    "high",
+	"high":     "high",
+	"major":    "high",
+	"warning":  "medium",
+	"medium":   "medium",
+	"minor":    "low",
+	"low":      "low",
+	"note":     "low",
+	"info":     "info",
+}
+
+// golangciReport is the subset of `golangci-lint run --out-format json`
+// (`--output.json.path` in v2) that is imported.
+type golangciReport struct {
+	Issues []golangciIssue `json:"Issues"`
+}
+
+type golangciIssue struct {
+	FromLinter string `json:"FromLinter"`
+	Text       string `json:"Text"`
+	Severity   string `json:"Severity"`
+	Pos        struct {
+		Filename string `json:"Filename"`
+		Line     int    `json:"Line"`
+		Column   int    `json:"Column"`
+	} `json:"Pos"`
+}
+
+// LoadGolangCIReport reads a golangci-lint JSON report and converts its issues
+// into open "lint" issues with the linter as ToolRuleID. Issues repeated in
+// the report at the same position are returned once. Like LoadExternalIssues,
+// file paths are expected relative to the scanned root.
+func LoadGolangCIReport(filename string) ([]models.TechnicalDebtIssue, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golangci-lint report %s: %w", filename, err)
+	}
+	if err := checkJSONSyntax(filename, data); err != nil {
+		return nil, err
+	}
+
+	var report golangciReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	seen := make(map[string]bool, len(report.Issues))
+	issues := make([]models.TechnicalDebtIssue, 0, len(report.Issues))
+	for i, lint := range report.Issues {
+		key := fmt.Sprintf("%s\x00%s\x00%d\x00%d\x00%s", lint.FromLinter, lint.Pos.Filename, lint.Pos.Line, lint.Pos.Column, lint.Text)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if lint.FromLinter == "" {
+			return nil, fmt.Errorf("%s: issue %d: FromLinter is empty", filename, i)
+		}
+
+		severity, ok := golangciSeverities[strings.ToLower(strings.TrimSpace(lint.Severity))]
+		if !ok {
+			severity = "medium"
+		}
+		linter := lint.FromLinter
+		issue := models.TechnicalDebtIssue{
+			ID:                 uuid.New(),
+			FilePath:           externalFilePath(lint.Pos.Filename),
+			IssueType:          "lint",
+			Severity:           severity,
+			Category:           defaultExternalCategory,
+			Message:            lint.Text,
+			ToolName:           "golangci-lint",
+			ToolRuleID:         &linter,
+			ConfidenceScore:    defaultExternalConfidence,
+			TechnicalDebtHours: defaultExternalDebtHours,
+			EffortMultiplier:   1.0,
+			Status:             "open",
+		}
+		if lint.Pos.Line > 0 {
+			line := lint.Pos.Line
+			issue.LineNumber = &line
+		}
+		if lint.Pos.Column > 0 {
+			column := lint.Pos.Column
+			issue.ColumnNumber = &column
+		}
+		if err := issue.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: issue %d: %w", filename, i, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
@@ -0,0 +1,52 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const golangciReport = `{
+  "Issues": [
+    {"FromLinter": "errcheck", "Text": "Error return value of ` + "`f.Close`" + ` is not checked", "Severity": "", "Pos": {"Filename": "cmd/main.go", "Offset": 120, "Line": 14, "Column": 10}},
+    {"FromLinter": "errcheck", "Text": "Error return value of ` + "`f.Close`" + ` is not checked", "Severity": "", "Pos": {"Filename": "cmd/main.go", "Offset": 120, "Line": 14, "Column": 10}},
+    {"FromLinter": "gosec", "Text": "G404: Use of weak random number generator", "Severity": "error", "Pos": {"Filename": "internal/token.go", "Line": 8, "Column": 2}},
+    {"FromLinter": "revive", "Text": "exported function Run should have comment", "Severity": "Warning", "Pos": {"Filename": "run.go", "Line": 3}},
+    {"FromLinter": "custom", "Text": "something odd", "Severity": "sev-9", "Pos": {"Filename": "run.go", "Line": 9}}
+  ],
+  "Report": {"Linters": [{"Name": "errcheck", "Enabled": true}]}
+}`
+
+func TestLoadGolangCIReport(t *testing.T) {
+	issues, err := analysis.LoadGolangCIReport(writeImportFile(t, "golangci.json", golangciReport))
+	require.NoError(t, err)
+	require.Len(t, issues, 4, "the repeated errcheck issue is imported once")
+
+	first := issues[0]
+	assert.Equal(t, "lint", first.IssueType)
+	assert.Equal(t, "golangci-lint", first.ToolName)
+	require.NotNil(t, first.ToolRuleID)
+	assert.Equal(t, "errcheck", *first.ToolRuleID)
+	assert.Equal(t, "/cmd/main.go", first.FilePath)
+	require.NotNil(t, first.LineNumber)
+	assert.Equal(t, 14, *first.LineNumber)
+	require.NotNil(t, first.ColumnNumber)
+	assert.Equal(t, 10, *first.ColumnNumber)
+
+	severities := make([]string, len(issues))
+	for i, issue := range issues {
+		severities[i] = issue.Severity
+	}
+	assert.Equal(t, []string{"medium", "high", "medium", "medium"}, severities, "empty and unknown severities default to medium")
+	assert.Nil(t, issues[2].ColumnNumber)
+}
+
+func TestLoadGolangCIReport_Errors(t *testing.T) {
+	_, err := analysis.LoadGolangCIReport(writeImportFile(t, "golangci.json", "{\n  \"Issues\": [\n    {\"FromLinter\": \"errcheck\"\n  ]\n}"))
+	assert.ErrorContains(t, err, "golangci.json:4: invalid character ']'")
+
+	_, err = analysis.LoadGolangCIReport(writeImportFile(t, "golangci.json", `{"Issues": [{"FromLinter": "errcheck", "Text": "x", "Pos": {"Filename": ""}}]}`))
+	assert.ErrorContains(t, err, "issue 0: file path is empty")
+}
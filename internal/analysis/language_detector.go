@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
 	"github.com/go-enry/go-enry/v2"
 )
 
@@ -20,18 +21,22 @@ func DetectLanguages(repoPath string) (*LanguageStats, error) {
 
 	breakdown := make(map[string]int64)
 	var totalBytes int64
+	ignore := debtdroneIgnore(repoPath)
 
-	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+	err := WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		if d.IsDir() {
 			dirName := d.Name()
-			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" {
+			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" || ignore.Ignored(path, true) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
 
 		info, err := d.Info()
 		if err != nil {
@@ -151,18 +156,22 @@ func DetectConfigFiles(repoPath string) ([]ConfigFile, error) {
 		"pytest.ini":       {"testing", "pytest"},
 		"phpunit.xml":      {"testing", "phpunit"},
 	}
+	ignore := debtdroneIgnore(repoPath)
 
-	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+	err := WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		if d.IsDir() {
 			dirName := d.Name()
-			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" {
+			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" || ignore.Ignored(path, true) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		if ignore.Ignored(path, false) {
+			return nil
+		}
 
 		relPath, err := filepath.Rel(repoPath, path)
 		if err != nil {
@@ -211,3 +220,14 @@ func DetectConfigFiles(repoPath string) ([]ConfigFile, error) {
 
 	return configFiles, nil
 }
+
+// debtdroneIgnore loads the .debtdroneignore rules of the repository at
+// repoPath. Unreadable rules only cost precision, so detection goes on
+// without them.
+func debtdroneIgnore(repoPath string) *git.IgnoreMatcher {
+	ignore, err := git.NewDebtdroneIgnoreMatcher(repoPath)
+	if err != nil {
+		log.Printf("⚠️  Ignoring %s rules for %s: %v", git.DebtdroneIgnoreFile, repoPath, err)
+	}
+	return ignore
+}
@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// severityRanks orders the severity levels for merging; unknown levels rank
+// below info.
+var severityRanks = map[string]int{
+	"critical": 5,
+	"high":     4,
+	"medium":   3,
+	"low":      2,
+	"info":     1,
+}
+
+// MergeIssues collapses issues that share a root, file, line and category
+// into a single issue, e.g. a complexity finding and an imported lint
+// finding on the same function. The merged issue is the most severe of the
+// group with the distinct messages joined, the highest confidence and debt
+// (the findings describe the same code, so their effort is not summed), and
+// the contributing tools and rules in its metadata under merged_tools,
+// merged_rules and merged_count. Issues without a line number are never
+// merged. The order of first occurrence is kept.
+func MergeIssues(issues []models.TechnicalDebtIssue) []models.TechnicalDebtIssue {
+	type group struct {
+		indexes []int
+	}
+	groups := map[string]*group{}
+	var order []string
+	for i, issue := range issues {
+		key := fmt.Sprintf("#%d", i)
+		if issue.LineNumber != nil {
+			key = strings.Join([]string{issue.Root, issue.FilePath, fmt.Sprint(*issue.LineNumber), issue.Category}, "\x00")
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.indexes = append(g.indexes, i)
+	}
+
+	merged := make([]models.TechnicalDebtIssue, 0, len(order))
+	for _, key := range order {
+		indexes := groups[key].indexes
+		if len(indexes) == 1 {
+			merged = append(merged, issues[indexes[0]])
+			continue
+		}
+		merged = append(merged, mergeGroup(issues, indexes))
+	}
+	return merged
+}
+
+// mergeGroup combines the issues at indexes, which share a location.
+func mergeGroup(issues []models.TechnicalDebtIssue, indexes []int) models.TechnicalDebtIssue {
+	base := indexes[0]
+	for _, i := range indexes[1:] {
+		if severityRanks[strings.ToLower(issues[i].Severity)] > severityRanks[strings.ToLower(issues[base].Severity)] {
+			base = i
+		}
+	}
+	result := issues[base]
+
+	var messages []string
+	seenMessages := map[string]bool{}
+	tools := map[string]bool{}
+	rules := map[string]bool{}
+	for _, i := range indexes {
+		issue := issues[i]
+		if !seenMessages[issue.Message] {
+			seenMessages[issue.Message] = true
+			messages = append(messages, issue.Message)
+		}
+		if issue.ToolName != "" {
+			tools[issue.ToolName] = true
+		}
+		if issue.ToolRuleID != nil && *issue.ToolRuleID != "" {
+			rules[*issue.ToolRuleID] = true
+		}
+		result.ConfidenceScore = max(result.ConfidenceScore, issue.ConfidenceScore)
+		result.TechnicalDebtHours = max(result.TechnicalDebtHours, issue.TechnicalDebtHours)
+	}
+	result.Message = strings.Join(messages, "; ")
+
+	metadata := make(map[string]interface{}, len(result.Metadata)+3)
+	for k, v := range result.Metadata {
+		metadata[k] = v
+	}
+	metadata["merged_tools"] = sortedKeys(tools)
+	if len(rules) > 0 {
+		metadata["merged_rules"] = sortedKeys(rules)
+	}
+	metadata["merged_count"] = len(indexes)
+	result.Metadata = metadata
+	return result
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
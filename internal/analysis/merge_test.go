@@ -0,0 +1,48 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeIssues(t *testing.T) {
+	line := func(n int) *int { return &n }
+	issues := []models.TechnicalDebtIssue{
+		{FilePath: "/main.go", LineNumber: line(10), Category: "complexity", Severity: "medium", ToolName: "debtdrone-complexity", ToolRuleID: strPtr("cyclomatic"), Message: "too complex", ConfidenceScore: 0.9, TechnicalDebtHours: 2, Metadata: map[string]interface{}{"cyclomatic": 18}},
+		{FilePath: "/main.go", LineNumber: line(12), Category: "complexity", Severity: "low", ToolName: "debtdrone-complexity", Message: "other function"},
+		{FilePath: "/main.go", LineNumber: line(10), Category: "complexity", Severity: "high", ToolName: "golangci-lint", ToolRuleID: strPtr("gocyclo"), Message: "cyclomatic complexity 18", ConfidenceScore: 0.7, TechnicalDebtHours: 1},
+		{FilePath: "/main.go", LineNumber: line(10), Category: "reliability", Severity: "medium", ToolName: "debtdrone-errcheck", Message: "ignored error"},
+		{FilePath: "/main.go", LineNumber: line(10), Category: "complexity", Severity: "low", ToolName: "golangci-lint", ToolRuleID: strPtr("gocyclo"), Message: "too complex"},
+		{FilePath: "go.sum", Category: "security", Severity: "high", ToolName: "trivy", Message: "CVE-1"},
+		{FilePath: "go.sum", Category: "security", Severity: "high", ToolName: "trivy", Message: "CVE-2"},
+		{FilePath: "/main.go", LineNumber: line(10), Category: "complexity", Severity: "low", ToolName: "golangci-lint", Message: "other root", Root: "svc"},
+	}
+
+	merged := analysis.MergeIssues(issues)
+	require.Len(t, merged, 6)
+
+	first := merged[0]
+	assert.Equal(t, "high", first.Severity)
+	assert.Equal(t, "gocyclo", *first.ToolRuleID)
+	assert.Equal(t, "too complex; cyclomatic complexity 18", first.Message)
+	assert.Equal(t, 0.9, first.ConfidenceScore)
+	assert.Equal(t, 2.0, first.TechnicalDebtHours)
+	assert.Equal(t, []string{"debtdrone-complexity", "golangci-lint"}, first.Metadata["merged_tools"])
+	assert.Equal(t, []string{"cyclomatic", "gocyclo"}, first.Metadata["merged_rules"])
+	assert.Equal(t, 3, first.Metadata["merged_count"])
+
+	assert.Equal(t, "other function", merged[1].Message)
+	assert.Equal(t, "ignored error", merged[2].Message)
+	assert.Nil(t, merged[2].Metadata)
+	assert.Equal(t, "CVE-1", merged[3].Message)
+	assert.Equal(t, "CVE-2", merged[4].Message)
+	assert.Equal(t, "other root", merged[5].Message)
+
+	// The inputs are left untouched.
+	assert.Equal(t, "medium", issues[0].Severity)
+	assert.NotContains(t, issues[0].Metadata, "merged_tools")
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constructor builds a fresh analyzer instance for a single run.
+type Constructor func() Analyzer
+
+// Registry maps short analyzer names (as used by --analyzers and
+// --disable-analyzers) to their constructors. Registration order is the order
+// in which selected analyzers run.
+type Registry struct {
+	order        []string
+	constructors map[string]Constructor
+}
+
+func NewRegistry() *Registry {
+	return &Registry{constructors: make(map[string]Constructor)}
+}
+
+// Register adds an analyzer under name. Registering the same name twice is a
+// programming error and panics.
+func (r *Registry) Register(name string, constructor Constructor) {
+	if _, exists := r.constructors[name]; exists {
+		panic(fmt.Sprintf("analysis: analyzer %q registered twice", name))
+	}
+	r.order = append(r.order, name)
+	r.constructors[name] = constructor
+}
+
+// Names returns the registered analyzer names in registration order.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.order...)
+}
+
+// Validate reports an error naming every entry of names that is not registered.
+func (r *Registry) Validate(names []string) error {
+	var unknown []string
+	for _, name := range names {
+		if _, ok := r.constructors[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown analyzer(s): %s (available: %s)", strings.Join(unknown, ", "), strings.Join(r.order, ", "))
+}
+
+// Select constructs the analyzers to run, as named by SelectNames.
+func (r *Registry) Select(enabled, disabled []string) ([]Analyzer, error) {
+	names, err := r.SelectNames(enabled, disabled)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]Analyzer, 0, len(names))
+	for _, name := range names {
+		selected = append(selected, r.constructors[name]())
+	}
+	return selected, nil
+}
+
+// SelectNames returns the names of the analyzers to run in registration
+// order. An empty enabled list selects every registered analyzer; names in
+// disabled are then removed. Both lists are validated first.
+func (r *Registry) SelectNames(enabled, disabled []string) ([]string, error) {
+	if err := r.Validate(enabled); err != nil {
+		return nil, err
+	}
+	if err := r.Validate(disabled); err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]bool, len(r.order))
+	if len(enabled) == 0 {
+		for _, name := range r.order {
+			want[name] = true
+		}
+	}
+	for _, name := range enabled {
+		want[name] = true
+	}
+	for _, name := range disabled {
+		delete(want, name)
+	}
+
+	var selected []string
+	for _, name := range r.order {
+		if want[name] {
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,72 @@
+package analysis_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+)
+
+type namedAnalyzer struct{ name string }
+
+func (a namedAnalyzer) Name() string { return a.name }
+
+func (a namedAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	return &analysis.Result{}, nil
+}
+
+func newTestRegistry() *analysis.Registry {
+	registry := analysis.NewRegistry()
+	for _, name := range []string{"lines", "complexity", "security"} {
+		registry.Register(name, func() analysis.Analyzer { return namedAnalyzer{name: name} })
+	}
+	return registry
+}
+
+func selectedNames(t *testing.T, selected []analysis.Analyzer) string {
+	t.Helper()
+	names := make([]string, len(selected))
+	for i, a := range selected {
+		names[i] = a.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRegistry_Select(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  []string
+		disabled []string
+		want     string
+	}{
+		{"default selects everything in order", nil, nil, "lines,complexity,security"},
+		{"enabled list keeps registration order", []string{"security", "lines"}, nil, "lines,security"},
+		{"disabled removes from default", nil, []string{"security"}, "lines,complexity"},
+		{"disabled wins over enabled", []string{"complexity", "security"}, []string{"security"}, "complexity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := newTestRegistry().Select(tt.enabled, tt.disabled)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if got := selectedNames(t, selected); got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_UnknownNames(t *testing.T) {
+	registry := newTestRegistry()
+
+	if _, err := registry.Select([]string{"duplication"}, nil); err == nil || !strings.Contains(err.Error(), "duplication") {
+		t.Errorf("expected an error naming the unknown analyzer, got %v", err)
+	}
+	if _, err := registry.Select(nil, []string{"nope"}); err == nil {
+		t.Error("expected an unknown disabled analyzer to be rejected")
+	}
+}
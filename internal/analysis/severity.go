@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// validSeverities lists the severity levels an override may map to.
+var validSeverities = map[string]bool{
+	"critical": true,
+	"high":     true,
+	"medium":   true,
+	"low":      true,
+	"info":     true,
+}
+
+// ValidateSeverityOverrides reports the first override whose target severity is
+// not a recognised level.
+func ValidateSeverityOverrides(overrides map[string]string) error {
+	for key, severity := range overrides {
+		if !validSeverities[strings.ToLower(severity)] {
+			return fmt.Errorf("invalid severity override %q: %q (valid: critical, high, medium, low, info)", key, severity)
+		}
+	}
+	return nil
+}
+
+// ApplySeverityOverrides rewrites issue severities in place according to
+// overrides and returns the number of issues changed. A key matching the
+// issue's tool_rule_id takes precedence over one matching its issue_type, so a
+// single CVE can be relaxed even when all security issues are raised.
+func ApplySeverityOverrides(issues []models.TechnicalDebtIssue, overrides map[string]string) int {
+	if len(overrides) == 0 {
+		return 0
+	}
+
+	changed := 0
+	for i := range issues {
+		severity, ok := "", false
+		if issues[i].ToolRuleID != nil {
+			severity, ok = overrides[*issues[i].ToolRuleID]
+		}
+		if !ok {
+			severity, ok = overrides[issues[i].IssueType]
+		}
+		if !ok {
+			continue
+		}
+
+		severity = strings.ToLower(severity)
+		if issues[i].Severity != severity {
+			issues[i].Severity = severity
+			changed++
+		}
+	}
+	return changed
+}
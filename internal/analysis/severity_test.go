@@ -0,0 +1,77 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestApplySeverityOverrides(t *testing.T) {
+	newIssues := func() []models.TechnicalDebtIssue {
+		return []models.TechnicalDebtIssue{
+			{IssueType: "security", Severity: "critical", ToolRuleID: strPtr("CVE-2021-44228")},
+			{IssueType: "security", Severity: "high", ToolRuleID: strPtr("CVE-2022-0001")},
+			{IssueType: "duplication", Severity: "low"},
+			{IssueType: "complexity", Severity: "high"},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		expected  []string
+		changed   int
+	}{
+		{
+			name:      "no overrides leaves severities untouched",
+			overrides: nil,
+			expected:  []string{"critical", "high", "low", "high"},
+			changed:   0,
+		},
+		{
+			name:      "rule id override",
+			overrides: map[string]string{"CVE-2021-44228": "low"},
+			expected:  []string{"low", "high", "low", "high"},
+			changed:   1,
+		},
+		{
+			name:      "issue type override",
+			overrides: map[string]string{"duplication": "MEDIUM"},
+			expected:  []string{"critical", "high", "medium", "high"},
+			changed:   1,
+		},
+		{
+			name:      "rule id wins over issue type",
+			overrides: map[string]string{"security": "critical", "CVE-2021-44228": "low"},
+			expected:  []string{"low", "critical", "low", "high"},
+			changed:   2,
+		},
+		{
+			name:      "override to the existing severity is not counted",
+			overrides: map[string]string{"complexity": "high"},
+			expected:  []string{"critical", "high", "low", "high"},
+			changed:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := newIssues()
+			changed := analysis.ApplySeverityOverrides(issues, tt.overrides)
+
+			assert.Equal(t, tt.changed, changed)
+			for i, want := range tt.expected {
+				assert.Equal(t, want, issues[i].Severity, "issue %d", i)
+			}
+		})
+	}
+}
+
+func TestValidateSeverityOverrides(t *testing.T) {
+	assert.NoError(t, analysis.ValidateSeverityOverrides(map[string]string{"CVE-1": "low", "complexity": "Critical"}))
+	assert.Error(t, analysis.ValidateSeverityOverrides(map[string]string{"complexity": "blocker"}))
+}
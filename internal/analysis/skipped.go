@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+)
+
+// Analyzers report why they did not run through a string metric named
+// "skip_reason" or ending in "_skip_reason", and the files they left out
+// through a map[string]int metric ending in "skipped_files" that counts them
+// by reason.
+const (
+	skipReasonMetric   = "skip_reason"
+	skippedFilesMetric = "skipped_files"
+)
+
+// Skipped summarizes what a run left out: the analyzers that did not run or
+// failed, and how many files were skipped for each reason. It lets a user
+// tell why findings they expected are missing.
+type Skipped struct {
+	Analyzers []SkippedAnalyzer `json:"analyzers,omitempty"`
+	Files     map[string]int    `json:"files,omitempty"`
+}
+
+// SkippedAnalyzer is an analyzer that produced no findings and why.
+type SkippedAnalyzer struct {
+	Analyzer string `json:"analyzer"`
+	Reason   string `json:"reason"`
+}
+
+// Empty reports whether nothing was skipped.
+func (s *Skipped) Empty() bool {
+	return s == nil || (len(s.Analyzers) == 0 && len(s.Files) == 0)
+}
+
+// AddAnalyzer records that the analyzer name was skipped for reason. The
+// same analyzer and reason are only recorded once, so scanning several roots
+// does not repeat them.
+func (s *Skipped) AddAnalyzer(name, reason string) {
+	for _, skipped := range s.Analyzers {
+		if skipped.Analyzer == name && skipped.Reason == reason {
+			return
+		}
+	}
+	s.Analyzers = append(s.Analyzers, SkippedAnalyzer{Analyzer: name, Reason: reason})
+}
+
+// AddFiles counts n more files skipped for reason.
+func (s *Skipped) AddFiles(reason string, n int) {
+	if n <= 0 {
+		return
+	}
+	if s.Files == nil {
+		s.Files = map[string]int{}
+	}
+	s.Files[reason] += n
+}
+
+// AddResult records the skips the analyzer name reported in the metrics of
+// its result.
+func (s *Skipped) AddResult(name string, result *Result) {
+	if result == nil {
+		return
+	}
+	keys := make([]string, 0, len(result.Metrics))
+	for key := range result.Metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch value := result.Metrics[key].(type) {
+		case string:
+			if value != "" && (key == skipReasonMetric || strings.HasSuffix(key, "_"+skipReasonMetric)) {
+				s.AddAnalyzer(name, value)
+			}
+		case map[string]int:
+			if strings.HasSuffix(key, skippedFilesMetric) {
+				for reason, n := range value {
+					s.AddFiles(reason, n)
+				}
+			}
+		}
+	}
+}
+
+// Merge adds the skips of other to s.
+func (s *Skipped) Merge(other *Skipped) {
+	if other == nil {
+		return
+	}
+	for _, skipped := range other.Analyzers {
+		s.AddAnalyzer(skipped.Analyzer, skipped.Reason)
+	}
+	for reason, n := range other.Files {
+		s.AddFiles(reason, n)
+	}
+}
+
+// SortedFileReasons returns the reasons files were skipped for, most files
+// first and then by name.
+func (s *Skipped) SortedFileReasons() []string {
+	reasons := make([]string, 0, len(s.Files))
+	for reason := range s.Files {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if s.Files[reasons[i]] != s.Files[reasons[j]] {
+			return s.Files[reasons[i]] > s.Files[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	return reasons
+}
@@ -0,0 +1,41 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipped(t *testing.T) {
+	var skipped analysis.Skipped
+	assert.True(t, skipped.Empty())
+
+	skipped.AddResult("security", &analysis.Result{Metrics: map[string]interface{}{
+		"skip_reason":           "trivy not installed",
+		"security_issues_count": 0,
+	}})
+	skipped.AddResult("container", &analysis.Result{Metrics: map[string]interface{}{
+		"container_skip_reason": "",
+	}})
+	skipped.AddResult("complexity", &analysis.Result{Metrics: map[string]interface{}{
+		"complexity_skipped_files": map[string]int{"unsupported": 3, "minified": 1},
+	}})
+	skipped.AddResult("lines", nil)
+	assert.False(t, skipped.Empty())
+
+	// A second root repeats the analyzer skip and adds to the file counts.
+	var other analysis.Skipped
+	other.AddAnalyzer("security", "trivy not installed")
+	other.AddAnalyzer("errcheck", "failed: boom")
+	other.AddFiles("minified", 2)
+	other.AddFiles("too_large", 0)
+	skipped.Merge(&other)
+
+	assert.Equal(t, []analysis.SkippedAnalyzer{
+		{Analyzer: "security", Reason: "trivy not installed"},
+		{Analyzer: "errcheck", Reason: "failed: boom"},
+	}, skipped.Analyzers)
+	assert.Equal(t, map[string]int{"unsupported": 3, "minified": 3}, skipped.Files)
+	assert.Equal(t, []string{"minified", "unsupported"}, skipped.SortedFileReasons())
+}
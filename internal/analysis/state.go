@@ -0,0 +1,165 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// DefaultStateFile is the conventional name of a LocalState file, kept at the
+// root of the scanned repository.
+const DefaultStateFile = ".debtdrone-state.json"
+
+// localStateFormat is bumped whenever the layout of LocalState changes.
+const localStateFormat = 1
+
+// LocalState records one CLI run in a small JSON file so the next run can
+// tell which issues are new without a database. It holds no absolute paths,
+// scan roots or repository IDs, so it stays valid in another checkout of the
+// same repository and can be committed; issues are sorted so that a committed
+// file diffs cleanly between runs.
+type LocalState struct {
+	Format      int          `json:"format"`
+	RunAt       time.Time    `json:"run_at"`
+	ToolVersion string       `json:"tool_version,omitempty"`
+	Summary     RunSummary   `json:"summary"`
+	Issues      []StateIssue `json:"issues"`
+}
+
+// StateIssue is the part of an issue a LocalState keeps: the fields its
+// fingerprint is computed from, plus what a diff reports.
+type StateIssue struct {
+	FilePath           string  `json:"file_path"`
+	LineNumber         *int    `json:"line_number,omitempty"`
+	IssueType          string  `json:"issue_type"`
+	ToolRuleID         *string `json:"tool_rule_id,omitempty"`
+	Severity           string  `json:"severity"`
+	Category           string  `json:"category"`
+	Message            string  `json:"message"`
+	TechnicalDebtHours float64 `json:"technical_debt_hours"`
+}
+
+// NewLocalState records issues as the state of a run at runAt.
+func NewLocalState(issues []models.TechnicalDebtIssue, toolVersion string, runAt time.Time) *LocalState {
+	state := &LocalState{
+		Format:      localStateFormat,
+		RunAt:       runAt.UTC(),
+		ToolVersion: toolVersion,
+		Summary:     Summarize(issues),
+		Issues:      make([]StateIssue, 0, len(issues)),
+	}
+	for _, issue := range issues {
+		state.Issues = append(state.Issues, StateIssue{
+			FilePath:           issue.FilePath,
+			LineNumber:         issue.LineNumber,
+			IssueType:          issue.IssueType,
+			ToolRuleID:         issue.ToolRuleID,
+			Severity:           issue.Severity,
+			Category:           issue.Category,
+			Message:            issue.Message,
+			TechnicalDebtHours: issue.TechnicalDebtHours,
+		})
+	}
+	sort.SliceStable(state.Issues, func(i, j int) bool {
+		a, b := state.Issues[i], state.Issues[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if la, lb := lineOrZero(a.LineNumber), lineOrZero(b.LineNumber); la != lb {
+			return la < lb
+		}
+		return a.Message < b.Message
+	})
+	return state
+}
+
+func lineOrZero(line *int) int {
+	if line == nil {
+		return 0
+	}
+	return *line
+}
+
+// LoadLocalState reads the state stored at path. ok is false when there is no
+// file yet, i.e. on the first run. A file that is not a state file, or one
+// written by an incompatible version, is an error rather than an empty state
+// so that a gate never silently treats every issue as new.
+func LoadLocalState(path string) (state *LocalState, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read state file: %w", err)
+	}
+	state = &LocalState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, false, fmt.Errorf("%s is not a valid state file: %w", path, err)
+	}
+	if state.Format != localStateFormat {
+		return nil, false, fmt.Errorf("%s has unsupported state format %d (expected %d); delete it to start over", path, state.Format, localStateFormat)
+	}
+	return state, true, nil
+}
+
+// Save writes the state to path, replacing any previous file atomically.
+func (s *LocalState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	data = append(data, '\n')
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create state directory: %w", err)
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	// CreateTemp makes the file private; a state file is meant to be shared.
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// IssuesForDiff returns the recorded issues in the form DiffIssues compares,
+// so the run the state describes can serve as the base of a diff.
+func (s *LocalState) IssuesForDiff() []models.TechnicalDebtIssue {
+	issues := make([]models.TechnicalDebtIssue, 0, len(s.Issues))
+	for _, recorded := range s.Issues {
+		issues = append(issues, models.TechnicalDebtIssue{
+			FilePath:           recorded.FilePath,
+			LineNumber:         recorded.LineNumber,
+			IssueType:          recorded.IssueType,
+			ToolRuleID:         recorded.ToolRuleID,
+			Severity:           recorded.Severity,
+			Category:           recorded.Category,
+			Message:            recorded.Message,
+			TechnicalDebtHours: recorded.TechnicalDebtHours,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,76 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", analysis.DefaultStateFile)
+
+	_, found, err := analysis.LoadLocalState(path)
+	require.NoError(t, err)
+	assert.False(t, found, "a missing file is the first run, not an error")
+
+	runAt := time.Date(2026, 10, 14, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	recorded := []models.TechnicalDebtIssue{
+		diffIssue("/b.go", 5, "Function 'old' has high complexity (30)", "high", 2.0),
+		diffIssue("/a.go", 40, "Function 'render' has high complexity (18)", "medium", 0.5),
+		diffIssue("/a.go", 10, "Function 'parse' has high complexity (21)", "medium", 1.0),
+	}
+	require.NoError(t, analysis.NewLocalState(recorded, "1.2.3", runAt).Save(path))
+
+	state, found, err := analysis.LoadLocalState(path)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.True(t, runAt.Equal(state.RunAt))
+	assert.Equal(t, "1.2.3", state.ToolVersion)
+	assert.Equal(t, 3, state.Summary.TotalIssues)
+	assert.InDelta(t, 3.5, state.Summary.TotalDebtHours, 1e-9)
+
+	var order []string
+	for _, issue := range state.Issues {
+		order = append(order, issue.FilePath+":"+issue.Message)
+	}
+	assert.Equal(t, []string{
+		"/a.go:Function 'parse' has high complexity (21)",
+		"/a.go:Function 'render' has high complexity (18)",
+		"/b.go:Function 'old' has high complexity (30)",
+	}, order, "issues are sorted so a committed state diffs cleanly")
+
+	// The state serves as the base of a diff even though it keeps no
+	// repository IDs.
+	head := []models.TechnicalDebtIssue{
+		diffIssue("/a.go", 12, "Function 'parse' has high complexity (22)", "medium", 1.0),
+		diffIssue("/c.go", 1, "Function 'fresh' has high complexity (16)", "low", 0.25),
+	}
+	for i := range head {
+		head[i].RepositoryID = uuid.New()
+	}
+	diff := analysis.DiffIssues(state.IssuesForDiff(), head)
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "/c.go", diff.Added[0].FilePath)
+	assert.Len(t, diff.Resolved, 2)
+}
+
+func TestLoadLocalState_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"corrupt.json": "{not json",
+		"future.json":  `{"format": 99, "issues": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, found, err := analysis.LoadLocalState(path)
+		assert.Error(t, err, name)
+		assert.False(t, found, name)
+	}
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// subprocessWaitDelay bounds how long Wait waits for the output pipes after
+// the process was killed, in case a grandchild outside its process group
+// still holds them.
+const subprocessWaitDelay = 5 * time.Second
+
+// memoryLimitPattern matches the GOMEMLIMIT syntax: bytes with an optional
+// B, KiB, MiB, GiB or TiB suffix.
+var memoryLimitPattern = regexp.MustCompile(`^[0-9]+(B|KiB|MiB|GiB|TiB)?$`)
+
+// SubprocessLimits are soft resource limits for the external tools analyzers
+// run, such as Trivy. They are passed as the Go runtime's GOMEMLIMIT and
+// GOMAXPROCS, which Go-based tools honor on every platform; they are hints,
+// not hard cgroup limits.
+type SubprocessLimits struct {
+	// Memory is a GOMEMLIMIT value such as "2GiB"; empty means no limit.
+	Memory string
+	// CPUs caps the threads running Go code at once; 0 means no limit.
+	CPUs int
+}
+
+// ValidateSubprocessLimits reports a malformed memory limit or a negative
+// CPU count.
+func ValidateSubprocessLimits(limits SubprocessLimits) error {
+	if limits.Memory != "" && !memoryLimitPattern.MatchString(limits.Memory) {
+		return fmt.Errorf("invalid memory limit %q: want bytes with an optional B, KiB, MiB, GiB or TiB suffix", limits.Memory)
+	}
+	if limits.CPUs < 0 {
+		return fmt.Errorf("invalid CPU limit %d: must be 0 or greater", limits.CPUs)
+	}
+	return nil
+}
+
+// CommandContext is exec.CommandContext for analyzer subprocesses. The
+// process runs in its own process group where the OS supports it, and the
+// whole group is killed when ctx ends, so a cancelled or timed-out scan
+// leaves no runaway scanner or helper behind. On Linux the process is also
+// killed if DebtDrone dies. The limits set by WithSubprocessLimits are
+// applied to its environment.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = subprocessWaitDelay
+	configureProcessGroup(cmd)
+
+	limits := SubprocessLimitsFromContext(ctx)
+	if limits.Memory != "" || limits.CPUs > 0 {
+		cmd.Env = os.Environ()
+		if limits.Memory != "" {
+			cmd.Env = append(cmd.Env, "GOMEMLIMIT="+limits.Memory)
+		}
+		if limits.CPUs > 0 {
+			cmd.Env = append(cmd.Env, "GOMAXPROCS="+strconv.Itoa(limits.CPUs))
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,19 @@
+//go:build linux
+// +build linux
+
+package analysis
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup starts cmd in a new process group that is killed as
+// a whole on cancellation. Pdeathsig kills the process when the thread that
+// started it exits, which in practice means when DebtDrone dies.
+func configureProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
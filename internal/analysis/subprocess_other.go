@@ -0,0 +1,10 @@
+//go:build !unix
+// +build !unix
+
+package analysis
+
+import "os/exec"
+
+// configureProcessGroup keeps the default cancellation, which kills the
+// process itself but not the children it started.
+func configureProcessGroup(cmd *exec.Cmd) {}
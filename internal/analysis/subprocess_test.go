@@ -0,0 +1,81 @@
+//go:build linux
+// +build linux
+
+package analysis_test
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSubprocessLimits(t *testing.T) {
+	for _, limits := range []analysis.SubprocessLimits{
+		{},
+		{Memory: "2GiB"},
+		{Memory: "512MiB", CPUs: 2},
+		{Memory: "1073741824"},
+	} {
+		assert.NoError(t, analysis.ValidateSubprocessLimits(limits), "%+v", limits)
+	}
+	for _, limits := range []analysis.SubprocessLimits{
+		{Memory: "2GB"},
+		{Memory: "-1"},
+		{Memory: "lots"},
+		{CPUs: -1},
+	} {
+		assert.Error(t, analysis.ValidateSubprocessLimits(limits), "%+v", limits)
+	}
+}
+
+func TestCommandContext_Limits(t *testing.T) {
+	ctx := analysis.WithSubprocessLimits(context.Background(), analysis.SubprocessLimits{Memory: "1GiB", CPUs: 2})
+	out, err := analysis.CommandContext(ctx, "sh", "-c", `echo "$GOMEMLIMIT $GOMAXPROCS"`).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "1GiB 2", strings.TrimSpace(string(out)))
+
+	out, err = analysis.CommandContext(context.Background(), "sh", "-c", `echo "[$GOMEMLIMIT]"`).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", strings.TrimSpace(string(out)), "no limits leaves the environment alone")
+}
+
+func TestCommandContext_CancelKillsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The shell starts a grandchild and waits on it, like a scanner running
+	// a helper; cancelling must take both down.
+	cmd := analysis.CommandContext(ctx, "sh", "-c", "sleep 60 & echo $!; wait")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	grandchild, err := strconv.Atoi(strings.TrimSpace(line))
+	require.NoError(t, err)
+
+	cancel()
+	assert.Error(t, cmd.Wait())
+	assert.Eventually(t, func() bool { return !running(grandchild) }, 5*time.Second, 20*time.Millisecond,
+		"grandchild %d survived the cancellation", grandchild)
+}
+
+// running reports whether pid is alive; a zombie waiting to be reaped counts
+// as dead.
+func running(pid int) bool {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
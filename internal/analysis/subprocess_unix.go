@@ -0,0 +1,18 @@
+//go:build unix && !linux
+// +build unix,!linux
+
+package analysis
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup starts cmd in a new process group that is killed as
+// a whole on cancellation. There is no parent-death signal outside Linux.
+func configureProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
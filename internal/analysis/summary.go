@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// RunSummary aggregates the issues of a single run.
+type RunSummary struct {
+	TotalIssues    int            `json:"total_issues"`
+	SeverityCounts map[string]int `json:"severity_counts"`
+	CategoryCounts map[string]int `json:"category_counts"`
+	// CategoryBreakdown extends CategoryCounts with the debt of each category.
+	CategoryBreakdown map[string]CategoryDebt `json:"category_breakdown"`
+	TotalDebtHours    float64                 `json:"total_debt_hours"`
+	// EffectiveDebtHours sums each issue's debt scaled by its EffortMultiplier.
+	EffectiveDebtHours float64 `json:"effective_debt_hours"`
+	AffectedFiles      int     `json:"affected_files"`
+	// Truncated is set by the caller when issue collection stopped at a cap,
+	// so the counts above cover part of the findings only.
+	Truncated bool `json:"truncated,omitempty"`
+	// ComplexityHistogram is set by the caller to the distribution of the
+	// cyclomatic complexity of the analyzed functions when the complexity
+	// analyzer ran, even if it found no functions.
+	ComplexityHistogram models.ComplexityHistogram `json:"complexity_histogram,omitempty"`
+}
+
+// CategoryDebt is the share of a run's issues and debt in one category.
+type CategoryDebt struct {
+	Category  string  `json:"-"`
+	Issues    int     `json:"issues"`
+	DebtHours float64 `json:"debt_hours"`
+}
+
+// SortedCategories returns the CategoryBreakdown entries by descending issue
+// count, then descending debt, then name.
+func (s RunSummary) SortedCategories() []CategoryDebt {
+	categories := make([]CategoryDebt, 0, len(s.CategoryBreakdown))
+	for name, entry := range s.CategoryBreakdown {
+		entry.Category = name
+		categories = append(categories, entry)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if a.Issues != b.Issues {
+			return a.Issues > b.Issues
+		}
+		if a.DebtHours != b.DebtHours {
+			return a.DebtHours > b.DebtHours
+		}
+		return a.Category < b.Category
+	})
+	return categories
+}
+
+// Summarize computes the RunSummary for issues. Files are counted per root so
+// the same relative path under two scanned roots counts twice.
+func Summarize(issues []models.TechnicalDebtIssue) RunSummary {
+	summary := RunSummary{
+		TotalIssues:       len(issues),
+		SeverityCounts:    map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0},
+		CategoryCounts:    map[string]int{},
+		CategoryBreakdown: map[string]CategoryDebt{},
+	}
+
+	files := make(map[string]bool)
+	for _, issue := range issues {
+		summary.SeverityCounts[issue.Severity]++
+		if issue.Category != "" {
+			summary.CategoryCounts[issue.Category]++
+			entry := summary.CategoryBreakdown[issue.Category]
+			entry.Issues++
+			entry.DebtHours += issue.TechnicalDebtHours
+			summary.CategoryBreakdown[issue.Category] = entry
+		}
+		summary.TotalDebtHours += issue.TechnicalDebtHours
+		summary.EffectiveDebtHours += issue.TechnicalDebtHours * issue.EffortMultiplier
+		if issue.FilePath != "" {
+			files[issue.Root+"\x00"+issue.FilePath] = true
+		}
+	}
+	summary.AffectedFiles = len(files)
+
+	return summary
+}
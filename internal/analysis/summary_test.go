@@ -0,0 +1,55 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{FilePath: "/a.go", Severity: "high", Category: "maintainability", TechnicalDebtHours: 1.5, EffortMultiplier: 2.0},
+		{FilePath: "/a.go", Severity: "medium", Category: "reliability", TechnicalDebtHours: 0.25, EffortMultiplier: 1.0},
+		{FilePath: "/b.go", Severity: "high", Category: "maintainability", TechnicalDebtHours: 0.25, EffortMultiplier: 0.0},
+		{FilePath: "/a.go", Root: "svc-b", Severity: "critical", Category: "security"},
+	}
+
+	summary := analysis.Summarize(issues)
+
+	assert.Equal(t, 4, summary.TotalIssues)
+	assert.Equal(t, map[string]int{"critical": 1, "high": 2, "medium": 1, "low": 0}, summary.SeverityCounts)
+	assert.Equal(t, map[string]int{"maintainability": 2, "reliability": 1, "security": 1}, summary.CategoryCounts)
+	assert.Equal(t, analysis.CategoryDebt{Issues: 2, DebtHours: 1.75}, summary.CategoryBreakdown["maintainability"])
+	assert.InDelta(t, 2.0, summary.TotalDebtHours, 1e-9)
+	assert.InDelta(t, 3.25, summary.EffectiveDebtHours, 1e-9)
+	assert.Equal(t, 3, summary.AffectedFiles)
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	summary := analysis.Summarize(nil)
+
+	assert.Equal(t, 0, summary.TotalIssues)
+	assert.Equal(t, 0, summary.SeverityCounts["critical"])
+	assert.NotNil(t, summary.CategoryCounts)
+	assert.NotNil(t, summary.CategoryBreakdown)
+	assert.Empty(t, summary.SortedCategories())
+}
+
+func TestRunSummary_SortedCategories(t *testing.T) {
+	issues := []models.TechnicalDebtIssue{
+		{Category: "style", TechnicalDebtHours: 0.1},
+		{Category: "security", TechnicalDebtHours: 2},
+		{Category: "maintainability", TechnicalDebtHours: 0.5},
+		{Category: "maintainability", TechnicalDebtHours: 0.5},
+		{Category: "reliability", TechnicalDebtHours: 2},
+	}
+
+	var names []string
+	for _, category := range analysis.Summarize(issues).SortedCategories() {
+		names = append(names, category.Category)
+	}
+
+	assert.Equal(t, []string{"maintainability", "reliability", "security", "style"}, names)
+}
@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// suppressionDirective starts an inline annotation in a comment of any
+// language:
+//
+//	debtdrone:ignore [types] [-- reason]
+//	debtdrone:ignore-next-line [types] [-- reason]
+//
+// types is a list of issue types or rule IDs separated by commas or spaces;
+// without one, every issue is suppressed.
+const suppressionDirective = "debtdrone:ignore"
+
+// commentLeaders are the characters that may precede an annotation on a line
+// holding nothing else.
+const commentLeaders = "/#*;%-!<{ \t"
+
+// Suppression is an issue dropped because of an inline annotation.
+type Suppression struct {
+	Issue models.TechnicalDebtIssue `json:"issue"`
+	// Line is the line of the annotation.
+	Line int `json:"line"`
+	// Reason is the text after "--" in the annotation, if any.
+	Reason string `json:"reason,omitempty"`
+}
+
+// annotation is a parsed debtdrone:ignore comment.
+type annotation struct {
+	line   int
+	target int // the line whose issues are suppressed, besides line itself
+	types  []string
+	reason string
+	// nextLineOnly is set for ignore-next-line, which does not cover the
+	// line it is on.
+	nextLineOnly bool
+}
+
+// matches reports whether the annotation covers issue.
+func (a annotation) matches(issue models.TechnicalDebtIssue) bool {
+	line := *issue.LineNumber
+	if line != a.target && (a.nextLineOnly || line != a.line) {
+		return false
+	}
+	if len(a.types) == 0 {
+		return true
+	}
+	for _, t := range a.types {
+		if strings.EqualFold(t, issue.IssueType) || (issue.ToolRuleID != nil && strings.EqualFold(t, *issue.ToolRuleID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSuppressions returns the debtdrone:ignore annotations of a source
+// file. An annotation covers the issues reported on its own line; when it is
+// the only thing on its line, it also covers the next line holding code
+// (annotations stacked above a line all apply to it). ignore-next-line only
+// covers that next line.
+func parseSuppressions(content []byte) []annotation {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, MaxLineLength), MaxLineLength)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	var annotations []annotation
+	for i, text := range lines {
+		start := strings.Index(text, suppressionDirective)
+		if start < 0 {
+			continue
+		}
+		rest := text[start+len(suppressionDirective):]
+		a := annotation{line: i + 1, target: -1}
+		if strings.HasPrefix(rest, "-next-line") {
+			a.nextLineOnly = true
+			rest = strings.TrimPrefix(rest, "-next-line")
+		}
+		// debtdrone:ignored and the like are not annotations.
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' && !strings.HasPrefix(rest, "*/") && !strings.HasPrefix(rest, "-->") {
+			continue
+		}
+		a.types, a.reason = parseSuppressionArgs(rest)
+
+		if a.nextLineOnly || strings.Trim(text[:start], commentLeaders) == "" {
+			for j := i + 1; j < len(lines); j++ {
+				next := strings.TrimSpace(lines[j])
+				if next != "" && !strings.Contains(next, suppressionDirective) {
+					a.target = j + 1
+					break
+				}
+			}
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations
+}
+
+// parseSuppressionArgs splits the text after the directive into the issue
+// types and the reason.
+func parseSuppressionArgs(args string) ([]string, string) {
+	// Block comments end on the same line: /* debtdrone:ignore */.
+	args = strings.TrimSpace(args)
+	for _, closer := range []string{"*/", "-->"} {
+		args = strings.TrimSpace(strings.TrimSuffix(args, closer))
+	}
+
+	reason := ""
+	if i := strings.Index(args, "--"); i >= 0 {
+		reason = strings.TrimSpace(args[i+2:])
+		args = args[:i]
+	}
+
+	types := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	return types, reason
+}
+
+// SuppressAnnotated drops the issues covered by a debtdrone:ignore annotation
+// in the file they are reported in, reading the files from root. Issues
+// without a line number are never suppressed, nor are the issues of files
+// that cannot be read.
+func SuppressAnnotated(root string, issues []models.TechnicalDebtIssue) ([]models.TechnicalDebtIssue, []Suppression) {
+	byFile := map[string][]annotation{}
+	kept := issues[:0:0]
+	var suppressed []Suppression
+	for _, issue := range issues {
+		if issue.LineNumber == nil || issue.FilePath == "" {
+			kept = append(kept, issue)
+			continue
+		}
+		annotations, ok := byFile[issue.FilePath]
+		if !ok {
+			annotations = fileSuppressions(root, issue.FilePath)
+			byFile[issue.FilePath] = annotations
+		}
+
+		matched := false
+		for _, a := range annotations {
+			if a.matches(issue) {
+				suppressed = append(suppressed, Suppression{Issue: issue, Line: a.line, Reason: a.reason})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, suppressed
+}
+
+// fileSuppressions reads the annotations of the file at the repository
+// relative path rel.
+func fileSuppressions(root, rel string) []annotation {
+	path := filepath.Join(root, filepath.FromSlash(strings.TrimLeft(rel, "/")))
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > MaxFileSize {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(content, []byte(suppressionDirective)) {
+		return nil
+	}
+	return parseSuppressions(content)
+}
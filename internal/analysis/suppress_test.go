@@ -0,0 +1,83 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressAnnotated(t *testing.T) {
+	root := t.TempDir()
+	source := `package main
+
+// debtdrone:ignore complexity -- table-driven parser, reviewed
+func parse() {}
+
+func load() { // debtdrone:ignore
+}
+
+// debtdrone:ignore-next-line blocking_call
+// debtdrone:ignore complexity
+
+func wait() {}
+
+func keep() {} // debtdrone:ignored is not an annotation
+
+/* debtdrone:ignore ignored_error, deadcode */
+func drop() {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte(source), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app.py"), []byte("x = 1\n# debtdrone:ignore-next-line -- legacy\ndef f():\n    pass  # debtdrone:ignore\n"), 0644))
+
+	line := func(n int) *int { return &n }
+	rule := "deadcode"
+	issues := []models.TechnicalDebtIssue{
+		{Message: "parse complexity", FilePath: "/main.go", LineNumber: line(4), IssueType: "complexity"},
+		{Message: "parse blocking", FilePath: "/main.go", LineNumber: line(4), IssueType: "blocking_call"},
+		{Message: "load anything", FilePath: "/main.go", LineNumber: line(6), IssueType: "security"},
+		{Message: "wait blocking", FilePath: "/main.go", LineNumber: line(12), IssueType: "blocking_call"},
+		{Message: "wait complexity", FilePath: "/main.go", LineNumber: line(12), IssueType: "complexity"},
+		{Message: "keep", FilePath: "/main.go", LineNumber: line(14), IssueType: "complexity"},
+		{Message: "drop by rule", FilePath: "/main.go", LineNumber: line(17), IssueType: "dead_code", ToolRuleID: &rule},
+		{Message: "no line", FilePath: "/main.go", IssueType: "complexity"},
+		{Message: "f", FilePath: "app.py", LineNumber: line(3), IssueType: "complexity"},
+		{Message: "f body", FilePath: "app.py", LineNumber: line(4), IssueType: "complexity"},
+		{Message: "python first line", FilePath: "app.py", LineNumber: line(2), IssueType: "complexity"},
+		{Message: "missing file", FilePath: "/gone.go", LineNumber: line(1), IssueType: "complexity"},
+	}
+
+	kept, suppressed := analysis.SuppressAnnotated(root, issues)
+
+	var keptMessages []string
+	for _, issue := range kept {
+		keptMessages = append(keptMessages, issue.Message)
+	}
+	assert.Equal(t, []string{"parse blocking", "keep", "no line", "python first line", "missing file"}, keptMessages)
+
+	require.Len(t, suppressed, 7)
+	assert.Equal(t, "parse complexity", suppressed[0].Issue.Message)
+	assert.Equal(t, 3, suppressed[0].Line)
+	assert.Equal(t, "table-driven parser, reviewed", suppressed[0].Reason)
+	assert.Equal(t, 6, suppressed[1].Line, "a trailing annotation covers its own line")
+	assert.Equal(t, 9, suppressed[2].Line, "stacked annotations apply to the next code line")
+	assert.Equal(t, 10, suppressed[3].Line)
+	assert.Equal(t, "drop by rule", suppressed[4].Issue.Message)
+	assert.Equal(t, "legacy", suppressed[5].Reason)
+	assert.Empty(t, suppressed[6].Reason)
+}
+
+func TestSuppressAnnotated_NoAnnotations(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+
+	line := 1
+	issues := []models.TechnicalDebtIssue{{FilePath: "/main.go", LineNumber: &line}}
+	kept, suppressed := analysis.SuppressAnnotated(root, issues)
+	assert.Equal(t, issues, kept)
+	assert.Empty(t, suppressed)
+}
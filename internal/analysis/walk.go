@@ -0,0 +1,121 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WalkDir is filepath.WalkDir over root, except that fn never sees the
+// symbolic links below root. filepath.WalkDir already does not descend into
+// symlinked directories, but it reports the links themselves, and a reader
+// that opens one follows it: a link to a directory fails to read, and a link
+// to a file may reach outside the repository (e.g. to /etc/passwd or another
+// checkout). A file linked from inside the repository is still visited under
+// its own path.
+func WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && path != root && d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		return fn(path, d, err)
+	})
+}
+
+// WalkTargetFiles calls fn for the files selected by WithTargetFiles in place
+// of a filepath.WalkDir over root. Before each file, fn sees the directories
+// leading to it from root, in walk order and at most once each, so directory
+// exclusions and filepath.SkipDir behave as in a full walk. Targets that do
+// not exist (e.g. deleted files), are not regular files or lie below a
+// symlinked directory are skipped, as WalkDir would never reach them.
+//
+// It reports false without calling fn when ctx selects no target files, in
+// which case the caller walks the whole tree.
+func WalkTargetFiles(ctx context.Context, root string, fn fs.WalkDirFunc) (bool, error) {
+	targets, ok := TargetFilesFromContext(ctx)
+	if !ok || len(targets) == 0 {
+		return false, nil
+	}
+
+	paths := make([]string, 0, len(targets))
+	for _, target := range targets {
+		rel := filepath.Clean(filepath.FromSlash(strings.TrimLeft(target, "/")))
+		if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		paths = append(paths, filepath.Join(root, rel))
+	}
+	sort.Strings(paths)
+
+	visited := map[string]bool{}
+	skipped := map[string]bool{}
+	for i, path := range paths {
+		if i > 0 && path == paths[i-1] {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		dirs := []string{root}
+		if rel, err := filepath.Rel(root, filepath.Dir(path)); err == nil && rel != "." {
+			dir := root
+			for _, segment := range strings.Split(rel, string(filepath.Separator)) {
+				dir = filepath.Join(dir, segment)
+				dirs = append(dirs, dir)
+			}
+		}
+
+		skip := false
+		for _, dir := range dirs {
+			if skipped[dir] {
+				skip = true
+				break
+			}
+			if visited[dir] {
+				continue
+			}
+			visited[dir] = true
+
+			dirInfo, err := os.Lstat(dir)
+			if err != nil || (dir != root && dirInfo.Mode()&fs.ModeSymlink != 0) {
+				skipped[dir] = true
+				skip = true
+				break
+			}
+			if err := fn(dir, fs.FileInfoToDirEntry(dirInfo), nil); err != nil {
+				if errors.Is(err, filepath.SkipAll) {
+					return true, nil
+				}
+				if errors.Is(err, filepath.SkipDir) {
+					skipped[dir] = true
+					skip = true
+					break
+				}
+				return true, err
+			}
+		}
+		if skip {
+			continue
+		}
+
+		if err := fn(path, fs.FileInfoToDirEntry(info), nil); err != nil {
+			if errors.Is(err, filepath.SkipAll) {
+				return true, nil
+			}
+			if errors.Is(err, filepath.SkipDir) {
+				// As in filepath.WalkDir, SkipDir on a file skips the rest of
+				// its directory.
+				skipped[filepath.Dir(path)] = true
+				continue
+			}
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,105 @@
+package analysis_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkTargetFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/b.go", "vendor/lib/c.go", "other.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("package x\n"), 0644))
+	}
+
+	var visited []string
+	visit := func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			visited = append(visited, filepath.ToSlash(rel)+"/")
+			if d.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		visited = append(visited, filepath.ToSlash(rel))
+		return nil
+	}
+
+	t.Run("without targets the caller walks", func(t *testing.T) {
+		visited = nil
+		walked, err := analysis.WalkTargetFiles(context.Background(), root, visit)
+		require.NoError(t, err)
+		assert.False(t, walked)
+		assert.Empty(t, visited)
+	})
+
+	t.Run("visits only targets and their directories", func(t *testing.T) {
+		visited = nil
+		ctx := analysis.WithTargetFiles(context.Background(), []string{"/pkg/b.go", "pkg/a.go", "deleted.go", "vendor/lib/c.go", "main.go", "pkg/a.go"})
+		walked, err := analysis.WalkTargetFiles(ctx, root, visit)
+		require.NoError(t, err)
+		assert.True(t, walked)
+		assert.Equal(t, []string{"./", "main.go", "pkg/", "pkg/a.go", "pkg/b.go", "vendor/"}, visited,
+			"missing files are skipped, directories are visited once and SkipDir applies")
+	})
+}
+
+func TestWalkDir_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.go"), []byte("package secret\n"), 0644))
+	for link, target := range map[string]string{
+		"loop":         ".",
+		"parent":       "..",
+		"outside":      outside,
+		"secret.go":    filepath.Join(outside, "secret.go"),
+		"alias.go":     "main.go",
+		"dangling.go":  "missing.go",
+		"sub/loop":     "..",
+		"sub/outside":  outside,
+		"sub/alias.go": "../main.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(link))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+
+	var visited []string
+	visit := func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			rel += "/"
+		}
+		visited = append(visited, filepath.ToSlash(rel))
+		return nil
+	}
+
+	t.Run("full walk terminates without following links", func(t *testing.T) {
+		visited = nil
+		require.NoError(t, analysis.WalkDir(root, visit))
+		assert.Equal(t, []string{"./", "main.go", "sub/"}, visited)
+	})
+
+	t.Run("targets below a symlinked directory are skipped", func(t *testing.T) {
+		visited = nil
+		ctx := analysis.WithTargetFiles(context.Background(), []string{"outside/secret.go", "sub/outside/secret.go", "loop/main.go", "secret.go", "main.go"})
+		walked, err := analysis.WalkTargetFiles(ctx, root, visit)
+		require.NoError(t, err)
+		assert.True(t, walked)
+		assert.Equal(t, []string{"./", "main.go", "sub/"}, visited)
+	})
+}
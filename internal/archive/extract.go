@@ -0,0 +1,212 @@
+// Package archive extracts the source archives a scan can analyze in place
+// of a checkout, such as the artifacts of a CI build.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxBytes caps the bytes Extract writes, so that a decompression
+// bomb cannot fill the disk.
+const DefaultMaxBytes int64 = 4 << 30
+
+// ErrInvalid is wrapped by the errors of archives that cannot be read, are
+// of an unsupported format, hold an entry that would be written outside the
+// extraction directory or extract to more than the size limit.
+var ErrInvalid = errors.New("invalid archive")
+
+// Extract writes the files and directories of the .tar, .tar.gz (.tgz) or
+// .zip archive at archivePath into dir, writing at most maxBytes. The format
+// is recognized from the content, not the file name. It returns the root of
+// the extracted tree: the archive's only top-level directory when it has
+// one, as a "git archive --prefix" or a zipped folder does, and dir
+// otherwise.
+//
+// An archive with an absolute path or a ".." entry is rejected before it
+// escapes dir. Symbolic links, hard links and other special entries are left
+// out, as a scan of a checkout skips symbolic links too.
+func Extract(ctx context.Context, archivePath, dir string, maxBytes int64) (string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read %s: %w", archivePath, err)
+	}
+	header = header[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	x := &extractor{ctx: ctx, dir: dir, limit: maxBytes, remaining: maxBytes}
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		info, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		if err := x.zip(f, info.Size()); err != nil {
+			return "", err
+		}
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bufio.NewReader(f))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		defer gz.Close()
+		if err := x.tar(gz); err != nil {
+			return "", err
+		}
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		if err := x.tar(f); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: %s is not a .tar, .tar.gz or .zip file", ErrInvalid, archivePath)
+	}
+	return extractedRoot(dir)
+}
+
+// extractor writes the entries of one archive.
+type extractor struct {
+	ctx       context.Context
+	dir       string
+	limit     int64
+	remaining int64
+}
+
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		target, err := x.target(header.Name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case target == "":
+		case header.Typeflag == tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case header.Typeflag == tar.TypeReg:
+			err = x.writeFile(target, tr, header.FileInfo().Mode())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	for _, entry := range zr.File {
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		target, err := x.target(entry.Name)
+		if err != nil {
+			return err
+		}
+		mode := entry.Mode()
+		switch {
+		case target == "":
+		case mode.IsDir():
+			err = os.MkdirAll(target, 0755)
+		case mode.IsRegular():
+			err = x.writeZipFile(target, entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extractor) writeZipFile(target string, entry *zip.File) error {
+	rc, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalid, entry.Name, err)
+	}
+	defer rc.Close()
+	return x.writeFile(target, rc, entry.Mode())
+}
+
+// target returns where the entry name is extracted to, or "" for the root
+// itself. Names that are absolute or climb out of the root are rejected.
+func (x *extractor) target(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(slashed) || filepath.VolumeName(name) != "" || slices.Contains(strings.Split(slashed, "/"), "..") {
+		return "", fmt.Errorf("%w: entry %q would be written outside the extraction directory", ErrInvalid, name)
+	}
+	clean := path.Clean(slashed)
+	if clean == "." {
+		return "", nil
+	}
+	return filepath.Join(x.dir, filepath.FromSlash(clean)), nil
+}
+
+// writeFile copies r to target, failing once the archive has written more
+// than its limit.
+func (x *extractor) writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(out, io.LimitReader(r, x.remaining+1))
+	x.remaining -= written
+	if err == nil && x.remaining < 0 {
+		err = fmt.Errorf("%w: it extracts to more than %d bytes", ErrInvalid, x.limit)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// extractedRoot returns the only directory directly below dir, or dir when
+// it holds anything else.
+func extractedRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
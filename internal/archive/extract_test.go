@@ -0,0 +1,156 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	name    string
+	body    string
+	dir     bool
+	symlink string
+}
+
+func writeTarGz(t *testing.T, entries []entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		switch {
+		case e.dir:
+			header = &tar.Header{Name: e.name, Mode: 0755, Typeflag: tar.TypeDir}
+		case e.symlink != "":
+			header = &tar.Header{Name: e.name, Linkname: e.symlink, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "src.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeZip(t *testing.T, entries []entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "src.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtract_TarGz(t *testing.T) {
+	path := writeTarGz(t, []entry{
+		{name: "api-1.0/", dir: true},
+		{name: "api-1.0/main.go", body: "package main\n"},
+		{name: "api-1.0/handler/api.go", body: "package handler\n"},
+		{name: "api-1.0/link.go", symlink: "main.go"},
+	})
+	dir := t.TempDir()
+	root, err := Extract(context.Background(), path, dir, DefaultMaxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != filepath.Join(dir, "api-1.0") {
+		t.Errorf("Expected the single top-level directory as root, got %s", root)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "handler", "api.go"))
+	if err != nil || string(got) != "package handler\n" {
+		t.Errorf("Expected the nested file to be extracted, got %q (%v)", got, err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, "link.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected the symbolic link to be left out, got %v", err)
+	}
+}
+
+func TestExtract_Zip(t *testing.T) {
+	path := writeZip(t, []entry{
+		{name: "main.go", body: "package main\n"},
+		{name: "pkg/util.go", body: "package pkg\n"},
+	})
+	dir := t.TempDir()
+	root, err := Extract(context.Background(), path, dir, DefaultMaxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != dir {
+		t.Errorf("Expected the extraction directory as root of an archive with several top-level entries, got %s", root)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pkg", "util.go")); err != nil {
+		t.Errorf("Expected pkg/util.go to be extracted: %v", err)
+	}
+}
+
+func TestExtract_Rejected(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "evil.go")
+	notArchive := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(notArchive, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		maxBytes int64
+		wantErr  string
+	}{
+		{"tar traversal", writeTarGz(t, []entry{{name: "src/../../evil.go", body: "x"}}), DefaultMaxBytes, "outside the extraction directory"},
+		{"zip traversal", writeZip(t, []entry{{name: "..\\evil.go", body: "x"}}), DefaultMaxBytes, "outside the extraction directory"},
+		{"absolute path", writeTarGz(t, []entry{{name: outside, body: "x"}}), DefaultMaxBytes, "outside the extraction directory"},
+		{"size limit", writeTarGz(t, []entry{{name: "a.go", body: "12345"}, {name: "b.go", body: "67890"}}), 8, "more than 8 bytes"},
+		{"not an archive", notArchive, DefaultMaxBytes, "is not a .tar, .tar.gz or .zip file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(context.Background(), tt.path, filepath.Join(t.TempDir(), "out"), tt.maxBytes)
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Expected ErrInvalid, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected the error to mention %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := os.Stat(outside); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written outside the extraction directory, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -44,6 +45,17 @@ type Config struct {
 	ResendAPIKey         string
 	EmailFrom            string
 	MaxRepoSizeMB        int
+	// DebtSpikeSigma is the number of standard deviations above the recent
+	// mean at which a run's debt is flagged as a spike.
+	DebtSpikeSigma float64
+	// IssueBatchSize is the number of issues inserted per statement when a
+	// run's issues are stored.
+	IssueBatchSize int
+	// CloneCacheDir, when set, keeps one working copy per repository URL so
+	// repeated clones only fetch new objects. Entries unused for longer than
+	// CloneCacheMaxAge are evicted.
+	CloneCacheDir    string
+	CloneCacheMaxAge time.Duration
 }
 
 func Load() *Config {
@@ -88,6 +100,12 @@ func Load() *Config {
 		ResendAPIKey:  resendAPIKey,
 		EmailFrom:     emailFrom,
 		MaxRepoSizeMB: getEnvInt("MAX_REPO_SIZE_MB", 500),
+
+		DebtSpikeSigma: getEnvFloat("DEBT_SPIKE_SIGMA", 2.0),
+		IssueBatchSize: getEnvInt("ISSUE_BATCH_SIZE", 500),
+
+		CloneCacheDir:    getEnv("CLONE_CACHE_DIR", ""),
+		CloneCacheMaxAge: getDuration("CLONE_CACHE_MAX_AGE", 7*24*time.Hour),
 	}
 }
 
@@ -107,6 +125,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -119,3 +146,9 @@ func getDuration(key string, defaultValue time.Duration) time.Duration {
 func (c *Config) ShutdownTimeout() time.Duration {
 	return getDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
 }
+
+// DatabaseDSN returns the lib/pq connection string for the configured database.
+func (c *Config) DatabaseDSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.DatabaseHost, c.DatabasePort, c.DatabaseUser, c.DatabasePassword, c.DatabaseName, c.DatabaseSSLMode)
+}
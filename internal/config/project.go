@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the repository-level configuration file written by
+// 'debtdrone init' and read by 'debtdrone scan'.
+const ProjectConfigFile = ".debtdrone.yaml"
+
+// ProjectConfig holds the settings of a .debtdrone.yaml file that the scan
+// pipeline consumes.
+type ProjectConfig struct {
+	// AnalysisDepth is the analysis profile of a scan that does not pass
+	// --profile: quick, standard (the default) or deep.
+	AnalysisDepth string `yaml:"analysis_depth"`
+
+	// SeverityOverrides remaps issue severities before output and the quality
+	// gate. Keys are a tool_rule_id (e.g. "CVE-2021-44228") or an issue_type
+	// (e.g. "complexity"); values are critical, high, medium, low or info.
+	SeverityOverrides map[string]string `yaml:"severity_overrides"`
+
+	// BlockingCalls replaces the synchronous JS/TS APIs flagged inside async
+	// functions and route handlers (e.g. "fs.readFileSync", "execSync").
+	BlockingCalls []string `yaml:"blocking_calls"`
+
+	// EffortMultipliers scale the debt of issues by file path, e.g. to weight
+	// legacy code up or generated code down to zero. The first matching rule
+	// applies.
+	EffortMultipliers []EffortMultiplierRule `yaml:"effort_multipliers"`
+
+	// Minified tunes how minified or bundled files are recognized; they are
+	// skipped by the complexity analysis and reported as committed
+	// artifacts. Omitted values keep the defaults.
+	Minified MinifiedConfig `yaml:"minified"`
+
+	// SecurityDebt tunes the debt hours security findings contribute to the
+	// total and the gate. Omitted values keep the defaults.
+	SecurityDebt SecurityDebtConfig `yaml:"security_debt"`
+
+	// Thresholds holds the file-level limits of the complexity analysis.
+	Thresholds ThresholdsConfig `yaml:"thresholds"`
+
+	// RefactoringDocs overrides the pages the refactoring suggestions link
+	// to, keyed by suggestion type (e.g. "extract_method"), so they can
+	// point to internal guidelines. An empty URL removes the link.
+	RefactoringDocs map[string]string `yaml:"refactoring_docs"`
+
+	// GeneratedFiles lists path patterns of generated files, on top of the
+	// built-in ones (e.g. "*.pb.go"), that the complexity analysis skips
+	// unless --include-generated is given.
+	GeneratedFiles []string `yaml:"generated_files"`
+
+	// Licenses configures the opt-in license scan of the security analysis.
+	Licenses LicensesConfig `yaml:"licenses"`
+
+	// Endpoints configures the hardcoded endpoint analysis.
+	Endpoints EndpointsConfig `yaml:"endpoints"`
+}
+
+// EndpointsConfig is the endpoints section of .debtdrone.yaml.
+type EndpointsConfig struct {
+	// AllowHosts lists the hosts, IP addresses and CIDR ranges that may be
+	// hardcoded, e.g. a public API whose address never changes.
+	AllowHosts []string `yaml:"allow_hosts"`
+	// IncludeTests also checks test files, which are skipped by default.
+	IncludeTests bool `yaml:"include_tests"`
+	// IncludeComments also checks comments, which are skipped by default.
+	// Documentation comments are never checked.
+	IncludeComments bool `yaml:"include_comments"`
+}
+
+// LicensesConfig is the licenses section of .debtdrone.yaml.
+type LicensesConfig struct {
+	// Scan turns on Trivy's license scanner, like --license-scan.
+	Scan bool `yaml:"scan"`
+	// Allow lists the licenses (e.g. "MIT", "Apache-2.0") accepted in this
+	// project; findings for them are not reported.
+	Allow []string `yaml:"allow"`
+}
+
+// ThresholdsConfig is the thresholds section of .debtdrone.yaml.
+type ThresholdsConfig struct {
+	// MaxFunctionsPerFile is the number of named functions a file may hold
+	// before it is reported as a large_file; 0 keeps the default.
+	MaxFunctionsPerFile int `yaml:"max_functions_per_file"`
+	// MaxReturns is the weighted number of return points a function may
+	// have before a too_many_returns suggestion; 0 keeps the default.
+	MaxReturns int `yaml:"max_returns"`
+	// Languages overrides the function complexity thresholds per language,
+	// keyed by language name (e.g. "go", "rust", "c/c++").
+	Languages map[string]LanguageThresholdsConfig `yaml:"languages"`
+}
+
+// LanguageThresholdsConfig is one entry of thresholds.languages. Omitted or
+// zero values keep the defaults.
+type LanguageThresholdsConfig struct {
+	CyclomaticHigh      int `yaml:"cyclomatic_high"`
+	CyclomaticCritical  int `yaml:"cyclomatic_critical"`
+	CognitiveHigh       int `yaml:"cognitive_high"`
+	CognitiveCritical   int `yaml:"cognitive_critical"`
+	NestingWarning      int `yaml:"nesting_warning"`
+	NestingCritical     int `yaml:"nesting_critical"`
+	ParameterWarning    int `yaml:"parameter_warning"`
+	ParameterCritical   int `yaml:"parameter_critical"`
+	LinesOfCodeWarning  int `yaml:"lines_of_code_warning"`
+	LinesOfCodeCritical int `yaml:"lines_of_code_critical"`
+}
+
+// SecurityDebtConfig is the security_debt section of .debtdrone.yaml.
+type SecurityDebtConfig struct {
+	// SeverityHours maps a vulnerability severity (critical, high, medium,
+	// low or info) to hours.
+	SeverityHours map[string]float64 `yaml:"severity_hours"`
+	// SecretHours is the cost of a hardcoded secret.
+	SecretHours float64 `yaml:"secret_hours"`
+	// SecretCategoryHours overrides SecretHours by Trivy secret category,
+	// e.g. AsymmetricPrivateKey.
+	SecretCategoryHours map[string]float64 `yaml:"secret_category_hours"`
+}
+
+// MinifiedConfig is the minified section of .debtdrone.yaml.
+type MinifiedConfig struct {
+	// AvgLineLength is the average line length, in bytes, from which a file
+	// counts as minified.
+	AvgLineLength int `yaml:"avg_line_length"`
+	// MinBytes leaves files smaller than this alone.
+	MinBytes int `yaml:"min_bytes"`
+	// Extensions are the file extensions checked (e.g. ".js"); files of
+	// other extensions, such as long-lined data tables, never match.
+	Extensions []string `yaml:"extensions"`
+}
+
+// EffortMultiplierRule is one path glob => multiplier entry of
+// effort_multipliers. Multiplier is a pointer so an omitted value can be told
+// apart from an explicit 0.
+type EffortMultiplierRule struct {
+	Path       string   `yaml:"path"`
+	Multiplier *float64 `yaml:"multiplier"`
+}
+
+// LoadProjectConfig reads the project configuration at path. When the file does
+// not exist and required is false, an empty configuration is returned so that
+// repositories without a .debtdrone.yaml keep the built-in defaults.
+func LoadProjectConfig(path string, required bool) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return &ProjectConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
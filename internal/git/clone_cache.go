@@ -0,0 +1,246 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CloneCache keeps one working copy per repository URL on disk so repeated
+// analyses of the same repository fetch only new objects instead of
+// re-downloading the whole history.
+//
+// Each entry is guarded by a per-URL mutex that is held from checkout until the
+// caller's Repository.Cleanup, so two workers never share or rewrite the same
+// checkout concurrently.
+type CloneCache struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewCloneCache creates (if needed) and returns a clone cache rooted at dir.
+func NewCloneCache(dir string) (*CloneCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create clone cache dir: %w", err)
+	}
+	return &CloneCache{
+		dir:   dir,
+		locks: make(map[string]*sync.Mutex),
+	}, nil
+}
+
+// NewServiceWithCloneCache returns a Service whose filesystem clones go through
+// cache. In-memory clones are never cached, and a clone falls back to a fresh
+// temporary directory only when the cache entry is unusable.
+func NewServiceWithCloneCache(cache *CloneCache) *Service {
+	return &Service{cache: cache}
+}
+
+func (c *CloneCache) entryName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *CloneCache) lockFor(name string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, ok := c.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.locks[name] = lock
+	}
+	return lock
+}
+
+// errUnusableEntry marks the refresh failures that mean the cache entry itself
+// is missing or broken, as opposed to a failed fetch.
+var errUnusableEntry = errors.New("unusable clone cache entry")
+
+// checkout returns the cached working copy for opts.URL positioned at the tip
+// of opts.Branch (or the default branch). A missing entry is cloned into the
+// cache; an entry that cannot be opened or checked out is treated as corrupt,
+// removed and cloned again; when that fails too, the error wraps
+// errUnusableEntry. A failed fetch leaves the entry alone and is returned as
+// is, since the remote, not the entry, is at fault. The returned
+// Repository holds the entry lock until its Cleanup is called.
+func (c *CloneCache) checkout(ctx context.Context, opts CloneOptions) (*Repository, error) {
+	name := c.entryName(opts.URL)
+	path := filepath.Join(c.dir, name)
+
+	lock := c.lockFor(name)
+	lock.Lock()
+
+	if err := c.refresh(ctx, path, opts); err != nil {
+		if !errors.Is(err, errUnusableEntry) {
+			lock.Unlock()
+			return nil, err
+		}
+		log.Printf("⚠️ [GitService] Clone cache entry %s unusable, re-cloning: %v", name, err)
+		if err := os.RemoveAll(path); err != nil {
+			lock.Unlock()
+			return nil, fmt.Errorf("%w: failed to remove corrupt cache entry: %w", errUnusableEntry, err)
+		}
+		if _, err := git.PlainCloneContext(ctx, path, false, buildCloneOptions(opts)); err != nil {
+			os.RemoveAll(path)
+			lock.Unlock()
+			return nil, fmt.Errorf("%w: failed to clone into cache: %w", errUnusableEntry, err)
+		}
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return &Repository{
+		FS:      osfs.New(path),
+		Path:    path,
+		release: lock.Unlock,
+	}, nil
+}
+
+// refresh fetches and checks out the requested branch in an existing entry.
+// Every failure but the fetch wraps errUnusableEntry.
+func (c *CloneCache) refresh(ctx context.Context, path string, opts CloneOptions) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%w: %w", errUnusableEntry, err)
+	}
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("%w: open: %w", errUnusableEntry, err)
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("%w: resolve HEAD: %w", errUnusableEntry, err)
+		}
+		if !head.Name().IsBranch() {
+			return fmt.Errorf("%w: HEAD is detached", errUnusableEntry)
+		}
+		branch = head.Name().Short()
+	}
+
+	localRef := plumbing.NewBranchReferenceName(branch)
+	remoteRef := plumbing.NewRemoteReferenceName("origin", branch)
+
+	fetchOpts := &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", localRef, remoteRef))},
+		Tags:       git.NoTags,
+		Depth:      opts.Depth,
+		Force:      true,
+		Auth:       buildAuth(opts.Token),
+	}
+	if err := repo.FetchContext(ctx, fetchOpts); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("fetch: %w", err)
+	}
+
+	remote, err := repo.Reference(remoteRef, true)
+	if err != nil {
+		return fmt.Errorf("%w: resolve %s: %w", errUnusableEntry, remoteRef, err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(localRef, remote.Hash())); err != nil {
+		return fmt.Errorf("%w: update %s: %w", errUnusableEntry, localRef, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("%w: worktree: %w", errUnusableEntry, err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: localRef, Force: true}); err != nil {
+		return fmt.Errorf("%w: checkout %s: %w", errUnusableEntry, branch, err)
+	}
+	return nil
+}
+
+// Evict removes cache entries that have not been used for longer than maxAge,
+// then removes the least recently used entries until the cache is no larger
+// than maxBytes. A zero limit disables that criterion. Entries currently
+// checked out are skipped. It returns the number of entries removed.
+func (c *CloneCache) Evict(maxAge time.Duration, maxBytes int64) (int, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read clone cache: %w", err)
+	}
+
+	type cacheEntry struct {
+		name    string
+		usedAt  time.Time
+		size    int64
+		removed bool
+	}
+
+	var entries []*cacheEntry
+	var totalSize int64
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		size, _ := (&Repository{Path: filepath.Join(c.dir, d.Name())}).GetSizeMB()
+		bytes := int64(size * 1024 * 1024)
+		entries = append(entries, &cacheEntry{name: d.Name(), usedAt: info.ModTime(), size: bytes})
+		totalSize += bytes
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].usedAt.Before(entries[j].usedAt) })
+
+	removed := 0
+	remove := func(e *cacheEntry) {
+		lock := c.lockFor(e.name)
+		if !lock.TryLock() {
+			return
+		}
+		defer lock.Unlock()
+
+		if err := os.RemoveAll(filepath.Join(c.dir, e.name)); err != nil {
+			log.Printf("⚠️ [GitService] Failed to evict clone cache entry %s: %v", e.name, err)
+			return
+		}
+		e.removed = true
+		totalSize -= e.size
+		removed++
+	}
+
+	if maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge)
+		for _, e := range entries {
+			if e.usedAt.Before(cutoff) {
+				remove(e)
+			}
+		}
+	}
+
+	if maxBytes > 0 {
+		for _, e := range entries {
+			if totalSize <= maxBytes {
+				break
+			}
+			if !e.removed {
+				remove(e)
+			}
+		}
+	}
+
+	return removed, nil
+}
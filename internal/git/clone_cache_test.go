@@ -0,0 +1,207 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// newOriginRepo creates a local repository with a single commit to clone from.
+func newOriginRepo(t *testing.T) (string, func(content string)) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := worktree.Add("main.go"); err != nil {
+			t.Fatal(err)
+		}
+		sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+		if _, err := worktree.Commit("update", &git.CommitOptions{Author: sig}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("package main\n")
+	return dir, commit
+}
+
+func TestCloneCache_ReusesAndRefreshesEntry(t *testing.T) {
+	origin, commit := newOriginRepo(t)
+	cache, err := NewCloneCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewServiceWithCloneCache(cache)
+	ctx := context.Background()
+
+	first, err := svc.Clone(ctx, CloneOptions{URL: origin})
+	if err != nil {
+		t.Fatalf("first clone failed: %v", err)
+	}
+	firstPath := first.Path
+	if err := first.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(firstPath); err != nil {
+		t.Fatalf("cached checkout should survive Cleanup: %v", err)
+	}
+
+	commit("package main\n\nfunc main() {}\n")
+
+	second, err := svc.Clone(ctx, CloneOptions{URL: origin})
+	if err != nil {
+		t.Fatalf("second clone failed: %v", err)
+	}
+	defer second.Cleanup()
+
+	if second.Path != firstPath {
+		t.Errorf("expected the cache entry to be reused, got %s and %s", firstPath, second.Path)
+	}
+	content, err := os.ReadFile(filepath.Join(second.Path, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "package main\n\nfunc main() {}\n" {
+		t.Errorf("expected the cached checkout to be fetched to the latest commit, got %q", content)
+	}
+}
+
+func TestCloneCache_SerializesAccessPerURL(t *testing.T) {
+	origin, _ := newOriginRepo(t)
+	cache, err := NewCloneCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewServiceWithCloneCache(cache)
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo, err := svc.Clone(context.Background(), CloneOptions{URL: origin})
+			if err != nil {
+				t.Errorf("clone failed: %v", err)
+				return
+			}
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+			repo.Cleanup()
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Errorf("expected one holder of the cache entry at a time, saw %d", maxActive)
+	}
+}
+
+func TestCloneCache_RecoversFromCorruptEntry(t *testing.T) {
+	origin, _ := newOriginRepo(t)
+	cache, err := NewCloneCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrupt := filepath.Join(cache.dir, cache.entryName(origin))
+	if err := os.MkdirAll(corrupt, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := NewServiceWithCloneCache(cache).Clone(context.Background(), CloneOptions{URL: origin})
+	if err != nil {
+		t.Fatalf("expected a corrupt entry to be re-cloned, got %v", err)
+	}
+	defer repo.Cleanup()
+
+	if _, err := os.Stat(filepath.Join(repo.Path, "main.go")); err != nil {
+		t.Errorf("expected a usable checkout after recovery: %v", err)
+	}
+}
+
+func TestCloneCache_KeepsEntryWhenFetchFails(t *testing.T) {
+	origin, _ := newOriginRepo(t)
+	cache, err := NewCloneCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewServiceWithCloneCache(cache)
+
+	repo, err := svc.Clone(context.Background(), CloneOptions{URL: origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Cleanup()
+
+	if err := os.RemoveAll(origin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Clone(context.Background(), CloneOptions{URL: origin}); err == nil || !strings.Contains(err.Error(), "fetch") {
+		t.Errorf("expected the fetch error of an unreachable remote, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Path, "main.go")); err != nil {
+		t.Errorf("expected the cache entry to survive a failed fetch: %v", err)
+	}
+}
+
+func TestCloneCache_Evict(t *testing.T) {
+	cache, err := NewCloneCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := filepath.Join(cache.dir, "old")
+	fresh := filepath.Join(cache.dir, "fresh")
+	for _, dir := range []string{old, fresh} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := cache.Evict(24*time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 entry evicted, got %d", removed)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("expected the stale entry to be removed")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("expected the fresh entry to be kept")
+	}
+}
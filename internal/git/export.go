@@ -0,0 +1,113 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ExportRef writes the files of the commit ref names, below repoPath, into
+// dir, and returns the commit's hash. The files are read from the tree
+// objects of the repository containing repoPath, so neither its working
+// tree nor its index is touched. Symbolic links and submodules are left out,
+// as a scan of the working tree skips them too.
+func (s *Service) ExportRef(ctx context.Context, repoPath, ref, dir string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to open the git repository of %s: %w", repoPath, err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnknownRef, ref)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q does not name a commit", ErrUnknownRef, ref)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the tree of %s: %w", ref, err)
+	}
+
+	sub, err := repositorySubdir(repo, repoPath)
+	if err != nil {
+		return "", err
+	}
+	if sub != "" {
+		if tree, err = tree.Tree(sub); err != nil {
+			return "", fmt.Errorf("%s does not exist at %s: %w", sub, ref, err)
+		}
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable && f.Mode != filemode.Deprecated {
+			return nil
+		}
+		return exportFile(f, filepath.Join(dir, filepath.FromSlash(f.Name)))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to export %s: %w", ref, err)
+	}
+	return commit.Hash.String(), nil
+}
+
+// repositorySubdir returns the slash-separated path of repoPath below the
+// root of repo's working tree, or "" for the root itself.
+func repositorySubdir(repo *git.Repository, repoPath string) (string, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to open the worktree of %s: %w", repoPath, err)
+	}
+	root, err := filepath.EvalSymlinks(worktree.Filesystem.Root())
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the working tree of its repository", repoPath)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func exportFile(f *object.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	perm := os.FileMode(0644)
+	if f.Mode == filemode.Executable {
+		perm = 0755
+	}
+	reader, err := f.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
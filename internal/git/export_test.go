@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFiles(t, dir, map[string]string{
+		"README.md":          "# api\n",
+		"svc/main.go":        "package main\n",
+		"svc/handler/api.go": "package handler\n",
+	})
+	if err := os.Symlink("main.go", filepath.Join(dir, "svc", "link.go")); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "initial")
+	runGit("tag", "-a", "v1", "-m", "release")
+	writeFiles(t, dir, map[string]string{"svc/main.go": "package main\n\nfunc main() {}\n"})
+	runGit("commit", "-q", "-am", "update")
+
+	svc := NewService()
+	commit, err := svc.GetCurrentCommitHash(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := t.TempDir()
+	hash, err := svc.ExportRef(context.Background(), filepath.Join(dir, "svc"), "v1", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" || hash == commit {
+		t.Errorf("Expected the commit of the tag, got %q (HEAD is %q)", hash, commit)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "main.go"))
+	if err != nil || string(got) != "package main\n" {
+		t.Errorf("Expected main.go as of v1, got %q (%v)", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "handler", "api.go")); err != nil {
+		t.Errorf("Expected the nested file to be exported: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(out, "README.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected only the files below the scanned directory, got README.md (%v)", err)
+	}
+	if _, err := os.Lstat(filepath.Join(out, "link.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected the symbolic link to be left out, got %v", err)
+	}
+
+	worktree, err := os.ReadFile(filepath.Join(dir, "svc", "main.go"))
+	if err != nil || string(worktree) != "package main\n\nfunc main() {}\n" {
+		t.Errorf("Expected the working tree to be left alone, got %q (%v)", worktree, err)
+	}
+
+	hash, err = svc.ExportRef(context.Background(), dir, "HEAD~1", t.TempDir())
+	if err != nil || hash == commit {
+		t.Errorf("Expected HEAD~1 to resolve to the first commit, got %q (%v)", hash, err)
+	}
+
+	if _, err := svc.ExportRef(context.Background(), dir, "no-such-tag", t.TempDir()); !errors.Is(err, ErrUnknownRef) {
+		t.Errorf("Expected ErrUnknownRef for an unknown ref, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// DebtdroneIgnoreFile is the name of the ignore file, kept at the root of the
+// repository, whose gitignore-style patterns exclude paths from debtdrone
+// only.
+const DebtdroneIgnoreFile = ".debtdroneignore"
+
+// IgnoreMatcher reports which paths of a checkout are excluded by its
+// .gitignore files (including nested ones and .git/info/exclude) or by its
+// .debtdroneignore. Files that are tracked in the index are never excluded by
+// a .gitignore pattern, and neither are the directories containing them;
+// .debtdroneignore patterns apply to tracked files too, since they exist to
+// exclude files that are committed.
+//
+// A nil *IgnoreMatcher ignores nothing, so walks can use it unconditionally.
+type IgnoreMatcher struct {
+	root        string
+	matcher     gitignore.Matcher
+	debtdrone   gitignore.Matcher
+	tracked     map[string]bool
+	trackedDirs map[string]bool
+}
+
+// NewIgnoreMatcher loads the ignore rules that apply to path. When path lies
+// inside a git worktree, the rules and tracked files of the whole worktree are
+// used, and the .debtdroneignore is read from the worktree root; otherwise
+// only the .gitignore files below path and path's own .debtdroneignore are
+// read. It returns nil when no rule exists. The rules of a file that ignores
+// path itself are dropped, since scanning an ignored directory explicitly
+// means its contents are wanted.
+func NewIgnoreMatcher(path string) (*IgnoreMatcher, error) {
+	return newIgnoreMatcher(path, true)
+}
+
+// NewDebtdroneIgnoreMatcher is NewIgnoreMatcher without the .gitignore rules,
+// for scans told to analyze ignored files: the .debtdroneignore still applies.
+func NewDebtdroneIgnoreMatcher(path string) (*IgnoreMatcher, error) {
+	return newIgnoreMatcher(path, false)
+}
+
+func newIgnoreMatcher(path string, withGitignore bool) (*IgnoreMatcher, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := absPath
+	tracked := map[string]bool{}
+	trackedDirs := map[string]bool{}
+
+	repo, err := git.PlainOpenWithOptions(absPath, &git.PlainOpenOptions{DetectDotGit: true})
+	switch {
+	case err == nil:
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open worktree: %w", err)
+		}
+		root = worktree.Filesystem.Root()
+
+		index, err := repo.Storer.Index()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index: %w", err)
+		}
+		for _, entry := range index.Entries {
+			tracked[entry.Name] = true
+			for dir := filepath.ToSlash(filepath.Dir(entry.Name)); dir != "."; dir = filepath.ToSlash(filepath.Dir(dir)) {
+				trackedDirs[dir] = true
+			}
+		}
+	case errors.Is(err, git.ErrRepositoryNotExists):
+	default:
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	m := &IgnoreMatcher{
+		root:        root,
+		tracked:     tracked,
+		trackedDirs: trackedDirs,
+	}
+	if withGitignore {
+		patterns, err := gitignore.ReadPatterns(osfs.New(root), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .gitignore files: %w", err)
+		}
+		if len(patterns) > 0 {
+			m.matcher = gitignore.NewMatcher(patterns)
+			if m.gitignored(absPath, true) {
+				m.matcher = nil
+			}
+		}
+	}
+
+	patterns, err := readDebtdroneIgnore(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) > 0 {
+		m.debtdrone = gitignore.NewMatcher(patterns)
+		if m.debtdroneIgnored(absPath, true) {
+			m.debtdrone = nil
+		}
+	}
+
+	if m.matcher == nil && m.debtdrone == nil {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// readDebtdroneIgnore parses the .debtdroneignore at root, if any. Its
+// patterns follow the .gitignore syntax, including "!" negation, and are
+// relative to root.
+func readDebtdroneIgnore(root string) ([]gitignore.Pattern, error) {
+	content, err := os.ReadFile(filepath.Join(root, DebtdroneIgnoreFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", DebtdroneIgnoreFile, err)
+	}
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", DebtdroneIgnoreFile, err)
+	}
+	return patterns, nil
+}
+
+// Ignored reports whether the file or directory at path should be skipped:
+// either a .gitignore or the .debtdroneignore excludes it.
+func (m *IgnoreMatcher) Ignored(path string, isDir bool) bool {
+	return m.IgnoredBy(path, isDir) != ""
+}
+
+// IgnoredBy returns the ignore file excluding path, DebtdroneIgnoreFile or
+// ".gitignore", or "" when path is not ignored.
+func (m *IgnoreMatcher) IgnoredBy(path string, isDir bool) string {
+	switch {
+	case m == nil:
+		return ""
+	case m.debtdroneIgnored(path, isDir):
+		return DebtdroneIgnoreFile
+	case m.gitignored(path, isDir):
+		return ".gitignore"
+	default:
+		return ""
+	}
+}
+
+func (m *IgnoreMatcher) gitignored(path string, isDir bool) bool {
+	rel, ok := m.relative(path)
+	if !ok || m.matcher == nil {
+		return false
+	}
+	if (isDir && m.trackedDirs[rel]) || (!isDir && m.tracked[rel]) {
+		return false
+	}
+	return m.matcher.Match(strings.Split(rel, "/"), isDir)
+}
+
+func (m *IgnoreMatcher) debtdroneIgnored(path string, isDir bool) bool {
+	rel, ok := m.relative(path)
+	if !ok || m.debtdrone == nil {
+		return false
+	}
+	return m.debtdrone.Match(strings.Split(rel, "/"), isDir)
+}
+
+// relative returns path relative to the root of the rules, slash-separated.
+// It reports false for the root itself and for paths outside it.
+func (m *IgnoreMatcher) relative(path string) (string, bool) {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
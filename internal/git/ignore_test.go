@@ -0,0 +1,197 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		".gitignore":           "dist/\n*.log\n",
+		"src/.gitignore":       "generated/\n",
+		"src/main.go":          "package main\n",
+		"src/generated/gen.go": "package generated\n",
+		"dist/bundle.js":       "x\n",
+		"dist/keep.js":         "x\n",
+		"debug.log":            "x\n",
+		"build/vendor.log":     "x\n",
+	})
+
+	// keep.js is tracked despite matching dist/, as with `git add -f`.
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{".gitignore", "src/main.go", "dist/keep.js"} {
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewIgnoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"src", true, false},
+		{"src/main.go", false, false},
+		{"src/generated", true, true},
+		{"debug.log", false, true},
+		{"build/vendor.log", false, true},
+		{"dist", true, false},
+		{"dist/keep.js", false, false},
+		{"dist/bundle.js", false, true},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(filepath.Join(dir, filepath.FromSlash(tt.path)), tt.isDir); got != tt.ignored {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+
+	t.Run("scanning a subdirectory applies the worktree's rules", func(t *testing.T) {
+		sub, err := NewIgnoreMatcher(filepath.Join(dir, "src"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sub.Ignored(filepath.Join(dir, "src", "generated"), true) {
+			t.Error("expected src/generated to be ignored")
+		}
+	})
+
+	t.Run("scanning an ignored directory ignores nothing", func(t *testing.T) {
+		sub, err := NewIgnoreMatcher(filepath.Join(dir, "src", "generated"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub != nil {
+			t.Error("expected no matcher for an explicitly scanned ignored directory")
+		}
+	})
+}
+
+func TestIgnoreMatcher_WithoutRepository(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".gitignore":    "target/\n",
+		"target/app.rs": "fn main() {}\n",
+		"src/app.rs":    "fn main() {}\n",
+	})
+
+	m, err := NewIgnoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Ignored(filepath.Join(dir, "target"), true) {
+		t.Error("expected target/ to be ignored outside a git repository")
+	}
+	if m.Ignored(filepath.Join(dir, "src", "app.rs"), false) {
+		t.Error("expected src/app.rs not to be ignored")
+	}
+
+	var none *IgnoreMatcher
+	if none.Ignored(filepath.Join(dir, "target"), true) {
+		t.Error("a nil matcher must ignore nothing")
+	}
+}
+
+func TestIgnoreMatcher_DebtdroneIgnore(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		".gitignore":           "*.log\n",
+		".debtdroneignore":     "# legacy code is not worth reporting\nlegacy/\n/fixtures/*.py\n!/fixtures/keep.py\n",
+		"legacy/old.py":        "x\n",
+		"src/legacy/new.py":    "x\n",
+		"fixtures/sample.py":   "x\n",
+		"fixtures/keep.py":     "x\n",
+		"src/fixtures/case.py": "x\n",
+		"debug.log":            "x\n",
+		"src/main.py":          "x\n",
+	})
+
+	// Tracked files are excluded by .debtdroneignore all the same.
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"legacy/old.py", "fixtures/sample.py", "src/main.py"} {
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewIgnoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path  string
+		isDir bool
+		by    string
+	}{
+		{"legacy", true, DebtdroneIgnoreFile},
+		{"src/legacy", true, DebtdroneIgnoreFile},
+		{"fixtures/sample.py", false, DebtdroneIgnoreFile},
+		{"fixtures/keep.py", false, ""},
+		{"src/fixtures/case.py", false, ""},
+		{"debug.log", false, ".gitignore"},
+		{"src/main.py", false, ""},
+	}
+	for _, tt := range tests {
+		if got := m.IgnoredBy(filepath.Join(dir, filepath.FromSlash(tt.path)), tt.isDir); got != tt.by {
+			t.Errorf("IgnoredBy(%q) = %q, want %q", tt.path, got, tt.by)
+		}
+	}
+
+	t.Run("without .gitignore rules", func(t *testing.T) {
+		m, err := NewDebtdroneIgnoreMatcher(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Ignored(filepath.Join(dir, "debug.log"), false) {
+			t.Error("expected debug.log not to be ignored")
+		}
+		if !m.Ignored(filepath.Join(dir, "legacy"), true) {
+			t.Error("expected legacy/ to be ignored by .debtdroneignore")
+		}
+	})
+
+	t.Run("scanning a subdirectory reads the root's file", func(t *testing.T) {
+		sub, err := NewDebtdroneIgnoreMatcher(filepath.Join(dir, "src"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sub.Ignored(filepath.Join(dir, "src", "legacy"), true) {
+			t.Error("expected src/legacy to be ignored")
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Hosting providers Permalinks can build links for.
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+)
+
+// Permalinks builds links to lines of files at one commit of a repository
+// hosted on GitHub, GitLab or Bitbucket Cloud, so a finding can point at the
+// exact code that was analyzed even after the branch moves on.
+type Permalinks struct {
+	Provider string
+	// WebURL is the repository's web page, e.g. https://github.com/acme/api.
+	WebURL string
+	Commit string
+	// Root is the repository's top-level directory; paths given to URL are
+	// resolved against it.
+	Root string
+}
+
+// ParseRemote returns the provider and web URL of a remote URL in any of the
+// forms git accepts: https://host/owner/repo.git, git@host:owner/repo.git or
+// ssh://git@host/owner/repo.git. Credentials and ports are dropped. ok is
+// false for hosts that are not recognizably GitHub (including GitHub
+// Enterprise), GitLab or bitbucket.org, and for local paths.
+func ParseRemote(remoteURL string) (provider, webURL string, ok bool) {
+	scheme, host, repoPath, ok := splitRemote(remoteURL)
+	if !ok {
+		return "", "", false
+	}
+	switch {
+	case host == "bitbucket.org":
+		provider = ProviderBitbucket
+	case strings.Contains(host, "github"):
+		provider = ProviderGitHub
+	case strings.Contains(host, "gitlab"):
+		provider = ProviderGitLab
+	default:
+		return "", "", false
+	}
+
+	// GitLab nests projects in groups; the others are always owner/name.
+	if provider != ProviderGitLab && strings.Count(repoPath, "/") != 1 {
+		return "", "", false
+	}
+	return provider, scheme + "://" + host + "/" + repoPath, true
+}
+
+// RemoteFullName returns the full name of the repository a remote URL points
+// to, e.g. "acme/api" for git@github.com:acme/api.git, on any host. It is the
+// full_name the repository is stored under. ok is false for local paths.
+func RemoteFullName(remoteURL string) (fullName string, ok bool) {
+	_, _, repoPath, ok := splitRemote(remoteURL)
+	return repoPath, ok
+}
+
+// splitRemote splits a remote URL into its web scheme, lower-cased host and
+// repository path without the ".git" suffix. ok is false when the path has
+// fewer than two segments or for local paths.
+func splitRemote(remoteURL string) (scheme, host, repoPath string, ok bool) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	scheme = "https"
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", "", "", false
+		}
+		switch u.Scheme {
+		case "http", "https":
+			scheme = u.Scheme
+		case "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return "", "", "", false
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path. A slash before the colon means
+		// a local path.
+		colon := strings.Index(remoteURL, ":")
+		if colon < 0 || strings.Contains(remoteURL[:colon], "/") {
+			return "", "", "", false
+		}
+		host, repoPath = remoteURL[:colon], remoteURL[colon+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	segments := strings.Split(repoPath, "/")
+	if host == "" || len(segments) < 2 {
+		return "", "", "", false
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return "", "", "", false
+		}
+	}
+	return scheme, strings.ToLower(host), repoPath, true
+}
+
+// NewPermalinks returns the permalinks of the repository whose origin remote
+// is remoteURL, at commit, checked out under root. ok is false when the host
+// is not recognized.
+func NewPermalinks(remoteURL, commit, root string) (*Permalinks, bool) {
+	provider, webURL, ok := ParseRemote(remoteURL)
+	if !ok || commit == "" {
+		return nil, false
+	}
+	return &Permalinks{Provider: provider, WebURL: webURL, Commit: commit, Root: root}, true
+}
+
+// URL returns the link to line of the file at path, an absolute path below
+// Root; a line of 0 or less links the whole file. It returns "" for paths
+// outside the repository.
+func (p *Permalinks) URL(path string, line int) string {
+	rel, ok := p.relative(path)
+	if !ok {
+		// git reports Root with symlinks resolved, e.g. /private/tmp for
+		// /tmp on macOS.
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return ""
+		}
+		if rel, ok = p.relative(resolved); !ok {
+			return ""
+		}
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	escaped := strings.Join(segments, "/")
+
+	var link, anchor string
+	switch p.Provider {
+	case ProviderGitLab:
+		link = fmt.Sprintf("%s/-/blob/%s/%s", p.WebURL, p.Commit, escaped)
+		anchor = "#L"
+	case ProviderBitbucket:
+		link = fmt.Sprintf("%s/src/%s/%s", p.WebURL, p.Commit, escaped)
+		anchor = "#lines-"
+	default:
+		link = fmt.Sprintf("%s/blob/%s/%s", p.WebURL, p.Commit, escaped)
+		anchor = "#L"
+	}
+	if line > 0 {
+		link += anchor + strconv.Itoa(line)
+	}
+	return link
+}
+
+func (p *Permalinks) relative(path string) (string, bool) {
+	rel, err := filepath.Rel(p.Root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// GetPermalinks returns the permalinks of the repository containing
+// repoPath at its HEAD commit, from the URL of its origin remote. It fails
+// when repoPath is not in a git repository, has no origin remote or the
+// host is not recognized.
+func (s *Service) GetPermalinks(ctx context.Context, repoPath string) (*Permalinks, error) {
+	root, err := gitOutput(ctx, repoPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the repository root: %w", err)
+	}
+	remoteURL, err := gitOutput(ctx, repoPath, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the origin remote: %w", err)
+	}
+	commit, err := s.GetCurrentCommitHash(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	links, ok := NewPermalinks(remoteURL, commit, filepath.FromSlash(root))
+	if !ok {
+		// The remote URL is left out since it may embed credentials.
+		return nil, fmt.Errorf("the origin remote is not on a supported git host")
+	}
+	return links, nil
+}
+
+// GetRemoteFullName returns the full name of the repository containing
+// repoPath, e.g. "acme/api", from the URL of its origin remote. It fails when
+// repoPath is not in a git repository or has no origin remote.
+func (s *Service) GetRemoteFullName(ctx context.Context, repoPath string) (string, error) {
+	remoteURL, err := gitOutput(ctx, repoPath, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the origin remote: %w", err)
+	}
+	fullName, ok := RemoteFullName(remoteURL)
+	if !ok {
+		// The remote URL is left out since it may embed credentials.
+		return "", fmt.Errorf("the origin remote does not name a repository")
+	}
+	return fullName, nil
+}
+
+func gitOutput(ctx context.Context, repoPath string, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,17 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package git
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// processAlive reports whether a process with the given PID exists. A
+// process owned by another user counts as alive.
+func processAlive(pid int) bool {
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
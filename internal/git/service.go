@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -14,12 +15,14 @@ import (
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage"
 	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 type Service struct {
+	cache *CloneCache
 }
 
 func NewService() *Service {
@@ -39,6 +42,10 @@ func (s *Service) OpenLocal(path string) (*Repository, error) {
 type Repository struct {
 	FS   billy.Filesystem
 	Path string
+
+	// release is set for clone-cache checkouts; Cleanup then releases the
+	// cache entry instead of deleting the directory.
+	release func()
 }
 type CloneOptions struct {
 	URL          string
@@ -55,6 +62,14 @@ func (s *Service) Clone(ctx context.Context, opts CloneOptions) (*Repository, er
 	var path string
 	var err error
 
+	if s.cache != nil && !opts.UseInMemory {
+		repo, err := s.cache.checkout(ctx, opts)
+		if !errors.Is(err, errUnusableEntry) {
+			return repo, err
+		}
+		log.Printf("⚠️ [GitService] Clone cache unavailable, falling back to a fresh clone: %v", err)
+	}
+
 	if opts.UseInMemory {
 		storer = memory.NewStorage()
 		fs = memfs.New()
@@ -65,37 +80,16 @@ func (s *Service) Clone(ctx context.Context, opts CloneOptions) (*Repository, er
 			return nil, err
 		}
 
-		path, err = os.MkdirTemp("", "debtdrone-repo-*")
+		path, err = MkdirTemp(TempDirClone)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create temp dir: %w", err)
 		}
 		fs = osfs.New(path)
 	}
 
-	cloneOpts := &git.CloneOptions{
-		URL:      opts.URL,
-		Progress: nil,
-		Tags:     git.NoTags,
-	}
-
-	if opts.Depth > 0 {
-		cloneOpts.Depth = opts.Depth
-	}
-
-	if opts.SingleBranch {
-		cloneOpts.SingleBranch = true
-	}
-
-	if opts.Branch != "" {
-		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
-	}
-
+	cloneOpts := buildCloneOptions(opts)
 	if opts.Token != "" {
 		log.Printf("🔐 [GitService] Cloning with auth token (len: %d)", len(opts.Token))
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: "oauth2",   // Use "oauth2" or generic username
-			Password: opts.Token, // Token as password is often more reliable
-		}
 	} else {
 		log.Printf("⚠️ [GitService] Cloning WITHOUT auth token")
 	}
@@ -108,7 +102,7 @@ func (s *Service) Clone(ctx context.Context, opts CloneOptions) (*Repository, er
 
 	if err != nil {
 		if path != "" {
-			os.RemoveAll(path)
+			RemoveTempDir(path)
 		}
 		return nil, fmt.Errorf("failed to clone repository: %w", err)
 	}
@@ -119,9 +113,47 @@ func (s *Service) Clone(ctx context.Context, opts CloneOptions) (*Repository, er
 	}, nil
 }
 
+func buildCloneOptions(opts CloneOptions) *git.CloneOptions {
+	cloneOpts := &git.CloneOptions{
+		URL:      opts.URL,
+		Progress: nil,
+		Tags:     git.NoTags,
+		Auth:     buildAuth(opts.Token),
+	}
+
+	if opts.Depth > 0 {
+		cloneOpts.Depth = opts.Depth
+	}
+
+	if opts.SingleBranch {
+		cloneOpts.SingleBranch = true
+	}
+
+	if opts.Branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+
+	return cloneOpts
+}
+
+func buildAuth(token string) transport.AuthMethod {
+	if token == "" {
+		return nil
+	}
+	return &http.BasicAuth{
+		Username: "oauth2", // Use "oauth2" or generic username
+		Password: token,    // Token as password is often more reliable
+	}
+}
+
 func (r *Repository) Cleanup() error {
+	if r.release != nil {
+		r.release()
+		r.release = nil
+		return nil
+	}
 	if r.Path != "" {
-		return os.RemoveAll(r.Path)
+		return RemoveTempDir(r.Path)
 	}
 	return nil
 }
@@ -195,6 +227,69 @@ func (s *Service) GetChangedFiles(ctx context.Context, repoPath, oldCommit, newC
 	return files, nil
 }
 
+// GetStagedFiles lists the files staged in the index of the repository that
+// contains repoPath, relative to repoPath and limited to files below it.
+// Staged deletions are left out since there is nothing left to analyze.
+func (s *Service) GetStagedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "diff", "--cached", "--name-only", "--diff-filter=d", "--relative", "-z")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged files: %w", err)
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// ErrUnknownRef is returned when a ref does not name a commit.
+var ErrUnknownRef = errors.New("unknown git ref")
+
+// HasCommit reports whether ref names a commit of the repository that
+// contains repoPath. It is false outside a repository and before the first
+// commit.
+func (s *Service) HasCommit(ctx context.Context, repoPath, ref string) bool {
+	return exec.CommandContext(ctx, "git", "-C", repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+// GetFilesChangedSince lists the tracked files of the repository that
+// contains repoPath whose content differs between the commit ref names and
+// the working tree, relative to repoPath and limited to files below it.
+// Deleted files are left out; a renamed file is listed under its new name.
+func (s *Service) GetFilesChangedSince(ctx context.Context, repoPath, ref string) ([]string, error) {
+	if !s.HasCommit(ctx, repoPath, ref) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRef, ref)
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "diff", "--name-only", "--diff-filter=d", "--relative", "-z", ref, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get files changed since %s: %w", ref, err)
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// GetFileAtRef returns the content of the file at relPath, relative to
+// repoPath, in the commit ref names. ok is false when the file does not
+// exist there; ref itself is expected to be valid.
+func (s *Service) GetFileAtRef(ctx context.Context, repoPath, ref, relPath string) (content []byte, ok bool) {
+	output, err := exec.CommandContext(ctx, "git", "-C", repoPath, "show", ref+":./"+filepath.ToSlash(relPath)).Output()
+	if err != nil {
+		return nil, false
+	}
+	return output, true
+}
+
 type CommitContext struct {
 	Hash          string
 	AuthorName    string
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The kinds of temporary directories debtdrone creates with MkdirTemp.
+const (
+	TempDirClone   = "repo"
+	TempDirRef     = "ref"
+	TempDirArchive = "archive"
+)
+
+// tempDirKinds are the kinds RemoveStaleTempDirs looks for.
+var tempDirKinds = []string{TempDirClone, TempDirRef, TempDirArchive}
+
+// DefaultStaleTempDirAge is how old a temporary directory must be before
+// RemoveStaleTempDirs deletes it.
+const DefaultStaleTempDirAge = 24 * time.Hour
+
+// liveTempDirs are the directories this process created and has not removed
+// yet, for RemoveTempDirs.
+var liveTempDirs = struct {
+	sync.Mutex
+	paths map[string]bool
+}{paths: make(map[string]bool)}
+
+// MkdirTemp creates a temporary directory of kind in the system temp
+// directory, named debtdrone-<kind>-<pid>-<random> so that RemoveStaleTempDirs
+// can tell whether the process that created it is still running. It is
+// removed by RemoveTempDir, or by RemoveTempDirs when the process is
+// interrupted.
+func MkdirTemp(kind string) (string, error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("debtdrone-%s-%d-*", kind, os.Getpid()))
+	if err != nil {
+		return "", err
+	}
+	liveTempDirs.Lock()
+	liveTempDirs.paths[dir] = true
+	liveTempDirs.Unlock()
+	return dir, nil
+}
+
+// RemoveTempDir deletes a directory created by MkdirTemp.
+func RemoveTempDir(dir string) error {
+	liveTempDirs.Lock()
+	delete(liveTempDirs.paths, dir)
+	liveTempDirs.Unlock()
+	return os.RemoveAll(dir)
+}
+
+// RemoveTempDirs deletes the directories MkdirTemp created in this process
+// that are still there, e.g. when a signal ends the process before the jobs
+// using them return. It returns the number of directories removed.
+func RemoveTempDirs() int {
+	liveTempDirs.Lock()
+	defer liveTempDirs.Unlock()
+	removed := 0
+	for dir := range liveTempDirs.paths {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("⚠️ [GitService] Failed to remove temporary directory %s: %v", dir, err)
+			continue
+		}
+		delete(liveTempDirs.paths, dir)
+		removed++
+	}
+	return removed
+}
+
+// RemoveStaleTempDirs deletes the temporary directories left behind by
+// debtdrone processes that were killed before they could clean up, such as
+// clones of an OOM-killed org scan. A directory is only removed once it is
+// older than maxAge, since the PID in its name may belong to a process of
+// another PID namespace sharing the temp directory, and even then it is kept
+// while a process with that PID is running on this host, so a concurrent job
+// never loses its checkout. It returns the number of directories removed.
+func RemoveStaleTempDirs(maxAge time.Duration) (int, error) {
+	return removeStaleTempDirs(os.TempDir(), maxAge, processAlive)
+}
+
+func removeStaleTempDirs(root string, maxAge time.Duration, alive func(pid int) bool) (int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, ok := tempDirOwner(entry.Name())
+		if !ok {
+			continue
+		}
+		if pid > 0 && (pid == os.Getpid() || alive(pid)) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+			log.Printf("⚠️ [GitService] Failed to remove stale temporary directory %s: %v", entry.Name(), err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// tempDirOwner reports whether name is a temporary directory of debtdrone
+// and returns the PID of the process that created it, or 0 when the name
+// does not say.
+func tempDirOwner(name string) (int, bool) {
+	for _, kind := range tempDirKinds {
+		rest, ok := strings.CutPrefix(name, "debtdrone-"+kind+"-")
+		if !ok {
+			continue
+		}
+		if pid, random, ok := strings.Cut(rest, "-"); ok && random != "" {
+			if n, err := strconv.Atoi(pid); err == nil && n > 0 {
+				return n, true
+			}
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMkdirTemp(t *testing.T) {
+	dir, err := MkdirTemp(TempDirRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	name := filepath.Base(dir)
+	if !strings.HasPrefix(name, "debtdrone-ref-"+strconv.Itoa(os.Getpid())+"-") {
+		t.Errorf("Expected the directory name to carry the PID, got %s", name)
+	}
+	if pid, ok := tempDirOwner(name); !ok || pid != os.Getpid() {
+		t.Errorf("Expected %s to be owned by this process, got %d (%v)", name, pid, ok)
+	}
+
+	if removed := RemoveTempDirs(); removed != 1 {
+		t.Errorf("Expected RemoveTempDirs to remove the directory, removed %d", removed)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be gone, got %v", dir, err)
+	}
+
+	dir, err = MkdirTemp(TempDirClone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveTempDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if removed := RemoveTempDirs(); removed != 0 {
+		t.Errorf("Expected a removed directory to be forgotten, removed %d", removed)
+	}
+}
+
+func TestRemoveStaleTempDirs(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	dirs := map[string]bool{
+		"debtdrone-repo-" + strconv.Itoa(os.Getpid()) + "-1": true,  // this process
+		"debtdrone-repo-4001-2":                              true,  // another running scan
+		"debtdrone-ref-4002-3":                               false, // a killed scan
+		"debtdrone-archive-4003-5":                           true,  // a recent one of another PID namespace
+		"debtdrone-repo-987654":                              false, // an old one of an older version
+		"debtdrone-ref-123456":                               true,  // a recent one of an older version
+		"debtdrone-cache-4002-4":                             true,  // not a kind of temporary directory
+		"other-repo":                                         true,
+	}
+	for name := range dirs {
+		if err := os.Mkdir(filepath.Join(root, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if name != "debtdrone-ref-123456" && name != "debtdrone-archive-4003-5" {
+			if err := os.Chtimes(filepath.Join(root, name), old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	alive := func(pid int) bool { return pid == 4001 }
+
+	removed, err := removeStaleTempDirs(root, DefaultStaleTempDirAge, alive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 stale directories to be removed, removed %d", removed)
+	}
+	for name, kept := range dirs {
+		_, err := os.Stat(filepath.Join(root, name))
+		if kept && err != nil {
+			t.Errorf("Expected %s to be kept, got %v", name, err)
+		}
+		if !kept && !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package github publishes scan results to GitHub through the Checks API, so
+// findings show up as inline annotations on pull requests.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+const (
+	// DefaultBaseURL is the REST API of github.com. GitHub Enterprise Server
+	// exposes it under https://HOST/api/v3.
+	DefaultBaseURL = "https://api.github.com"
+	// MaxAnnotationsPerRequest is the Checks API limit on annotations sent in
+	// one create or update request; larger sets are sent in batches.
+	MaxAnnotationsPerRequest = 50
+	// DefaultCheckName is the name the check run is listed under.
+	DefaultCheckName = "DebtDrone"
+)
+
+// Annotation marks a range of lines of a file in a check run.
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// AnnotationLevel is "notice", "warning" or "failure".
+	AnnotationLevel string `json:"annotation_level"`
+	Message         string `json:"message"`
+	Title           string `json:"title,omitempty"`
+}
+
+// CheckRun is a completed check run to publish for a commit.
+type CheckRun struct {
+	Name    string
+	HeadSHA string
+	// Conclusion is a Checks API conclusion such as "success" or "failure".
+	Conclusion  string
+	Title       string
+	Summary     string
+	Annotations []Annotation
+}
+
+// Client talks to the GitHub REST API with a token that can write checks
+// (a GitHub App token, or GITHUB_TOKEN with `checks: write` in Actions).
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the API at baseURL, or DefaultBaseURL when
+// baseURL is empty.
+func NewClient(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// CreateCheckRun creates a completed check run on repo ("owner/name") and
+// attaches its annotations, the first batch with the run and the rest with
+// one update per batch. It returns the ID of the check run, which is set even
+// when a later batch fails.
+func (c *Client) CreateCheckRun(ctx context.Context, repo string, run CheckRun) (int64, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return 0, fmt.Errorf("invalid repository %q: want owner/name", repo)
+	}
+	if run.Name == "" {
+		run.Name = DefaultCheckName
+	}
+
+	batches := batchAnnotations(run.Annotations)
+	create := map[string]interface{}{
+		"name":       run.Name,
+		"head_sha":   run.HeadSHA,
+		"status":     "completed",
+		"conclusion": run.Conclusion,
+		"output":     checkOutput(run, batches[0]),
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	endpoint := fmt.Sprintf("/repos/%s/%s/check-runs", owner, name)
+	if err := c.do(ctx, http.MethodPost, endpoint, create, &created); err != nil {
+		return 0, err
+	}
+
+	for i, batch := range batches[1:] {
+		update := map[string]interface{}{"output": checkOutput(run, batch)}
+		if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", endpoint, created.ID), update, nil); err != nil {
+			return created.ID, fmt.Errorf("annotation batch %d of %d: %w", i+2, len(batches), err)
+		}
+	}
+	return created.ID, nil
+}
+
+// checkOutput is the output object of a create or update request. Title and
+// summary are required on every request.
+func checkOutput(run CheckRun, annotations []Annotation) map[string]interface{} {
+	return map[string]interface{}{
+		"title":       run.Title,
+		"summary":     run.Summary,
+		"annotations": annotations,
+	}
+}
+
+// batchAnnotations splits annotations into batches the API accepts. There is
+// always at least one batch, possibly empty.
+func batchAnnotations(annotations []Annotation) [][]Annotation {
+	batches := [][]Annotation{{}}
+	for i := 0; i < len(annotations); i += MaxAnnotationsPerRequest {
+		end := min(i+MaxAnnotationsPerRequest, len(annotations))
+		if i == 0 {
+			batches[0] = annotations[:end]
+			continue
+		}
+		batches = append(batches, annotations[i:end])
+	}
+	return batches
+}
+
+// do sends a JSON request and decodes the response into out when it is not
+// nil. Non-2xx responses are returned as errors with GitHub's message.
+func (c *Client) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(raw, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(raw))
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, endpoint, resp.Status, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: failed to decode response: %w", method, endpoint, err)
+	}
+	return nil
+}
+
+// AnnotationsFromIssues turns scan issues into annotations. Paths are made
+// relative to the repository root, prefixed with the issue's scan root when
+// it is a relative path other than "."; issues without a line annotate the
+// first line of their file, and issues without a file are left out.
+func AnnotationsFromIssues(issues []models.TechnicalDebtIssue) []Annotation {
+	annotations := make([]Annotation, 0, len(issues))
+	for _, issue := range issues {
+		if issue.FilePath == "" {
+			continue
+		}
+		filePath := strings.TrimLeft(issue.FilePath, "/")
+		if root := strings.ReplaceAll(issue.Root, "\\", "/"); root != "" && !path.IsAbs(root) && !strings.Contains(root, ":") {
+			filePath = path.Join(root, filePath)
+		}
+
+		line := 1
+		if issue.LineNumber != nil && *issue.LineNumber > 0 {
+			line = *issue.LineNumber
+		}
+
+		title := issue.IssueType
+		if issue.ToolRuleID != nil && *issue.ToolRuleID != "" {
+			title = fmt.Sprintf("%s (%s)", issue.IssueType, *issue.ToolRuleID)
+		}
+		message := issue.Message
+		if issue.Description != nil && *issue.Description != "" {
+			message += "\n\n" + *issue.Description
+		}
+
+		annotations = append(annotations, Annotation{
+			Path:            path.Clean(filePath),
+			StartLine:       line,
+			EndLine:         line,
+			AnnotationLevel: annotationLevel(issue.Severity),
+			Message:         message,
+			Title:           fmt.Sprintf("[%s] %s", strings.ToUpper(issue.Severity), title),
+		})
+	}
+	return annotations
+}
+
+// annotationLevel maps a DebtDrone severity to a Checks API level.
+func annotationLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "failure"
+	case "medium":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
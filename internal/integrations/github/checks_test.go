@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	body   map[string]interface{}
+}
+
+func newChecksServer(t *testing.T, status int) (*httptest.Server, func() []recordedRequest) {
+	var mu sync.Mutex
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.Path, body: body})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		if status >= 300 {
+			fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":42}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestCreateCheckRun_BatchesAnnotations(t *testing.T) {
+	server, requests := newChecksServer(t, http.StatusCreated)
+
+	annotations := make([]Annotation, 120)
+	for i := range annotations {
+		annotations[i] = Annotation{Path: "main.go", StartLine: i + 1, EndLine: i + 1, AnnotationLevel: "warning", Message: "m"}
+	}
+	id, err := NewClient("secret", server.URL+"/").CreateCheckRun(context.Background(), "octo/app", CheckRun{
+		HeadSHA:     "abc123",
+		Conclusion:  "failure",
+		Title:       "3 issues",
+		Summary:     "summary",
+		Annotations: annotations,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	got := requests()
+	require.Len(t, got, 3)
+	assert.Equal(t, http.MethodPost, got[0].method)
+	assert.Equal(t, "/repos/octo/app/check-runs", got[0].path)
+	assert.Equal(t, "DebtDrone", got[0].body["name"])
+	assert.Equal(t, "abc123", got[0].body["head_sha"])
+	assert.Equal(t, "completed", got[0].body["status"])
+	assert.Equal(t, "failure", got[0].body["conclusion"])
+
+	sizes := []int{}
+	for i, req := range got {
+		if i > 0 {
+			assert.Equal(t, http.MethodPatch, req.method)
+			assert.Equal(t, "/repos/octo/app/check-runs/42", req.path)
+		}
+		output := req.body["output"].(map[string]interface{})
+		assert.Equal(t, "3 issues", output["title"])
+		sizes = append(sizes, len(output["annotations"].([]interface{})))
+	}
+	assert.Equal(t, []int{50, 50, 20}, sizes)
+}
+
+func TestCreateCheckRun_NoAnnotations(t *testing.T) {
+	server, requests := newChecksServer(t, http.StatusCreated)
+
+	_, err := NewClient("secret", server.URL).CreateCheckRun(context.Background(), "octo/app", CheckRun{HeadSHA: "abc123", Conclusion: "success"})
+	require.NoError(t, err)
+
+	got := requests()
+	require.Len(t, got, 1)
+	output := got[0].body["output"].(map[string]interface{})
+	assert.Empty(t, output["annotations"])
+}
+
+func TestCreateCheckRun_Errors(t *testing.T) {
+	server, _ := newChecksServer(t, http.StatusForbidden)
+	client := NewClient("secret", server.URL)
+
+	_, err := client.CreateCheckRun(context.Background(), "octo/app", CheckRun{HeadSHA: "abc123"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden: Resource not accessible by integration")
+
+	_, err = client.CreateCheckRun(context.Background(), "octo", CheckRun{HeadSHA: "abc123"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid repository "octo"`)
+}
+
+func TestAnnotationsFromIssues(t *testing.T) {
+	line := 12
+	rule := "CVE-2021-44228"
+	description := "Upgrade to 2.17.1."
+	issues := []models.TechnicalDebtIssue{
+		{FilePath: "/internal/app.go", LineNumber: &line, Severity: "high", IssueType: "complexity", Message: "too complex"},
+		{FilePath: "go.sum", Severity: "critical", IssueType: "security", ToolRuleID: &rule, Message: "vulnerable", Description: &description, Root: "svc-a"},
+		{FilePath: "/lib.py", LineNumber: &line, Severity: "low", IssueType: "lint", Message: "style", Root: "."},
+		{Severity: "medium", IssueType: "lint", Message: "no file"},
+	}
+
+	annotations := AnnotationsFromIssues(issues)
+	require.Len(t, annotations, 3)
+
+	assert.Equal(t, Annotation{Path: "internal/app.go", StartLine: 12, EndLine: 12, AnnotationLevel: "failure", Message: "too complex", Title: "[HIGH] complexity"}, annotations[0])
+	assert.Equal(t, "svc-a/go.sum", annotations[1].Path)
+	assert.Equal(t, 1, annotations[1].StartLine)
+	assert.Equal(t, "[CRITICAL] security (CVE-2021-44228)", annotations[1].Title)
+	assert.Equal(t, "vulnerable\n\nUpgrade to 2.17.1.", annotations[1].Message)
+	assert.Equal(t, "lib.py", annotations[2].Path)
+	assert.Equal(t, "notice", annotations[2].AnnotationLevel)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +41,14 @@ type ComplexityMetric struct {
 	RefactoringSuggestions []RefactoringSuggestion `json:"refactoring_suggestions,omitempty" db:"refactoring_suggestions"`
 	Language               string                  `json:"language" db:"language"`
 	Metadata               *string                 `json:"metadata,omitempty" db:"metadata"`
+	// NotebookCell is the 1-based cell of a Jupyter notebook the function
+	// was found in; StartLine and EndLine then count from the cell's start.
+	NotebookCell *int `json:"notebook_cell,omitempty" db:"-"`
+	// ReturnCount is the number of return statements in the function, of
+	// which GuardReturnCount are guard clauses opening its body. Both are
+	// stored in Metadata, see ReturnMetadata.
+	ReturnCount      int `json:"return_count,omitempty" db:"-"`
+	GuardReturnCount int `json:"guard_return_count,omitempty" db:"-"`
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -50,6 +59,81 @@ type ComplexityConfig struct {
 	CognitiveThreshold  int
 	CostPerPoint        int
 	AnalysisMode        string
+	// Minified decides which files are skipped as minified bundles; zero
+	// fields take the DefaultMinifiedThresholds values.
+	Minified MinifiedThresholds
+	// MaxFunctionsPerFile is the number of named functions a file may hold
+	// before it is reported as too large; zero takes
+	// DefaultMaxFunctionsPerFile.
+	MaxFunctionsPerFile int
+	// LanguageThresholds replaces the complexity thresholds of the languages
+	// it names, e.g. to let match-heavy Rust branch more than Go.
+	LanguageThresholds map[string]ComplexityThresholds
+	// MaxReturns is the weighted number of return points a function may
+	// have before a too_many_returns suggestion is made; zero takes
+	// DefaultMaxReturns.
+	MaxReturns int
+	// OutlierDetection reports the functions whose length or cyclomatic
+	// complexity lie far above the mean of the run as complexity_outlier
+	// issues.
+	OutlierDetection bool
+	// RefactoringDocs maps refactoring suggestion types to the pages their
+	// DocURL links; nil takes DefaultRefactoringDocs.
+	RefactoringDocs map[string]string
+	// IncludeGenerated analyzes generated files too. By default the files
+	// go-enry recognizes as generated and those matching
+	// DefaultGeneratedPatterns or GeneratedPatterns are skipped.
+	IncludeGenerated bool
+	// GeneratedPatterns are path patterns of further generated files, e.g.
+	// the output of an in-house code generator.
+	GeneratedPatterns []string
+}
+
+// DefaultGeneratedPatterns name the output of common code generators that
+// go-enry does not recognize by content. Nobody edits these files, so their
+// complexity issues cannot be fixed where they are reported.
+var DefaultGeneratedPatterns = []string{
+	"*.pb.go", "*_gen.go", "*.gen.go", "*_generated.go",
+	"*.g.dart", "*.freezed.dart",
+	"*_pb2.py", "*_pb2_grpc.py",
+	"*.generated.*",
+}
+
+// DefaultMaxFunctionsPerFile is the number of functions from which a file
+// should usually be split into several files or modules.
+const DefaultMaxFunctionsPerFile = 30
+
+// MinifiedThresholds tell minified or bundled output apart from source: a
+// file with one of Extensions that is at least MinBytes long and whose lines
+// average at least AvgLineLength bytes. Long-lined source of other
+// extensions, such as generated data tables, is never matched.
+type MinifiedThresholds struct {
+	AvgLineLength int
+	MinBytes      int
+	Extensions    []string
+}
+
+func DefaultMinifiedThresholds() MinifiedThresholds {
+	return MinifiedThresholds{
+		AvgLineLength: 250,
+		MinBytes:      2048,
+		Extensions:    []string{".js", ".mjs", ".cjs"},
+	}
+}
+
+// WithDefaults returns t with its zero fields set to the defaults.
+func (t MinifiedThresholds) WithDefaults() MinifiedThresholds {
+	defaults := DefaultMinifiedThresholds()
+	if t.AvgLineLength == 0 {
+		t.AvgLineLength = defaults.AvgLineLength
+	}
+	if t.MinBytes == 0 {
+		t.MinBytes = defaults.MinBytes
+	}
+	if len(t.Extensions) == 0 {
+		t.Extensions = defaults.Extensions
+	}
+	return t
 }
 
 func DefaultComplexityConfig() ComplexityConfig {
@@ -67,6 +151,8 @@ type RefactoringSuggestion struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Reason      string `json:"reason"`
+	// DocURL links a page explaining the refactoring; see RefactoringDocs.
+	DocURL string `json:"doc_url,omitempty"`
 }
 
 type FileComplexitySummary struct {
@@ -109,6 +195,32 @@ type RepositoryComplexitySummary struct {
 	LowIssues                int       `json:"low_issues" db:"low_issues"`
 }
 
+// LanguageComplexityBreakdown aggregates the complexity metrics of one
+// analysis run for a single language.
+type LanguageComplexityBreakdown struct {
+	Language                  string  `json:"language" db:"language"`
+	FunctionCount             int     `json:"function_count" db:"function_count"`
+	AvgCyclomaticComplexity   float64 `json:"avg_cyclomatic_complexity" db:"avg_cyclomatic_complexity"`
+	MaxCyclomaticComplexity   int     `json:"max_cyclomatic_complexity" db:"max_cyclomatic_complexity"`
+	TotalLinesOfCode          int     `json:"total_lines_of_code" db:"total_lines_of_code"`
+	TotalTechnicalDebtMinutes int     `json:"total_technical_debt_minutes" db:"total_technical_debt_minutes"`
+}
+
+// SuggestionHit is one refactoring suggestion of a given type made for a
+// function, as listed by a repository's refactoring backlog.
+type SuggestionHit struct {
+	MetricID             uuid.UUID `json:"metric_id" db:"metric_id"`
+	AnalysisRunID        uuid.UUID `json:"analysis_run_id" db:"analysis_run_id"`
+	FilePath             string    `json:"file_path" db:"file_path"`
+	FunctionName         string    `json:"function_name" db:"function_name"`
+	StartLine            int       `json:"start_line" db:"start_line"`
+	CyclomaticComplexity int       `json:"cyclomatic_complexity" db:"cyclomatic_complexity"`
+	Type                 string    `json:"type" db:"type"`
+	Priority             string    `json:"priority" db:"priority"`
+	Title                string    `json:"title" db:"title"`
+	Reason               string    `json:"reason" db:"reason"`
+}
+
 type ComplexityThresholds struct {
 	CyclomaticHigh      int `json:"cyclomatic_high"`
 	CyclomaticCritical  int `json:"cyclomatic_critical"`
@@ -263,6 +375,120 @@ func GenerateRefactoringSuggestions(cyclomatic, cognitive, nesting, params, loc
 	return suggestions
 }
 
+// MaxReturnValues is the number of return values a function may declare before
+// a split_return suggestion is made.
+const MaxReturnValues = 3
+
+// GenerateSignatureSuggestions complements GenerateRefactoringSuggestions with
+// smells read from a function's signature: boolean flag parameters, which
+// usually mean the function does two things, and long return tuples. They are
+// informational unless the function is also complex.
+func GenerateSignatureSuggestions(flagParams []string, returnValues, cyclomatic int) []RefactoringSuggestion {
+	suggestions := []RefactoringSuggestion{}
+
+	priority := "info"
+	if cyclomatic > 10 {
+		priority = "medium"
+	}
+
+	if len(flagParams) > 0 {
+		suggestions = append(suggestions, RefactoringSuggestion{
+			Type:        "replace_flag_argument",
+			Priority:    priority,
+			Title:       "Replace Flag Argument",
+			Description: "Split the function into one function per behavior, or pass an explicit option type instead of a boolean",
+			Reason:      formatString("Boolean parameter(s) %s select between behaviors inside one function", strings.Join(flagParams, ", ")),
+		})
+	}
+
+	if returnValues > MaxReturnValues {
+		suggestions = append(suggestions, RefactoringSuggestion{
+			Type:        "split_return",
+			Priority:    priority,
+			Title:       "Split Return Values",
+			Description: "Return a named result struct or object, or split the function so each part returns less",
+			Reason:      formatString("Function returns %d values, exceeding the recommended maximum of %d", returnValues, MaxReturnValues),
+		})
+	}
+
+	return suggestions
+}
+
+// DefaultMaxReturns is the weighted number of return points from which a
+// function's exits are usually hard to follow.
+const DefaultMaxReturns = 5
+
+// guardReturnWeight is what a guard clause counts towards the return points
+// of a function. Returns that reject bad input at the top keep the rest of
+// the body flat, so they cost a reader much less than an exit hidden in the
+// middle of the logic.
+const guardReturnWeight = 0.5
+
+// WeightedReturns scores the return points of a function that has returns
+// return statements, guards of them guard clauses.
+func WeightedReturns(returns, guards int) float64 {
+	return float64(returns-guards) + guardReturnWeight*float64(guards)
+}
+
+// GenerateReturnSuggestions returns a too_many_returns suggestion when the
+// weighted return points of a function exceed maxReturns, of high priority
+// from twice that many.
+func GenerateReturnSuggestions(returns, guards, maxReturns int) []RefactoringSuggestion {
+	suggestions := []RefactoringSuggestion{}
+
+	weighted := WeightedReturns(returns, guards)
+	if weighted > float64(maxReturns) {
+		priority := "medium"
+		if weighted > float64(2*maxReturns) {
+			priority = "high"
+		}
+		suggestions = append(suggestions, RefactoringSuggestion{
+			Type:        "too_many_returns",
+			Priority:    priority,
+			Title:       "Reduce Return Points",
+			Description: "Move the checks that exit early to the top of the function, or extract the branches that return into separate functions",
+			Reason:      formatString("Function has %d return statements (%d guard clauses), a weighted %.1f exceeding the threshold of %d", returns, guards, weighted, maxReturns),
+		})
+	}
+
+	return suggestions
+}
+
+// ReturnMetadata encodes the return points of the metric as the JSON object
+// stored in Metadata, so they can be queried without a column of their own.
+// It returns nil for a function without return statements.
+func (m ComplexityMetric) ReturnMetadata() *string {
+	if m.ReturnCount == 0 {
+		return nil
+	}
+	metadata := formatString(`{"return_count":%d,"guard_return_count":%d,"weighted_return_count":%g}`,
+		m.ReturnCount, m.GuardReturnCount, WeightedReturns(m.ReturnCount, m.GuardReturnCount))
+	return &metadata
+}
+
+// GenerateFileSuggestions returns the refactoring suggestions for a file as a
+// whole: a split_file suggestion when it holds more than maxFunctions
+// functions, of high priority from twice that many.
+func GenerateFileSuggestions(summary FileComplexitySummary, maxFunctions int) []RefactoringSuggestion {
+	suggestions := []RefactoringSuggestion{}
+
+	if summary.FunctionCount > maxFunctions {
+		priority := "medium"
+		if summary.FunctionCount > 2*maxFunctions {
+			priority = "high"
+		}
+		suggestions = append(suggestions, RefactoringSuggestion{
+			Type:        "split_file",
+			Priority:    priority,
+			Title:       "Split File",
+			Description: "Move groups of related functions into separate files or modules with a single responsibility each",
+			Reason:      formatString("File defines %d functions, exceeding the threshold of %d", summary.FunctionCount, maxFunctions),
+		})
+	}
+
+	return suggestions
+}
+
 func formatString(format string, args ...interface{}) string {
 	return fmt.Sprintf(format, args...)
 }
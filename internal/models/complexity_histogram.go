@@ -0,0 +1,67 @@
+package models
+
+import "strconv"
+
+// ComplexityBucket counts the functions whose cyclomatic complexity lies
+// between Min and Max, both included. The last bucket has no upper bound and
+// a Max of 0.
+type ComplexityBucket struct {
+	Range     string `json:"range"`
+	Min       int    `json:"min"`
+	Max       int    `json:"max,omitempty"`
+	Functions int    `json:"functions"`
+}
+
+// ComplexityHistogram is the distribution of the cyclomatic complexity of a
+// run's functions over fixed ranges, from the simplest to the most complex.
+type ComplexityHistogram []ComplexityBucket
+
+// complexityBucketBounds are the lower bounds of the histogram buckets.
+var complexityBucketBounds = []int{1, 6, 11, 21}
+
+// NewComplexityHistogram buckets metrics by cyclomatic complexity. Every
+// bucket is present, so no functions give a histogram of zeros.
+func NewComplexityHistogram(metrics []ComplexityMetric) ComplexityHistogram {
+	histogram := make(ComplexityHistogram, len(complexityBucketBounds))
+	for i, lower := range complexityBucketBounds {
+		histogram[i] = ComplexityBucket{Min: lower, Range: strconv.Itoa(lower) + "+"}
+		if i+1 < len(complexityBucketBounds) {
+			histogram[i].Max = complexityBucketBounds[i+1] - 1
+			histogram[i].Range = strconv.Itoa(lower) + "-" + strconv.Itoa(histogram[i].Max)
+		}
+	}
+	for _, metric := range metrics {
+		i := len(histogram) - 1
+		for i > 0 && metric.CyclomaticComplexity < histogram[i].Min {
+			i--
+		}
+		histogram[i].Functions++
+	}
+	return histogram
+}
+
+// Add returns the histogram counting the functions of both h and other,
+// e.g. to combine the histograms of several scanned roots. A nil h counts
+// no functions.
+func (h ComplexityHistogram) Add(other ComplexityHistogram) ComplexityHistogram {
+	if h == nil {
+		h = NewComplexityHistogram(nil)
+	}
+	sum := make(ComplexityHistogram, len(h))
+	copy(sum, h)
+	for i := range sum {
+		if i < len(other) {
+			sum[i].Functions += other[i].Functions
+		}
+	}
+	return sum
+}
+
+// Functions is the number of functions the histogram counts.
+func (h ComplexityHistogram) Functions() int {
+	total := 0
+	for _, bucket := range h {
+		total += bucket.Functions
+	}
+	return total
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestNewComplexityHistogram(t *testing.T) {
+	var metrics []ComplexityMetric
+	for _, complexity := range []int{1, 5, 6, 10, 11, 20, 21, 48, 3} {
+		metrics = append(metrics, ComplexityMetric{CyclomaticComplexity: complexity})
+	}
+	histogram := NewComplexityHistogram(metrics)
+
+	want := []ComplexityBucket{
+		{Range: "1-5", Min: 1, Max: 5, Functions: 3},
+		{Range: "6-10", Min: 6, Max: 10, Functions: 2},
+		{Range: "11-20", Min: 11, Max: 20, Functions: 2},
+		{Range: "21+", Min: 21, Functions: 2},
+	}
+	if len(histogram) != len(want) {
+		t.Fatalf("Expected %d buckets, got %+v", len(want), histogram)
+	}
+	for i := range want {
+		if histogram[i] != want[i] {
+			t.Errorf("Bucket %d: expected %+v, got %+v", i, want[i], histogram[i])
+		}
+	}
+	if histogram.Functions() != len(metrics) {
+		t.Errorf("Expected %d functions, got %d", len(metrics), histogram.Functions())
+	}
+
+	empty := NewComplexityHistogram(nil)
+	if len(empty) != len(want) || empty.Functions() != 0 {
+		t.Errorf("Expected every bucket to be present without functions, got %+v", empty)
+	}
+
+	var merged ComplexityHistogram
+	merged = merged.Add(histogram).Add(NewComplexityHistogram(metrics[:1]))
+	if merged[0].Functions != 4 || merged.Functions() != len(metrics)+1 {
+		t.Errorf("Expected the counts of both histograms, got %+v", merged)
+	}
+	if histogram[0].Functions != 3 {
+		t.Errorf("Expected Add to leave its operands alone, got %+v", histogram)
+	}
+}
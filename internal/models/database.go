@@ -87,6 +87,19 @@ type UserConfiguration struct {
 	UpdatedAt                     time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// IsTokenExpired reports whether the access token has passed its expiry.
+// Tokens without a recorded expiry, such as personal access tokens, never
+// expire.
+func (c *UserConfiguration) IsTokenExpired() bool {
+	return c.TokenExpiresAt != nil && !time.Now().Before(*c.TokenExpiresAt)
+}
+
+// AnalysisProfile returns the profile AnalysisDepth selects; an empty depth
+// is DefaultAnalysisProfile.
+func (c *UserConfiguration) AnalysisProfile() (AnalysisProfile, error) {
+	return ParseAnalysisProfile(c.AnalysisDepth)
+}
+
 type UserRepository struct {
 	ID                            uuid.UUID  `json:"id" db:"id"`
 	UserID                        uuid.UUID  `json:"user_id" db:"user_id"`
@@ -152,6 +165,9 @@ type AnalysisRun struct {
 	Delta                   map[string]interface{} `json:"delta,omitempty" db:"-"`
 	CreatedAt               time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt               time.Time              `json:"updated_at" db:"updated_at"`
+	// DebtSpike reports whether the run's debt is abnormally high versus the
+	// repository's recent snapshots; see DetectDebtSpike.
+	DebtSpike bool `json:"debt_spike" db:"-"`
 }
 
 type TechnicalDebtIssue struct {
@@ -181,6 +197,9 @@ type TechnicalDebtIssue struct {
 	Comments           []string   `json:"comments" db:"comments"`
 	CodeSnippet        *string    `json:"code_snippet" db:"code_snippet"`
 	SurroundingContext *string    `json:"surrounding_context" db:"surrounding_context"`
+	// Metadata carries structured, tool-specific data (e.g. Trivy's fixed_version,
+	// complexity's raw metrics) stored as JSONB. Description stays human-readable.
+	Metadata map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
 	// External integration link tracking (Link & Sync)
 	ExternalID       *string `json:"external_id" db:"external_id"`             // e.g., "PROJ-123" or Trello card ID
 	ExternalPlatform *string `json:"external_platform" db:"external_platform"` // "jira" or "trello"
@@ -193,6 +212,8 @@ type TechnicalDebtIssue struct {
 	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
 	RepositoryName     string    `json:"repository_name,omitempty" db:"-"`
 	RepositoryFullName string    `json:"repository_full_name,omitempty" db:"-"`
+	Root               string    `json:"root,omitempty" db:"-"` // Scan root the issue was found under (CLI multi-path scans)
+	URL                string    `json:"url,omitempty" db:"-"`  // Permalink to the issue's line at the scanned commit (CLI scans of hosted repositories)
 }
 
 type IssueComment struct {
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserConfiguration_IsTokenExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{"no expiry recorded", nil, false},
+		{"expired", &past, true},
+		{"still valid", &future, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &UserConfiguration{TokenExpiresAt: tt.expiresAt}
+			if got := c.IsTokenExpired(); got != tt.want {
+				t.Errorf("IsTokenExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserConfiguration_AnalysisProfile(t *testing.T) {
+	tests := []struct {
+		depth   string
+		want    AnalysisProfile
+		wantErr bool
+	}{
+		{"", ProfileStandard, false},
+		{"quick", ProfileQuick, false},
+		{"Deep", ProfileDeep, false},
+		{"standard", ProfileStandard, false},
+		{"thorough", "", true},
+	}
+	for _, tt := range tests {
+		c := &UserConfiguration{AnalysisDepth: tt.depth}
+		got, err := c.AnalysisProfile()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("AnalysisProfile(%q) error = %v, wantErr %v", tt.depth, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("AnalysisProfile(%q) = %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+}
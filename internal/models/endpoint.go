@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net"
+	"strings"
+)
+
+// EndpointPolicy configures the hardcoded endpoint analyzer.
+type EndpointPolicy struct {
+	// AllowedHosts lists the hosts that may be hardcoded. An entry matches
+	// that host and its subdomains; "*.corp.example" matches the subdomains
+	// only. An IP address or CIDR range (e.g. "10.0.0.0/8") matches the
+	// addresses it covers.
+	AllowedHosts []string
+	// IncludeTests also checks test files and testdata directories.
+	IncludeTests bool
+	// IncludeComments also checks comments. Documentation comments are never
+	// checked.
+	IncludeComments bool
+}
+
+// Allows reports whether host, a hostname or IP address, is on the
+// allowlist. Hostnames are matched case-insensitively.
+func (p EndpointPolicy) Allows(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, entry := range p.AllowedHosts {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if ip != nil {
+			if _, network, err := net.ParseCIDR(entry); err == nil {
+				if network.Contains(ip) {
+					return true
+				}
+				continue
+			}
+			if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(entry, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
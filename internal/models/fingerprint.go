@@ -0,0 +1,61 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// volatileNumberPattern matches the numbers of issue messages, with the
+// character before them. Analyzers embed line numbers and metric values
+// ("complexity 23", "line 118", "2.5h") in their messages; these drift
+// between scans without the underlying issue changing. Digits that continue
+// an identifier, as in "handler2" or "parse_v2", are part of a name and are
+// not matched.
+var volatileNumberPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}_])[0-9]+(\.[0-9]+)?`)
+
+// Fingerprint returns the canonical, stable identity of an issue as a hex
+// encoded SHA-256 digest. It is the single definition used to populate
+// FingerprintHash, so baselines, reconciliation and report formats agree.
+//
+// Included: RepositoryID, the normalized FilePath (forward slashes, no leading
+// "./" or "/"), IssueType, ToolRuleID and the Message with every number that
+// is not part of an identifier replaced by "#".
+//
+// Excluded: LineNumber, ColumnNumber, Severity, debt hours, snippets, IDs and
+// timestamps. Moving an issue within a file or a metric value changing
+// therefore does not change its fingerprint.
+func (i *TechnicalDebtIssue) Fingerprint() string {
+	ruleID := ""
+	if i.ToolRuleID != nil {
+		ruleID = *i.ToolRuleID
+	}
+
+	parts := []string{
+		i.RepositoryID.String(),
+		normalizeFingerprintPath(i.FilePath),
+		i.IssueType,
+		ruleID,
+		normalizeFingerprintMessage(i.Message),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeFingerprintPath(p string) string {
+	p = strings.ReplaceAll(strings.TrimSpace(p), "\\", "/")
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(p)
+	p = strings.TrimPrefix(p, "./")
+	return strings.TrimLeft(p, "/")
+}
+
+func normalizeFingerprintMessage(msg string) string {
+	msg = volatileNumberPattern.ReplaceAllString(msg, "${1}#")
+	return strings.Join(strings.Fields(msg), " ")
+}
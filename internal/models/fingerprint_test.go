@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newFingerprintIssue(line int) TechnicalDebtIssue {
+	rule := "cyclomatic_complexity"
+	return TechnicalDebtIssue{
+		ID:                 uuid.New(),
+		AnalysisRunID:      uuid.New(),
+		RepositoryID:       uuid.MustParse("5b0e2f3c-1d2a-4c6b-9a8e-7f1d2c3b4a59"),
+		FilePath:           "/internal/api/handler.go",
+		LineNumber:         &line,
+		IssueType:          "complexity",
+		Severity:           "high",
+		Message:            "Function 'ProcessRequest' has cyclomatic complexity of 23 (line 112)",
+		ToolRuleID:         &rule,
+		TechnicalDebtHours: 1.5,
+	}
+}
+
+func TestFingerprint_StableAcrossScans(t *testing.T) {
+	first := newFingerprintIssue(112)
+	second := newFingerprintIssue(140)
+	second.Message = "Function 'ProcessRequest' has cyclomatic complexity of 25 (line 140)"
+	second.Severity = "critical"
+	second.FilePath = "internal/api/handler.go"
+
+	if first.Fingerprint() != second.Fingerprint() {
+		t.Errorf("expected identical fingerprints for the same issue across scans, got %s and %s",
+			first.Fingerprint(), second.Fingerprint())
+	}
+	if len(first.Fingerprint()) != 64 {
+		t.Errorf("expected a hex SHA-256 digest, got %q", first.Fingerprint())
+	}
+}
+
+func TestFingerprint_DistinguishesIssues(t *testing.T) {
+	base := newFingerprintIssue(112)
+
+	tests := []struct {
+		name   string
+		mutate func(*TechnicalDebtIssue)
+	}{
+		{"different repository", func(i *TechnicalDebtIssue) { i.RepositoryID = uuid.New() }},
+		{"different file", func(i *TechnicalDebtIssue) { i.FilePath = "/internal/api/router.go" }},
+		{"different issue type", func(i *TechnicalDebtIssue) { i.IssueType = "security" }},
+		{"different rule", func(i *TechnicalDebtIssue) { r := "nesting_depth"; i.ToolRuleID = &r }},
+		{"nil rule", func(i *TechnicalDebtIssue) { i.ToolRuleID = nil }},
+		{"different function", func(i *TechnicalDebtIssue) { i.Message = "Function 'Route' has cyclomatic complexity of 23" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := newFingerprintIssue(112)
+			tt.mutate(&other)
+			if base.Fingerprint() == other.Fingerprint() {
+				t.Errorf("expected fingerprints to differ for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestFingerprint_DigitsInIdentifiers(t *testing.T) {
+	first := newFingerprintIssue(10)
+	first.Message = "Function 'handler1' has cyclomatic complexity of 23 (line 10)"
+	second := newFingerprintIssue(30)
+	second.Message = "Function 'handler2' has cyclomatic complexity of 23 (line 30)"
+	if first.Fingerprint() == second.Fingerprint() {
+		t.Errorf("expected functions whose names differ in a digit to get distinct fingerprints, both got %s", first.Fingerprint())
+	}
+
+	drifted := newFingerprintIssue(42)
+	drifted.Message = "Function 'handler1' has cyclomatic complexity of 31 (line 42)"
+	if first.Fingerprint() != drifted.Fingerprint() {
+		t.Errorf("expected the metric value and line of handler1 to be ignored, got %s and %s", first.Fingerprint(), drifted.Fingerprint())
+	}
+}
+
+func TestNormalizeFingerprintMessage(t *testing.T) {
+	tests := map[string]string{
+		"Function 'parse_v2' has 12 parameters":         "Function 'parse_v2' has # parameters",
+		"Estimated debt 2.5h (line 118)":                "Estimated debt #h (line #)",
+		"File has 45 functions; md5sum and sha256 used": "File has # functions; md5sum and sha256 used",
+		"23 returns in x2y":                             "# returns in x2y",
+	}
+	for msg, want := range tests {
+		if got := normalizeFingerprintMessage(msg); got != want {
+			t.Errorf("normalizeFingerprintMessage(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestFingerprint_PathNormalization(t *testing.T) {
+	paths := []string{"internal/api/handler.go", "/internal/api/handler.go", "./internal/api/handler.go", "internal\\api\\handler.go"}
+
+	want := ""
+	for _, p := range paths {
+		issue := newFingerprintIssue(1)
+		issue.FilePath = p
+		if want == "" {
+			want = issue.Fingerprint()
+			continue
+		}
+		if got := issue.Fingerprint(); got != want {
+			t.Errorf("path %q produced fingerprint %s, want %s", p, got, want)
+		}
+	}
+}
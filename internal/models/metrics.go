@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -51,6 +52,82 @@ type MetricsTrend struct {
 	Direction     string  `json:"direction"`
 }
 
+// NewMetricsTrend compares current against previous. A zero previous value
+// yields a zero ChangePercent rather than an infinite one.
+func NewMetricsTrend(current, previous float64) MetricsTrend {
+	trend := MetricsTrend{
+		CurrentValue:  current,
+		PreviousValue: previous,
+		Change:        current - previous,
+		Direction:     "stable",
+	}
+	if previous != 0 {
+		trend.ChangePercent = trend.Change / previous * 100
+	}
+	switch {
+	case trend.Change > 0:
+		trend.Direction = "up"
+	case trend.Change < 0:
+		trend.Direction = "down"
+	}
+	return trend
+}
+
+// DefaultDebtSpikeSigma is the number of standard deviations above the mean
+// of recent history at which a run's debt counts as a spike.
+const DefaultDebtSpikeSigma = 2.0
+
+// minDebtSpikeHistory is the fewest prior snapshots DetectDebtSpike needs;
+// with less history a standard deviation means little and nothing is
+// flagged.
+const minDebtSpikeHistory = 3
+
+// minDebtSpikeHours is the least increase over the mean that counts as a
+// spike, however steady the history is.
+const minDebtSpikeHours = 1.0
+
+// DebtSpike is the outcome of comparing a run's debt with recent history.
+type DebtSpike struct {
+	Spike     bool    `json:"debt_spike"`
+	Mean      float64 `json:"mean"`
+	StdDev    float64 `json:"std_dev"`
+	Threshold float64 `json:"threshold"`
+}
+
+// DetectDebtSpike flags latest when its TechnicalDebtHours exceeds the mean of
+// the prior snapshots by more than sigma population standard deviations, and
+// by more than minDebtSpikeHours, so a flat history does not turn every small
+// increase into a spike. A sigma of zero or less means DefaultDebtSpikeSigma.
+// Fewer than three prior snapshots are never flagged and leave the statistics
+// zero.
+func DetectDebtSpike(latest RepositoryMetricsSnapshot, prior []RepositoryMetricsSnapshot, sigma float64) DebtSpike {
+	if len(prior) < minDebtSpikeHistory {
+		return DebtSpike{}
+	}
+	if sigma <= 0 {
+		sigma = DefaultDebtSpikeSigma
+	}
+
+	var sum float64
+	for _, snap := range prior {
+		sum += snap.TechnicalDebtHours
+	}
+	mean := sum / float64(len(prior))
+	var squares float64
+	for _, snap := range prior {
+		squares += (snap.TechnicalDebtHours - mean) * (snap.TechnicalDebtHours - mean)
+	}
+	stdDev := math.Sqrt(squares / float64(len(prior)))
+
+	threshold := mean + max(sigma*stdDev, minDebtSpikeHours)
+	return DebtSpike{
+		Spike:     latest.TechnicalDebtHours > threshold,
+		Mean:      mean,
+		StdDev:    stdDev,
+		Threshold: threshold,
+	}
+}
+
 type DashboardStats struct {
 	TotalRepositories       int          `json:"total_repositories"`
 	ActiveRepositories      int          `json:"active_repositories"`
@@ -65,6 +142,10 @@ type DashboardStats struct {
 	IssuesTrend             MetricsTrend `json:"issues_trend"`
 	CoverageTrend           MetricsTrend `json:"coverage_trend"`
 	ComplexityTrend         MetricsTrend `json:"complexity_trend"`
+	// DebtTrend compares TotalTechnicalDebtHours with the snapshot total from a
+	// month earlier, as MetricsStore.GetDebtTrend computes both. Cached stats
+	// written before this field existed decode it as the zero trend.
+	DebtTrend MetricsTrend `json:"debt_trend"`
 }
 
 type ActiveUsersSummary struct {
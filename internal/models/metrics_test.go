@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewMetricsTrend(t *testing.T) {
+	tests := []struct {
+		name              string
+		current, previous float64
+		wantChange        float64
+		wantPercent       float64
+		wantDirection     string
+	}{
+		{"debt grew", 150, 100, 50, 50, "up"},
+		{"debt shrank", 75, 100, -25, -25, "down"},
+		{"unchanged", 100, 100, 0, 0, "stable"},
+		{"no previous value", 40, 0, 40, 0, "up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := NewMetricsTrend(tt.current, tt.previous)
+			if trend.Change != tt.wantChange || trend.ChangePercent != tt.wantPercent || trend.Direction != tt.wantDirection {
+				t.Errorf("NewMetricsTrend(%v, %v) = %+v", tt.current, tt.previous, trend)
+			}
+		})
+	}
+}
+
+func TestDashboardStats_LegacyCacheBlob(t *testing.T) {
+	legacy := []byte(`{"total_repositories": 3, "total_issues": 12, "issues_trend": {"current_value": 12}}`)
+
+	var stats DashboardStats
+	if err := json.Unmarshal(legacy, &stats); err != nil {
+		t.Fatalf("legacy dashboard_stats blob failed to decode: %v", err)
+	}
+	if stats.TotalTechnicalDebtHours != 0 || stats.DebtTrend != (MetricsTrend{}) {
+		t.Errorf("expected missing debt fields to decode as zero, got %+v / %+v", stats.TotalTechnicalDebtHours, stats.DebtTrend)
+	}
+	if stats.TotalRepositories != 3 {
+		t.Errorf("expected existing fields to decode, got %+v", stats)
+	}
+}
+
+func TestDetectDebtSpike(t *testing.T) {
+	history := func(hours ...float64) []RepositoryMetricsSnapshot {
+		snapshots := make([]RepositoryMetricsSnapshot, len(hours))
+		for i, h := range hours {
+			snapshots[i].TechnicalDebtHours = h
+		}
+		return snapshots
+	}
+	latest := func(hours float64) RepositoryMetricsSnapshot {
+		return RepositoryMetricsSnapshot{TechnicalDebtHours: hours}
+	}
+
+	tests := []struct {
+		name   string
+		latest float64
+		prior  []RepositoryMetricsSnapshot
+		sigma  float64
+		want   bool
+	}{
+		// Mean 100, standard deviation 10: the default threshold is 120.
+		{"above default threshold", 121, history(90, 110, 90, 110), 0, true},
+		{"at default threshold", 120, history(90, 110, 90, 110), 0, false},
+		{"within custom sigma", 121, history(90, 110, 90, 110), 3, false},
+		{"above custom sigma", 105, history(90, 110, 90, 110), 0.4, true},
+		{"drop is not a spike", 10, history(90, 110, 90, 110), 0, false},
+		{"too little history", 1000, history(100, 100), 0, false},
+		{"small rise over flat history", 100.5, history(100, 100, 100), 0, false},
+		{"rise over flat history", 102, history(100, 100, 100), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spike := DetectDebtSpike(latest(tt.latest), tt.prior, tt.sigma)
+			if spike.Spike != tt.want {
+				t.Errorf("DetectDebtSpike(%v) = %+v, want spike=%v", tt.latest, spike, tt.want)
+			}
+		})
+	}
+
+	spike := DetectDebtSpike(latest(121), history(90, 110, 90, 110), 0)
+	if spike.Mean != 100 || spike.StdDev != 10 || spike.Threshold != 120 {
+		t.Errorf("Expected mean 100, std dev 10 and threshold 120, got %+v", spike)
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnalysisProfile is a preset of the analyzers a scan runs, trading depth
+// for speed. It is the value of UserConfiguration.AnalysisDepth, the
+// analysis_depth key of .debtdrone.yaml and the --profile flag.
+type AnalysisProfile string
+
+const (
+	// ProfileQuick runs the complexity analysis alone, over the files
+	// changed since the last commit, for a fast local check.
+	ProfileQuick AnalysisProfile = "quick"
+	// ProfileStandard runs the source analyzers and the security scan.
+	ProfileStandard AnalysisProfile = "standard"
+	// ProfileDeep runs every analyzer, including the dependency and dead
+	// code analyses.
+	ProfileDeep AnalysisProfile = "deep"
+)
+
+// DefaultAnalysisProfile is the profile of a scan that names none.
+const DefaultAnalysisProfile = ProfileStandard
+
+// ParseAnalysisProfile parses a profile name, case-insensitively. An empty
+// name is DefaultAnalysisProfile.
+func ParseAnalysisProfile(name string) (AnalysisProfile, error) {
+	switch profile := AnalysisProfile(strings.ToLower(strings.TrimSpace(name))); profile {
+	case "":
+		return DefaultAnalysisProfile, nil
+	case ProfileQuick, ProfileStandard, ProfileDeep:
+		return profile, nil
+	}
+	return "", fmt.Errorf("unknown analysis profile %q (valid: quick, standard, deep)", name)
+}
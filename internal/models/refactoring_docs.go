@@ -0,0 +1,41 @@
+package models
+
+// DefaultRefactoringDocs maps the refactoring suggestion types to a page
+// explaining the refactoring, which becomes their DocURL. Types without an
+// entry get no link.
+var DefaultRefactoringDocs = map[string]string{
+	"extract_method":             "https://refactoring.guru/extract-method",
+	"reduce_nesting":             "https://refactoring.guru/replace-nested-conditional-with-guard-clauses",
+	"simplify_logic":             "https://refactoring.guru/refactoring/techniques/simplifying-conditional-expressions",
+	"introduce_parameter_object": "https://refactoring.guru/introduce-parameter-object",
+	"split_function":             "https://refactoring.guru/smells/long-method",
+	"replace_flag_argument":      "https://martinfowler.com/bliki/FlagArgument.html",
+	"too_many_returns":           "https://refactoring.guru/replace-nested-conditional-with-guard-clauses",
+	"split_file":                 "https://refactoring.guru/extract-class",
+}
+
+// RefactoringDocs returns DefaultRefactoringDocs with overrides applied, so
+// an organization can link its own guidelines. An override with an empty URL
+// removes the link of its type.
+func RefactoringDocs(overrides map[string]string) map[string]string {
+	docs := make(map[string]string, len(DefaultRefactoringDocs)+len(overrides))
+	for suggestionType, url := range DefaultRefactoringDocs {
+		docs[suggestionType] = url
+	}
+	for suggestionType, url := range overrides {
+		if url == "" {
+			delete(docs, suggestionType)
+			continue
+		}
+		docs[suggestionType] = url
+	}
+	return docs
+}
+
+// AttachDocURLs sets the DocURL of each suggestion to the page docs maps its
+// Type to, leaving it empty for types docs does not know.
+func AttachDocURLs(suggestions []RefactoringSuggestion, docs map[string]string) {
+	for i := range suggestions {
+		suggestions[i].DocURL = docs[suggestions[i].Type]
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRefactoringDocs(t *testing.T) {
+	docs := RefactoringDocs(map[string]string{
+		"extract_method": "https://wiki.example.com/extract-method",
+		"reduce_nesting": "",
+		"custom_type":    "https://wiki.example.com/custom",
+	})
+	if docs["extract_method"] != "https://wiki.example.com/extract-method" || docs["custom_type"] == "" {
+		t.Errorf("Expected the overrides to apply, got %v", docs)
+	}
+	if _, ok := docs["reduce_nesting"]; ok {
+		t.Errorf("Expected an empty URL to remove the link, got %q", docs["reduce_nesting"])
+	}
+	if docs["split_file"] != DefaultRefactoringDocs["split_file"] {
+		t.Errorf("Expected the types without an override to keep their default, got %q", docs["split_file"])
+	}
+	if DefaultRefactoringDocs["extract_method"] == docs["extract_method"] {
+		t.Error("Expected the defaults to be left alone")
+	}
+
+	suggestions := []RefactoringSuggestion{{Type: "extract_method"}, {Type: "split_return"}}
+	AttachDocURLs(suggestions, docs)
+	if suggestions[0].DocURL != "https://wiki.example.com/extract-method" {
+		t.Errorf("Expected extract_method to be linked, got %q", suggestions[0].DocURL)
+	}
+	data, err := json.Marshal(suggestions[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "doc_url") {
+		t.Errorf("Expected an unmapped suggestion to omit doc_url, got %s", data)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import "strings"
+
+// SecurityDebtCosts is the cost model of security findings: the debt hours a
+// vulnerability adds by severity, and a hardcoded secret by its category.
+type SecurityDebtCosts struct {
+	// SeverityHours maps critical, high, medium, low and info to hours.
+	SeverityHours map[string]float64
+	// SecretHours is the cost of a secret whose category is not listed in
+	// SecretCategoryHours.
+	SecretHours float64
+	// SecretCategoryHours maps a Trivy secret category (e.g.
+	// "AsymmetricPrivateKey", "Slack"), matched case-insensitively, to hours,
+	// so a leaked private key can cost more than a low-risk token.
+	SecretCategoryHours map[string]float64
+}
+
+func DefaultSecurityDebtCosts() SecurityDebtCosts {
+	return SecurityDebtCosts{
+		SeverityHours: map[string]float64{
+			"critical": 8.0,
+			"high":     4.0,
+			"medium":   2.0,
+			"low":      1.0,
+			"info":     1.0,
+		},
+		SecretHours: 4.0,
+	}
+}
+
+// WithDefaults returns c with the severities it leaves out and a zero
+// SecretHours set to the defaults.
+func (c SecurityDebtCosts) WithDefaults() SecurityDebtCosts {
+	defaults := DefaultSecurityDebtCosts()
+	severityHours := defaults.SeverityHours
+	for severity, hours := range c.SeverityHours {
+		severityHours[strings.ToLower(severity)] = hours
+	}
+	c.SeverityHours = severityHours
+	if c.SecretHours == 0 {
+		c.SecretHours = defaults.SecretHours
+	}
+	return c
+}
+
+// VulnerabilityHours returns the debt of a vulnerability of the given
+// severity; an unknown severity costs as much as info.
+func (c SecurityDebtCosts) VulnerabilityHours(severity string) float64 {
+	if hours, ok := c.SeverityHours[strings.ToLower(severity)]; ok {
+		return hours
+	}
+	return c.SeverityHours["info"]
+}
+
+// SecretCost returns the debt of a secret of the given category.
+func (c SecurityDebtCosts) SecretCost(category string) float64 {
+	for name, hours := range c.SecretCategoryHours {
+		if strings.EqualFold(name, category) {
+			return hours
+		}
+	}
+	return c.SecretHours
+}
+
+// LicensePolicy configures the opt-in license scan of the security analyzer.
+type LicensePolicy struct {
+	// Enabled adds Trivy's license scanner to the filesystem scan.
+	Enabled bool
+	// Allowed lists the licenses the project accepts, as SPDX identifiers
+	// (e.g. "MIT", "Apache-2.0") matched case-insensitively. Findings for
+	// them are dropped.
+	Allowed []string
+}
+
+// Allows reports whether license is on the allowlist.
+func (p LicensePolicy) Allows(license string) bool {
+	for _, allowed := range p.Allowed {
+		if strings.EqualFold(allowed, license) {
+			return true
+		}
+	}
+	return false
+}
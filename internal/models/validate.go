@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Issue enums accepted by Validate. The issue store orders by severity with a
+// CASE over these names, so anything else would sort as unknown.
+var (
+	validIssueSeverities = map[string]bool{
+		"critical": true,
+		"high":     true,
+		"medium":   true,
+		"low":      true,
+		"info":     true,
+	}
+	validIssueStatuses = map[string]bool{
+		"open":     true,
+		"resolved": true,
+		"ignored":  true,
+	}
+	validIssueCategories = map[string]bool{
+		"configuration":           true,
+		"container_vulnerability": true,
+		"license":                 true,
+		"maintainability":         true,
+		"maintenance":             true,
+		"performance":             true,
+		"reliability":             true,
+		"security":                true,
+		"vulnerability":           true,
+		"secret":                  true,
+		"style":                   true,
+	}
+)
+
+// maxIssueDebtHours bounds TechnicalDebtHours; an estimate beyond a working
+// year is a unit or overflow bug rather than a real estimate.
+const maxIssueDebtHours = 2000
+
+// Validate reports the first field of the issue that cannot be stored: an
+// unknown severity, status or category, an empty file path or message, a
+// confidence score outside [0, 1], or negative or non-finite debt hours or
+// effort multiplier.
+func (i *TechnicalDebtIssue) Validate() error {
+	switch {
+	case !validIssueSeverities[i.Severity]:
+		return fmt.Errorf("invalid severity %q (valid: %s)", i.Severity, enumList(validIssueSeverities))
+	case !validIssueStatuses[i.Status]:
+		return fmt.Errorf("invalid status %q (valid: %s)", i.Status, enumList(validIssueStatuses))
+	case !validIssueCategories[i.Category]:
+		return fmt.Errorf("invalid category %q (valid: %s)", i.Category, enumList(validIssueCategories))
+	case strings.TrimSpace(i.FilePath) == "":
+		return fmt.Errorf("file path is empty")
+	case strings.TrimSpace(i.Message) == "":
+		return fmt.Errorf("message is empty")
+	case math.IsNaN(i.ConfidenceScore) || i.ConfidenceScore < 0 || i.ConfidenceScore > 1:
+		return fmt.Errorf("invalid confidence score %v (must be between 0 and 1)", i.ConfidenceScore)
+	case math.IsNaN(i.TechnicalDebtHours) || i.TechnicalDebtHours < 0 || i.TechnicalDebtHours > maxIssueDebtHours:
+		return fmt.Errorf("invalid technical debt hours %v (must be between 0 and %d)", i.TechnicalDebtHours, maxIssueDebtHours)
+	case math.IsNaN(i.EffortMultiplier) || math.IsInf(i.EffortMultiplier, 0) || i.EffortMultiplier < 0:
+		return fmt.Errorf("invalid effort multiplier %v (must be 0 or greater)", i.EffortMultiplier)
+	}
+	return nil
+}
+
+// InvalidIssue is an issue rejected by Validate, identified by its index in
+// the batch it was submitted with.
+type InvalidIssue struct {
+	Index int
+	Err   error
+}
+
+// ValidateIssues validates every issue and returns the invalid ones in order.
+func ValidateIssues(issues []TechnicalDebtIssue) []InvalidIssue {
+	var invalid []InvalidIssue
+	for idx := range issues {
+		if err := issues[idx].Validate(); err != nil {
+			invalid = append(invalid, InvalidIssue{Index: idx, Err: err})
+		}
+	}
+	return invalid
+}
+
+func enumList(values map[string]bool) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func newValidIssue() TechnicalDebtIssue {
+	return TechnicalDebtIssue{
+		FilePath:           "/internal/api/handler.go",
+		IssueType:          "complexity",
+		Severity:           "high",
+		Category:           "maintainability",
+		Message:            "Function 'ProcessRequest' has cyclomatic complexity of 23",
+		ConfidenceScore:    0.9,
+		TechnicalDebtHours: 1.5,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*TechnicalDebtIssue)
+		wantErr string
+	}{
+		{"valid issue", func(*TechnicalDebtIssue) {}, ""},
+		{"info severity", func(i *TechnicalDebtIssue) { i.Severity = "info" }, ""},
+		{"configuration category", func(i *TechnicalDebtIssue) { i.Category = "configuration" }, ""},
+		{"zero effort multiplier", func(i *TechnicalDebtIssue) { i.EffortMultiplier = 0 }, ""},
+		{"empty severity", func(i *TechnicalDebtIssue) { i.Severity = "" }, "invalid severity"},
+		{"uppercase severity", func(i *TechnicalDebtIssue) { i.Severity = "HIGH" }, "invalid severity"},
+		{"unknown status", func(i *TechnicalDebtIssue) { i.Status = "closed" }, "invalid status"},
+		{"unknown category", func(i *TechnicalDebtIssue) { i.Category = strings.Repeat("x", 300) }, "invalid category"},
+		{"empty file path", func(i *TechnicalDebtIssue) { i.FilePath = " " }, "file path is empty"},
+		{"empty message", func(i *TechnicalDebtIssue) { i.Message = "" }, "message is empty"},
+		{"confidence above one", func(i *TechnicalDebtIssue) { i.ConfidenceScore = 1.5 }, "invalid confidence score"},
+		{"NaN confidence", func(i *TechnicalDebtIssue) { i.ConfidenceScore = math.NaN() }, "invalid confidence score"},
+		{"negative hours", func(i *TechnicalDebtIssue) { i.TechnicalDebtHours = -1 }, "invalid technical debt hours"},
+		{"infinite hours", func(i *TechnicalDebtIssue) { i.TechnicalDebtHours = math.Inf(1) }, "invalid technical debt hours"},
+		{"negative effort multiplier", func(i *TechnicalDebtIssue) { i.EffortMultiplier = -2 }, "invalid effort multiplier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := newValidIssue()
+			tt.mutate(&issue)
+			err := issue.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIssues_ReportsIndexes(t *testing.T) {
+	issues := []TechnicalDebtIssue{newValidIssue(), newValidIssue(), newValidIssue()}
+	issues[0].Severity = ""
+	issues[2].Status = "unknown"
+
+	invalid := ValidateIssues(issues)
+	if len(invalid) != 2 || invalid[0].Index != 0 || invalid[1].Index != 2 {
+		t.Fatalf("ValidateIssues() = %+v, want issues 0 and 2", invalid)
+	}
+	if ValidateIssues(issues[1:2]) != nil {
+		t.Errorf("expected a valid batch to report nothing")
+	}
+}
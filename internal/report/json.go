@@ -0,0 +1,73 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// jsonReporter writes the issues as a JSON array.
+type jsonReporter struct {
+	opts Options
+}
+
+func newJSONReporter(opts Options) Reporter {
+	return jsonReporter{opts: opts}
+}
+
+func (r jsonReporter) Report(w io.Writer, issues []models.TechnicalDebtIssue, _ analysis.RunSummary) error {
+	if issues == nil {
+		issues = []models.TechnicalDebtIssue{}
+	}
+	return newEncoder(w, r.opts).Encode(issues)
+}
+
+// jsonFullReporter writes the issues together with the run summary as a
+// single JSON object, so consumers do not have to recompute the aggregates.
+// The suppressed issues, when listed, are not part of the summary; what the
+// run skipped and the LineCounter's per-language line breakdown are only
+// included when there are any.
+type jsonFullReporter struct {
+	opts Options
+}
+
+func newJSONFullReporter(opts Options) Reporter {
+	return jsonFullReporter{opts: opts}
+}
+
+func (r jsonFullReporter) Report(w io.Writer, issues []models.TechnicalDebtIssue, summary analysis.RunSummary) error {
+	if issues == nil {
+		issues = []models.TechnicalDebtIssue{}
+	}
+	skipped := r.opts.Skipped
+	if skipped.Empty() {
+		skipped = nil
+	}
+	languages, _ := r.opts.Metrics["languages"].(map[string]analyzers.LanguageLineStats)
+	report := struct {
+		Issues     []models.TechnicalDebtIssue            `json:"issues"`
+		Summary    analysis.RunSummary                    `json:"summary"`
+		Languages  map[string]analyzers.LanguageLineStats `json:"languages,omitempty"`
+		Suppressed []analysis.Suppression                 `json:"suppressed,omitempty"`
+		Skipped    *analysis.Skipped                      `json:"skipped,omitempty"`
+	}{
+		Issues:     issues,
+		Summary:    summary,
+		Languages:  languages,
+		Suppressed: r.opts.Suppressed,
+		Skipped:    skipped,
+	}
+	return newEncoder(w, r.opts).Encode(report)
+}
+
+// newEncoder returns an encoder writing to w, indented when opts.Pretty.
+func newEncoder(w io.Writer, opts Options) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	if opts.Pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder
+}
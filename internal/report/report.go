@@ -0,0 +1,122 @@
+// Package report writes the findings of a scan in the formats the CLI offers.
+// Each format is a Reporter registered by name in a Registry, so --format and
+// --list-formats follow what is registered, and programs embedding the scanner
+// can add formats of their own next to text, json and json-full.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// Reporter writes the issues of a run and their summary to w in one format.
+type Reporter interface {
+	Report(w io.Writer, issues []models.TechnicalDebtIssue, summary analysis.RunSummary) error
+}
+
+// Options carries what a run produced besides its issues, and how it is to be
+// laid out. Each format uses what applies to it and ignores the rest.
+type Options struct {
+	// Width is the number of columns the text tables are fitted to.
+	Width int
+	// Quiet limits the text report to the findings and suppressed tables.
+	Quiet bool
+	// Pretty indents JSON output.
+	Pretty bool
+	// Suppressed are the issues dropped by inline annotations, when they are
+	// to be listed.
+	Suppressed []analysis.Suppression
+	// Skipped is what the run left out of the analysis.
+	Skipped *analysis.Skipped
+	// Metrics are the aggregated metrics of the run's analyzers.
+	Metrics map[string]interface{}
+}
+
+// Constructor builds the Reporter of a format for one run.
+type Constructor func(opts Options) Reporter
+
+// Format names a registered format and says what it writes.
+type Format struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Registry maps format names to the constructors of their reporters. Names
+// are matched case-insensitively and listed in registration order.
+type Registry struct {
+	formats      []Format
+	constructors map[string]Constructor
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{constructors: make(map[string]Constructor)}
+}
+
+// Register adds the format name. It panics if name is already registered, as
+// that is a programming error rather than something a user can fix.
+func (r *Registry) Register(name, description string, constructor Constructor) {
+	name = strings.ToLower(name)
+	if _, exists := r.constructors[name]; exists {
+		panic(fmt.Sprintf("report: format %q registered twice", name))
+	}
+	r.formats = append(r.formats, Format{Name: name, Description: description})
+	r.constructors[name] = constructor
+}
+
+// Formats returns the registered formats in registration order.
+func (r *Registry) Formats() []Format {
+	return append([]Format(nil), r.formats...)
+}
+
+// Names returns the registered format names in registration order.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.formats))
+	for i, format := range r.formats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// Validate returns an error listing the available formats if name is not
+// registered.
+func (r *Registry) Validate(name string) error {
+	if _, ok := r.constructors[strings.ToLower(name)]; !ok {
+		return fmt.Errorf("unknown format %q (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+	return nil
+}
+
+// New returns the reporter of the format name, configured with opts.
+func (r *Registry) New(name string, opts Options) (Reporter, error) {
+	if err := r.Validate(name); err != nil {
+		return nil, err
+	}
+	return r.constructors[strings.ToLower(name)](opts), nil
+}
+
+// defaultRegistry holds the built-in formats and those added with Register.
+var defaultRegistry = func() *Registry {
+	registry := NewRegistry()
+	registry.Register("text", "Tables of the findings, suppressions and skipped files, with a summary", newTextReporter)
+	registry.Register("json", "JSON array of the issues", newJSONReporter)
+	registry.Register("json-full", "JSON object with the issues, the run summary and what was suppressed or skipped", newJSONFullReporter)
+	return registry
+}()
+
+// Default returns the registry the scan command selects its output format
+// from.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a format to the default registry, making it available to
+// --format. It is meant to be called from an init function, before any scan
+// runs, and panics if the name is taken.
+func Register(name, description string, constructor Constructor) {
+	defaultRegistry.Register(name, description, constructor)
+}
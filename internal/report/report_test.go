@@ -0,0 +1,160 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countReporter writes the number of issues, standing in for a format an
+// embedder registers.
+type countReporter struct {
+	prefix string
+}
+
+func (r countReporter) Report(w io.Writer, issues []models.TechnicalDebtIssue, summary analysis.RunSummary) error {
+	_, err := fmt.Fprintf(w, "%s%d issues, %.1fh\n", r.prefix, len(issues), summary.TotalDebtHours)
+	return err
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("count", "Number of issues", func(opts Options) Reporter {
+		return countReporter{prefix: strings.Repeat(">", opts.Width)}
+	})
+	registry.Register("Other", "Another format", func(Options) Reporter { return countReporter{} })
+
+	assert.Equal(t, []string{"count", "other"}, registry.Names())
+	assert.Equal(t, Format{Name: "count", Description: "Number of issues"}, registry.Formats()[0])
+	assert.Panics(t, func() { registry.Register("COUNT", "", nil) })
+
+	reporter, err := registry.New("Count", Options{Width: 2})
+	require.NoError(t, err)
+	issues := []models.TechnicalDebtIssue{{TechnicalDebtHours: 1.5}}
+	var out bytes.Buffer
+	require.NoError(t, reporter.Report(&out, issues, analysis.Summarize(issues)))
+	assert.Equal(t, ">>1 issues, 1.5h\n", out.String())
+
+	_, err = registry.New("xml", Options{})
+	assert.EqualError(t, err, `unknown format "xml" (available: count, other)`)
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t, []string{"text", "json", "json-full"}, Default().Names())
+	assert.NoError(t, Default().Validate("JSON"))
+}
+
+func reportIssues() []models.TechnicalDebtIssue {
+	line := 12
+	rule := "cyclomatic"
+	return []models.TechnicalDebtIssue{{
+		FilePath:           "/main.go",
+		LineNumber:         &line,
+		Severity:           "high",
+		Category:           "maintainability",
+		Message:            "Function 'run' has high cyclomatic complexity",
+		ToolRuleID:         &rule,
+		TechnicalDebtHours: 2,
+	}}
+}
+
+func TestJSONReporters(t *testing.T) {
+	issues := reportIssues()
+	summary := analysis.Summarize(issues)
+
+	var out bytes.Buffer
+	reporter, err := Default().New("json", Options{})
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(&out, nil, summary))
+	assert.Equal(t, "[]\n", out.String(), "no issues are an empty array, not null")
+
+	out.Reset()
+	reporter, err = Default().New("json-full", Options{Pretty: true, Skipped: &analysis.Skipped{}})
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	assert.Contains(t, out.String(), "\n  \"summary\": {")
+	assert.NotContains(t, out.String(), "skipped", "nothing skipped is left out")
+
+	var report struct {
+		Issues  []models.TechnicalDebtIssue `json:"issues"`
+		Summary analysis.RunSummary         `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Len(t, report.Issues, 1)
+	assert.Equal(t, 1, report.Summary.TotalIssues)
+	assert.Nil(t, report.Summary.ComplexityHistogram, "without the complexity analyzer there is no histogram")
+
+	out.Reset()
+	summary.ComplexityHistogram = models.NewComplexityHistogram(nil)
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, models.NewComplexityHistogram(nil), report.Summary.ComplexityHistogram, "no functions are an all-zero histogram")
+	assert.NotContains(t, out.String(), "languages", "no line counts are left out")
+
+	out.Reset()
+	languages := map[string]analyzers.LanguageLineStats{
+		"Go": {Files: 2, TotalLines: 30, CodeLines: 22, CommentLines: 3, BlankLines: 5},
+	}
+	reporter, err = Default().New("json-full", Options{Metrics: map[string]interface{}{"languages": languages}})
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	var withLines struct {
+		Languages map[string]analyzers.LanguageLineStats `json:"languages"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &withLines))
+	assert.Equal(t, languages, withLines.Languages)
+}
+
+func TestTextReporter(t *testing.T) {
+	issues := reportIssues()
+	summary := analysis.Summarize(issues)
+	skipped := &analysis.Skipped{Files: map[string]int{"minified": 1}}
+
+	var out bytes.Buffer
+	reporter, err := Default().New("text", Options{Width: 80, Skipped: skipped})
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	for _, want := range []string{"SEVERITY", "/main.go:12", "cyclomatic", "CATEGORY", "1 file", "minified or bundled output", "SUMMARY", "2.0h"} {
+		assert.Contains(t, out.String(), want)
+	}
+	assert.NotContains(t, out.String(), "COMPLEXITY", "no functions are no histogram")
+
+	out.Reset()
+	summary.ComplexityHistogram = models.NewComplexityHistogram([]models.ComplexityMetric{
+		{CyclomaticComplexity: 2}, {CyclomaticComplexity: 3}, {CyclomaticComplexity: 12},
+	})
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	assert.Contains(t, out.String(), "COMPLEXITY   FUNCTIONS\n----------   ---------\n"+
+		"1-5                  2   "+strings.Repeat("█", histogramBarWidth)+"\n"+
+		"6-10                 0\n"+
+		"11-20                1   "+strings.Repeat("█", histogramBarWidth/2)+"\n"+
+		"21+                  0\n")
+
+	out.Reset()
+	reporter, err = Default().New("text", Options{Width: 80, Quiet: true, Skipped: skipped})
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	assert.Contains(t, out.String(), "/main.go:12")
+	assert.NotContains(t, out.String(), "SUMMARY")
+	assert.NotContains(t, out.String(), "SKIPPED")
+	assert.NotContains(t, out.String(), "COMPLEXITY")
+	assert.NotContains(t, out.String(), "TRUNCATED")
+
+	out.Reset()
+	summary.Truncated = true
+	require.NoError(t, reporter.Report(&out, issues, summary))
+	assert.Contains(t, out.String(), "TRUNCATED", "even a quiet report says it is incomplete")
+
+	out.Reset()
+	require.NoError(t, reporter.Report(&out, nil, analysis.Summarize(nil)))
+	assert.Equal(t, "No technical debt issues found.\n", out.String())
+}
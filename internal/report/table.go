@@ -0,0 +1,91 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+const (
+	// tablePadding is the padding between columns of the text tables.
+	tablePadding = 3
+	// minColumnWidth is the narrowest a truncated column gets, so a very
+	// narrow width still shows something of every cell.
+	minColumnWidth = 12
+)
+
+// columnFit says how a table column gives way when rows are too wide.
+type columnFit int
+
+const (
+	// fixedColumn is never truncated, e.g. the severity.
+	fixedColumn columnFit = iota
+	// truncateEnd cuts the end of cells, e.g. messages.
+	truncateEnd
+	// truncateStart cuts the start of cells, so a file path keeps its file
+	// name and line number.
+	truncateStart
+)
+
+// fitRows truncates cells with an ellipsis so that rows, aligned by
+// tabwriter with tablePadding between columns, fit in width. The widest
+// truncatable column gives way first, and none is cut below minColumnWidth,
+// so a row can still exceed a very small width.
+func fitRows(rows [][]string, fits []columnFit, width int) {
+	widths := make([]int, len(fits))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	total := tablePadding * (len(fits) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > width {
+		widest := -1
+		for i, fit := range fits {
+			if fit != fixedColumn && widths[i] > minColumnWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = truncateCell(cell, widths[i], fits[i])
+		}
+	}
+}
+
+// truncateCell shortens s to width runes, marking the cut with "...".
+func truncateCell(s string, width int, fit columnFit) string {
+	runes := []rune(s)
+	if fit == fixedColumn || len(runes) <= width {
+		return s
+	}
+	const ellipsis = "..."
+	keep := max(width-len(ellipsis), 0)
+	if fit == truncateStart {
+		return ellipsis + string(runes[len(runes)-keep:])
+	}
+	return string(runes[:keep]) + ellipsis
+}
+
+// writeTable fits rows to width and writes them as tab-aligned columns.
+func writeTable(out io.Writer, rows [][]string, fits []columnFit, width int) error {
+	fitRows(rows, fits, width)
+	w := tabwriter.NewWriter(out, 0, 0, tablePadding, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateCell(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		fit   columnFit
+		want  string
+	}{
+		{"short", 10, truncateEnd, "short"},
+		{"a long message here", 10, truncateEnd, "a long ..."},
+		{"internal/analysis/engine.go:42", 16, truncateStart, ".../engine.go:42"},
+		{"café crème brûlée", 8, truncateEnd, "café ..."},
+		{"CRITICAL", 3, fixedColumn, "CRITICAL"},
+	}
+	for _, tt := range tests {
+		if got := truncateCell(tt.in, tt.width, tt.fit); got != tt.want {
+			t.Errorf("truncateCell(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTable_FitsWidth(t *testing.T) {
+	rows := [][]string{
+		{"SEVERITY", "FILE:LINE", "MESSAGE"},
+		{"HIGH", "internal/analysis/analyzers/security/trivy_image.go:120", "Function 'runImageScan' has a cyclomatic complexity of 21 (threshold 15)"},
+		{"LOW", "main.go:3", "short"},
+	}
+	var out bytes.Buffer
+	if err := writeTable(&out, rows, []columnFit{fixedColumn, truncateStart, truncateEnd}, 60); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(strings.TrimRight(line, " ")); n > 60 {
+			t.Errorf("Line is %d columns wide, want at most 60: %q", n, line)
+		}
+	}
+	if !strings.Contains(lines[1], "...") || !strings.Contains(lines[1], "trivy_image.go:120") {
+		t.Errorf("Expected the path to keep its file name and line, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "main.go:3") || !strings.HasSuffix(strings.TrimRight(lines[2], " "), "short") {
+		t.Errorf("Expected short cells to be left alone, got %q", lines[2])
+	}
+}
@@ -0,0 +1,273 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// textReporter writes the findings table, a notice when the issues were
+// truncated, the suppressed issues and, unless quiet, the category breakdown, the line counts, the
+// complexity histogram, what was skipped and the summary footer.
+type textReporter struct {
+	opts Options
+}
+
+func newTextReporter(opts Options) Reporter {
+	return textReporter{opts: opts}
+}
+
+func (r textReporter) Report(w io.Writer, issues []models.TechnicalDebtIssue, summary analysis.RunSummary) error {
+	if err := writeFindings(w, issues, r.opts.Width); err != nil {
+		return err
+	}
+	if summary.Truncated {
+		fmt.Fprintln(w, "\nTRUNCATED: issue collection stopped at --max-issues; the scan found more issues than are listed.")
+	}
+	if err := writeSuppressed(w, r.opts.Suppressed, r.opts.Width); err != nil {
+		return err
+	}
+	if r.opts.Quiet {
+		return nil
+	}
+	if err := writeCategoryBreakdown(w, summary); err != nil {
+		return err
+	}
+	if err := writeLineCounts(w, r.opts.Metrics); err != nil {
+		return err
+	}
+	if err := writeComplexityHistogram(w, summary.ComplexityHistogram); err != nil {
+		return err
+	}
+	if err := writeSkipped(w, r.opts.Skipped); err != nil {
+		return err
+	}
+	return writeSummaryFooter(w, summary, r.opts.Metrics)
+}
+
+// writeFindings outputs the scan results in a clean table using
+// text/tabwriter, fitted to width: long file paths and messages are cut with
+// an ellipsis.
+func writeFindings(out io.Writer, issues []models.TechnicalDebtIssue, width int) error {
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "No technical debt issues found.")
+		return nil
+	}
+
+	rows := [][]string{
+		{"SEVERITY", "FILE:LINE", "RULE", "MESSAGE"},
+		{"--------", "---------", "----", "-------"},
+	}
+	for _, issue := range issues {
+		// Format File:Line
+		location := issue.FilePath
+		if issue.LineNumber != nil {
+			location = fmt.Sprintf("%s:%d", issue.FilePath, *issue.LineNumber)
+		}
+
+		// Format Rule
+		rule := "N/A"
+		if issue.ToolRuleID != nil && *issue.ToolRuleID != "" {
+			rule = *issue.ToolRuleID
+		}
+
+		rows = append(rows, []string{strings.ToUpper(issue.Severity), location, rule, issue.Message})
+	}
+
+	return writeTable(out, rows, []columnFit{fixedColumn, truncateStart, truncateEnd, truncateEnd}, width)
+}
+
+// writeSuppressed outputs the issues dropped by inline annotations beneath
+// the findings table, with the annotation and its reason, fitted to width.
+func writeSuppressed(out io.Writer, suppressed []analysis.Suppression, width int) error {
+	if len(suppressed) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	rows := [][]string{
+		{"SUPPRESSED", "FILE:LINE", "ANNOTATION", "REASON"},
+		{"----------", "---------", "----------", "------"},
+	}
+	for _, s := range suppressed {
+		location := fmt.Sprintf("%s:%d", s.Issue.FilePath, *s.Issue.LineNumber)
+		reason := s.Reason
+		if reason == "" {
+			reason = "(no reason given)"
+		}
+		rows = append(rows, []string{s.Issue.IssueType, location, fmt.Sprintf("line %d", s.Line), reason})
+	}
+
+	return writeTable(out, rows, []columnFit{fixedColumn, truncateStart, fixedColumn, truncateEnd}, width)
+}
+
+// skippedFileReasons describes the reasons the complexity analysis skips
+// files for in the text report.
+var skippedFileReasons = map[string]string{
+	"unsupported": "unsupported file type",
+	"too_large":   fmt.Sprintf("larger than %d MB", analysis.MaxFileSize/(1024*1024)),
+	"minified":    "minified or bundled output",
+	"generated":   "generated code",
+	"parse_error": "could not be parsed",
+}
+
+// writeSkipped outputs the analyzers that did not run and the files left out
+// of the analysis, and why, beneath the findings table so that missing
+// findings can be explained. Nothing is printed when nothing was skipped.
+func writeSkipped(out io.Writer, skipped *analysis.Skipped) error {
+	if skipped.Empty() {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SKIPPED\tREASON")
+	fmt.Fprintln(w, "-------\t------")
+	for _, analyzer := range skipped.Analyzers {
+		fmt.Fprintf(w, "%s analyzer\t%s\n", analyzer.Analyzer, analyzer.Reason)
+	}
+	for _, reason := range skipped.SortedFileReasons() {
+		files := "files"
+		if skipped.Files[reason] == 1 {
+			files = "file"
+		}
+		description, ok := skippedFileReasons[reason]
+		if !ok {
+			description = reason
+		}
+		fmt.Fprintf(w, "%d %s\t%s\n", skipped.Files[reason], files, description)
+	}
+
+	return w.Flush()
+}
+
+// writeCategoryBreakdown outputs the issues and debt per category beneath
+// the findings table, largest categories first.
+func writeCategoryBreakdown(out io.Writer, summary analysis.RunSummary) error {
+	if len(summary.CategoryBreakdown) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tISSUES\tDEBT")
+	fmt.Fprintln(w, "--------\t------\t----")
+	for _, category := range summary.SortedCategories() {
+		fmt.Fprintf(w, "%s\t%d\t%.1fh\n", category.Category, category.Issues, category.DebtHours)
+	}
+
+	return w.Flush()
+}
+
+// writeLineCounts outputs the LineCounter's per-language code/comment/blank
+// breakdown beneath the findings table.
+func writeLineCounts(out io.Writer, metrics map[string]interface{}) error {
+	languages, ok := metrics["languages"].(map[string]analyzers.LanguageLineStats)
+	if !ok || len(languages) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tFILES\tCODE\tCOMMENT\tBLANK")
+	fmt.Fprintln(w, "--------\t-----\t----\t-------\t-----")
+
+	var total analyzers.LanguageLineStats
+	for _, name := range names {
+		stats := languages[name]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", name, stats.Files, stats.CodeLines, stats.CommentLines, stats.BlankLines)
+		total.Files += stats.Files
+		total.CodeLines += stats.CodeLines
+		total.CommentLines += stats.CommentLines
+		total.BlankLines += stats.BlankLines
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%d\n", total.Files, total.CodeLines, total.CommentLines, total.BlankLines)
+
+	return w.Flush()
+}
+
+// histogramBarWidth is the length of the bar of the fullest histogram bucket.
+const histogramBarWidth = 40
+
+// writeComplexityHistogram outputs the distribution of the functions'
+// cyclomatic complexity as a bar chart scaled to the fullest bucket. Nothing
+// is printed when no functions were analyzed.
+func writeComplexityHistogram(out io.Writer, histogram models.ComplexityHistogram) error {
+	if histogram.Functions() == 0 {
+		return nil
+	}
+	fullest := 0
+	for _, bucket := range histogram {
+		fullest = max(fullest, bucket.Functions)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "COMPLEXITY   FUNCTIONS")
+	fmt.Fprintln(out, "----------   ---------")
+	for _, bucket := range histogram {
+		bar := bucket.Functions * histogramBarWidth / fullest
+		if bar == 0 && bucket.Functions > 0 {
+			bar = 1
+		}
+		line := fmt.Sprintf("%-10s   %9d   %s", bucket.Range, bucket.Functions, strings.Repeat("█", bar))
+		if _, err := fmt.Fprintln(out, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSummaryFooter closes the text report with a one-screen overview: the
+// issue and severity counts, total debt, affected files and, when functions
+// were analyzed, their average complexity. Values are right-aligned in one
+// column.
+func writeSummaryFooter(out io.Writer, summary analysis.RunSummary, metrics map[string]interface{}) error {
+	rows := [][3]string{
+		{"Issues", strconv.Itoa(summary.TotalIssues), fmt.Sprintf("critical %d, high %d, medium %d, low %d",
+			summary.SeverityCounts["critical"], summary.SeverityCounts["high"],
+			summary.SeverityCounts["medium"], summary.SeverityCounts["low"])},
+		{"Technical debt", fmt.Sprintf("%.1fh", summary.TotalDebtHours), ""},
+	}
+	if math.Abs(summary.EffectiveDebtHours-summary.TotalDebtHours) >= 0.05 {
+		rows = append(rows, [3]string{"Effective debt", fmt.Sprintf("%.1fh", summary.EffectiveDebtHours), "after effort multipliers"})
+	}
+	rows = append(rows, [3]string{"Files affected", strconv.Itoa(summary.AffectedFiles), ""})
+	if functions, _ := metrics["complexity_functions_analyzed"].(int); functions > 0 {
+		average, _ := metrics["complexity_avg_cyclomatic"].(float64)
+		rows = append(rows, [3]string{"Avg complexity", fmt.Sprintf("%.1f", average), fmt.Sprintf("over %d functions", functions)})
+	}
+
+	labelWidth, valueWidth := 0, 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, len(row[0]))
+		valueWidth = max(valueWidth, len(row[1]))
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "SUMMARY")
+	fmt.Fprintln(out, "-------")
+	for _, row := range rows {
+		line := fmt.Sprintf("%-*s   %*s", labelWidth, row[0], valueWidth, row[1])
+		if row[2] != "" {
+			line += "   " + row[2]
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// Package server receives repository push webhooks from GitHub and GitLab
+// and queues an analysis of the pushed commit, so a self-hosted deployment
+// analyzes repositories as they change instead of on a schedule.
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/store"
+	"github.com/google/uuid"
+)
+
+// maxWebhookBytes caps the payload read from a webhook request; GitHub
+// sends at most 25 MB.
+const maxWebhookBytes = 25 << 20
+
+// webhookTriggerSource is the AnalysisRun.TriggerSource of webhook runs.
+const webhookTriggerSource = "webhook"
+
+// Job is an analysis queued for a pushed commit. Run is already stored with
+// the "pending" status.
+type Job struct {
+	Run        *models.AnalysisRun
+	Repository *models.UserRepository
+	// Config holds the credentials to clone Repository with.
+	Config   *models.UserConfiguration
+	CloneURL string
+}
+
+// JobSubmitter queues analysis jobs. ctx bounds the submission, not the
+// analysis, which outlives the webhook request.
+type JobSubmitter interface {
+	SubmitJobCtx(ctx context.Context, job Job) error
+}
+
+// RepositoryFinder finds the repository a webhook is about. GetByURL returns
+// nil when no repository has the URL.
+type RepositoryFinder interface {
+	GetByURL(url string) (*models.UserRepository, error)
+}
+
+// ConfigFinder finds the platform configuration, and so the credentials, of
+// a repository.
+type ConfigFinder interface {
+	GetByID(id string) (*models.UserConfiguration, error)
+}
+
+// RunRecorder stores the analysis runs webhooks create.
+type RunRecorder interface {
+	Create(run *models.AnalysisRun) error
+	UpdateStatus(ctx context.Context, runID uuid.UUID, status string, results map[string]interface{}) error
+}
+
+// WebhookHandler accepts GitHub and GitLab push webhooks signed with the
+// shared secret (config.Config.GitHubWebhookSecret). It answers 401 to a
+// webhook that is unsigned or signed with another secret, 404 to a push to a
+// repository it does not know and 202 once the analysis is queued. Other
+// events, tag pushes and branch deletions are acknowledged with 204 and
+// ignored.
+type WebhookHandler struct {
+	secret  []byte
+	repos   RepositoryFinder
+	configs ConfigFinder
+	runs    RunRecorder
+	jobs    JobSubmitter
+	now     func() time.Time
+}
+
+// NewWebhookHandler returns a handler that verifies webhooks with secret.
+// With an empty secret every webhook is rejected.
+func NewWebhookHandler(secret string, repos RepositoryFinder, configs ConfigFinder, runs RunRecorder, jobs JobSubmitter) *WebhookHandler {
+	return &WebhookHandler{
+		secret:  []byte(secret),
+		repos:   repos,
+		configs: configs,
+		runs:    runs,
+		jobs:    jobs,
+		now:     time.Now,
+	}
+}
+
+// push is what a push webhook says about the pushed commit.
+type push struct {
+	// urls are the repository URLs the payload gives, most likely to be
+	// stored first.
+	urls    []string
+	branch  string
+	commit  string
+	deleted bool
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, "failed to read webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	var isPush bool
+	var parse func([]byte) (push, error)
+	switch {
+	case r.Header.Get("X-GitHub-Event") != "":
+		if !h.validGitHubSignature(r.Header.Get("X-Hub-Signature-256"), body) {
+			http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+			return
+		}
+		isPush, parse = r.Header.Get("X-GitHub-Event") == "push", parseGitHubPush
+	case r.Header.Get("X-Gitlab-Event") != "":
+		if !h.validGitLabToken(r.Header.Get("X-Gitlab-Token")) {
+			http.Error(w, "invalid webhook token", http.StatusUnauthorized)
+			return
+		}
+		isPush, parse = r.Header.Get("X-Gitlab-Event") == "Push Hook", parseGitLabPush
+	default:
+		http.Error(w, "missing webhook signature", http.StatusUnauthorized)
+		return
+	}
+	if !isPush {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event, err := parse(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if event.deleted || event.branch == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	repo, cloneURL, err := h.findRepository(event.urls)
+	if err != nil {
+		log.Printf("❌ [Webhook] Failed to look up repository %v: %v", event.urls, err)
+		http.Error(w, "failed to look up repository", http.StatusInternalServerError)
+		return
+	}
+	if repo == nil || !repo.AnalysisEnabled {
+		http.Error(w, "unknown repository", http.StatusNotFound)
+		return
+	}
+	config, err := h.configs.GetByID(repo.UserConfigID.String())
+	if errors.Is(err, store.ErrUserNotFound) || (err == nil && config == nil) {
+		http.Error(w, "unknown repository", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [Webhook] Failed to load configuration of %s: %v", repo.FullName, err)
+		http.Error(w, "failed to load repository configuration", http.StatusInternalServerError)
+		return
+	}
+
+	triggerSource := webhookTriggerSource
+	run := &models.AnalysisRun{
+		ID:            uuid.New(),
+		UserID:        repo.UserID,
+		RepositoryID:  repo.ID,
+		UserConfigID:  config.ID,
+		RunType:       "full",
+		TriggerSource: &triggerSource,
+		StartedAt:     h.now(),
+		Status:        "pending",
+		CommitHash:    &event.commit,
+		Branch:        &event.branch,
+	}
+	if err := h.runs.Create(run); err != nil {
+		log.Printf("❌ [Webhook] Failed to create analysis run for %s: %v", repo.FullName, err)
+		http.Error(w, "failed to create analysis run", http.StatusInternalServerError)
+		return
+	}
+	if err := h.jobs.SubmitJobCtx(r.Context(), Job{Run: run, Repository: repo, Config: config, CloneURL: cloneURL}); err != nil {
+		log.Printf("❌ [Webhook] Failed to queue analysis run %s: %v", run.ID, err)
+		if err := h.runs.UpdateStatus(r.Context(), run.ID, "failed", map[string]interface{}{"error": err.Error()}); err != nil {
+			log.Printf("⚠️  [Webhook] Failed to mark analysis run %s as failed: %v", run.ID, err)
+		}
+		http.Error(w, "failed to queue analysis", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"analysis_run_id": run.ID.String()})
+}
+
+// findRepository returns the first repository stored under one of urls and
+// the URL it matched, which is what the job clones.
+func (h *WebhookHandler) findRepository(urls []string) (*models.UserRepository, string, error) {
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		repo, err := h.repos.GetByURL(url)
+		if err != nil || repo != nil {
+			return repo, url, err
+		}
+	}
+	return nil, "", nil
+}
+
+// validGitHubSignature reports whether signature, an X-Hub-Signature-256
+// header, is the HMAC-SHA256 of body with the secret.
+func (h *WebhookHandler) validGitHubSignature(signature string, body []byte) bool {
+	if len(h.secret) == 0 {
+		return false
+	}
+	sum, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+// validGitLabToken reports whether token, an X-Gitlab-Token header, is the
+// secret. GitLab sends the secret itself rather than a signature.
+func (h *WebhookHandler) validGitLabToken(token string) bool {
+	return len(h.secret) > 0 && subtle.ConstantTimeCompare([]byte(token), h.secret) == 1
+}
+
+func parseGitHubPush(body []byte) (push, error) {
+	var payload struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Deleted    bool   `json:"deleted"`
+		Repository struct {
+			HTMLURL  string `json:"html_url"`
+			CloneURL string `json:"clone_url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return push{}, fmt.Errorf("invalid push payload: %w", err)
+	}
+	return push{
+		urls:    []string{payload.Repository.HTMLURL, payload.Repository.CloneURL},
+		branch:  branchName(payload.Ref),
+		commit:  payload.After,
+		deleted: payload.Deleted,
+	}, nil
+}
+
+func parseGitLabPush(body []byte) (push, error) {
+	var payload struct {
+		Ref         string  `json:"ref"`
+		CheckoutSHA *string `json:"checkout_sha"`
+		Project     struct {
+			WebURL     string `json:"web_url"`
+			GitHTTPURL string `json:"git_http_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return push{}, fmt.Errorf("invalid push payload: %w", err)
+	}
+	event := push{
+		urls:   []string{payload.Project.WebURL, payload.Project.GitHTTPURL},
+		branch: branchName(payload.Ref),
+		// GitLab sends a null checkout_sha when the branch was deleted.
+		deleted: payload.CheckoutSHA == nil,
+	}
+	if payload.CheckoutSHA != nil {
+		event.commit = *payload.CheckoutSHA
+	}
+	return event, nil
+}
+
+// branchName returns the branch ref names, or "" for a ref that is not a
+// branch, such as a tag.
+func branchName(ref string) string {
+	if !strings.HasPrefix(ref, "refs/heads/") {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
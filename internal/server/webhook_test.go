@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/endrilickollari/debtdrone-cli/internal/store"
+	"github.com/endrilickollari/debtdrone-cli/internal/store/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "s3cret"
+
+type fakeConfigs map[uuid.UUID]*models.UserConfiguration
+
+func (c fakeConfigs) GetByID(id string) (*models.UserConfiguration, error) {
+	if config, ok := c[uuid.MustParse(id)]; ok {
+		return config, nil
+	}
+	return nil, store.ErrUserNotFound
+}
+
+type fakeSubmitter struct {
+	jobs []Job
+	err  error
+}
+
+func (s *fakeSubmitter) SubmitJobCtx(ctx context.Context, job Job) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+type webhookFixture struct {
+	handler *WebhookHandler
+	repo    models.UserRepository
+	config  *models.UserConfiguration
+	runs    *memory.InMemoryRunStore
+	jobs    *fakeSubmitter
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	config := &models.UserConfiguration{ID: uuid.New(), PlatformType: "github"}
+	repo := models.UserRepository{
+		ID: uuid.New(), UserID: uuid.New(), UserConfigID: config.ID,
+		FullName: "acme/api", URL: "https://github.com/acme/api", AnalysisEnabled: true,
+	}
+	repos := memory.NewInMemoryRepositoryStore()
+	require.NoError(t, repos.Create(&repo))
+	require.NoError(t, repos.Create(&models.UserRepository{
+		ID: uuid.New(), UserConfigID: config.ID, FullName: "acme/old", URL: "https://github.com/acme/old",
+	}))
+
+	f := &webhookFixture{repo: repo, config: config, runs: memory.NewInMemoryRunStore(), jobs: &fakeSubmitter{}}
+	f.handler = NewWebhookHandler(testSecret, repos, fakeConfigs{config.ID: config}, f.runs, f.jobs)
+	f.handler.now = func() time.Time { return time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC) }
+	return f
+}
+
+func githubSignature(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func githubPush(repoURL, ref string) string {
+	return `{"ref":"` + ref + `","after":"9fceb02d0ae598e95dc970b74767f19372d61af8","deleted":false,` +
+		`"repository":{"html_url":"` + repoURL + `","clone_url":"` + repoURL + `.git"}}`
+}
+
+func serve(h http.Handler, event, signature, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler_GitHubPush(t *testing.T) {
+	f := newWebhookFixture(t)
+	body := githubPush(f.repo.URL, "refs/heads/main")
+
+	rec := serve(f.handler, "push", githubSignature(testSecret, body), body)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	require.Len(t, f.runs.Runs, 1)
+	run := f.runs.Runs[0]
+	assert.Equal(t, f.repo.ID, run.RepositoryID)
+	assert.Equal(t, f.repo.UserID, run.UserID)
+	assert.Equal(t, f.config.ID, run.UserConfigID)
+	assert.Equal(t, "pending", run.Status)
+	assert.Equal(t, "webhook", *run.TriggerSource)
+	assert.Equal(t, "main", *run.Branch)
+	assert.Equal(t, "9fceb02d0ae598e95dc970b74767f19372d61af8", *run.CommitHash)
+
+	require.Len(t, f.jobs.jobs, 1)
+	job := f.jobs.jobs[0]
+	assert.Equal(t, run.ID, job.Run.ID)
+	assert.Equal(t, f.config, job.Config, "the job carries the repository's credentials")
+	assert.Equal(t, f.repo.URL, job.CloneURL)
+
+	var response map[string]string
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, run.ID.String(), response["analysis_run_id"])
+}
+
+func TestWebhookHandler_GitLabPush(t *testing.T) {
+	f := newWebhookFixture(t)
+	body := `{"object_kind":"push","ref":"refs/heads/develop","checkout_sha":"da1560886d4f094c3e6c9ef40349f7d38b5d27d7",` +
+		`"project":{"web_url":"https://gitlab.com/acme/api","git_http_url":"https://github.com/acme/api"}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Push Hook")
+	req.Header.Set("X-Gitlab-Token", testSecret)
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	require.Len(t, f.jobs.jobs, 1)
+	assert.Equal(t, "develop", *f.jobs.jobs[0].Run.Branch)
+	assert.Equal(t, "https://github.com/acme/api", f.jobs.jobs[0].CloneURL, "the URL the repository is stored under is cloned")
+}
+
+func TestWebhookHandler_Rejects(t *testing.T) {
+	f := newWebhookFixture(t)
+	body := githubPush(f.repo.URL, "refs/heads/main")
+	unknown := githubPush("https://github.com/acme/unknown", "refs/heads/main")
+	disabled := githubPush("https://github.com/acme/old", "refs/heads/main")
+
+	tests := []struct {
+		name      string
+		event     string
+		signature string
+		body      string
+		want      int
+	}{
+		{"unsigned", "push", "", body, http.StatusUnauthorized},
+		{"wrong secret", "push", githubSignature("other", body), body, http.StatusUnauthorized},
+		{"tampered payload", "push", githubSignature(testSecret, body), strings.Replace(body, "main", "prod", 1), http.StatusUnauthorized},
+		{"malformed signature", "push", "sha1=deadbeef", body, http.StatusUnauthorized},
+		{"unknown repository", "push", githubSignature(testSecret, unknown), unknown, http.StatusNotFound},
+		{"analysis disabled", "push", githubSignature(testSecret, disabled), disabled, http.StatusNotFound},
+		{"other event", "issues", githubSignature(testSecret, body), body, http.StatusNoContent},
+		{"tag push", "push", githubSignature(testSecret, githubPush(f.repo.URL, "refs/tags/v1.0.0")), githubPush(f.repo.URL, "refs/tags/v1.0.0"), http.StatusNoContent},
+		{"malformed payload", "push", githubSignature(testSecret, "{"), "{", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(f.handler, tt.event, tt.signature, tt.body)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Empty(t, f.runs.Runs, "rejected webhooks create no run")
+	assert.Empty(t, f.jobs.jobs)
+
+	t.Run("no event header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		f.handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("gitlab wrong token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("X-Gitlab-Event", "Push Hook")
+		req.Header.Set("X-Gitlab-Token", "guess")
+		rec := httptest.NewRecorder()
+		f.handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("no secret configured", func(t *testing.T) {
+		h := NewWebhookHandler("", f.handler.repos, f.handler.configs, f.runs, f.jobs)
+		rec := serve(h, "push", githubSignature("", body), body)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestWebhookHandler_SubmitFailure(t *testing.T) {
+	f := newWebhookFixture(t)
+	f.jobs.err = errors.New("queue full")
+	body := githubPush(f.repo.URL, "refs/heads/main")
+
+	rec := serve(f.handler, "push", githubSignature(testSecret, body), body)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Len(t, f.runs.Runs, 1)
+	assert.Equal(t, "failed", f.runs.Runs[0].Status, "a run that was never queued is not left pending")
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+)
+
+const (
+	defaultOrgWorkers    = 4
+	defaultOrgJobTimeout = 30 * time.Minute
+	orgWorstRepositories = 5
+)
+
+// OrgRepository is one repository of an organization sync.
+type OrgRepository struct {
+	// ID is the repository's store ID, passed to MarkAsInaccessible when the
+	// repository cannot be cloned.
+	ID     string
+	URL    string
+	Token  string
+	Branch string
+}
+
+// OrgScanOptions configures OrgAnalyzer.Analyze.
+type OrgScanOptions struct {
+	Scan ScanOptions
+	// Workers caps the number of repositories analyzed at once. Zero means 4.
+	Workers int
+	// JobTimeout bounds the clone and analysis of a single repository. Zero
+	// means 30 minutes.
+	JobTimeout time.Duration
+}
+
+// OrgRepositoryResult is the outcome of analyzing one repository. Err is set
+// when the repository could not be cloned or analyzed, or is the context error
+// when its job timed out or the sync was cancelled; Summary is then zero.
+type OrgRepositoryResult struct {
+	Repository OrgRepository       `json:"repository"`
+	Summary    analysis.RunSummary `json:"summary"`
+	Err        error               `json:"-"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// OrgSummary rolls up the per-repository results of an organization sync.
+type OrgSummary struct {
+	Repositories   []OrgRepositoryResult `json:"repositories"`
+	TotalIssues    int                   `json:"total_issues"`
+	TotalDebtHours float64               `json:"total_debt_hours"`
+	FailedCount    int                   `json:"failed_count"`
+	// WorstRepositories are the analyzed repositories with the most debt
+	// hours, highest first.
+	WorstRepositories []OrgRepositoryResult `json:"worst_repositories"`
+}
+
+// InaccessibleMarker is the part of the repository store OrgAnalyzer needs to
+// flag repositories it could not reach.
+type InaccessibleMarker interface {
+	MarkAsInaccessible(id string) error
+}
+
+// OrgAnalyzer fans an organization's repositories out over a bounded number of
+// workers, running the same analyzers as ScanService against a fresh clone of
+// each one.
+type OrgAnalyzer struct {
+	scanner *ScanService
+	repos   InaccessibleMarker
+	clone   func(ctx context.Context, opts git.CloneOptions) (*git.Repository, error)
+}
+
+func NewOrgAnalyzer(scanner *ScanService, repos InaccessibleMarker) *OrgAnalyzer {
+	return &OrgAnalyzer{scanner: scanner, repos: repos, clone: scanner.gitService.Clone}
+}
+
+// Analyze clones and analyzes every repository and returns the org rollup.
+// A repository that fails is recorded in its result and does not abort the
+// batch; only one that cannot be cloned is marked inaccessible, since a
+// failed analysis or a timeout says nothing about access to it. Analyze only returns an error when ctx is
+// cancelled before every repository has been processed.
+func (a *OrgAnalyzer) Analyze(ctx context.Context, repositories []OrgRepository, opts OrgScanOptions) (*OrgSummary, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultOrgWorkers
+	}
+	timeout := opts.JobTimeout
+	if timeout <= 0 {
+		timeout = defaultOrgJobTimeout
+	}
+
+	results := make([]OrgRepositoryResult, len(repositories))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = a.analyzeRepository(ctx, repositories[i], opts.Scan, timeout)
+			}
+		}()
+	}
+
+submit:
+	for i := range repositories {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break submit
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("org analysis cancelled: %w", err)
+	}
+	return summarizeOrg(results), nil
+}
+
+func (a *OrgAnalyzer) analyzeRepository(ctx context.Context, repository OrgRepository, opts ScanOptions, timeout time.Duration) OrgRepositoryResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := OrgRepositoryResult{Repository: repository}
+	fail := func(err error) OrgRepositoryResult {
+		result.Err = err
+		result.Error = err.Error()
+		return result
+	}
+
+	repo, err := a.clone(ctx, git.CloneOptions{
+		URL:          repository.URL,
+		Branch:       repository.Branch,
+		Token:        repository.Token,
+		SingleBranch: true,
+		Depth:        1,
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fail(ctxErr)
+		}
+		a.markInaccessible(repository)
+		return fail(err)
+	}
+	defer repo.Cleanup()
+
+	scan, err := a.scanner.runRepository(ctx, repo, opts, nil)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fail(ctxErr)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("analysis failed: %w", err))
+	}
+
+	result.Summary = analysis.Summarize(scan.Issues)
+	return result
+}
+
+func (a *OrgAnalyzer) markInaccessible(repository OrgRepository) {
+	if a.repos == nil || repository.ID == "" {
+		return
+	}
+	if err := a.repos.MarkAsInaccessible(repository.ID); err != nil {
+		log.Printf("⚠️ [OrgAnalyzer] Failed to mark %s inaccessible: %v", repository.URL, err)
+	}
+}
+
+func summarizeOrg(results []OrgRepositoryResult) *OrgSummary {
+	summary := &OrgSummary{Repositories: results}
+
+	var analyzed []OrgRepositoryResult
+	for _, result := range results {
+		if result.Err != nil {
+			summary.FailedCount++
+			continue
+		}
+		summary.TotalIssues += result.Summary.TotalIssues
+		summary.TotalDebtHours += result.Summary.TotalDebtHours
+		analyzed = append(analyzed, result)
+	}
+
+	sort.SliceStable(analyzed, func(i, j int) bool {
+		return analyzed[i].Summary.TotalDebtHours > analyzed[j].Summary.TotalDebtHours
+	})
+	if len(analyzed) > orgWorstRepositories {
+		analyzed = analyzed[:orgWorstRepositories]
+	}
+	summary.WorstRepositories = analyzed
+
+	return summary
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOrgAnalyzer reports debtHours of debt in every repository, fails in
+// those whose directory is named "broken" and blocks until the job ends in
+// those named "slow".
+type fakeOrgAnalyzer struct {
+	debtHours map[string]float64
+}
+
+func (a *fakeOrgAnalyzer) Name() string { return "fake" }
+
+func (a *fakeOrgAnalyzer) Analyze(ctx context.Context, repo *git.Repository) (*analysis.Result, error) {
+	switch name := filepath.Base(repo.Path); name {
+	case "broken":
+		return nil, errors.New("parser crashed")
+	case "slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	default:
+		return &analysis.Result{Issues: []models.TechnicalDebtIssue{
+			{FilePath: "/main.go", Severity: "high", TechnicalDebtHours: a.debtHours[name]},
+		}}, nil
+	}
+}
+
+type fakeMarker struct {
+	mu     sync.Mutex
+	marked []string
+}
+
+func (m *fakeMarker) MarkAsInaccessible(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.marked = append(m.marked, id)
+	return nil
+}
+
+// newFakeOrgAnalyzer returns an OrgAnalyzer whose clones are local directories
+// named after the last segment of the repository URL; a URL ending in
+// "private" fails to clone for lack of credentials.
+func newFakeOrgAnalyzer(t *testing.T, debtHours map[string]float64) (*OrgAnalyzer, *fakeMarker) {
+	registry := DefaultRegistry()
+	registry.Register("fake", func() analysis.Analyzer { return &fakeOrgAnalyzer{debtHours: debtHours} })
+	scanner := &ScanService{gitService: git.NewService(), registry: registry}
+	marker := &fakeMarker{}
+
+	root := t.TempDir()
+	orgAnalyzer := NewOrgAnalyzer(scanner, marker)
+	orgAnalyzer.clone = func(ctx context.Context, opts git.CloneOptions) (*git.Repository, error) {
+		name := opts.URL[strings.LastIndex(opts.URL, "/")+1:]
+		if name == "private" {
+			return nil, fmt.Errorf("failed to clone repository: %w", transport.ErrAuthenticationRequired)
+		}
+		return git.NewService().OpenLocal(filepath.Join(root, name))
+	}
+	for _, name := range []string{"api", "web", "cli", "broken", "slow"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, name), 0755))
+	}
+	return orgAnalyzer, marker
+}
+
+func orgRepositories(names ...string) []OrgRepository {
+	var repositories []OrgRepository
+	for _, name := range names {
+		repositories = append(repositories, OrgRepository{ID: "id-" + name, URL: "https://github.com/acme/" + name})
+	}
+	return repositories
+}
+
+func TestOrgAnalyzer_PartialFailure(t *testing.T) {
+	orgAnalyzer, marker := newFakeOrgAnalyzer(t, map[string]float64{"api": 2})
+
+	summary, err := orgAnalyzer.Analyze(context.Background(), orgRepositories("api", "private", "broken", "slow"), OrgScanOptions{
+		Scan:       ScanOptions{Analyzers: []string{"fake"}, Strict: true},
+		JobTimeout: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Len(t, summary.Repositories, 4)
+
+	api, private, broken, slow := summary.Repositories[0], summary.Repositories[1], summary.Repositories[2], summary.Repositories[3]
+	assert.NoError(t, api.Err)
+	assert.Equal(t, 1, api.Summary.TotalIssues)
+	assert.ErrorIs(t, private.Err, transport.ErrAuthenticationRequired)
+	assert.ErrorContains(t, broken.Err, "analysis failed")
+	assert.Equal(t, context.DeadlineExceeded, slow.Err)
+	assert.Equal(t, 3, summary.FailedCount)
+
+	// Only the repository that could not be cloned is inaccessible.
+	assert.Equal(t, []string{"id-private"}, marker.marked)
+}
+
+func TestOrgAnalyzer_Rollup(t *testing.T) {
+	orgAnalyzer, _ := newFakeOrgAnalyzer(t, map[string]float64{"api": 2, "web": 5.5, "cli": 0.5})
+
+	summary, err := orgAnalyzer.Analyze(context.Background(), orgRepositories("api", "web", "broken", "cli"), OrgScanOptions{
+		Scan:    ScanOptions{Analyzers: []string{"fake"}, Strict: true},
+		Workers: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.TotalIssues)
+	assert.InDelta(t, 8, summary.TotalDebtHours, 0.001)
+	assert.Equal(t, 1, summary.FailedCount)
+
+	var worst []string
+	for _, result := range summary.WorstRepositories {
+		worst = append(worst, result.Repository.ID)
+	}
+	assert.Equal(t, []string{"id-web", "id-api", "id-cli"}, worst)
+}
+
+func TestOrgAnalyzer_Cancelled(t *testing.T) {
+	orgAnalyzer, marker := newFakeOrgAnalyzer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := orgAnalyzer.Analyze(ctx, orgRepositories("api", "private"), OrgScanOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, marker.marked)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
+	"github.com/endrilickollari/debtdrone-cli/internal/store/memory"
+)
+
+// ScanPlan describes what Run would do for one repository without running
+// any analyzer: the analyzers selected and the files the complexity walk
+// would reach.
+type ScanPlan struct {
+	Path      string   `json:"path"`
+	Analyzers []string `json:"analyzers"`
+	analyzers.ComplexityPlan
+}
+
+// Plan opens the repository at path and reports what a Run with opts would
+// analyze. It only walks the tree: nothing is parsed, cached or stored.
+func (s *ScanService) Plan(ctx context.Context, path string, opts ScanOptions) (*ScanPlan, error) {
+	repo, err := s.gitService.OpenLocal(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	opts = s.applyProfile(ctx, repo, opts)
+	names, err := s.registry.SelectNames(opts.Analyzers, disabledAnalyzers(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	targetFiles, err := s.targetFiles(ctx, repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.partial() {
+		// As in Run, nothing staged or changed means nothing to analyze.
+		if len(targetFiles) == 0 {
+			return &ScanPlan{Path: repo.Path, Analyzers: names, ComplexityPlan: analyzers.ComplexityPlan{
+				Languages:   map[string]int{},
+				SkippedDirs: []analyzers.SkippedDir{},
+			}}, nil
+		}
+		ctx = analysis.WithTargetFiles(ctx, targetFiles)
+	}
+	matcher, err := ignoreMatcher(repo.Path, opts)
+	if err != nil {
+		log.Printf("⚠️ [ScanService] Ignoring .gitignore and .debtdroneignore rules for %s: %v", repo.Path, err)
+	}
+	ctx = analysis.WithIgnoreMatcher(ctx, matcher)
+
+	complexityPlan, err := analyzers.NewComplexityAnalyzer(memory.NewInMemoryComplexityStore()).Plan(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return &ScanPlan{Path: repo.Path, Analyzers: names, ComplexityPlan: *complexityPlan}, nil
+}
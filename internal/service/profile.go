@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+)
+
+// quickAnalyzers are the registry names a quick scan runs.
+var quickAnalyzers = []string{"complexity"}
+
+// standardAnalyzers are the registry names a standard scan runs: everything
+// that reads the source itself, plus the security and container scans. The
+// dependency analysis and the dead code heuristic are left to deep scans.
+var standardAnalyzers = []string{"lines", "complexity", "errcheck", "blocking", "indentation", "endpoints", "todos", "security", "container"}
+
+// applyProfile returns opts narrowed to what opts.Profile runs. The profile
+// only selects analyzers when opts.Analyzers is empty, so naming analyzers
+// explicitly always takes precedence, and the options that turn analyzers
+// off (SecurityScan, DisabledAnalyzers, a missing ContainerImage) still
+// apply on top of it.
+//
+//   - quick runs the complexity analyzer over the files changed since HEAD,
+//     unless Staged or ChangedSince already restrict the scan. Outside a git
+//     repository, or before its first commit, and for a Ref the whole tree
+//     is analyzed.
+//   - standard runs standardAnalyzers, plus dead code detection when
+//     DeadCode is set.
+//   - deep runs every analyzer, with dead code detection turned on.
+func (s *ScanService) applyProfile(ctx context.Context, repo *git.Repository, opts ScanOptions) ScanOptions {
+	switch opts.Profile {
+	case models.ProfileQuick:
+		if len(opts.Analyzers) == 0 {
+			opts.Analyzers = quickAnalyzers
+		}
+		if !opts.partial() && s.gitService.HasCommit(ctx, repo.Path, "HEAD") {
+			opts.ChangedSince = "HEAD"
+		}
+	case models.ProfileDeep:
+		opts.DeadCode = true
+	default:
+		if len(opts.Analyzers) == 0 {
+			opts.Analyzers = standardAnalyzers
+			if opts.DeadCode {
+				opts.Analyzers = append(slices.Clone(standardAnalyzers), "deadcode")
+			}
+		}
+	}
+	return opts
+}
@@ -3,10 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sync"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
 	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers"
 	"github.com/endrilickollari/debtdrone-cli/internal/analysis/analyzers/security"
+	"github.com/endrilickollari/debtdrone-cli/internal/archive"
+	"github.com/endrilickollari/debtdrone-cli/internal/config"
 	"github.com/endrilickollari/debtdrone-cli/internal/git"
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/endrilickollari/debtdrone-cli/internal/store/memory"
@@ -14,67 +20,510 @@ import (
 )
 
 type ScanOptions struct {
+	// Profile selects the analyzers run when Analyzers is empty; the zero
+	// value is models.DefaultAnalysisProfile. See applyProfile.
+	Profile       models.AnalysisProfile
 	MaxComplexity int
 	SecurityScan  bool
+	// Strict aborts the scan when any analyzer returns an error instead of
+	// skipping it and continuing with the remaining analyzers.
+	Strict bool
+	// Analyzers restricts the run to these registry names; empty means all.
+	Analyzers []string
+	// DisabledAnalyzers removes these registry names from the selection.
+	DisabledAnalyzers []string
+	// BlockingAPIs overrides the blocking analyzer's default API list.
+	BlockingAPIs []string
+	// NoGitignore analyzes files excluded by .gitignore rules instead of
+	// skipping them. The .debtdroneignore still applies.
+	NoGitignore bool
+	// CacheDir holds the per-repository caches of file-level results that
+	// let unchanged files skip parsing on the next run. Empty disables
+	// caching.
+	CacheDir string
+	// ToolVersion keys the cache so results from another release are never
+	// reused.
+	ToolVersion string
+	// Staged restricts the file-level analyzers to the files staged in the
+	// git index, for pre-commit hooks. The security scan, which covers the
+	// whole tree, is skipped.
+	Staged bool
+	// ChangedSince is a git ref. When set, the file-level analyzers only see
+	// the files changed between it and the working tree, and the complexity
+	// analyzer only reports the functions whose body changed, so an edit to
+	// one function of a large file surfaces that function alone. As with
+	// Staged, the security and container scans are skipped.
+	ChangedSince string
+	// Ref is a git commit, tag or branch whose tree is analyzed in place of
+	// the working tree. The files are exported from the git objects into a
+	// temporary directory, so the checkout is never altered. It cannot be
+	// combined with Staged or ChangedSince, and the analysis cache is not
+	// used.
+	Ref string
+	// Archive makes Run treat its path as a .tar, .tar.gz or .zip file whose
+	// content is analyzed in place of a checkout, e.g. a build artifact. It
+	// is extracted into a temporary directory, and the analyzers that need a
+	// git history see none. It cannot be combined with Staged, ChangedSince
+	// or Ref, and the analysis cache is not used.
+	Archive bool
+	// DeadCode enables the heuristic Go dead code analyzer, which is off by
+	// default. Naming "deadcode" in Analyzers enables it too.
+	DeadCode bool
+	// ContainerImage is the image reference the "container" analyzer scans
+	// with `trivy image`. It only runs when this is set, and never for
+	// staged or changed-since scans.
+	ContainerImage string
+	// Minified overrides the thresholds that recognize minified files, which
+	// the complexity analyzer skips and reports as committed artifacts.
+	Minified models.MinifiedThresholds
+	// MaxFunctionsPerFile is the number of named functions from which the
+	// complexity analyzer reports a file as too large; zero keeps
+	// models.DefaultMaxFunctionsPerFile.
+	MaxFunctionsPerFile int
+	// MaxReturns is the weighted number of return points from which the
+	// complexity analyzer suggests too_many_returns; zero keeps
+	// models.DefaultMaxReturns.
+	MaxReturns int
+	// OutlierDetection makes the complexity analyzer report the functions
+	// that are outliers among the repository's own functions.
+	OutlierDetection bool
+	// LanguageThresholds replaces the complexity thresholds of the languages
+	// it names; see complexity.NewLanguageFactory.
+	LanguageThresholds map[string]models.ComplexityThresholds
+	// RefactoringDocs maps refactoring suggestion types to the pages they
+	// link to; nil keeps models.DefaultRefactoringDocs.
+	RefactoringDocs map[string]string
+	// IncludeGenerated makes the complexity analyzer analyze generated
+	// files, which it skips by default. GeneratedPatterns names generated
+	// files beyond those it recognizes; see models.ComplexityConfig.
+	IncludeGenerated  bool
+	GeneratedPatterns []string
+	// SecurityDebt overrides the debt hours of vulnerabilities and secrets;
+	// what it leaves out keeps the DefaultSecurityDebtCosts values.
+	SecurityDebt models.SecurityDebtCosts
+	// EndpointPolicy lists the hosts the hardcoded endpoint analyzer allows
+	// and whether it checks test files and comments.
+	EndpointPolicy models.EndpointPolicy
+	// LicensePolicy turns on the license scan of the security analyzer and
+	// lists the licenses it does not report.
+	LicensePolicy models.LicensePolicy
+	// MaxIssues caps the issues of a run: those beyond it, in analyzer
+	// order, are dropped before they are fingerprinted and
+	// ScanResult.Truncated is set. Zero keeps every issue.
+	MaxIssues int
+	// Jobs bounds how many analyzers run at once, so e.g. the Trivy scan
+	// (I/O bound) overlaps the complexity walk (CPU bound). Values below 2
+	// run them one at a time. Results are merged in registry order either
+	// way, so the issue order does not depend on Jobs.
+	Jobs int
+	// SubprocessLimits are the soft memory and CPU limits of the external
+	// tools analyzers run, such as Trivy.
+	SubprocessLimits analysis.SubprocessLimits
+
+	// origin is the path a Ref or Archive scan read its tree from; it stands in
+	// for the temporary directory in the repository ID.
+	origin string
 }
 
+// ScanProgress is reported when an analyzer starts and again when it
+// finishes (Done). Completed counts the analyzers finished so far, which
+// with several jobs is not the same as Index.
 type ScanProgress struct {
 	AnalyzerName string
 	Index        int
 	Total        int
+	Done         bool
+	Completed    int
+}
+
+// ScanResult is the merged output of every analyzer that ran during a scan.
+type ScanResult struct {
+	Issues  []models.TechnicalDebtIssue
+	Metrics map[string]interface{}
+	// Suppressed holds the issues dropped by debtdrone:ignore annotations in
+	// the source; they are not part of Issues.
+	Suppressed []analysis.Suppression
+	// Skipped lists the analyzers that did not run or failed, under their
+	// registry names, and the files left out of the analysis.
+	Skipped analysis.Skipped
+	// Truncated is set when Issues was cut at ScanOptions.MaxIssues.
+	Truncated bool
+	// Commit is the hash of the commit a ScanOptions.Ref scan analyzed.
+	Commit string
 }
 
+// DefaultMaxIssues is the default of --max-issues: far more issues than a
+// sensible scan reports, but few enough that a scan of a vendored tree
+// cannot produce a report of gigabytes.
+const DefaultMaxIssues = 100000
+
 type ScanService struct {
 	gitService *git.Service
+	registry   *analysis.Registry
 }
 
 func NewScanService() *ScanService {
 	return &ScanService{
-		gitService: git.NewService(),
+		gitService: newGitService(config.Load()),
+		registry:   DefaultRegistry(),
+	}
+}
+
+// newGitService returns a git service whose clones go through the clone cache
+// of cfg, when one is configured and usable.
+func newGitService(cfg *config.Config) *git.Service {
+	if cfg.CloneCacheDir == "" {
+		return git.NewService()
+	}
+	cache, err := git.NewCloneCache(cfg.CloneCacheDir)
+	if err != nil {
+		log.Printf("⚠️ [ScanService] Cloning without a cache: %v", err)
+		return git.NewService()
 	}
+	if _, err := cache.Evict(cfg.CloneCacheMaxAge, 0); err != nil {
+		log.Printf("⚠️ [ScanService] %v", err)
+	}
+	return git.NewServiceWithCloneCache(cache)
 }
 
-func (s *ScanService) Run(ctx context.Context, path string, opts ScanOptions, onProgress func(ScanProgress)) ([]models.TechnicalDebtIssue, error) {
+// DefaultRegistry returns the registry of every analyzer a scan can run, in
+// execution order. The CLI validates --analyzers and --disable-analyzers
+// against it so the accepted names always match what Run constructs.
+func DefaultRegistry() *analysis.Registry {
+	registry := analysis.NewRegistry()
+	registry.Register("lines", func() analysis.Analyzer { return analyzers.NewLineCounter() })
+	registry.Register("complexity", func() analysis.Analyzer {
+		return analyzers.NewComplexityAnalyzer(memory.NewInMemoryComplexityStore())
+	})
+	registry.Register("errcheck", func() analysis.Analyzer { return analyzers.NewGoErrorCheckAnalyzer() })
+	registry.Register("deadcode", func() analysis.Analyzer { return analyzers.NewGoDeadCodeAnalyzer() })
+	registry.Register("blocking", func() analysis.Analyzer { return analyzers.NewBlockingCallAnalyzer() })
+	registry.Register("indentation", func() analysis.Analyzer { return analyzers.NewIndentationAnalyzer() })
+	registry.Register("endpoints", func() analysis.Analyzer { return analyzers.NewEndpointAnalyzer() })
+	registry.Register("todos", func() analysis.Analyzer { return analyzers.NewTodoAnalyzer() })
+	registry.Register("dependencies", func() analysis.Analyzer { return analyzers.NewDependencyAnalyzer() })
+	registry.Register("security", func() analysis.Analyzer { return security.NewTrivyAnalyzer() })
+	registry.Register("container", func() analysis.Analyzer { return security.NewTrivyImageAnalyzer() })
+	return registry
+}
+
+// Run opens the repository at path and analyzes it. See runRepository for the
+// results returned when ctx ends early.
+func (s *ScanService) Run(ctx context.Context, path string, opts ScanOptions, onProgress func(ScanProgress)) (*ScanResult, error) {
+	if opts.Archive {
+		return s.runArchive(ctx, path, opts, onProgress)
+	}
+	if opts.Ref != "" {
+		return s.runRef(ctx, path, opts, onProgress)
+	}
 	repo, err := s.gitService.OpenLocal(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
+	return s.runRepository(ctx, repo, opts, onProgress)
+}
+
+// runRef analyzes the tree of opts.Ref below path from a temporary export.
+// Its issues get the repository ID of path, as those of a working tree scan
+// would, so their fingerprints compare across refs. An unknown ref fails
+// with git.ErrUnknownRef before anything is analyzed.
+func (s *ScanService) runRef(ctx context.Context, path string, opts ScanOptions, onProgress func(ScanProgress)) (*ScanResult, error) {
+	if opts.partial() {
+		return nil, fmt.Errorf("a scan of git ref %q cannot be restricted to staged or changed files", opts.Ref)
+	}
+	dir, err := git.MkdirTemp(git.TempDirRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the export directory: %w", err)
+	}
+	defer git.RemoveTempDir(dir)
+
+	commit, err := s.gitService.ExportRef(ctx, path, opts.Ref, dir)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := s.gitService.OpenLocal(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	opts.CacheDir = ""
+	opts.origin = path
+	result, err := s.runRepository(ctx, repo, opts, onProgress)
+	if result != nil {
+		result.Commit = commit
+	}
+	return result, err
+}
+
+// runArchive analyzes the archive at path from a temporary extraction. Its
+// issues get the repository ID of the archive path, so two scans of the same
+// artifact compare. An archive that cannot be extracted fails with
+// archive.ErrInvalid before anything is analyzed.
+func (s *ScanService) runArchive(ctx context.Context, path string, opts ScanOptions, onProgress func(ScanProgress)) (*ScanResult, error) {
+	if opts.partial() || opts.Ref != "" {
+		return nil, fmt.Errorf("a scan of archive %s cannot be restricted to a git ref or to staged or changed files", path)
+	}
+	dir, err := git.MkdirTemp(git.TempDirArchive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the extraction directory: %w", err)
+	}
+	defer git.RemoveTempDir(dir)
+
+	root, err := archive.Extract(ctx, path, dir, archive.DefaultMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := s.gitService.OpenLocal(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	opts.CacheDir = ""
+	opts.origin = path
+	return s.runRepository(ctx, repo, opts, onProgress)
+}
+
+// partial reports whether opts restricts the scan to part of the tree.
+func (opts ScanOptions) partial() bool {
+	return opts.Staged || opts.ChangedSince != ""
+}
+
+// disabledAnalyzers is opts.DisabledAnalyzers plus the analyzers opts turns
+// off: the security scan unless enabled and the whole tree is scanned, dead
+// code unless requested and the container scan without an image.
+func disabledAnalyzers(opts ScanOptions) []string {
+	disabled := append([]string(nil), opts.DisabledAnalyzers...)
+	if !opts.SecurityScan || opts.partial() {
+		disabled = append(disabled, "security")
+	}
+	if !opts.DeadCode && !slices.Contains(opts.Analyzers, "deadcode") {
+		disabled = append(disabled, "deadcode")
+	}
+	if opts.ContainerImage == "" || opts.partial() {
+		disabled = append(disabled, "container")
+	}
+	return disabled
+}
+
+// ignoreMatcher loads the .gitignore and .debtdroneignore rules of the
+// repository at path. NoGitignore drops the .gitignore rules only.
+func ignoreMatcher(path string, opts ScanOptions) (*git.IgnoreMatcher, error) {
+	if opts.NoGitignore {
+		return git.NewDebtdroneIgnoreMatcher(path)
+	}
+	return git.NewIgnoreMatcher(path)
+}
 
-	complexityStore := memory.NewInMemoryComplexityStore()
-	lineCounter := analyzers.NewLineCounter()
-	complexityAnalyzer := analyzers.NewComplexityAnalyzer(complexityStore)
+// runRepository runs the selected analyzers over an already opened or cloned
+// repository, up to opts.Jobs at a time. When ctx is cancelled or its
+// deadline passes, the remaining analyzers are skipped and the results of
+// those that finished are returned together with an error wrapping ctx.Err().
+func (s *ScanService) runRepository(ctx context.Context, repo *git.Repository, opts ScanOptions, onProgress func(ScanProgress)) (*ScanResult, error) {
+	opts = s.applyProfile(ctx, repo, opts)
+	analyzersList, err := s.registry.Select(opts.Analyzers, disabledAnalyzers(opts))
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.registry.SelectNames(opts.Analyzers, disabledAnalyzers(opts))
+	if err != nil {
+		return nil, err
+	}
 
-	analyzersList := []analysis.Analyzer{lineCounter, complexityAnalyzer}
-	if opts.SecurityScan {
-		analyzersList = append(analyzersList, security.NewTrivyAnalyzer())
+	targetFiles, err := s.targetFiles(ctx, repo, opts)
+	if err != nil {
+		return nil, err
+	}
+	// With nothing staged or changed every analyzer would fall back to a
+	// full walk.
+	if opts.partial() && len(targetFiles) == 0 {
+		return &ScanResult{Metrics: map[string]interface{}{}}, nil
 	}
 
 	// Enrich context
-	ctx = context.WithValue(ctx, "analysisRunID", uuid.New())
-	ctx = context.WithValue(ctx, "repositoryID", uuid.New())
-	ctx = context.WithValue(ctx, "userID", uuid.New())
-	ctx = context.WithValue(ctx, "complexityConfig", models.ComplexityConfig{
+	ctx = analysis.WithRunID(ctx, uuid.New())
+	repositoryPath := repo.Path
+	if opts.origin != "" {
+		repositoryPath = opts.origin
+	}
+	ctx = analysis.WithRepositoryID(ctx, RepositoryID(repositoryPath))
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithComplexityConfig(ctx, models.ComplexityConfig{
 		CyclomaticThreshold: opts.MaxComplexity,
+		Minified:            opts.Minified,
+		MaxFunctionsPerFile: opts.MaxFunctionsPerFile,
+		MaxReturns:          opts.MaxReturns,
+		OutlierDetection:    opts.OutlierDetection,
+		LanguageThresholds:  opts.LanguageThresholds,
+		RefactoringDocs:     opts.RefactoringDocs,
+		IncludeGenerated:    opts.IncludeGenerated,
+		GeneratedPatterns:   opts.GeneratedPatterns,
 	})
+	ctx = analysis.WithBlockingAPIs(ctx, opts.BlockingAPIs)
+	ctx = analysis.WithContainerImage(ctx, opts.ContainerImage)
+	ctx = analysis.WithSecurityDebtCosts(ctx, opts.SecurityDebt)
+	ctx = analysis.WithLicensePolicy(ctx, opts.LicensePolicy)
+	ctx = analysis.WithEndpointPolicy(ctx, opts.EndpointPolicy)
+	ctx = analysis.WithSubprocessLimits(ctx, opts.SubprocessLimits)
+	if opts.partial() {
+		ctx = analysis.WithTargetFiles(ctx, targetFiles)
+	}
+	if opts.ChangedSince != "" {
+		ctx = analysis.WithBaseContents(ctx, s.baseContents(ctx, repo, opts.ChangedSince, targetFiles))
+	}
+	// Unreadable ignore rules only cost precision, so the scan goes on
+	// without them.
+	matcher, err := ignoreMatcher(repo.Path, opts)
+	if err != nil {
+		log.Printf("⚠️ [ScanService] Ignoring .gitignore and .debtdroneignore rules for %s: %v", repo.Path, err)
+	}
+	ctx = analysis.WithIgnoreMatcher(ctx, matcher)
+	var cache *analysis.FileCache
+	if opts.CacheDir != "" {
+		var err error
+		cache, err = analysis.OpenFileCache(filepath.Join(opts.CacheDir, fileCacheName(repo.Path)), opts.ToolVersion)
+		if err != nil {
+			log.Printf("⚠️ [ScanService] %v", err)
+		}
+		ctx = analysis.WithFileCache(ctx, cache)
+	}
 
-	var allIssues []models.TechnicalDebtIssue
 	total := len(analyzersList)
+	collector := analysis.NewCollector(names)
 
-	for i, analyzer := range analyzersList {
+	// runCtx is cancelled when a strict scan hits its first failure, so the
+	// analyzers still running stop early and the rest never start.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		completed int
+		strictErr error
+	)
+	report := func(i int, done bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			completed++
+		}
 		if onProgress != nil {
 			onProgress(ScanProgress{
-				AnalyzerName: analyzer.Name(),
+				AnalyzerName: analyzersList[i].Name(),
 				Index:        i,
 				Total:        total,
+				Done:         done,
+				Completed:    completed,
 			})
 		}
+	}
 
-		result, err := analyzer.Analyze(ctx, repo)
-		if err != nil {
+	slots := make(chan struct{}, max(opts.Jobs, 1))
+	for i, analyzer := range analyzersList {
+		slots <- struct{}{}
+		if runCtx.Err() != nil {
+			<-slots
+			break
+		}
+		report(i, false)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			result, err := analyzer.Analyze(runCtx, repo)
+			collector.Add(i, result, err)
+			if err != nil && opts.Strict && runCtx.Err() == nil {
+				mu.Lock()
+				if strictErr == nil {
+					strictErr = fmt.Errorf("analyzer %s failed: %w", analyzer.Name(), err)
+				}
+				mu.Unlock()
+				cancel()
+			}
+			report(i, true)
+		}()
+	}
+	wg.Wait()
+	if strictErr != nil && ctx.Err() == nil {
+		return nil, strictErr
+	}
+
+	var skipped analysis.Skipped
+	var aborted error
+	for i, o := range collector.Outcomes() {
+		if !o.Ran {
+			if aborted == nil {
+				aborted = fmt.Errorf("scan aborted before analyzer %s: %w", analyzersList[i].Name(), ctx.Err())
+			}
 			continue
 		}
-		allIssues = append(allIssues, result.Issues...)
+		if o.Err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				if aborted == nil {
+					aborted = fmt.Errorf("scan aborted during analyzer %s: %w", analyzersList[i].Name(), ctxErr)
+				}
+			} else {
+				skipped.AddAnalyzer(o.Analyzer, "failed: "+o.Err.Error())
+			}
+			continue
+		}
+		skipped.AddResult(o.Analyzer, o.Result)
+	}
+	allIssues, allMetrics := collector.Merge()
+
+	allIssues, suppressed := analysis.SuppressAnnotated(repo.Path, allIssues)
+	truncated := opts.MaxIssues > 0 && len(allIssues) > opts.MaxIssues
+	if truncated {
+		allIssues = allIssues[:opts.MaxIssues]
+	}
+	for i := range allIssues {
+		allIssues[i].FingerprintHash = allIssues[i].Fingerprint()
+	}
+	for i := range suppressed {
+		suppressed[i].Issue.FingerprintHash = suppressed[i].Issue.Fingerprint()
+	}
+
+	// An aborted or partial run touched only part of the tree; saving it
+	// would evict the entries of every file it did not reach.
+	if aborted == nil && !opts.partial() {
+		if err := cache.Save(); err != nil {
+			log.Printf("⚠️ [ScanService] Failed to save the analysis cache: %v", err)
+		}
 	}
 
-	return allIssues, nil
+	return &ScanResult{Issues: allIssues, Metrics: allMetrics, Suppressed: suppressed, Skipped: skipped, Truncated: truncated}, aborted
+}
+
+// targetFiles returns the files a partial scan is restricted to: the staged
+// files or those changed since opts.ChangedSince. It returns nil for a scan
+// of the whole tree.
+func (s *ScanService) targetFiles(ctx context.Context, repo *git.Repository, opts ScanOptions) ([]string, error) {
+	switch {
+	case opts.Staged:
+		return s.gitService.GetStagedFiles(ctx, repo.Path)
+	case opts.ChangedSince != "":
+		return s.gitService.GetFilesChangedSince(ctx, repo.Path, opts.ChangedSince)
+	}
+	return nil, nil
+}
+
+// baseContents reads the content of files at ref for WithBaseContents,
+// leaving out the files that did not exist there.
+func (s *ScanService) baseContents(ctx context.Context, repo *git.Repository, ref string, files []string) map[string][]byte {
+	contents := make(map[string][]byte, len(files))
+	for _, file := range files {
+		if content, ok := s.gitService.GetFileAtRef(ctx, repo.Path, ref, file); ok {
+			contents[filepath.ToSlash(file)] = content
+		}
+	}
+	return contents
+}
+
+// RepositoryID returns the repository ID Run assigns to the issues of the
+// checkout at path. It is derived from the path so that issue fingerprints
+// stay stable across repeated local scans.
+func RepositoryID(path string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(path))
+}
+
+// fileCacheName names the cache file of the repository checked out at path.
+func fileCacheName(path string) string {
+	return "files-" + uuid.NewSHA1(uuid.NameSpaceURL, []byte(path)).String() + ".json"
 }
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/git"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultRegistry_IssuesValidate runs every registered analyzer over the
+// analyzer fixtures and checks that the store would accept each issue, so an
+// analyzer cannot report a category or severity that Validate rejects.
+func TestDefaultRegistry_IssuesValidate(t *testing.T) {
+	absPath, err := filepath.Abs("../analysis/analyzers/testdata")
+	require.NoError(t, err)
+	repo := &git.Repository{FS: osfs.New(absPath), Path: absPath}
+
+	ctx := analysis.WithRunID(context.Background(), uuid.New())
+	ctx = analysis.WithRepositoryID(ctx, uuid.New())
+	ctx = analysis.WithUserID(ctx, uuid.New())
+	ctx = analysis.WithCLI(ctx)
+
+	registry := DefaultRegistry()
+	reported := 0
+	for _, name := range registry.Names() {
+		t.Run(name, func(t *testing.T) {
+			selected, err := registry.Select([]string{name}, nil)
+			require.NoError(t, err)
+			result, err := selected[0].Analyze(ctx, repo)
+			require.NoError(t, err)
+			for _, issue := range result.Issues {
+				assert.NoError(t, issue.Validate(), "%s issue %q at %s", name, issue.Message, issue.FilePath)
+			}
+			reported += len(result.Issues)
+		})
+	}
+	assert.NotZero(t, reported, "the fixtures should produce issues to validate")
+}
@@ -24,12 +24,22 @@ type AnalysisRunStoreInterface interface {
 	GetBillableScanCount(orgID string, startOfMonth time.Time) (int64, error)
 }
 
+// debtSpikeWindow is how many prior daily snapshots a run's debt is compared
+// with to detect a spike.
+const debtSpikeWindow = 30
+
 type DBAnalysisRunStore struct {
 	db *sql.DB
+	// DebtSpikeSigma is the number of standard deviations above the recent
+	// mean at which Get flags a run's debt as a spike; zero means
+	// models.DefaultDebtSpikeSigma.
+	DebtSpikeSigma float64
 }
 
-func NewDBAnalysisRunStore(db *sql.DB) *DBAnalysisRunStore {
-	return &DBAnalysisRunStore{db: db}
+// NewDBAnalysisRunStore returns a store flagging debt spikes at
+// debtSpikeSigma standard deviations, usually config.Config.DebtSpikeSigma.
+func NewDBAnalysisRunStore(db *sql.DB, debtSpikeSigma float64) *DBAnalysisRunStore {
+	return &DBAnalysisRunStore{db: db, DebtSpikeSigma: debtSpikeSigma}
 }
 
 func (s *DBAnalysisRunStore) Create(run *models.AnalysisRun) error {
@@ -88,9 +98,47 @@ func (s *DBAnalysisRunStore) Get(id string) (*models.AnalysisRun, error) {
 		}
 	}
 
+	if run.Status == "completed" {
+		prior, err := s.getPriorSnapshots(run.RepositoryID, run.StartedAt)
+		if err != nil {
+			// The spike flag is advisory, so the run is still returned.
+			log.Printf("⚠️  [AnalysisStore] Failed to load snapshots for debt spike detection of run %s: %v", run.ID, err)
+		} else {
+			latest := models.RepositoryMetricsSnapshot{TechnicalDebtHours: run.TotalTechnicalDebtHours}
+			run.DebtSpike = models.DetectDebtSpike(latest, prior, s.DebtSpikeSigma).Spike
+		}
+	}
+
 	return &run, nil
 }
 
+// getPriorSnapshots returns the repository's most recent snapshots from the
+// days before startedAt, newest first. The snapshot of the run's own day is
+// left out since it may already include the run.
+func (s *DBAnalysisRunStore) getPriorSnapshots(repositoryID uuid.UUID, startedAt time.Time) ([]models.RepositoryMetricsSnapshot, error) {
+	rows, err := s.db.Query(`
+		SELECT snapshot_date, technical_debt_hours
+		FROM repository_metrics_snapshots
+		WHERE repository_id = $1 AND snapshot_date < $2::date
+		ORDER BY snapshot_date DESC
+		LIMIT $3
+	`, repositoryID, startedAt, debtSpikeWindow)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []models.RepositoryMetricsSnapshot
+	for rows.Next() {
+		var snap models.RepositoryMetricsSnapshot
+		if err := rows.Scan(&snap.SnapshotDate, &snap.TechnicalDebtHours); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
 func (s *DBAnalysisRunStore) List(userID string, status string, limit, offset int) ([]models.AnalysisRun, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
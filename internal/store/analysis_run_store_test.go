@@ -0,0 +1,70 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runQuery answers the queries of DBAnalysisRunStore.Get for a completed run
+// with debtHours of debt, after the daily snapshots in history. A nil history
+// fails the snapshot query.
+func runQuery(runID uuid.UUID, debtHours float64, history []float64) func(string, []driver.Value) (*fakeRows, error) {
+	startedAt := time.Date(2026, 5, 20, 10, 0, 0, 0, time.UTC)
+	return func(query string, args []driver.Value) (*fakeRows, error) {
+		switch {
+		case strings.Contains(query, "FROM repository_metrics_snapshots"):
+			if history == nil {
+				return nil, fmt.Errorf("relation \"repository_metrics_snapshots\" does not exist")
+			}
+			rows := &fakeRows{columns: []string{"snapshot_date", "technical_debt_hours"}}
+			for i, hours := range history {
+				rows.values = append(rows.values, []driver.Value{startedAt.AddDate(0, 0, -1-i), hours})
+			}
+			return rows, nil
+		case strings.Contains(query, "WHERE id = $1"):
+			return &fakeRows{columns: make([]string, 24), values: [][]driver.Value{{
+				runID.String(), uuid.New().String(), uuid.New().String(), uuid.New().String(), "full", nil,
+				startedAt, nil, nil, "completed", nil,
+				int64(12), int64(1), int64(2), int64(4), int64(5),
+				debtHours, 0.0, 0.0,
+				nil, nil, nil, startedAt, startedAt,
+			}}}, nil
+		}
+		// No previous run.
+		return &fakeRows{columns: []string{"total_technical_debt_hours", "critical_issues_count"}}, nil
+	}
+}
+
+func TestAnalysisRunStore_DebtSpike(t *testing.T) {
+	// Mean 100, standard deviation 10.
+	history := []float64{90, 110, 90, 110}
+	tests := []struct {
+		name    string
+		sigma   float64
+		history []float64
+		want    bool
+	}{
+		{"default sigma", 0, history, true},
+		{"configured sigma", 3, history, false},
+		{"snapshots unavailable", 0, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runID := uuid.New()
+			s := NewDBAnalysisRunStore(openFakeDB(t, &fakeDB{query: runQuery(runID, 125, tt.history)}), tt.sigma)
+
+			run, err := s.Get(runID.String())
+			require.NoError(t, err, "a failed spike check still returns the run")
+			assert.Equal(t, runID, run.ID)
+			assert.Equal(t, tt.want, run.DebtSpike)
+		})
+	}
+}
@@ -16,6 +16,9 @@ type ComplexityStoreInterface interface {
 	GetByRepository(ctx context.Context, repositoryID uuid.UUID, filters ComplexityFilters) ([]models.ComplexityMetric, error)
 	GetFileSummary(ctx context.Context, analysisRunID uuid.UUID, filePath string) (*models.FileComplexitySummary, error)
 	GetRepositorySummary(ctx context.Context, analysisRunID uuid.UUID) (*models.RepositoryComplexitySummary, error)
+	GetLanguageBreakdown(ctx context.Context, analysisRunID uuid.UUID) ([]models.LanguageComplexityBreakdown, error)
+	GetFunctionHistory(ctx context.Context, repositoryID uuid.UUID, filePath, functionName string, limit int) ([]models.ComplexityMetric, error)
+	GetSuggestionsByType(ctx context.Context, repositoryID uuid.UUID, suggestionType string, limit int) ([]models.SuggestionHit, error)
 }
 
 type ComplexityStore struct {
@@ -319,6 +322,184 @@ func (s *ComplexityStore) GetRepositorySummary(ctx context.Context, analysisRunI
 	return &summary, nil
 }
 
+// GetLanguageBreakdown groups the run's complexity metrics by language, most
+// indebted language first. Metrics stored before the language was recorded are
+// grouped under "unknown".
+func (s *ComplexityStore) GetLanguageBreakdown(ctx context.Context, analysisRunID uuid.UUID) ([]models.LanguageComplexityBreakdown, error) {
+	query := `
+		SELECT
+			COALESCE(NULLIF(language, ''), 'unknown') AS lang,
+			COUNT(*),
+			COALESCE(AVG(cyclomatic_complexity), 0),
+			COALESCE(MAX(cyclomatic_complexity), 0),
+			COALESCE(SUM(lines_of_code), 0),
+			COALESCE(SUM(technical_debt_minutes), 0)
+		FROM complexity_metrics
+		WHERE analysis_run_id = $1
+		GROUP BY lang
+		ORDER BY SUM(technical_debt_minutes) DESC, lang ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, analysisRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	var breakdown []models.LanguageComplexityBreakdown
+	for rows.Next() {
+		var b models.LanguageComplexityBreakdown
+		if err := rows.Scan(
+			&b.Language, &b.FunctionCount,
+			&b.AvgCyclomaticComplexity, &b.MaxCyclomaticComplexity,
+			&b.TotalLinesOfCode, &b.TotalTechnicalDebtMinutes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan language breakdown: %w", err)
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	return breakdown, rows.Err()
+}
+
+// GetFunctionHistory returns one function's metrics across the repository's
+// runs, oldest run first, so its complexity can be charted over time. With a
+// positive limit only the most recent limit runs are returned. Functions are
+// matched by file path and name, so a renamed or moved function starts a new,
+// separate history and an unknown one yields an empty result.
+func (s *ComplexityStore) GetFunctionHistory(ctx context.Context, repositoryID uuid.UUID, filePath, functionName string, limit int) ([]models.ComplexityMetric, error) {
+	query := `
+		SELECT
+			id, user_id, repository_id, analysis_run_id,
+			file_path, function_name, start_line, end_line, start_column, end_column,
+			cyclomatic_complexity, cognitive_complexity, nesting_depth, parameter_count, lines_of_code,
+			halstead_volume, halstead_difficulty, halstead_effort, halstead_time, halstead_bugs,
+			severity, complexity_category, technical_debt_minutes,
+			code_snippet, refactoring_suggestions, language, metadata,
+			created_at, updated_at
+		FROM (
+			SELECT cm.*, ar.started_at AS run_started_at
+			FROM complexity_metrics cm
+			INNER JOIN analysis_runs ar ON cm.analysis_run_id = ar.id
+			WHERE cm.repository_id = $1 AND cm.file_path = $2 AND cm.function_name = $3
+			ORDER BY ar.started_at DESC, cm.start_line
+	`
+	args := []interface{}{repositoryID, filePath, functionName}
+	if limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, limit)
+	}
+	query += `
+		) history
+		ORDER BY run_started_at ASC, start_line
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query function history: %w", err)
+	}
+	defer rows.Close()
+
+	metrics := []models.ComplexityMetric{}
+	for rows.Next() {
+		var metric models.ComplexityMetric
+		var suggestionsJSON []byte
+
+		err := rows.Scan(
+			&metric.ID, &metric.UserID, &metric.RepositoryID, &metric.AnalysisRunID,
+			&metric.FilePath, &metric.FunctionName, &metric.StartLine, &metric.EndLine, &metric.StartColumn, &metric.EndColumn,
+			&metric.CyclomaticComplexity, &metric.CognitiveComplexity, &metric.NestingDepth, &metric.ParameterCount, &metric.LinesOfCode,
+			&metric.HalsteadVolume, &metric.HalsteadDifficulty, &metric.HalsteadEffort, &metric.HalsteadTime, &metric.HalsteadBugs,
+			&metric.Severity, &metric.ComplexityCategory, &metric.TechnicalDebtMinutes,
+			&metric.CodeSnippet, &suggestionsJSON, &metric.Language, &metric.Metadata,
+			&metric.CreatedAt, &metric.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metric: %w", err)
+		}
+
+		if len(suggestionsJSON) > 0 {
+			var suggestions []models.RefactoringSuggestion
+			if err := json.Unmarshal(suggestionsJSON, &suggestions); err == nil {
+				metric.RefactoringSuggestions = suggestions
+			}
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// GetSuggestionsByType lists the refactoring suggestions of suggestionType,
+// e.g. "extract_method", made for the repository's functions in the latest
+// run that analyzed each of them, high priority first. With a positive limit
+// at most limit hits are returned. Metrics stored without suggestions, whose
+// column is NULL or a JSON null, contribute nothing.
+func (s *ComplexityStore) GetSuggestionsByType(ctx context.Context, repositoryID uuid.UUID, suggestionType string, limit int) ([]models.SuggestionHit, error) {
+	query := `
+		SELECT
+			latest.id, latest.analysis_run_id, latest.file_path, latest.function_name,
+			latest.start_line, latest.cyclomatic_complexity,
+			suggestion->>'type', COALESCE(suggestion->>'priority', ''),
+			COALESCE(suggestion->>'title', ''), COALESCE(suggestion->>'reason', '')
+		FROM (
+			SELECT DISTINCT ON (cm.file_path, cm.function_name) cm.*
+			FROM complexity_metrics cm
+			INNER JOIN analysis_runs ar ON cm.analysis_run_id = ar.id
+			WHERE cm.repository_id = $1
+			ORDER BY cm.file_path, cm.function_name, ar.started_at DESC
+		) latest
+		CROSS JOIN LATERAL jsonb_array_elements(
+			CASE WHEN jsonb_typeof(latest.refactoring_suggestions::jsonb) = 'array'
+				THEN latest.refactoring_suggestions::jsonb
+				ELSE '[]'::jsonb
+			END
+		) AS suggestion
+		WHERE suggestion->>'type' = $2
+		ORDER BY
+			CASE suggestion->>'priority'
+				WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3
+				ELSE 4
+			END,
+			latest.cyclomatic_complexity DESC, latest.file_path, latest.start_line
+	`
+	args := []interface{}{repositoryID, suggestionType}
+	if limit > 0 {
+		query += ` LIMIT $3`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query refactoring suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []models.SuggestionHit{}
+	for rows.Next() {
+		var hit models.SuggestionHit
+		if err := rows.Scan(
+			&hit.MetricID, &hit.AnalysisRunID, &hit.FilePath, &hit.FunctionName,
+			&hit.StartLine, &hit.CyclomaticComplexity,
+			&hit.Type, &hit.Priority, &hit.Title, &hit.Reason,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan refactoring suggestion: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating refactoring suggestions: %w", err)
+	}
+
+	return hits, nil
+}
+
 type ComplexityFilters struct {
 	Severity      string
 	MinComplexity int
@@ -20,6 +20,7 @@ type ConfigStoreInterface interface {
 	Delete(id string) error
 	GetUserPersonalOrganizationID(userID string) (uuid.UUID, error)
 	MarkAsConnected(id string) error
+	MarkTokenRefreshed(id string, newToken string, expiresAt time.Time) error
 	GetByProvider(organizationID string, provider string) (*models.UserConfiguration, error)
 	ListAll() ([]*models.UserConfiguration, error)
 }
@@ -44,9 +45,10 @@ func (s *DBConfigStore) Create(config *models.UserConfiguration) error {
 		INSERT INTO user_configurations (
 			id, user_id, organization_id, organization_name, organization_url, platform_type,
 			access_token_encrypted, auto_sync_enabled, sync_frequency_minutes,
-			created_at, updated_at, metadata, is_connected, connected_at
+			created_at, updated_at, metadata, is_connected, connected_at,
+			repository_filters, excluded_repositories, included_repositories
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 		)
 	`
 
@@ -55,6 +57,7 @@ func (s *DBConfigStore) Create(config *models.UserConfiguration) error {
 		config.PlatformType, config.AccessTokenEncrypted, config.AutoSyncEnabled,
 		config.SyncFrequencyMinutes, config.CreatedAt, config.UpdatedAt, config.Metadata,
 		config.IsConnected, config.ConnectedAt,
+		config.RepositoryFilters, pq.Array(config.ExcludedRepositories), pq.Array(config.IncludedRepositories),
 	)
 
 	if err != nil {
@@ -82,7 +85,8 @@ func (s *DBConfigStore) Update(config *models.UserConfiguration) error {
 		    cyclomatic_complexity_threshold = $7, cognitive_complexity_threshold = $8,
 		    debt_cost_per_complexity_point = $9, updated_at = $10,
 		    organization_id = $11, is_connected = $12, connected_at = $13,
-		    metadata = $14
+		    metadata = $14, repository_filters = $15,
+		    excluded_repositories = $16, included_repositories = $17
 		WHERE id = $1
 	`
 
@@ -93,7 +97,8 @@ func (s *DBConfigStore) Update(config *models.UserConfiguration) error {
 		config.CyclomaticComplexityThreshold, config.CognitiveComplexityThreshold,
 		config.DebtCostPerComplexityPoint, config.UpdatedAt,
 		config.OrganizationID, config.IsConnected, config.ConnectedAt,
-		config.Metadata,
+		config.Metadata, config.RepositoryFilters,
+		pq.Array(config.ExcludedRepositories), pq.Array(config.IncludedRepositories),
 	)
 
 	if err != nil {
@@ -120,11 +125,13 @@ func (s *DBConfigStore) GetByID(id string) (*models.UserConfiguration, error) {
 
 	query := `
 		SELECT id, user_id, organization_id, organization_name, organization_url, platform_type,
-		       access_token_encrypted, auto_sync_enabled, sync_frequency_minutes,
+		       access_token_encrypted, refresh_token_encrypted, token_expires_at,
+		       auto_sync_enabled, sync_frequency_minutes,
 		       last_sync_at, next_sync_at,
 		       cyclomatic_complexity_threshold, cognitive_complexity_threshold, debt_cost_per_complexity_point,
 		       created_at, updated_at,
-		       is_connected, connected_at, metadata
+		       is_connected, connected_at, metadata,
+		       repository_filters, excluded_repositories, included_repositories
 		FROM user_configurations
 		WHERE id = $1
 	`
@@ -132,11 +139,13 @@ func (s *DBConfigStore) GetByID(id string) (*models.UserConfiguration, error) {
 	config := &models.UserConfiguration{}
 	err = s.db.QueryRow(query, configUUID).Scan(
 		&config.ID, &config.UserID, &config.OrganizationID, &config.OrganizationName, &config.OrganizationURL,
-		&config.PlatformType, &config.AccessTokenEncrypted, &config.AutoSyncEnabled,
+		&config.PlatformType, &config.AccessTokenEncrypted, &config.RefreshTokenEncrypted, &config.TokenExpiresAt,
+		&config.AutoSyncEnabled,
 		&config.SyncFrequencyMinutes, &config.LastSyncAt, &config.NextSyncAt,
 		&config.CyclomaticComplexityThreshold, &config.CognitiveComplexityThreshold, &config.DebtCostPerComplexityPoint,
 		&config.CreatedAt, &config.UpdatedAt,
 		&config.IsConnected, &config.ConnectedAt, &config.Metadata,
+		&config.RepositoryFilters, pq.Array(&config.ExcludedRepositories), pq.Array(&config.IncludedRepositories),
 	)
 
 	if err == sql.ErrNoRows {
@@ -161,11 +170,13 @@ func (s *DBConfigStore) ListByUserID(userID string) ([]*models.UserConfiguration
 
 	query := `
 		SELECT id, user_id, organization_id, organization_name, organization_url, platform_type,
-		       access_token_encrypted, auto_sync_enabled, sync_frequency_minutes,
+		       access_token_encrypted, refresh_token_encrypted, token_expires_at,
+		       auto_sync_enabled, sync_frequency_minutes,
 		       last_sync_at, next_sync_at,
 		       cyclomatic_complexity_threshold, cognitive_complexity_threshold, debt_cost_per_complexity_point,
 		       created_at, updated_at,
-		       is_connected, connected_at, metadata
+		       is_connected, connected_at, metadata,
+		       repository_filters, excluded_repositories, included_repositories
 		FROM user_configurations
 		WHERE user_id = $1
 		ORDER BY organization_name ASC
@@ -183,11 +194,13 @@ func (s *DBConfigStore) ListByUserID(userID string) ([]*models.UserConfiguration
 		config := &models.UserConfiguration{}
 		err := rows.Scan(
 			&config.ID, &config.UserID, &config.OrganizationID, &config.OrganizationName, &config.OrganizationURL,
-			&config.PlatformType, &config.AccessTokenEncrypted, &config.AutoSyncEnabled,
+			&config.PlatformType, &config.AccessTokenEncrypted, &config.RefreshTokenEncrypted, &config.TokenExpiresAt,
+			&config.AutoSyncEnabled,
 			&config.SyncFrequencyMinutes, &config.LastSyncAt, &config.NextSyncAt,
 			&config.CyclomaticComplexityThreshold, &config.CognitiveComplexityThreshold, &config.DebtCostPerComplexityPoint,
 			&config.CreatedAt, &config.UpdatedAt,
 			&config.IsConnected, &config.ConnectedAt, &config.Metadata,
+			&config.RepositoryFilters, pq.Array(&config.ExcludedRepositories), pq.Array(&config.IncludedRepositories),
 		)
 		if err != nil {
 			log.Printf("⚠️  [ConfigStore] Error scanning configuration: %v", err)
@@ -306,6 +319,37 @@ func (s *DBConfigStore) MarkAsConnected(id string) error {
 	return nil
 }
 
+// MarkTokenRefreshed stores a refreshed (already encrypted) access token and
+// its new expiry. The token itself is never logged.
+func (s *DBConfigStore) MarkTokenRefreshed(id string, newToken string, expiresAt time.Time) error {
+	log.Printf("🔵 [ConfigStore] Storing refreshed token for config: %s (expires %s)", id, expiresAt.Format(time.RFC3339))
+
+	configUUID, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE user_configurations
+		SET access_token_encrypted = $2, token_expires_at = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := s.db.Exec(query, configUUID, newToken, expiresAt, time.Now())
+	if err != nil {
+		log.Printf("❌ [ConfigStore] Failed to store refreshed token: %v", err)
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	log.Printf("✅ [ConfigStore] Refreshed token stored")
+	return nil
+}
+
 func (s *DBConfigStore) GetByProvider(organizationID string, provider string) (*models.UserConfiguration, error) {
 	log.Printf("🔍 [ConfigStore] Getting configuration by OrgID: %s, Provider: %s", organizationID, provider)
 
@@ -316,11 +360,13 @@ func (s *DBConfigStore) GetByProvider(organizationID string, provider string) (*
 
 	query := `
 		SELECT id, user_id, organization_id, organization_name, organization_url, platform_type,
-		       access_token_encrypted, auto_sync_enabled, sync_frequency_minutes,
+		       access_token_encrypted, refresh_token_encrypted, token_expires_at,
+		       auto_sync_enabled, sync_frequency_minutes,
 		       last_sync_at, next_sync_at,
 		       cyclomatic_complexity_threshold, cognitive_complexity_threshold, debt_cost_per_complexity_point,
 		       created_at, updated_at,
-		       is_connected, connected_at, metadata
+		       is_connected, connected_at, metadata,
+		       repository_filters, excluded_repositories, included_repositories
 		FROM user_configurations
 		WHERE organization_id = $1 AND platform_type = $2
 		LIMIT 1
@@ -329,11 +375,13 @@ func (s *DBConfigStore) GetByProvider(organizationID string, provider string) (*
 	config := &models.UserConfiguration{}
 	err = s.db.QueryRow(query, orgUUID, provider).Scan(
 		&config.ID, &config.UserID, &config.OrganizationID, &config.OrganizationName, &config.OrganizationURL,
-		&config.PlatformType, &config.AccessTokenEncrypted, &config.AutoSyncEnabled,
+		&config.PlatformType, &config.AccessTokenEncrypted, &config.RefreshTokenEncrypted, &config.TokenExpiresAt,
+		&config.AutoSyncEnabled,
 		&config.SyncFrequencyMinutes, &config.LastSyncAt, &config.NextSyncAt,
 		&config.CyclomaticComplexityThreshold, &config.CognitiveComplexityThreshold, &config.DebtCostPerComplexityPoint,
 		&config.CreatedAt, &config.UpdatedAt,
 		&config.IsConnected, &config.ConnectedAt, &config.Metadata,
+		&config.RepositoryFilters, pq.Array(&config.ExcludedRepositories), pq.Array(&config.IncludedRepositories),
 	)
 
 	if err == sql.ErrNoRows {
@@ -353,11 +401,13 @@ func (s *DBConfigStore) ListAll() ([]*models.UserConfiguration, error) {
 
 	query := `
 		SELECT id, user_id, organization_id, organization_name, organization_url, platform_type,
-		       access_token_encrypted, auto_sync_enabled, sync_frequency_minutes,
+		       access_token_encrypted, refresh_token_encrypted, token_expires_at,
+		       auto_sync_enabled, sync_frequency_minutes,
 		       last_sync_at, next_sync_at,
 		       cyclomatic_complexity_threshold, cognitive_complexity_threshold, debt_cost_per_complexity_point,
 		       created_at, updated_at,
-		       is_connected, connected_at, metadata
+		       is_connected, connected_at, metadata,
+		       repository_filters, excluded_repositories, included_repositories
 		FROM user_configurations
 		ORDER BY created_at DESC
 	`
@@ -374,11 +424,13 @@ func (s *DBConfigStore) ListAll() ([]*models.UserConfiguration, error) {
 		config := &models.UserConfiguration{}
 		err := rows.Scan(
 			&config.ID, &config.UserID, &config.OrganizationID, &config.OrganizationName, &config.OrganizationURL,
-			&config.PlatformType, &config.AccessTokenEncrypted, &config.AutoSyncEnabled,
+			&config.PlatformType, &config.AccessTokenEncrypted, &config.RefreshTokenEncrypted, &config.TokenExpiresAt,
+			&config.AutoSyncEnabled,
 			&config.SyncFrequencyMinutes, &config.LastSyncAt, &config.NextSyncAt,
 			&config.CyclomaticComplexityThreshold, &config.CognitiveComplexityThreshold, &config.DebtCostPerComplexityPoint,
 			&config.CreatedAt, &config.UpdatedAt,
 			&config.IsConnected, &config.ConnectedAt, &config.Metadata,
+			&config.RepositoryFilters, pq.Array(&config.ExcludedRepositories), pq.Array(&config.IncludedRepositories),
 		)
 		if err != nil {
 			log.Printf("⚠️  [ConfigStore] Error scanning configuration: %v", err)
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConfigTable keeps user_configurations rows in memory for a fakeDB. It
+// understands just the statement shapes DBConfigStore sends.
+type fakeConfigTable struct {
+	rows []map[string]driver.Value
+}
+
+var (
+	insertColumnsRe = regexp.MustCompile(`(?s)INSERT INTO\s+\w+\s*\((.*?)\)`)
+	selectColumnsRe = regexp.MustCompile(`(?s)SELECT\s+(.*?)\s+FROM`)
+	assignmentRe    = regexp.MustCompile(`(\w+) = \$(\d+)`)
+)
+
+// configColumnDefaults stands in for the column defaults of the schema.
+var configColumnDefaults = map[string]driver.Value{
+	"cyclomatic_complexity_threshold": int64(10),
+	"cognitive_complexity_threshold":  int64(15),
+	"debt_cost_per_complexity_point":  int64(30),
+}
+
+func newFakeConfigStore(t *testing.T) *DBConfigStore {
+	table := &fakeConfigTable{}
+	return NewDBConfigStore(openFakeDB(t, &fakeDB{query: table.query, exec: table.exec}))
+}
+
+// assignments maps the columns of "col = $n" pairs in clause to their
+// arguments.
+func assignments(clause string, args []driver.Value) map[string]driver.Value {
+	values := map[string]driver.Value{}
+	for _, m := range assignmentRe.FindAllStringSubmatch(clause, -1) {
+		n, _ := strconv.Atoi(m[2])
+		values[m[1]] = args[n-1]
+	}
+	return values
+}
+
+func splitColumns(list string) []string {
+	columns := strings.Split(list, ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+	return columns
+}
+
+func (t *fakeConfigTable) matching(where map[string]driver.Value) []map[string]driver.Value {
+	var rows []map[string]driver.Value
+	for _, row := range t.rows {
+		matched := true
+		for column, value := range where {
+			if fmt.Sprint(row[column]) != fmt.Sprint(value) {
+				matched = false
+			}
+		}
+		if matched {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (t *fakeConfigTable) exec(query string, args []driver.Value) (driver.Result, error) {
+	switch {
+	case strings.Contains(query, "INSERT INTO"):
+		row := map[string]driver.Value{}
+		for column, value := range configColumnDefaults {
+			row[column] = value
+		}
+		for i, column := range splitColumns(insertColumnsRe.FindStringSubmatch(query)[1]) {
+			row[column] = args[i]
+		}
+		t.rows = append(t.rows, row)
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "UPDATE"):
+		set, where, _ := strings.Cut(query, "WHERE")
+		rows := t.matching(assignments(where, args))
+		for _, row := range rows {
+			for column, value := range assignments(set, args) {
+				row[column] = value
+			}
+		}
+		return driver.RowsAffected(len(rows)), nil
+	}
+	return nil, fmt.Errorf("unsupported statement: %s", query)
+}
+
+func (t *fakeConfigTable) query(query string, args []driver.Value) (*fakeRows, error) {
+	columns := splitColumns(selectColumnsRe.FindStringSubmatch(query)[1])
+	where := map[string]driver.Value{}
+	if _, clause, ok := strings.Cut(query, "WHERE"); ok {
+		where = assignments(clause, args)
+	}
+	var values [][]driver.Value
+	for _, row := range t.matching(where) {
+		record := make([]driver.Value, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		values = append(values, record)
+	}
+	return &fakeRows{columns: columns, values: values}, nil
+}
+
+func TestConfigStore_RepositoryFiltersRoundTrip(t *testing.T) {
+	s := newFakeConfigStore(t)
+
+	filters := `{"topics":["backend"]}`
+	orgID := uuid.New()
+	config := &models.UserConfiguration{
+		UserID:               uuid.New(),
+		OrganizationID:       &orgID,
+		OrganizationName:     "acme",
+		PlatformType:         "github",
+		RepositoryFilters:    &filters,
+		ExcludedRepositories: []string{"acme/legacy", "acme/docs, archived"},
+		IncludedRepositories: []string{"acme/api"},
+	}
+	require.NoError(t, s.Create(config))
+
+	got, err := s.GetByID(config.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/legacy", "acme/docs, archived"}, got.ExcludedRepositories)
+	assert.Equal(t, []string{"acme/api"}, got.IncludedRepositories)
+	require.NotNil(t, got.RepositoryFilters)
+	assert.Equal(t, filters, *got.RepositoryFilters)
+
+	got.ExcludedRepositories = []string{"acme/sandbox"}
+	got.IncludedRepositories = nil
+	got.RepositoryFilters = nil
+	require.NoError(t, s.Update(got))
+
+	updated, err := s.GetByID(config.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/sandbox"}, updated.ExcludedRepositories)
+	assert.Empty(t, updated.IncludedRepositories)
+	assert.Nil(t, updated.RepositoryFilters)
+
+	listed, err := s.ListByUserID(config.UserID.String())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, []string{"acme/sandbox"}, listed[0].ExcludedRepositories)
+
+	byProvider, err := s.GetByProvider(orgID.String(), "github")
+	require.NoError(t, err)
+	require.NotNil(t, byProvider)
+	assert.Equal(t, []string{"acme/sandbox"}, byProvider.ExcludedRepositories)
+
+	all, err := s.ListAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, []string{"acme/sandbox"}, all[0].ExcludedRepositories)
+}
+
+func TestConfigStore_MarkTokenRefreshed(t *testing.T) {
+	s := newFakeConfigStore(t)
+
+	config := &models.UserConfiguration{UserID: uuid.New(), OrganizationName: "acme", PlatformType: "gitlab"}
+	require.NoError(t, s.Create(config))
+
+	got, err := s.GetByID(config.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, got.TokenExpiresAt)
+	assert.False(t, got.IsTokenExpired())
+
+	expired := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, s.MarkTokenRefreshed(config.ID.String(), "old-token", expired))
+	got, err = s.GetByID(config.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, got.TokenExpiresAt)
+	assert.True(t, got.TokenExpiresAt.Equal(expired))
+	assert.True(t, got.IsTokenExpired())
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, s.MarkTokenRefreshed(config.ID.String(), "new-token", expiresAt))
+	listed, err := s.ListByUserID(config.UserID.String())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.NotNil(t, listed[0].AccessTokenEncrypted)
+	assert.Equal(t, "new-token", *listed[0].AccessTokenEncrypted)
+	assert.False(t, listed[0].IsTokenExpired())
+
+	assert.ErrorIs(t, s.MarkTokenRefreshed(uuid.New().String(), "t", expiresAt), ErrUserNotFound)
+	assert.Error(t, s.MarkTokenRefreshed("not-a-uuid", "t", expiresAt))
+}
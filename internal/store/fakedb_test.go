@@ -0,0 +1,173 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB is a database/sql driver for the store tests. It records the
+// statements and transactions a store sends and answers them with the query
+// and exec hooks, so each test only describes the statement shapes it cares
+// about. Values go through the same driver.Valuer and sql.Scanner conversions
+// (pq.Array included) a real connection would apply.
+type fakeDB struct {
+	// query answers a query; when nil, queries return no rows.
+	query func(query string, args []driver.Value) (*fakeRows, error)
+	// exec answers any other statement; when nil, it affects one row.
+	exec func(query string, args []driver.Value) (driver.Result, error)
+	// latency is waited out on each round trip, standing in for the network.
+	latency time.Duration
+
+	mu        sync.Mutex
+	queries   []fakeStatement
+	execs     []fakeStatement
+	commits   int
+	rollbacks int
+}
+
+// fakeStatement is a statement received by a fakeDB.
+type fakeStatement struct {
+	query string
+	args  []driver.Value
+}
+
+var fakeDBs sync.Map
+
+func init() {
+	sql.Register("fakedb", fakeConnector{})
+}
+
+// openFakeDB returns a database answered by d until the test ends.
+func openFakeDB(tb testing.TB, d *fakeDB) *sql.DB {
+	fakeDBs.Store(tb.Name(), d)
+	db, err := sql.Open("fakedb", tb.Name())
+	require.NoError(tb, err)
+	tb.Cleanup(func() {
+		db.Close()
+		fakeDBs.Delete(tb.Name())
+	})
+	return db
+}
+
+// statements returns the statements sent with Exec whose query contains
+// keyword.
+func (d *fakeDB) statements(keyword string) []fakeStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var matched []fakeStatement
+	for _, exec := range d.execs {
+		if strings.Contains(exec.query, keyword) {
+			matched = append(matched, exec)
+		}
+	}
+	return matched
+}
+
+// roundTrip waits out the simulated latency. It spins since time.Sleep
+// rounds short waits up to the timer resolution.
+func (d *fakeDB) roundTrip() {
+	for deadline := time.Now().Add(d.latency); time.Now().Before(deadline); {
+	}
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Open(name string) (driver.Conn, error) {
+	d, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("no fake database %q", name)
+	}
+	return &fakeConn{db: d.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{c.db}, nil }
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.commits++
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.db
+	d.roundTrip()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.execs = append(d.execs, fakeStatement{query: s.query, args: args})
+	if d.exec == nil {
+		return driver.RowsAffected(1), nil
+	}
+	return d.exec(s.query, args)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.db
+	d.roundTrip()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, fakeStatement{query: s.query, args: args})
+	if d.query == nil {
+		return &fakeRows{}, nil
+	}
+	rows, err := d.query(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// fakeRows returns values, one row per entry, under columns.
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
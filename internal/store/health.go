@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// requiredTables are the tables the DB-backed stores query directly.
+var requiredTables = []string{
+	"users",
+	"user_configurations",
+	"user_repositories",
+	"user_sessions",
+	"analysis_runs",
+	"technical_debt_issues",
+	"issue_comments",
+	"issue_activity_log",
+	"complexity_metrics",
+}
+
+// requiredFunctions are the stored procedures created by the migrations that
+// the stores and the metrics pipeline call.
+var requiredFunctions = []string{
+	"create_metrics_snapshot",
+	"calculate_issue_trends",
+	"cleanup_expired_metrics_cache",
+}
+
+// HealthCheck pings the database and verifies that every table and function
+// the stores depend on exists. It should be called once at startup, before any
+// job is accepted, so migration drift surfaces as a single actionable error
+// instead of a cryptic failure deep inside the first analysis.
+func HealthCheck(ctx context.Context, db *sql.DB) error {
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+
+	missingTables, err := findMissingTables(ctx, db)
+	if err != nil {
+		return err
+	}
+	missingFunctions, err := findMissingFunctions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if len(missingTables) == 0 && len(missingFunctions) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(missingTables) > 0 {
+		problems = append(problems, "tables: "+strings.Join(missingTables, ", "))
+	}
+	if len(missingFunctions) > 0 {
+		problems = append(problems, "functions: "+strings.Join(missingFunctions, ", "))
+	}
+	return fmt.Errorf("database schema is missing required objects (%s); run the pending migrations", strings.Join(problems, "; "))
+}
+
+func findMissingTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	query := `
+		SELECT t.name
+		FROM unnest($1::text[]) AS t(name)
+		WHERE to_regclass(t.name) IS NULL
+		ORDER BY t.name
+	`
+	return queryNames(ctx, db, query, requiredTables, "tables")
+}
+
+func findMissingFunctions(ctx context.Context, db *sql.DB) ([]string, error) {
+	query := `
+		SELECT f.name
+		FROM unnest($1::text[]) AS f(name)
+		WHERE NOT EXISTS (SELECT 1 FROM pg_proc p WHERE p.proname = f.name)
+		ORDER BY f.name
+	`
+	return queryNames(ctx, db, query, requiredFunctions, "functions")
+}
+
+func queryNames(ctx context.Context, db *sql.DB, query string, names []string, kind string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check required %s: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan missing %s: %w", kind, err)
+		}
+		missing = append(missing, name)
+	}
+	return missing, rows.Err()
+}
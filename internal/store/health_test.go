@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name             string
+		missingTables    []string
+		missingFunctions []string
+		wantErr          string
+	}{
+		{name: "migrated"},
+		{name: "missing table", missingTables: []string{"analysis_runs"}, wantErr: "tables: analysis_runs"},
+		{
+			name:             "missing tables and functions",
+			missingTables:    []string{"complexity_metrics", "issue_comments"},
+			missingFunctions: []string{"create_metrics_snapshot"},
+			wantErr:          "(tables: complexity_metrics, issue_comments; functions: create_metrics_snapshot); run the pending migrations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := func(names []string) (*fakeRows, error) {
+				result := &fakeRows{columns: []string{"name"}}
+				for _, name := range names {
+					result.values = append(result.values, []driver.Value{name})
+				}
+				return result, nil
+			}
+			db := openFakeDB(t, &fakeDB{query: func(query string, args []driver.Value) (*fakeRows, error) {
+				if strings.Contains(query, "to_regclass") {
+					return rows(tt.missingTables)
+				}
+				return rows(tt.missingFunctions)
+			}})
+
+			err := HealthCheck(context.Background(), db)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -18,6 +18,8 @@ type IssueActivityStoreInterface interface {
 	GetIssueTrends(repositoryID, issueType string, filePath *string) (*models.IssueTrends, error)
 	GetRelatedIssues(issueID string, limit int) ([]models.TechnicalDebtIssue, error)
 	CalculateTrends(repositoryID string) error
+	// ExplainIssue composes an issue with its activity, related issues and issue type trend.
+	ExplainIssue(issueID string) (*IssueExplanation, error)
 }
 
 type DBIssueActivityStore struct {
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// ErrIssueNotFound is returned when no stored issue has the requested ID.
+var ErrIssueNotFound = errors.New("issue not found")
+
+// explainRelatedLimit caps the related issues of an explanation.
+const explainRelatedLimit = 10
+
+// IssueExplanation is everything needed to review one stored issue: the
+// issue itself, its activity log, the issues related to it and the trend of
+// its issue type across the repository.
+type IssueExplanation struct {
+	Issue    models.TechnicalDebtIssue   `json:"issue"`
+	Activity []models.IssueActivityLog   `json:"activity"`
+	Related  []models.TechnicalDebtIssue `json:"related"`
+	Trends   *models.IssueTrends         `json:"trends"`
+}
+
+type issueGetter interface {
+	Get(id string) (*models.TechnicalDebtIssue, error)
+}
+
+type issueHistory interface {
+	GetActivityByIssueID(issueID string) ([]models.IssueActivityLog, error)
+	GetIssueTrends(repositoryID, issueType string, filePath *string) (*models.IssueTrends, error)
+	GetRelatedIssues(issueID string, limit int) ([]models.TechnicalDebtIssue, error)
+}
+
+// ExplainIssue gathers the explanation of an issue in one call. It returns
+// ErrIssueNotFound when the issue does not exist.
+func (s *DBIssueActivityStore) ExplainIssue(issueID string) (*IssueExplanation, error) {
+	return explainIssue(NewDBTechnicalDebtIssueStore(s.db), s, issueID)
+}
+
+func explainIssue(issues issueGetter, history issueHistory, issueID string) (*IssueExplanation, error) {
+	if _, err := uuid.Parse(issueID); err != nil {
+		return nil, fmt.Errorf("invalid issue ID %q: %w", issueID, err)
+	}
+
+	issue, err := issues.Get(issueID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && issue == nil) {
+		return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, issueID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", issueID, err)
+	}
+
+	activity, err := history.GetActivityByIssueID(issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity for issue %s: %w", issueID, err)
+	}
+	related, err := history.GetRelatedIssues(issueID, explainRelatedLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues related to %s: %w", issueID, err)
+	}
+	trends, err := history.GetIssueTrends(issue.RepositoryID.String(), issue.IssueType, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s trends for issue %s: %w", issue.IssueType, issueID, err)
+	}
+
+	return &IssueExplanation{Issue: *issue, Activity: activity, Related: related, Trends: trends}, nil
+}
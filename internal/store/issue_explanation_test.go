@@ -0,0 +1,83 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIssueGetter map[string]models.TechnicalDebtIssue
+
+func (f fakeIssueGetter) Get(id string) (*models.TechnicalDebtIssue, error) {
+	issue, ok := f[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &issue, nil
+}
+
+type fakeIssueHistory struct {
+	activity     []models.IssueActivityLog
+	related      []models.TechnicalDebtIssue
+	relatedErr   error
+	relatedLimit int
+	trendsFor    string
+}
+
+func (f *fakeIssueHistory) GetActivityByIssueID(issueID string) ([]models.IssueActivityLog, error) {
+	return f.activity, nil
+}
+
+func (f *fakeIssueHistory) GetIssueTrends(repositoryID, issueType string, filePath *string) (*models.IssueTrends, error) {
+	f.trendsFor = repositoryID + "/" + issueType
+	return &models.IssueTrends{IssueType: issueType, FilePath: filePath, OpenCount: 4}, nil
+}
+
+func (f *fakeIssueHistory) GetRelatedIssues(issueID string, limit int) ([]models.TechnicalDebtIssue, error) {
+	f.relatedLimit = limit
+	return f.related, f.relatedErr
+}
+
+func TestExplainIssue(t *testing.T) {
+	id := uuid.New()
+	repositoryID := uuid.New()
+	issues := fakeIssueGetter{id.String(): {ID: id, RepositoryID: repositoryID, IssueType: "complexity"}}
+	history := &fakeIssueHistory{
+		activity: []models.IssueActivityLog{{ActivityType: "created"}, {ActivityType: "assigned"}},
+		related:  []models.TechnicalDebtIssue{{ID: uuid.New(), IssueType: "complexity"}},
+	}
+
+	explanation, err := explainIssue(issues, history, id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, explanation.Issue.ID)
+	assert.Len(t, explanation.Activity, 2)
+	assert.Len(t, explanation.Related, 1)
+	assert.Equal(t, explainRelatedLimit, history.relatedLimit)
+	require.NotNil(t, explanation.Trends)
+	assert.Nil(t, explanation.Trends.FilePath)
+	assert.Equal(t, repositoryID.String()+"/complexity", history.trendsFor)
+
+	t.Run("unknown issue", func(t *testing.T) {
+		missing := uuid.New().String()
+		_, err := explainIssue(issues, history, missing)
+		assert.ErrorIs(t, err, ErrIssueNotFound)
+		assert.ErrorContains(t, err, missing)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		_, err := explainIssue(issues, history, "not-a-uuid")
+		assert.ErrorContains(t, err, `invalid issue ID "not-a-uuid"`)
+		assert.NotErrorIs(t, err, ErrIssueNotFound)
+	})
+
+	t.Run("failures are wrapped", func(t *testing.T) {
+		history.relatedErr = errors.New("connection reset")
+		_, err := explainIssue(issues, history, id.String())
+		assert.ErrorContains(t, err, "failed to get issues related to "+id.String()+": connection reset")
+	})
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// exportPageSize is the number of issues ExportOpenIssuesCSV reads per query.
+const exportPageSize = 1000
+
+// exportColumns is the header row of ExportOpenIssuesCSV.
+var exportColumns = []string{
+	"id", "repository", "file_path", "line_number", "issue_type", "severity", "category",
+	"status", "message", "tool_name", "tool_rule_id", "technical_debt_hours", "created_at",
+}
+
+// ExportOpenIssuesCSV writes every issue that is neither resolved nor ignored
+// in the repositories of the organizations userID belongs to as RFC 4180 CSV,
+// with the repository's full name in place of its ID. An organization can
+// hold hundreds of thousands of issues, so they are read in pages ordered by
+// ID, each starting after the last ID of the one before, and each page is
+// flushed to w before the next is read.
+func (s *DBTechnicalDebtIssueStore) ExportOpenIssuesCSV(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	return s.exportOpenIssuesCSV(ctx, userID, w, exportPageSize)
+}
+
+func (s *DBTechnicalDebtIssueStore) exportOpenIssuesCSV(ctx context.Context, userID uuid.UUID, w io.Writer, pageSize int) error {
+	out := csv.NewWriter(w)
+	out.UseCRLF = true
+	if err := out.Write(exportColumns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	after := uuid.Nil
+	for {
+		n, last, err := s.exportIssuePage(ctx, userID, after, pageSize, out)
+		if err != nil {
+			return err
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		if n < pageSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// exportIssuePage writes the open issues whose ID follows after, at most
+// limit of them, and returns how many it wrote and the ID of the last one.
+func (s *DBTechnicalDebtIssueStore) exportIssuePage(ctx context.Context, userID, after uuid.UUID, limit int, out *csv.Writer) (int, uuid.UUID, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			i.id, COALESCE(r.full_name, r.name, ''), i.file_path, i.line_number,
+			i.issue_type, i.severity, i.category, i.status, i.message,
+			i.tool_name, i.tool_rule_id, i.technical_debt_hours, i.created_at
+		FROM technical_debt_issues i
+		LEFT JOIN user_repositories r ON i.repository_id = r.id
+		WHERE EXISTS (
+			SELECT 1 FROM user_repositories ur
+			JOIN organization_members om ON ur.organization_id = om.organization_id
+			WHERE ur.id = i.repository_id AND om.user_id = $1
+		)
+		AND i.status <> ALL($2)
+		AND i.id > $3
+		ORDER BY i.id
+		LIMIT $4
+	`, userID, pq.Array(closedIssueStatuses), after, limit)
+	if err != nil {
+		return 0, after, fmt.Errorf("failed to export issues: %w", err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var (
+			id                                                  uuid.UUID
+			repository, filePath, issueType, severity, category string
+			status, message, toolName                           string
+			lineNumber                                          sql.NullInt64
+			toolRuleID                                          sql.NullString
+			debtHours                                           float64
+			createdAt                                           time.Time
+		)
+		if err := rows.Scan(&id, &repository, &filePath, &lineNumber, &issueType, &severity, &category,
+			&status, &message, &toolName, &toolRuleID, &debtHours, &createdAt); err != nil {
+			return n, after, fmt.Errorf("failed to scan exported issue: %w", err)
+		}
+
+		line := ""
+		if lineNumber.Valid {
+			line = strconv.FormatInt(lineNumber.Int64, 10)
+		}
+		record := []string{
+			id.String(), repository, filePath, line, issueType, severity, category,
+			status, message, toolName, toolRuleID.String,
+			strconv.FormatFloat(debtHours, 'f', -1, 64), createdAt.UTC().Format(time.RFC3339),
+		}
+		if err := out.Write(record); err != nil {
+			return n, after, fmt.Errorf("failed to write CSV: %w", err)
+		}
+		n++
+		after = id
+	}
+	if err := rows.Err(); err != nil {
+		return n, after, fmt.Errorf("failed to export issues: %w", err)
+	}
+	return n, after, nil
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportQuery answers the keyset queries of ExportOpenIssuesCSV from rows,
+// which it sorts by ID, and records how many rows each returned in pages.
+// The queries take the user ID, the closed statuses, the ID to start after
+// and the page size.
+func exportQuery(rows [][]driver.Value, pages *[]int) func(string, []driver.Value) (*fakeRows, error) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0].(string) < rows[j][0].(string) })
+	return func(_ string, args []driver.Value) (*fakeRows, error) {
+		after, limit := args[2].(string), int(args[3].(int64))
+
+		var values [][]driver.Value
+		for _, row := range rows {
+			if row[0].(string) > after && len(values) < limit {
+				values = append(values, row)
+			}
+		}
+		*pages = append(*pages, len(values))
+		return &fakeRows{columns: exportColumns, values: values}, nil
+	}
+}
+
+func exportRow(repository, filePath string, line interface{}, severity, message string, rule interface{}) []driver.Value {
+	return []driver.Value{
+		uuid.New().String(), repository, filePath, line, "complexity", severity, "maintainability",
+		"open", message, "complexity_analyzer", rule, 0.5, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestExportOpenIssuesCSV(t *testing.T) {
+	var pages []int
+	s := newFakeIssueStore(t, &fakeDB{query: exportQuery([][]driver.Value{
+		exportRow("acme/api", "/main.go", int64(12), "high", "Function 'run' has high cyclomatic complexity of 14 (threshold: 10)", "cyclomatic"),
+		exportRow("acme/api", "/util.go", nil, "low", "File defines 32 functions", nil),
+		exportRow("acme/web", "/app.ts", int64(3), "medium", "Message with \"quotes\", a comma\nand a newline", nil),
+		exportRow("acme/web", "/index.ts", int64(1), "critical", "Function 'render' has deep nesting", nil),
+		exportRow("acme/cli", "/cmd.go", int64(40), "high", "Function 'main' has complexity issues", nil),
+	}, &pages)})
+
+	var out strings.Builder
+	require.NoError(t, s.exportOpenIssuesCSV(context.Background(), uuid.New(), &out, 2))
+
+	assert.Equal(t, []int{2, 2, 1}, pages, "issues are read two at a time")
+	assert.True(t, strings.HasPrefix(out.String(), strings.Join(exportColumns, ",")+"\r\n"))
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	assert.Equal(t, exportColumns, records[0])
+
+	byPath := map[string][]string{}
+	for _, record := range records[1:] {
+		byPath[record[2]] = record
+	}
+	assert.Equal(t, []string{"acme/api", "/main.go", "12", "complexity", "high"}, byPath["/main.go"][1:6])
+	assert.Equal(t, "cyclomatic", byPath["/main.go"][10])
+	assert.Equal(t, "0.5", byPath["/main.go"][11])
+	assert.Equal(t, "2026-10-01T12:00:00Z", byPath["/main.go"][12])
+	assert.Equal(t, "", byPath["/util.go"][3], "an issue without a line leaves the column empty")
+	assert.Equal(t, "", byPath["/util.go"][10])
+	assert.Equal(t, "Message with \"quotes\", a comma\nand a newline", byPath["/app.ts"][8])
+}
+
+func TestExportOpenIssuesCSV_FullLastPage(t *testing.T) {
+	var pages []int
+	s := newFakeIssueStore(t, &fakeDB{query: exportQuery([][]driver.Value{
+		exportRow("acme/api", "/a.go", int64(1), "low", "a", nil),
+		exportRow("acme/api", "/b.go", int64(1), "low", "b", nil),
+	}, &pages)})
+
+	var out strings.Builder
+	require.NoError(t, s.exportOpenIssuesCSV(context.Background(), uuid.New(), &out, 2))
+	assert.Equal(t, []int{2, 0}, pages, "a full page is followed by one more query")
+	assert.Equal(t, 3, strings.Count(out.String(), "\r\n"))
+}
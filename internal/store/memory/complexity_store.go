@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sort"
 
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/endrilickollari/debtdrone-cli/internal/store"
@@ -48,3 +49,111 @@ func (s *InMemoryComplexityStore) GetFileSummary(ctx context.Context, analysisRu
 func (s *InMemoryComplexityStore) GetRepositorySummary(ctx context.Context, analysisRunID uuid.UUID) (*models.RepositoryComplexitySummary, error) {
 	return nil, nil
 }
+
+func (s *InMemoryComplexityStore) GetLanguageBreakdown(ctx context.Context, analysisRunID uuid.UUID) ([]models.LanguageComplexityBreakdown, error) {
+	byLanguage := map[string]*models.LanguageComplexityBreakdown{}
+	for _, m := range s.Metrics {
+		if m.AnalysisRunID != analysisRunID {
+			continue
+		}
+		language := m.Language
+		if language == "" {
+			language = "unknown"
+		}
+		b, ok := byLanguage[language]
+		if !ok {
+			b = &models.LanguageComplexityBreakdown{Language: language}
+			byLanguage[language] = b
+		}
+		b.AvgCyclomaticComplexity += float64(m.CyclomaticComplexity)
+		b.FunctionCount++
+		if m.CyclomaticComplexity > b.MaxCyclomaticComplexity {
+			b.MaxCyclomaticComplexity = m.CyclomaticComplexity
+		}
+		b.TotalLinesOfCode += m.LinesOfCode
+		b.TotalTechnicalDebtMinutes += m.TechnicalDebtMinutes
+	}
+
+	results := make([]models.LanguageComplexityBreakdown, 0, len(byLanguage))
+	for _, b := range byLanguage {
+		b.AvgCyclomaticComplexity /= float64(b.FunctionCount)
+		results = append(results, *b)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].TotalTechnicalDebtMinutes != results[j].TotalTechnicalDebtMinutes {
+			return results[i].TotalTechnicalDebtMinutes > results[j].TotalTechnicalDebtMinutes
+		}
+		return results[i].Language < results[j].Language
+	})
+	return results, nil
+}
+
+// GetFunctionHistory orders by CreatedAt since no run timestamps are kept in
+// memory.
+func (s *InMemoryComplexityStore) GetFunctionHistory(ctx context.Context, repositoryID uuid.UUID, filePath, functionName string, limit int) ([]models.ComplexityMetric, error) {
+	results := []models.ComplexityMetric{}
+	for _, m := range s.Metrics {
+		if m.RepositoryID == repositoryID && m.FilePath == filePath && m.FunctionName == functionName {
+			results = append(results, m)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.Before(results[j].CreatedAt)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[len(results)-limit:]
+	}
+	return results, nil
+}
+
+// GetSuggestionsByType keeps every matching suggestion since no run timestamps
+// are kept in memory to tell which metrics are the latest.
+func (s *InMemoryComplexityStore) GetSuggestionsByType(ctx context.Context, repositoryID uuid.UUID, suggestionType string, limit int) ([]models.SuggestionHit, error) {
+	rank := map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+	priorityRank := func(priority string) int {
+		if r, ok := rank[priority]; ok {
+			return r
+		}
+		return len(rank)
+	}
+
+	results := []models.SuggestionHit{}
+	for _, m := range s.Metrics {
+		if m.RepositoryID != repositoryID {
+			continue
+		}
+		for _, suggestion := range m.RefactoringSuggestions {
+			if suggestion.Type != suggestionType {
+				continue
+			}
+			results = append(results, models.SuggestionHit{
+				MetricID:             m.ID,
+				AnalysisRunID:        m.AnalysisRunID,
+				FilePath:             m.FilePath,
+				FunctionName:         m.FunctionName,
+				StartLine:            m.StartLine,
+				CyclomaticComplexity: m.CyclomaticComplexity,
+				Type:                 suggestion.Type,
+				Priority:             suggestion.Priority,
+				Title:                suggestion.Title,
+				Reason:               suggestion.Reason,
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if ri, rj := priorityRank(results[i].Priority), priorityRank(results[j].Priority); ri != rj {
+			return ri < rj
+		}
+		if results[i].CyclomaticComplexity != results[j].CyclomaticComplexity {
+			return results[i].CyclomaticComplexity > results[j].CyclomaticComplexity
+		}
+		if results[i].FilePath != results[j].FilePath {
+			return results[i].FilePath < results[j].FilePath
+		}
+		return results[i].StartLine < results[j].StartLine
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
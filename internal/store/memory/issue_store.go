@@ -75,6 +75,16 @@ func (s *InMemoryIssueStore) GetOpenIssueSummary(repositoryID uuid.UUID) (*store
 	return &store.OpenIssueSummary{}, nil
 }
 
+func (s *InMemoryIssueStore) CountOpenByRepository(repositoryID string) (map[string]int, error) {
+	counts := store.NewOpenSeverityCounts()
+	for _, issue := range s.Issues {
+		if issue.RepositoryID.String() == repositoryID && issue.Status == "open" {
+			counts[issue.Severity]++
+		}
+	}
+	return counts, nil
+}
+
 func (s *InMemoryIssueStore) ReconcileIssuesForAnalyzer(repositoryID uuid.UUID, analyzerName string, newIssues []models.TechnicalDebtIssue) (int, int, error) {
 	// In-memory implementation: simple clear and replace
 	deleted := 0
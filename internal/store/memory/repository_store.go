@@ -1,7 +1,10 @@
 package memory
 
 import (
+	"time"
+
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
 )
 
 type InMemoryRepositoryStore struct {
@@ -44,6 +47,15 @@ func (s *InMemoryRepositoryStore) GetByFullName(userID, fullName string) (*model
 	return nil, nil
 }
 
+func (s *InMemoryRepositoryStore) GetByURL(url string) (*models.UserRepository, error) {
+	for _, repo := range s.Repos {
+		if repo.URL == url {
+			return &repo, nil
+		}
+	}
+	return nil, nil
+}
+
 func (s *InMemoryRepositoryStore) ListByUserID(userID string) ([]*models.UserRepository, error) {
 	return []*models.UserRepository{}, nil
 }
@@ -56,6 +68,38 @@ func (s *InMemoryRepositoryStore) UpsertRepository(repo *models.UserRepository)
 	return s.Create(repo)
 }
 
+// BulkUpsert matches repos to the stored repositories by user and full name
+// like store.DBRepositoryStore.BulkUpsert, keeping the ID, AnalysisEnabled
+// and CreatedAt of those already stored.
+func (s *InMemoryRepositoryStore) BulkUpsert(repos []*models.UserRepository) (inserted, updated int, err error) {
+	now := time.Now()
+	for _, repo := range repos {
+		index := -1
+		for i, existing := range s.Repos {
+			if existing.UserID == repo.UserID && existing.FullName == repo.FullName {
+				index = i
+				break
+			}
+		}
+		repo.UpdatedAt = now
+		if index < 0 {
+			if repo.ID == uuid.Nil {
+				repo.ID = uuid.New()
+			}
+			repo.CreatedAt = now
+			s.Repos = append(s.Repos, *repo)
+			inserted++
+			continue
+		}
+		repo.ID = s.Repos[index].ID
+		repo.AnalysisEnabled = s.Repos[index].AnalysisEnabled
+		repo.CreatedAt = s.Repos[index].CreatedAt
+		s.Repos[index] = *repo
+		updated++
+	}
+	return inserted, updated, nil
+}
+
 func (s *InMemoryRepositoryStore) MarkAsInaccessible(id string) error {
 	return nil
 }
@@ -0,0 +1,255 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// SnapshotGranularity controls how GetMetricsSnapshots buckets snapshots.
+type SnapshotGranularity string
+
+const (
+	GranularityDaily   SnapshotGranularity = "daily"
+	GranularityWeekly  SnapshotGranularity = "weekly"
+	GranularityMonthly SnapshotGranularity = "monthly"
+)
+
+// dateTruncUnits maps the aggregating granularities to their date_trunc unit.
+// Daily is absent because it returns the stored rows unchanged.
+var dateTruncUnits = map[SnapshotGranularity]string{
+	GranularityWeekly:  "week",
+	GranularityMonthly: "month",
+}
+
+// SnapshotOptions narrows the rows GetMetricsSnapshots returns. The zero
+// value returns every snapshot in the range.
+type SnapshotOptions struct {
+	// SkipEmpty drops snapshots without any measurement, such as the days
+	// before a repository's first analysis. A clean run that measured zero
+	// issues is kept.
+	SkipEmpty bool
+	// Offset skips this many snapshots, after SkipEmpty, for paging.
+	Offset int
+	// Limit caps the number of snapshots returned; zero or less means no cap.
+	Limit int
+}
+
+// Leaderboard orderings accepted by GetRepositorySummaries. Each sorts the
+// worst repositories first.
+const (
+	LeaderboardByDebt     = "debt"
+	LeaderboardByCritical = "critical"
+	LeaderboardByCoverage = "coverage"
+)
+
+// leaderboardOrderClauses maps each leaderboard ordering to its ORDER BY
+// clause. Ties fall back to the repository name so pages are stable.
+var leaderboardOrderClauses = map[string]string{
+	LeaderboardByDebt:     "r.latest_total_technical_debt_hours DESC, r.full_name ASC",
+	LeaderboardByCritical: "r.latest_critical_issues_count DESC, r.latest_high_issues_count DESC, r.full_name ASC",
+	LeaderboardByCoverage: "r.latest_test_coverage_percentage ASC, r.full_name ASC",
+}
+
+// measuredSnapshot is the condition SnapshotOptions.SkipEmpty adds: a
+// snapshot has at least one metric recorded.
+const measuredSnapshot = `num_nonnulls(total_issues_count, critical_issues_count, high_issues_count,
+	medium_issues_count, low_issues_count, technical_debt_hours, test_coverage_percentage,
+	duplication_percentage, complexity_score) > 0`
+
+type MetricsStoreInterface interface {
+	GetMetricsSnapshots(ctx context.Context, userID, repositoryID uuid.UUID, from, to time.Time, granularity SnapshotGranularity, opts SnapshotOptions) ([]models.RepositoryMetricsSnapshot, error)
+	GetRepositorySummaries(ctx context.Context, userID uuid.UUID, sortBy string, limit int) ([]models.RepositorySummary, error)
+	GetDebtTrend(ctx context.Context, userID uuid.UUID, now time.Time) (models.MetricsTrend, error)
+}
+
+type MetricsStore struct {
+	db *sql.DB
+}
+
+func NewMetricsStore(db *sql.DB) *MetricsStore {
+	return &MetricsStore{db: db}
+}
+
+// GetMetricsSnapshots returns the repository's snapshots between from and to in
+// ascending date order. With a weekly or monthly granularity snapshots are
+// averaged per bucket and each bucket's start is reported as SnapshotDate;
+// aggregated rows have a nil ID. An empty granularity means daily. Metrics a
+// snapshot did not record are reported as zero. opts is applied in the query:
+// SkipEmpty leaves unmeasured snapshots out before bucketing, and Offset and
+// Limit page the resulting rows, so pages line up with what is displayed.
+func (s *MetricsStore) GetMetricsSnapshots(ctx context.Context, userID, repositoryID uuid.UUID, from, to time.Time, granularity SnapshotGranularity, opts SnapshotOptions) ([]models.RepositoryMetricsSnapshot, error) {
+	if granularity == "" {
+		granularity = GranularityDaily
+	}
+
+	where := "user_id = $1 AND repository_id = $2 AND snapshot_date BETWEEN $3 AND $4"
+	if opts.SkipEmpty {
+		where += " AND " + measuredSnapshot
+	}
+
+	var query string
+	if granularity == GranularityDaily {
+		query = fmt.Sprintf(`
+			SELECT id, user_id, repository_id, snapshot_date,
+				COALESCE(total_issues_count, 0), COALESCE(critical_issues_count, 0), COALESCE(high_issues_count, 0),
+				COALESCE(medium_issues_count, 0), COALESCE(low_issues_count, 0),
+				COALESCE(technical_debt_hours, 0), COALESCE(test_coverage_percentage, 0),
+				COALESCE(duplication_percentage, 0), COALESCE(complexity_score, 0),
+				created_at
+			FROM repository_metrics_snapshots
+			WHERE %s
+			ORDER BY snapshot_date ASC
+		`, where)
+	} else {
+		unit, ok := dateTruncUnits[granularity]
+		if !ok {
+			return nil, fmt.Errorf("invalid snapshot granularity %q: must be daily, weekly or monthly", granularity)
+		}
+		// unit comes from the allow-list above, so formatting it into the
+		// query is safe.
+		query = fmt.Sprintf(`
+			SELECT '00000000-0000-0000-0000-000000000000'::uuid, user_id, repository_id,
+				date_trunc('%[1]s', snapshot_date) AS bucket,
+				COALESCE(ROUND(AVG(total_issues_count))::int, 0), COALESCE(ROUND(AVG(critical_issues_count))::int, 0),
+				COALESCE(ROUND(AVG(high_issues_count))::int, 0), COALESCE(ROUND(AVG(medium_issues_count))::int, 0),
+				COALESCE(ROUND(AVG(low_issues_count))::int, 0),
+				COALESCE(AVG(technical_debt_hours), 0), COALESCE(AVG(test_coverage_percentage), 0),
+				COALESCE(AVG(duplication_percentage), 0), COALESCE(AVG(complexity_score), 0),
+				MAX(created_at)
+			FROM repository_metrics_snapshots
+			WHERE %[2]s
+			GROUP BY user_id, repository_id, bucket
+			ORDER BY bucket ASC
+		`, unit, where)
+	}
+
+	args := []interface{}{userID, repositoryID, from, to}
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if opts.Offset > 0 {
+		args = append(args, opts.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.RepositoryMetricsSnapshot
+	for rows.Next() {
+		var snap models.RepositoryMetricsSnapshot
+		if err := rows.Scan(
+			&snap.ID, &snap.UserID, &snap.RepositoryID, &snap.SnapshotDate,
+			&snap.TotalIssuesCount, &snap.CriticalIssuesCount, &snap.HighIssuesCount, &snap.MediumIssuesCount, &snap.LowIssuesCount,
+			&snap.TechnicalDebtHours, &snap.TestCoveragePercentage, &snap.DuplicationPercentage, &snap.ComplexityScore,
+			&snap.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan metrics snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// GetRepositorySummaries returns the user's repositories as a leaderboard
+// sorted by sortBy (LeaderboardByDebt, LeaderboardByCritical or
+// LeaderboardByCoverage; empty means by debt), worst first. AvgDebt30d is the
+// average technical debt of the last 30 days of snapshots and stays nil for a
+// repository without any. A limit of zero or less returns every repository.
+func (s *MetricsStore) GetRepositorySummaries(ctx context.Context, userID uuid.UUID, sortBy string, limit int) ([]models.RepositorySummary, error) {
+	if sortBy == "" {
+		sortBy = LeaderboardByDebt
+	}
+	orderBy, ok := leaderboardOrderClauses[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid leaderboard sort %q: must be debt, critical or coverage", sortBy)
+	}
+
+	// orderBy comes from the allow-list above, so formatting it into the
+	// query is safe.
+	query := fmt.Sprintf(`
+		SELECT r.id, r.user_id, r.name, r.full_name, r.last_analysis_at, r.last_analysis_status,
+			r.latest_total_technical_debt_hours, r.latest_critical_issues_count, r.latest_high_issues_count,
+			r.latest_test_coverage_percentage, r.latest_duplication_percentage,
+			s.avg_debt_30d
+		FROM user_repositories r
+		LEFT JOIN (
+			SELECT repository_id, AVG(technical_debt_hours) AS avg_debt_30d
+			FROM repository_metrics_snapshots
+			WHERE user_id = $1 AND snapshot_date >= NOW() - INTERVAL '30 days'
+			GROUP BY repository_id
+		) s ON s.repository_id = r.id
+		WHERE r.user_id = $1
+		ORDER BY %s
+	`, orderBy)
+	args := []interface{}{userID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []models.RepositorySummary
+	for rows.Next() {
+		var summary models.RepositorySummary
+		var avgDebt sql.NullFloat64
+		if err := rows.Scan(
+			&summary.RepositoryID, &summary.UserID, &summary.Name, &summary.FullName,
+			&summary.LastAnalysisAt, &summary.LastAnalysisStatus,
+			&summary.LatestTotalTechnicalDebtHours, &summary.LatestCriticalIssuesCount, &summary.LatestHighIssuesCount,
+			&summary.LatestTestCoveragePercentage, &summary.LatestDuplicationPercentage,
+			&avgDebt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan repository summary: %w", err)
+		}
+		if avgDebt.Valid {
+			summary.AvgDebt30d = &avgDebt.Float64
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetDebtTrend compares the user's total technical debt, the sum of the
+// latest debt of each repository, with the total a month before now: the
+// sum of each repository's last snapshot on or before that date. A
+// repository without a snapshot that old counts as no debt then. The
+// current total is the trend's CurrentValue, which is what
+// DashboardStats.TotalTechnicalDebtHours reports.
+func (s *MetricsStore) GetDebtTrend(ctx context.Context, userID uuid.UUID, now time.Time) (models.MetricsTrend, error) {
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(latest_total_technical_debt_hours), 0)
+				FROM user_repositories WHERE user_id = $1),
+			(SELECT COALESCE(SUM(technical_debt_hours), 0) FROM (
+				SELECT DISTINCT ON (repository_id) technical_debt_hours
+				FROM repository_metrics_snapshots
+				WHERE user_id = $1 AND snapshot_date <= $2
+				ORDER BY repository_id, snapshot_date DESC
+			) month_ago)
+	`
+	var current, previous float64
+	if err := s.db.QueryRowContext(ctx, query, userID, now.AddDate(0, -1, 0)).Scan(&current, &previous); err != nil {
+		return models.MetricsTrend{}, fmt.Errorf("failed to get debt trend: %w", err)
+	}
+	return models.NewMetricsTrend(current, previous), nil
+}
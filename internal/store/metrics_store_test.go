@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetricsSnapshots_Options(t *testing.T) {
+	userID, repositoryID := uuid.New(), uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	tests := []struct {
+		name        string
+		granularity SnapshotGranularity
+		opts        SnapshotOptions
+		filtered    bool
+		paging      string
+		pageArgs    []driver.Value
+	}{
+		{"zero options return every snapshot", GranularityDaily, SnapshotOptions{}, false, "", nil},
+		{"skip empty filters in the query", GranularityDaily, SnapshotOptions{SkipEmpty: true}, true, "", nil},
+		{"limit caps the query", GranularityDaily, SnapshotOptions{Limit: 2}, false, "LIMIT $5", []driver.Value{int64(2)}},
+		{"offset pages after skipping", GranularityDaily, SnapshotOptions{SkipEmpty: true, Offset: 1, Limit: 1}, true, "LIMIT $5 OFFSET $6", []driver.Value{int64(1), int64(1)}},
+		{"offset without a limit", GranularityDaily, SnapshotOptions{Offset: 3}, false, "OFFSET $5", []driver.Value{int64(3)}},
+		{"non-positive limit is no cap", GranularityDaily, SnapshotOptions{Limit: -1}, false, "", nil},
+		{"buckets skip before averaging", GranularityWeekly, SnapshotOptions{SkipEmpty: true, Limit: 4}, true, "LIMIT $5", []driver.Value{int64(4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDB{}
+			s := NewMetricsStore(openFakeDB(t, d))
+
+			_, err := s.GetMetricsSnapshots(context.Background(), userID, repositoryID, from, to, tt.granularity, tt.opts)
+			require.NoError(t, err)
+			require.Len(t, d.queries, 1)
+			query, args := d.queries[0].query, d.queries[0].args
+
+			where := query[strings.Index(query, "WHERE"):strings.Index(query, "ORDER BY")]
+			assert.Equal(t, tt.filtered, strings.Contains(where, "num_nonnulls("))
+			assert.NotContains(t, where, "= 0", "zero metrics are measurements")
+			assert.True(t, strings.HasSuffix(strings.Join(strings.Fields(query), " "), strings.TrimSpace("ASC "+tt.paging)), "query ends with %q: %s", tt.paging, query)
+			assert.Equal(t, tt.pageArgs, append([]driver.Value(nil), args[4:]...))
+		})
+	}
+}
+
+func TestGetMetricsSnapshots_KeepsCleanRuns(t *testing.T) {
+	created := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	d := &fakeDB{query: func(query string, args []driver.Value) (*fakeRows, error) {
+		return &fakeRows{columns: make([]string, 14), values: [][]driver.Value{
+			// A clean run: every metric measured as zero.
+			{uuid.New().String(), uuid.New().String(), uuid.New().String(), created,
+				int64(0), int64(0), int64(0), int64(0), int64(0), 0.0, 0.0, 0.0, 0.0, created},
+		}}, nil
+	}}
+	s := NewMetricsStore(openFakeDB(t, d))
+
+	snapshots, err := s.GetMetricsSnapshots(context.Background(), uuid.New(), uuid.New(), created, created, GranularityDaily, SnapshotOptions{SkipEmpty: true})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1, "rows the query returns are not filtered again")
+	assert.Equal(t, created, snapshots[0].SnapshotDate)
+	assert.Zero(t, snapshots[0].TotalIssuesCount)
+}
+
+func TestGetDebtTrend(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)
+	d := &fakeDB{query: func(query string, args []driver.Value) (*fakeRows, error) {
+		assert.Contains(t, query, "SUM(latest_total_technical_debt_hours)")
+		assert.Contains(t, query, "DISTINCT ON (repository_id)", "each repository counts its last snapshot once")
+		return &fakeRows{columns: []string{"current", "previous"}, values: [][]driver.Value{{150.0, 120.0}}}, nil
+	}}
+	s := NewMetricsStore(openFakeDB(t, d))
+
+	trend, err := s.GetDebtTrend(context.Background(), userID, now)
+	require.NoError(t, err)
+	assert.Equal(t, models.MetricsTrend{
+		CurrentValue:  150,
+		PreviousValue: 120,
+		Change:        30,
+		ChangePercent: 25,
+		Direction:     "up",
+	}, trend)
+
+	require.Len(t, d.queries, 1)
+	assert.Equal(t, userID.String(), d.queries[0].args[0])
+	assert.Equal(t, time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC), d.queries[0].args[1], "snapshots are read as of a month earlier")
+}
+
+func TestGetRepositorySummaries(t *testing.T) {
+	userID := uuid.New()
+	analyzedAt := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	summaryRows := func(query string, args []driver.Value) (*fakeRows, error) {
+		return &fakeRows{columns: make([]string, 12), values: [][]driver.Value{
+			{uuid.New().String(), userID.String(), "api", "acme/api", analyzedAt, "completed",
+				42.5, int64(3), int64(7), 61.0, 4.5, 40.0},
+			{uuid.New().String(), userID.String(), "web", "acme/web", nil, nil,
+				12.0, int64(0), int64(1), 80.0, 2.0, nil},
+		}}, nil
+	}
+
+	tests := []struct {
+		name    string
+		sortBy  string
+		limit   int
+		orderBy string
+		args    []driver.Value
+	}{
+		{"default sorts by debt", "", 0, "ORDER BY r.latest_total_technical_debt_hours DESC, r.full_name ASC", []driver.Value{userID.String()}},
+		{"critical first", LeaderboardByCritical, 10,
+			"ORDER BY r.latest_critical_issues_count DESC, r.latest_high_issues_count DESC, r.full_name ASC LIMIT $2",
+			[]driver.Value{userID.String(), int64(10)}},
+		{"lowest coverage first", LeaderboardByCoverage, -1, "ORDER BY r.latest_test_coverage_percentage ASC, r.full_name ASC", []driver.Value{userID.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDB{query: summaryRows}
+			s := NewMetricsStore(openFakeDB(t, d))
+
+			summaries, err := s.GetRepositorySummaries(context.Background(), userID, tt.sortBy, tt.limit)
+			require.NoError(t, err)
+			require.Len(t, d.queries, 1)
+			query := strings.Join(strings.Fields(d.queries[0].query), " ")
+			assert.True(t, strings.HasSuffix(query, tt.orderBy), "query ends with %q: %s", tt.orderBy, query)
+			assert.Contains(t, query, "AVG(technical_debt_hours) AS avg_debt_30d", "the average covers the snapshots")
+			assert.Contains(t, query, "snapshot_date >= NOW() - INTERVAL '30 days'", "of the last 30 days")
+			assert.Equal(t, tt.args, d.queries[0].args)
+
+			require.Len(t, summaries, 2)
+			assert.Equal(t, "acme/api", summaries[0].FullName, "rows keep the query's order")
+			assert.Equal(t, 42.5, summaries[0].LatestTotalTechnicalDebtHours)
+			assert.Equal(t, 3, summaries[0].LatestCriticalIssuesCount)
+			require.NotNil(t, summaries[0].AvgDebt30d)
+			assert.Equal(t, 40.0, *summaries[0].AvgDebt30d)
+			require.NotNil(t, summaries[0].LastAnalysisStatus)
+			assert.Equal(t, "completed", *summaries[0].LastAnalysisStatus)
+
+			assert.Equal(t, "acme/web", summaries[1].FullName)
+			assert.Nil(t, summaries[1].AvgDebt30d, "a repository without recent snapshots has no average")
+			assert.Nil(t, summaries[1].LastAnalysisAt)
+		})
+	}
+
+	_, err := NewMetricsStore(openFakeDB(t, &fakeDB{})).GetRepositorySummaries(context.Background(), userID, "name", 0)
+	assert.ErrorContains(t, err, `invalid leaderboard sort "name"`)
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+)
+
+// DBRepositoryStore keeps the repositories synced from users' organizations
+// in user_repositories.
+type DBRepositoryStore struct {
+	db *sql.DB
+}
+
+func NewDBRepositoryStore(db *sql.DB) *DBRepositoryStore {
+	return &DBRepositoryStore{db: db}
+}
+
+// DefaultRepositoryBatchSize is the number of repositories BulkUpsert writes
+// per statement.
+const DefaultRepositoryBatchSize = 500
+
+// repositoryUpsertColumns are the columns BulkUpsert writes, in argument
+// order.
+var repositoryUpsertColumns = []string{
+	"id", "user_id", "organization_id", "user_config_id", "name", "full_name", "url",
+	"platform_type", "primary_language", "size_bytes", "default_branch", "last_commit_date",
+	"is_private", "is_fork", "analysis_enabled", "created_at", "updated_at",
+}
+
+// repositorySyncedColumns are the columns BulkUpsert overwrites when a
+// repository is already stored: what the platform reports about it. The ID,
+// whether the user enabled analysis and when the repository was first synced
+// are kept, as are the results of its analyses.
+var repositorySyncedColumns = []string{
+	"organization_id", "user_config_id", "name", "url", "platform_type", "primary_language",
+	"size_bytes", "default_branch", "last_commit_date", "is_private", "is_fork", "updated_at",
+}
+
+// maxRepositoryBatchSize keeps a multi-row upsert within PostgreSQL's limit
+// of 65535 bind parameters per statement.
+var maxRepositoryBatchSize = 65535 / len(repositoryUpsertColumns)
+
+// repositoryKey identifies a repository the way the upsert's conflict
+// target does.
+type repositoryKey struct {
+	userID   uuid.UUID
+	fullName string
+}
+
+// BulkUpsert inserts the repositories of repos that are not stored yet and
+// updates those that are, matched by user and full name, in one transaction
+// with one INSERT ... ON CONFLICT statement per DefaultRepositoryBatchSize
+// repositories instead of a lookup and a write per repository. It requires
+// the unique constraint on user_repositories (user_id, full_name) that the
+// conflict target names; without it PostgreSQL rejects the statement.
+//
+// A new repository keeps the AnalysisEnabled it is passed with; an existing
+// one keeps its ID, AnalysisEnabled and CreatedAt. Either way the stored
+// values are written back to repos. A repository listed more than once is
+// written once, with its last entry, and counted once.
+func (s *DBRepositoryStore) BulkUpsert(repos []*models.UserRepository) (inserted, updated int, err error) {
+	return s.bulkUpsert(repos, DefaultRepositoryBatchSize)
+}
+
+func (s *DBRepositoryStore) bulkUpsert(repos []*models.UserRepository, batchSize int) (inserted, updated int, err error) {
+	if len(repos) == 0 {
+		return 0, 0, nil
+	}
+
+	// PostgreSQL refuses to update the same row twice in one statement, so
+	// each repository is sent once.
+	last := make(map[repositoryKey]*models.UserRepository, len(repos))
+	var unique []*models.UserRepository
+	for _, repo := range repos {
+		key := repositoryKey{repo.UserID, repo.FullName}
+		if _, seen := last[key]; !seen {
+			unique = append(unique, repo)
+		}
+		last[key] = repo
+	}
+	for i, repo := range unique {
+		unique[i] = last[repositoryKey{repo.UserID, repo.FullName}]
+	}
+
+	now := time.Now()
+	for _, repo := range unique {
+		if repo.ID == uuid.Nil {
+			repo.ID = uuid.New()
+		}
+		repo.CreatedAt = now
+		repo.UpdatedAt = now
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	batchSize = min(batchSize, maxRepositoryBatchSize)
+	for start := 0; start < len(unique); start += batchSize {
+		batch := unique[start:min(start+batchSize, len(unique))]
+		n, err := upsertRepositoryBatch(tx, batch, last)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to upsert repositories %d-%d: %w", start, start+len(batch)-1, err)
+		}
+		inserted += n
+		updated += len(batch) - n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, repo := range repos {
+		stored := last[repositoryKey{repo.UserID, repo.FullName}]
+		if repo != stored {
+			*repo = *stored
+		}
+	}
+	return inserted, updated, nil
+}
+
+// upsertRepositoryBatch writes batch in one statement, copies the stored ID,
+// AnalysisEnabled and CreatedAt back to the repositories in byKey and returns
+// how many of them were inserted.
+func upsertRepositoryBatch(tx *sql.Tx, batch []*models.UserRepository, byKey map[repositoryKey]*models.UserRepository) (int, error) {
+	var query strings.Builder
+	query.WriteString("INSERT INTO user_repositories (")
+	query.WriteString(strings.Join(repositoryUpsertColumns, ", "))
+	query.WriteString(") VALUES ")
+
+	args := make([]interface{}, 0, len(batch)*len(repositoryUpsertColumns))
+	for i, repo := range batch {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for column := range repositoryUpsertColumns {
+			if column > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", len(args)+column+1)
+		}
+		query.WriteString(")")
+
+		args = append(args,
+			repo.ID, repo.UserID, repo.OrganizationID, repo.UserConfigID, repo.Name, repo.FullName, repo.URL,
+			repo.PlatformType, repo.PrimaryLanguage, repo.SizeBytes, repo.DefaultBranch, repo.LastCommitDate,
+			repo.IsPrivate, repo.IsFork, repo.AnalysisEnabled, repo.CreatedAt, repo.UpdatedAt,
+		)
+	}
+
+	query.WriteString(" ON CONFLICT (user_id, full_name) DO UPDATE SET ")
+	for i, column := range repositorySyncedColumns {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		fmt.Fprintf(&query, "%s = EXCLUDED.%s", column, column)
+	}
+	// xmax is zero only on a row version the statement inserted.
+	query.WriteString(" RETURNING user_id, full_name, id, analysis_enabled, created_at, (xmax = 0) AS inserted")
+
+	rows, err := tx.Query(query.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	inserted := 0
+	for rows.Next() {
+		var (
+			key             repositoryKey
+			id              uuid.UUID
+			analysisEnabled bool
+			createdAt       time.Time
+			isNew           bool
+		)
+		if err := rows.Scan(&key.userID, &key.fullName, &id, &analysisEnabled, &createdAt, &isNew); err != nil {
+			return 0, fmt.Errorf("failed to scan upserted repository: %w", err)
+		}
+		if repo := byKey[key]; repo != nil {
+			repo.ID = id
+			repo.AnalysisEnabled = analysisEnabled
+			repo.CreatedAt = createdAt
+		}
+		if isNew {
+			inserted++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// GetByURL returns the repository stored with url, or nil when there is
+// none. When several users synced the same repository, the one with analysis
+// enabled and the most recently synced is returned.
+func (s *DBRepositoryStore) GetByURL(url string) (*models.UserRepository, error) {
+	query := `
+		SELECT id, user_id, organization_id, user_config_id, name, full_name, url,
+			platform_type, default_branch, is_private, analysis_enabled, created_at, updated_at
+		FROM user_repositories
+		WHERE url = $1
+		ORDER BY analysis_enabled DESC, updated_at DESC
+		LIMIT 1
+	`
+	repo := &models.UserRepository{}
+	err := s.db.QueryRow(query, url).Scan(
+		&repo.ID, &repo.UserID, &repo.OrganizationID, &repo.UserConfigID, &repo.Name, &repo.FullName, &repo.URL,
+		&repo.PlatformType, &repo.DefaultBranch, &repo.IsPrivate, &repo.AnalysisEnabled, &repo.CreatedAt, &repo.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository by url: %w", err)
+	}
+	return repo, nil
+}
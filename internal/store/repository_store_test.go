@@ -0,0 +1,137 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepositoryTable answers the upserts of BulkUpsert for a fakeDB from
+// rows keyed by user ID and full name, the way the ON CONFLICT clause
+// matches them.
+type fakeRepositoryTable struct {
+	rows map[string]map[string]driver.Value
+}
+
+func newFakeRepositoryStore(tb testing.TB, table *fakeRepositoryTable) (*DBRepositoryStore, *fakeDB) {
+	d := &fakeDB{query: table.upsert}
+	return NewDBRepositoryStore(openFakeDB(tb, d)), d
+}
+
+// upsert takes the values of repositoryUpsertColumns for each repository.
+func (t *fakeRepositoryTable) upsert(query string, args []driver.Value) (*fakeRows, error) {
+	if !strings.Contains(query, "ON CONFLICT (user_id, full_name) DO UPDATE") {
+		return nil, fmt.Errorf("unsupported statement: %s", query)
+	}
+
+	var values [][]driver.Value
+	for start := 0; start < len(args); start += len(repositoryUpsertColumns) {
+		row := map[string]driver.Value{}
+		for i, column := range repositoryUpsertColumns {
+			row[column] = args[start+i]
+		}
+		key := fmt.Sprint(row["user_id"], "/", row["full_name"])
+
+		existing, conflict := t.rows[key]
+		if conflict {
+			for _, column := range repositorySyncedColumns {
+				existing[column] = row[column]
+			}
+			row = existing
+		} else {
+			t.rows[key] = row
+		}
+		values = append(values, []driver.Value{
+			row["user_id"], row["full_name"], row["id"], row["analysis_enabled"], row["created_at"], !conflict,
+		})
+	}
+	return &fakeRows{columns: []string{"user_id", "full_name", "id", "analysis_enabled", "created_at", "inserted"}, values: values}, nil
+}
+
+func TestBulkUpsert_MixedBatch(t *testing.T) {
+	userID := uuid.New()
+	firstSynced := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	existingID := uuid.New()
+	table := &fakeRepositoryTable{rows: map[string]map[string]driver.Value{
+		fmt.Sprint(userID.String(), "/", "acme/api"): {
+			"id": existingID.String(), "user_id": userID.String(), "full_name": "acme/api",
+			"default_branch": "master", "analysis_enabled": false, "created_at": firstSynced,
+		},
+	}}
+	s, d := newFakeRepositoryStore(t, table)
+
+	api := &models.UserRepository{UserID: userID, Name: "api", FullName: "acme/api", DefaultBranch: "main", AnalysisEnabled: true}
+	web := &models.UserRepository{UserID: userID, Name: "web", FullName: "acme/web", AnalysisEnabled: true}
+	cli := &models.UserRepository{UserID: userID, Name: "cli", FullName: "acme/cli"}
+	webAgain := &models.UserRepository{UserID: userID, Name: "web", FullName: "acme/web", DefaultBranch: "develop", AnalysisEnabled: true}
+
+	inserted, updated, err := s.bulkUpsert([]*models.UserRepository{api, web, cli, webAgain}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inserted)
+	assert.Equal(t, 1, updated)
+	assert.Len(t, d.queries, 2, "three distinct repositories are written two per statement")
+	assert.Equal(t, 1, d.commits, "every batch is written in one transaction")
+
+	assert.Equal(t, existingID, api.ID, "an existing repository keeps its ID")
+	assert.False(t, api.AnalysisEnabled, "an existing repository keeps whether analysis is enabled")
+	assert.True(t, api.CreatedAt.Equal(firstSynced), "an existing repository keeps when it was first synced")
+	assert.Equal(t, "main", table.rows[fmt.Sprint(userID.String(), "/", "acme/api")]["default_branch"])
+
+	assert.NotEqual(t, uuid.Nil, web.ID)
+	assert.True(t, web.AnalysisEnabled, "a new repository keeps the AnalysisEnabled it was passed with")
+	assert.False(t, cli.AnalysisEnabled)
+	assert.Equal(t, web.ID, webAgain.ID, "a repository listed twice is written once")
+	assert.Equal(t, "develop", web.DefaultBranch, "the last entry of a repository listed twice wins")
+	assert.Len(t, table.rows, 3)
+
+	inserted, updated, err = s.BulkUpsert([]*models.UserRepository{{UserID: userID, Name: "cli", FullName: "acme/cli"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, inserted)
+	assert.Equal(t, 1, updated)
+}
+
+func TestBulkUpsert_Empty(t *testing.T) {
+	table := &fakeRepositoryTable{rows: map[string]map[string]driver.Value{}}
+	s, d := newFakeRepositoryStore(t, table)
+
+	inserted, updated, err := s.BulkUpsert(nil)
+	require.NoError(t, err)
+	assert.Zero(t, inserted)
+	assert.Zero(t, updated)
+	assert.Empty(t, d.queries)
+}
+
+func TestGetByURL(t *testing.T) {
+	repoID, userID, configID := uuid.New(), uuid.New(), uuid.New()
+	synced := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	d := &fakeDB{query: func(query string, args []driver.Value) (*fakeRows, error) {
+		if args[0] != "https://github.com/acme/api" {
+			return &fakeRows{}, nil
+		}
+		return &fakeRows{columns: make([]string, 13), values: [][]driver.Value{{
+			repoID.String(), userID.String(), uuid.New().String(), configID.String(), "api", "acme/api", args[0],
+			"github", "main", true, true, synced, synced,
+		}}}, nil
+	}}
+	s := NewDBRepositoryStore(openFakeDB(t, d))
+
+	repo, err := s.GetByURL("https://github.com/acme/api")
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, repoID, repo.ID)
+	assert.Equal(t, configID, repo.UserConfigID)
+	assert.Equal(t, "acme/api", repo.FullName)
+	assert.True(t, repo.AnalysisEnabled)
+	assert.Contains(t, d.queries[0].query, "ORDER BY analysis_enabled DESC, updated_at DESC", "an enabled copy of a shared repository wins")
+
+	repo, err = s.GetByURL("https://github.com/acme/unknown")
+	require.NoError(t, err)
+	assert.Nil(t, repo)
+}
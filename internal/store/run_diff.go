@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrRunNotFound is returned when no stored analysis run has the requested ID.
+var ErrRunNotFound = errors.New("analysis run not found")
+
+// ListByAnalysisRun returns the issues recorded by an analysis run.
+func (s *DBTechnicalDebtIssueStore) ListByAnalysisRun(ctx context.Context, runID uuid.UUID) ([]models.TechnicalDebtIssue, error) {
+	query := `
+		SELECT id, user_id, repository_id, analysis_run_id, file_path, line_number, column_number,
+		       issue_type, severity, category, message, description, tool_name, tool_rule_id,
+		       confidence_score, technical_debt_hours, effort_multiplier, status,
+		       resolution_reason, assigned_to_user_id, resolved_at, resolved_by_user_id,
+		       ignore_until, comments, code_snippet, surrounding_context,
+		       fingerprint_hash, jira_sync_status, trello_sync_status,
+		       external_id, external_platform, external_url, metadata,
+		       created_at, updated_at
+		FROM technical_debt_issues
+		WHERE analysis_run_id = $1
+		ORDER BY file_path, line_number
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues for run %s: %w", runID, err)
+	}
+	defer rows.Close()
+
+	var issues []models.TechnicalDebtIssue
+	for rows.Next() {
+		var issue models.TechnicalDebtIssue
+		var assignedTo, resolvedBy sql.NullString
+		var externalIDNull, externalPlatformNull, externalURLNull, fingerprintHashNull sql.NullString
+		var metadataJSON []byte
+		err := rows.Scan(
+			&issue.ID, &issue.UserID, &issue.RepositoryID, &issue.AnalysisRunID, &issue.FilePath,
+			&issue.LineNumber, &issue.ColumnNumber, &issue.IssueType, &issue.Severity, &issue.Category,
+			&issue.Message, &issue.Description, &issue.ToolName, &issue.ToolRuleID,
+			&issue.ConfidenceScore, &issue.TechnicalDebtHours, &issue.EffortMultiplier, &issue.Status,
+			&issue.ResolutionReason, &assignedTo, &issue.ResolvedAt, &resolvedBy,
+			&issue.IgnoreUntil, pq.Array(&issue.Comments), &issue.CodeSnippet, &issue.SurroundingContext,
+			&fingerprintHashNull, &issue.JiraSyncStatus, &issue.TrelloSyncStatus,
+			&externalIDNull, &externalPlatformNull, &externalURLNull, &metadataJSON,
+			&issue.CreatedAt, &issue.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan issue for run %s: %w", runID, err)
+		}
+		issue.AssignedToUserID = scanNullableUUID(assignedTo)
+		issue.ResolvedByUserID = scanNullableUUID(resolvedBy)
+		if externalIDNull.Valid {
+			issue.ExternalID = &externalIDNull.String
+		}
+		if externalPlatformNull.Valid {
+			issue.ExternalPlatform = &externalPlatformNull.String
+		}
+		if externalURLNull.Valid {
+			issue.ExternalURL = &externalURLNull.String
+		}
+		if fingerprintHashNull.Valid {
+			issue.FingerprintHash = fingerprintHashNull.String
+		}
+		issue.Metadata = unmarshalIssueMetadata(metadataJSON)
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+// DiffRuns compares the issues of two analysis runs: issues added in head,
+// issues resolved since base, and issues whose severity changed, matched by
+// canonical fingerprint. See analysis.DiffIssues.
+func (s *DBTechnicalDebtIssueStore) DiffRuns(ctx context.Context, baseRunID, headRunID uuid.UUID) (analysis.RunDiff, error) {
+	base, err := s.ListByAnalysisRun(ctx, baseRunID)
+	if err != nil {
+		return analysis.RunDiff{}, err
+	}
+	head, err := s.ListByAnalysisRun(ctx, headRunID)
+	if err != nil {
+		return analysis.RunDiff{}, err
+	}
+
+	diff := analysis.DiffIssues(base, head)
+	diff.BaseRunID = baseRunID
+	diff.HeadRunID = headRunID
+	return diff, nil
+}
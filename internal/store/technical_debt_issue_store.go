@@ -3,9 +3,12 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -22,14 +25,47 @@ func scanNullableUUID(ns sql.NullString) *uuid.UUID {
 	return &id
 }
 
+// marshalIssueMetadata encodes issue metadata for the JSONB column. Empty
+// metadata is stored as NULL rather than an empty object.
+func marshalIssueMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(metadata)
+}
+
+// unmarshalIssueMetadata decodes the JSONB metadata column. NULL or malformed
+// values yield nil so a bad row never fails an entire listing.
+func unmarshalIssueMetadata(data []byte) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil
+	}
+	return metadata
+}
+
 type IssueFilters struct {
 	Severity     *string
 	Status       *string
 	IssueType    *string
 	RepositoryID *string
 	UserID       *string
+	// MinConfidence excludes issues whose confidence_score is below it.
+	MinConfidence *float64
+	// AssignedToUserID restricts results to issues assigned to that user.
+	AssignedToUserID *string
+
+	// excludedStatuses is set by ListAssignedTo to hide closed issues when
+	// the caller does not filter by status.
+	excludedStatuses []string
 }
 
+// closedIssueStatuses are the statuses of issues nobody needs to act on.
+var closedIssueStatuses = []string{"resolved", "ignored"}
+
 // OpenIssueSummary holds the aggregated counts of open issues by severity
 type OpenIssueSummary struct {
 	CriticalCount  int     `json:"critical_count"`
@@ -45,11 +81,15 @@ type TechnicalDebtIssueStoreInterface interface {
 	Get(id string) (*models.TechnicalDebtIssue, error)
 	List(limit, offset int) ([]models.TechnicalDebtIssue, error)
 	ListWithFilters(filters IssueFilters, limit, offset int) ([]models.TechnicalDebtIssue, int, error)
+	// ListAssignedTo lists the issues assigned to a user, excluding resolved and ignored issues unless filters.Status asks for them.
+	ListAssignedTo(userID string, filters IssueFilters, limit, offset int) ([]models.TechnicalDebtIssue, int, error)
 	Update(issue *models.TechnicalDebtIssue) error
 	IssueExists(repositoryID uuid.UUID, filePath string, lineNumber *int, issueType string, toolRuleID *string) (bool, error)
 	TouchExistingIssue(repositoryID uuid.UUID, analysisRunID uuid.UUID, filePath string, lineNumber *int, issueType string, toolRuleID *string) error
 	ResolveMissingIssues(repositoryID uuid.UUID, currentAnalysisRunID uuid.UUID, resolutionReason string) ([]uuid.UUID, error)
 	GetOpenIssueSummary(repositoryID uuid.UUID) (*OpenIssueSummary, error)
+	// CountOpenByRepository returns the open issue counts by severity for a repository, from stored data only.
+	CountOpenByRepository(repositoryID string) (map[string]int, error)
 	// ReconcileIssuesForAnalyzer atomically replaces all issues for a specific analyzer in a repository.
 	// This implements the "Clear-and-Replace" strategy to ensure idempotent scans.
 	ReconcileIssuesForAnalyzer(repositoryID uuid.UUID, analyzerName string, newIssues []models.TechnicalDebtIssue) (int, int, error)
@@ -69,6 +109,33 @@ type TechnicalDebtIssueStoreInterface interface {
 
 type DBTechnicalDebtIssueStore struct {
 	db *sql.DB
+	// SkipInvalid makes BatchCreate insert the valid issues of a batch and
+	// drop the invalid ones. By default a batch containing an invalid issue is
+	// rejected before anything is written. Either way the invalid issues are
+	// reported through an *InvalidIssuesError.
+	SkipInvalid bool
+	// BatchSize is the number of issues BatchCreate inserts per statement;
+	// zero or less means DefaultIssueBatchSize.
+	BatchSize int
+}
+
+// InvalidIssuesError lists the issues of a batch that failed validation.
+type InvalidIssuesError struct {
+	Invalid []models.InvalidIssue
+	// Skipped reports whether the valid issues of the batch were written.
+	Skipped bool
+}
+
+func (e *InvalidIssuesError) Error() string {
+	parts := make([]string, 0, len(e.Invalid))
+	for _, invalid := range e.Invalid {
+		parts = append(parts, fmt.Sprintf("issue %d: %v", invalid.Index, invalid.Err))
+	}
+	action := "rejected batch"
+	if e.Skipped {
+		action = "skipped"
+	}
+	return fmt.Sprintf("%s: %d invalid issues: %s", action, len(e.Invalid), strings.Join(parts, "; "))
 }
 
 func NewDBTechnicalDebtIssueStore(db *sql.DB) *DBTechnicalDebtIssueStore {
@@ -98,6 +165,10 @@ func (s *DBTechnicalDebtIssueStore) IssueExists(repositoryID uuid.UUID, filePath
 }
 
 func (s *DBTechnicalDebtIssueStore) Create(issue *models.TechnicalDebtIssue) error {
+	if err := issue.Validate(); err != nil {
+		return fmt.Errorf("invalid issue: %w", err)
+	}
+
 	exists, err := s.IssueExists(issue.RepositoryID, issue.FilePath, issue.LineNumber, issue.IssueType, issue.ToolRuleID)
 	if err != nil {
 		return fmt.Errorf("failed to check for duplicate issue: %w", err)
@@ -112,70 +183,104 @@ func (s *DBTechnicalDebtIssueStore) Create(issue *models.TechnicalDebtIssue) err
 			issue_type, severity, category, message, description, tool_name, tool_rule_id,
 			confidence_score, technical_debt_hours, effort_multiplier, status, code_snippet,
 			fingerprint_hash, jira_sync_status, trello_sync_status,
-			external_id, external_platform, external_url,
+			external_id, external_platform, external_url, metadata,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
 	`
 
 	if issue.ID == uuid.Nil {
 		issue.ID = uuid.New()
 	}
+	if issue.FingerprintHash == "" {
+		issue.FingerprintHash = issue.Fingerprint()
+	}
 	now := time.Now()
 	issue.CreatedAt = now
 	issue.UpdatedAt = now
 
+	metadataJSON, err := marshalIssueMetadata(issue.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue metadata: %w", err)
+	}
+
 	_, execErr := s.db.Exec(query,
 		issue.ID, issue.UserID, issue.RepositoryID, issue.AnalysisRunID, issue.FilePath,
 		issue.LineNumber, issue.ColumnNumber, issue.IssueType, issue.Severity, issue.Category,
 		issue.Message, issue.Description, issue.ToolName, issue.ToolRuleID, issue.ConfidenceScore,
 		issue.TechnicalDebtHours, issue.EffortMultiplier, issue.Status, issue.CodeSnippet,
 		issue.FingerprintHash, issue.JiraSyncStatus, issue.TrelloSyncStatus,
-		issue.ExternalID, issue.ExternalPlatform, issue.ExternalURL,
+		issue.ExternalID, issue.ExternalPlatform, issue.ExternalURL, metadataJSON,
 		issue.CreatedAt, issue.UpdatedAt,
 	)
 	return execErr
 }
 
+// DefaultIssueBatchSize is the number of issues BatchCreate inserts per
+// statement when BatchSize is not set.
+const DefaultIssueBatchSize = 500
+
+// issueInsertColumns are the columns BatchCreate writes, in argument order.
+var issueInsertColumns = []string{
+	"id", "user_id", "repository_id", "analysis_run_id", "file_path", "line_number", "column_number",
+	"issue_type", "severity", "category", "message", "description", "tool_name", "tool_rule_id",
+	"confidence_score", "technical_debt_hours", "effort_multiplier", "status", "code_snippet",
+	"fingerprint_hash", "jira_sync_status", "trello_sync_status",
+	"external_id", "external_platform", "external_url", "metadata",
+	"created_at", "updated_at",
+}
+
+// maxIssueBatchSize keeps a multi-row insert within PostgreSQL's limit of
+// 65535 bind parameters per statement.
+var maxIssueBatchSize = 65535 / len(issueInsertColumns)
+
+// BatchCreate inserts issues in one transaction, so either all of them are
+// written or none. Issues already open or ignored in the repository at the
+// same file, line, type and rule are not inserted again; their
+// analysis_run_id is moved to the new run instead so ResolveMissingIssues
+// leaves them open. The existing issues are loaded up front in one query per
+// repository and the new ones are inserted BatchSize rows per statement.
+// Invalid issues are reported by index in an *InvalidIssuesError; see
+// SkipInvalid.
 func (s *DBTechnicalDebtIssueStore) BatchCreate(issues []models.TechnicalDebtIssue) error {
 	if len(issues) == 0 {
 		return nil
 	}
 
+	var invalidErr error
+	skip := make(map[int]bool)
+	if invalid := models.ValidateIssues(issues); len(invalid) > 0 {
+		invalidErr = &InvalidIssuesError{Invalid: invalid, Skipped: s.SkipInvalid}
+		if !s.SkipInvalid || len(invalid) == len(issues) {
+			return invalidErr
+		}
+		for _, issue := range invalid {
+			skip[issue.Index] = true
+		}
+	}
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO technical_debt_issues (
-			id, user_id, repository_id, analysis_run_id, file_path, line_number, column_number,
-			issue_type, severity, category, message, description, tool_name, tool_rule_id,
-			confidence_score, technical_debt_hours, effort_multiplier, status, code_snippet,
-			fingerprint_hash, jira_sync_status, trello_sync_status,
-			external_id, external_platform, external_url,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
-	`)
+	existing, err := loadExistingIssueIDs(tx, issues, skip)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return err
 	}
-	defer stmt.Close()
 
 	now := time.Now()
-
+	touched := make(map[uuid.UUID][]string)
+	var fresh []*models.TechnicalDebtIssue
 	for i := range issues {
+		if skip[i] {
+			continue
+		}
 		issue := &issues[i]
 
-		exists, err := s.IssueExists(issue.RepositoryID, issue.FilePath, issue.LineNumber, issue.IssueType, issue.ToolRuleID)
-		if err != nil {
-			return fmt.Errorf("failed to check for duplicate issue %d: %w", i, err)
-		}
-		if exists {
-			// Touch the existing issue to update its analysis_run_id
-			// This prevents it from being marked as 'resolved' by ResolveMissingIssues
-			if err := s.TouchExistingIssue(issue.RepositoryID, issue.AnalysisRunID, issue.FilePath, issue.LineNumber, issue.IssueType, issue.ToolRuleID); err != nil {
-				return fmt.Errorf("failed to touch existing issue %d: %w", i, err)
+		if ids := existing[issueDedupKeyOf(issue)]; len(ids) > 0 {
+			for _, id := range ids {
+				touched[issue.AnalysisRunID] = append(touched[issue.AnalysisRunID], id.String())
 			}
 			continue
 		}
@@ -183,29 +288,160 @@ func (s *DBTechnicalDebtIssueStore) BatchCreate(issues []models.TechnicalDebtIss
 		if issue.ID == uuid.Nil {
 			issue.ID = uuid.New()
 		}
+		if issue.FingerprintHash == "" {
+			issue.FingerprintHash = issue.Fingerprint()
+		}
 		issue.CreatedAt = now
 		issue.UpdatedAt = now
+		fresh = append(fresh, issue)
+	}
 
-		var execErr error
-		_, execErr = stmt.Exec(
+	// Touch the existing issues to update their analysis_run_id. This
+	// prevents them from being marked as 'resolved' by ResolveMissingIssues.
+	for runID, ids := range touched {
+		if _, err := tx.Exec(`
+			UPDATE technical_debt_issues
+			SET analysis_run_id = $1, updated_at = NOW()
+			WHERE id = ANY($2::uuid[])
+		`, runID, pq.Array(ids)); err != nil {
+			return fmt.Errorf("failed to touch existing issues: %w", err)
+		}
+	}
+
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultIssueBatchSize
+	}
+	batchSize = min(batchSize, maxIssueBatchSize)
+	for start := 0; start < len(fresh); start += batchSize {
+		batch := fresh[start:min(start+batchSize, len(fresh))]
+		if err := insertIssueBatch(tx, batch); err != nil {
+			return fmt.Errorf("failed to insert issues %d-%d: %w", start, start+len(batch)-1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return invalidErr
+}
+
+// issueDedupKey identifies the issues IssueExists treats as the same one.
+type issueDedupKey struct {
+	repositoryID uuid.UUID
+	filePath     string
+	lineNumber   int
+	hasLine      bool
+	issueType    string
+	toolRuleID   string
+	hasRule      bool
+}
+
+func issueDedupKeyOf(issue *models.TechnicalDebtIssue) issueDedupKey {
+	key := issueDedupKey{repositoryID: issue.RepositoryID, filePath: issue.FilePath, issueType: issue.IssueType}
+	if issue.LineNumber != nil {
+		key.lineNumber, key.hasLine = *issue.LineNumber, true
+	}
+	if issue.ToolRuleID != nil {
+		key.toolRuleID, key.hasRule = *issue.ToolRuleID, true
+	}
+	return key
+}
+
+// loadExistingIssueIDs returns the IDs of the open and ignored issues of the
+// repositories and files of issues, by the key BatchCreate deduplicates on.
+func loadExistingIssueIDs(tx *sql.Tx, issues []models.TechnicalDebtIssue, skip map[int]bool) (map[issueDedupKey][]uuid.UUID, error) {
+	filesByRepository := make(map[uuid.UUID]map[string]bool)
+	for i := range issues {
+		if skip[i] {
+			continue
+		}
+		files := filesByRepository[issues[i].RepositoryID]
+		if files == nil {
+			files = make(map[string]bool)
+			filesByRepository[issues[i].RepositoryID] = files
+		}
+		files[issues[i].FilePath] = true
+	}
+
+	existing := make(map[issueDedupKey][]uuid.UUID)
+	for repositoryID, files := range filesByRepository {
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, path)
+		}
+		rows, err := tx.Query(`
+			SELECT id, file_path, line_number, issue_type, tool_rule_id
+			FROM technical_debt_issues
+			WHERE repository_id = $1
+			  AND file_path = ANY($2)
+			  AND status IN ('open', 'ignored')
+		`, repositoryID, pq.Array(paths))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing issues: %w", err)
+		}
+		for rows.Next() {
+			var id uuid.UUID
+			var line sql.NullInt64
+			var rule sql.NullString
+			key := issueDedupKey{repositoryID: repositoryID}
+			if err := rows.Scan(&id, &key.filePath, &line, &key.issueType, &rule); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan existing issue: %w", err)
+			}
+			key.lineNumber, key.hasLine = int(line.Int64), line.Valid
+			key.toolRuleID, key.hasRule = rule.String, rule.Valid
+			existing[key] = append(existing[key], id)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing issues: %w", err)
+		}
+	}
+	return existing, nil
+}
+
+// insertIssueBatch writes batch with a single multi-row INSERT.
+func insertIssueBatch(tx *sql.Tx, batch []*models.TechnicalDebtIssue) error {
+	var query strings.Builder
+	query.WriteString("INSERT INTO technical_debt_issues (")
+	query.WriteString(strings.Join(issueInsertColumns, ", "))
+	query.WriteString(") VALUES ")
+
+	args := make([]interface{}, 0, len(batch)*len(issueInsertColumns))
+	for i, issue := range batch {
+		metadataJSON, err := marshalIssueMetadata(issue.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata of %s: %w", issue.ID, err)
+		}
+
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for column := range issueInsertColumns {
+			if column > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", len(args)+column+1)
+		}
+		query.WriteString(")")
+
+		args = append(args,
 			issue.ID, issue.UserID, issue.RepositoryID, issue.AnalysisRunID, issue.FilePath,
 			issue.LineNumber, issue.ColumnNumber, issue.IssueType, issue.Severity, issue.Category,
 			issue.Message, issue.Description, issue.ToolName, issue.ToolRuleID, issue.ConfidenceScore,
 			issue.TechnicalDebtHours, issue.EffortMultiplier, issue.Status, issue.CodeSnippet,
 			issue.FingerprintHash, issue.JiraSyncStatus, issue.TrelloSyncStatus,
-			issue.ExternalID, issue.ExternalPlatform, issue.ExternalURL,
+			issue.ExternalID, issue.ExternalPlatform, issue.ExternalURL, metadataJSON,
 			issue.CreatedAt, issue.UpdatedAt,
 		)
-		if execErr != nil {
-			return fmt.Errorf("failed to insert issue %d: %w", i, execErr)
-		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+	_, err := tx.Exec(query.String(), args...)
+	return err
 }
 
 func (s *DBTechnicalDebtIssueStore) Get(id string) (*models.TechnicalDebtIssue, error) {
@@ -216,7 +452,7 @@ func (s *DBTechnicalDebtIssueStore) Get(id string) (*models.TechnicalDebtIssue,
 		       i.resolution_reason, i.assigned_to_user_id, i.resolved_at, i.resolved_by_user_id,
 		       i.ignore_until, i.comments, i.code_snippet, i.surrounding_context,
 		       i.fingerprint_hash, i.jira_sync_status, i.trello_sync_status,
-		       i.external_id, i.external_platform, i.external_url,
+		       i.external_id, i.external_platform, i.external_url, i.metadata,
 		       i.created_at, i.updated_at,
 		       COALESCE(r.name, '') as repository_name,
 		       COALESCE(r.full_name, '') as repository_full_name
@@ -228,6 +464,7 @@ func (s *DBTechnicalDebtIssueStore) Get(id string) (*models.TechnicalDebtIssue,
 	var issue models.TechnicalDebtIssue
 	var assignedTo, resolvedBy sql.NullString
 	var externalIDNull, externalPlatformNull, externalURLNull, fingerprintHashNull sql.NullString
+	var metadataJSON []byte
 	err := s.db.QueryRow(query, id).Scan(
 		&issue.ID, &issue.UserID, &issue.RepositoryID, &issue.AnalysisRunID, &issue.FilePath,
 		&issue.LineNumber, &issue.ColumnNumber, &issue.IssueType, &issue.Severity, &issue.Category,
@@ -236,7 +473,7 @@ func (s *DBTechnicalDebtIssueStore) Get(id string) (*models.TechnicalDebtIssue,
 		&issue.ResolutionReason, &assignedTo, &issue.ResolvedAt, &resolvedBy,
 		&issue.IgnoreUntil, pq.Array(&issue.Comments), &issue.CodeSnippet, &issue.SurroundingContext,
 		&fingerprintHashNull, &issue.JiraSyncStatus, &issue.TrelloSyncStatus,
-		&externalIDNull, &externalPlatformNull, &externalURLNull,
+		&externalIDNull, &externalPlatformNull, &externalURLNull, &metadataJSON,
 		&issue.CreatedAt, &issue.UpdatedAt,
 		&issue.RepositoryName, &issue.RepositoryFullName,
 	)
@@ -257,6 +494,7 @@ func (s *DBTechnicalDebtIssueStore) Get(id string) (*models.TechnicalDebtIssue,
 	if fingerprintHashNull.Valid {
 		issue.FingerprintHash = fingerprintHashNull.String
 	}
+	issue.Metadata = unmarshalIssueMetadata(metadataJSON)
 	return &issue, nil
 }
 
@@ -268,7 +506,7 @@ func (s *DBTechnicalDebtIssueStore) List(limit, offset int) ([]models.TechnicalD
 		       resolution_reason, assigned_to_user_id, resolved_at, resolved_by_user_id,
 		       ignore_until, comments, code_snippet, surrounding_context,
 		       fingerprint_hash, jira_sync_status, trello_sync_status,
-		       external_id, external_platform, external_url,
+		       external_id, external_platform, external_url, metadata,
 		       created_at, updated_at
 		FROM technical_debt_issues
 		ORDER BY created_at DESC
@@ -286,6 +524,7 @@ func (s *DBTechnicalDebtIssueStore) List(limit, offset int) ([]models.TechnicalD
 		var issue models.TechnicalDebtIssue
 		var assignedTo, resolvedBy sql.NullString
 		var externalIDNull, externalPlatformNull, externalURLNull, fingerprintHashNull sql.NullString
+		var metadataJSON []byte
 		err := rows.Scan(
 			&issue.ID, &issue.UserID, &issue.RepositoryID, &issue.AnalysisRunID, &issue.FilePath,
 			&issue.LineNumber, &issue.ColumnNumber, &issue.IssueType, &issue.Severity, &issue.Category,
@@ -294,7 +533,7 @@ func (s *DBTechnicalDebtIssueStore) List(limit, offset int) ([]models.TechnicalD
 			&issue.ResolutionReason, &assignedTo, &issue.ResolvedAt, &resolvedBy,
 			&issue.IgnoreUntil, pq.Array(&issue.Comments), &issue.CodeSnippet, &issue.SurroundingContext,
 			&fingerprintHashNull, &issue.JiraSyncStatus, &issue.TrelloSyncStatus,
-			&externalIDNull, &externalPlatformNull, &externalURLNull,
+			&externalIDNull, &externalPlatformNull, &externalURLNull, &metadataJSON,
 			&issue.CreatedAt, &issue.UpdatedAt,
 		)
 		if err != nil {
@@ -314,12 +553,15 @@ func (s *DBTechnicalDebtIssueStore) List(limit, offset int) ([]models.TechnicalD
 		if fingerprintHashNull.Valid {
 			issue.FingerprintHash = fingerprintHashNull.String
 		}
+		issue.Metadata = unmarshalIssueMetadata(metadataJSON)
 		issues = append(issues, issue)
 	}
 	return issues, nil
 }
 
-func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit, offset int) ([]models.TechnicalDebtIssue, int, error) {
+// buildIssueFilterWhere translates filters into a WHERE clause over the
+// technical_debt_issues alias "i" and its positional arguments.
+func buildIssueFilterWhere(filters IssueFilters) (string, []interface{}, error) {
 	whereClauses := []string{}
 	args := []interface{}{}
 	argCount := 1
@@ -327,7 +569,7 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 	if filters.UserID != nil && *filters.UserID != "" {
 		userUUID, err := uuid.Parse(*filters.UserID)
 		if err != nil {
-			return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+			return "", nil, fmt.Errorf("invalid user ID: %w", err)
 		}
 		whereClauses = append(whereClauses, fmt.Sprintf(`
 			EXISTS (
@@ -340,6 +582,16 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 		argCount++
 	}
 
+	if filters.AssignedToUserID != nil && *filters.AssignedToUserID != "" {
+		assigneeUUID, err := uuid.Parse(*filters.AssignedToUserID)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid assignee ID: %w", err)
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("i.assigned_to_user_id = $%d", argCount))
+		args = append(args, assigneeUUID)
+		argCount++
+	}
+
 	if filters.Severity != nil && *filters.Severity != "" {
 		whereClauses = append(whereClauses, fmt.Sprintf("i.severity = $%d", argCount))
 		args = append(args, *filters.Severity)
@@ -350,6 +602,10 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 		whereClauses = append(whereClauses, fmt.Sprintf("i.status = $%d", argCount))
 		args = append(args, *filters.Status)
 		argCount++
+	} else if len(filters.excludedStatuses) > 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("i.status <> ALL($%d)", argCount))
+		args = append(args, pq.Array(filters.excludedStatuses))
+		argCount++
 	}
 
 	if filters.IssueType != nil && *filters.IssueType != "" {
@@ -361,13 +617,18 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 	if filters.RepositoryID != nil && *filters.RepositoryID != "" {
 		repoUUID, err := uuid.Parse(*filters.RepositoryID)
 		if err != nil {
-			return nil, 0, fmt.Errorf("invalid repository ID: %w", err)
+			return "", nil, fmt.Errorf("invalid repository ID: %w", err)
 		}
 		whereClauses = append(whereClauses, fmt.Sprintf("i.repository_id = $%d", argCount))
 		args = append(args, repoUUID)
 		argCount++
 	}
 
+	if filters.MinConfidence != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("i.confidence_score >= $%d", argCount))
+		args = append(args, *filters.MinConfidence)
+	}
+
 	whereClause := ""
 	if len(whereClauses) > 0 {
 		whereClause = "WHERE " + whereClauses[0]
@@ -375,10 +636,19 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 			whereClause += " AND " + clause
 		}
 	}
+	return whereClause, args, nil
+}
+
+func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit, offset int) ([]models.TechnicalDebtIssue, int, error) {
+	whereClause, args, err := buildIssueFilterWhere(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	argCount := len(args) + 1
 
 	countQuery := "SELECT COUNT(*) FROM technical_debt_issues i " + whereClause
 	var total int
-	err := s.db.QueryRow(countQuery, args...).Scan(&total)
+	err = s.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -390,7 +660,7 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 			i.resolution_reason, i.assigned_to_user_id, i.resolved_at, i.resolved_by_user_id,
 			i.ignore_until, i.comments, i.code_snippet, i.surrounding_context,
 			i.fingerprint_hash, i.jira_sync_status, i.trello_sync_status,
-			i.external_id, i.external_platform, i.external_url,
+			i.external_id, i.external_platform, i.external_url, i.metadata,
 			i.created_at, i.updated_at,
 			COALESCE(r.name, '') as repository_name,
 			COALESCE(r.full_name, '') as repository_full_name
@@ -421,6 +691,7 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 		var issue models.TechnicalDebtIssue
 		var assignedTo, resolvedBy sql.NullString
 		var externalIDNull, externalPlatformNull, externalURLNull, fingerprintHashNull sql.NullString
+		var metadataJSON []byte
 		err := rows.Scan(
 			&issue.ID, &issue.UserID, &issue.RepositoryID, &issue.AnalysisRunID, &issue.FilePath,
 			&issue.LineNumber, &issue.ColumnNumber, &issue.IssueType, &issue.Severity, &issue.Category,
@@ -429,7 +700,7 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 			&issue.ResolutionReason, &assignedTo, &issue.ResolvedAt, &resolvedBy,
 			&issue.IgnoreUntil, pq.Array(&issue.Comments), &issue.CodeSnippet, &issue.SurroundingContext,
 			&fingerprintHashNull, &issue.JiraSyncStatus, &issue.TrelloSyncStatus,
-			&externalIDNull, &externalPlatformNull, &externalURLNull,
+			&externalIDNull, &externalPlatformNull, &externalURLNull, &metadataJSON,
 			&issue.CreatedAt, &issue.UpdatedAt,
 			&issue.RepositoryName, &issue.RepositoryFullName,
 		)
@@ -450,12 +721,27 @@ func (s *DBTechnicalDebtIssueStore) ListWithFilters(filters IssueFilters, limit,
 		if fingerprintHashNull.Valid {
 			issue.FingerprintHash = fingerprintHashNull.String
 		}
+		issue.Metadata = unmarshalIssueMetadata(metadataJSON)
 		issues = append(issues, issue)
 	}
 
 	return issues, total, nil
 }
 
+// ListAssignedTo lists the issues assigned to userID with the same filtering,
+// ordering and paging as ListWithFilters. Unassigned issues never match.
+// Resolved and ignored issues are left out unless filters.Status selects them.
+func (s *DBTechnicalDebtIssueStore) ListAssignedTo(userID string, filters IssueFilters, limit, offset int) ([]models.TechnicalDebtIssue, int, error) {
+	if userID == "" {
+		return nil, 0, fmt.Errorf("invalid assignee ID: empty")
+	}
+	filters.AssignedToUserID = &userID
+	if filters.Status == nil || *filters.Status == "" {
+		filters.excludedStatuses = closedIssueStatuses
+	}
+	return s.ListWithFilters(filters, limit, offset)
+}
+
 // TouchExistingIssue updates the analysis_run_id of an existing issue to mark it as still present in the codebase.
 // This prevents the issue from being auto-resolved by ResolveMissingIssues.
 func (s *DBTechnicalDebtIssueStore) TouchExistingIssue(repositoryID uuid.UUID, analysisRunID uuid.UUID, filePath string, lineNumber *int, issueType string, toolRuleID *string) error {
@@ -643,6 +929,58 @@ func (s *DBTechnicalDebtIssueStore) GetOpenIssueSummary(repositoryID uuid.UUID)
 	return &summary, nil
 }
 
+// openCountSeverities are the severities always present in the result of
+// CountOpenByRepository, so callers can index it without checking.
+var openCountSeverities = []string{"critical", "high", "medium", "low", "info"}
+
+// NewOpenSeverityCounts returns a severity count map with every known
+// severity set to zero.
+func NewOpenSeverityCounts() map[string]int {
+	counts := make(map[string]int, len(openCountSeverities))
+	for _, severity := range openCountSeverities {
+		counts[severity] = 0
+	}
+	return counts
+}
+
+// CountOpenByRepository returns the number of open issues per severity stored for a repository.
+// It is a single aggregate query meant for quality-gate pre-checks that should not re-analyze anything.
+// A repository without issues yields all zeros rather than an error.
+func (s *DBTechnicalDebtIssueStore) CountOpenByRepository(repositoryID string) (map[string]int, error) {
+	repoID, err := uuid.Parse(repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository ID %q: %w", repositoryID, err)
+	}
+
+	query := `
+		SELECT severity, COUNT(*)
+		FROM technical_debt_issues
+		WHERE repository_id = $1 AND status = 'open'
+		GROUP BY severity
+	`
+
+	rows, err := s.db.Query(query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open issues: %w", err)
+	}
+	defer rows.Close()
+
+	counts := NewOpenSeverityCounts()
+	for rows.Next() {
+		var severity string
+		var count int
+		if err := rows.Scan(&severity, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan open issue count: %w", err)
+		}
+		counts[severity] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count open issues: %w", err)
+	}
+
+	return counts, nil
+}
+
 // ReconcileIssuesForAnalyzer performs an atomic "Sync-to-Truth" upsert.
 // It ensures that stable issues (matching fingerprint) are updated, not recreated.
 // Issues present in the DB but missing from the current analysis are marked as 'resolved'.
@@ -662,14 +1000,14 @@ func (s *DBTechnicalDebtIssueStore) ReconcileIssuesForAnalyzer(repositoryID uuid
 			id, user_id, repository_id, analysis_run_id, file_path, line_number, column_number,
 			issue_type, severity, category, message, description, tool_name, tool_rule_id,
 			confidence_score, technical_debt_hours, effort_multiplier, status, code_snippet, surrounding_context,
-			fingerprint_hash, jira_sync_status, trello_sync_status,
+			fingerprint_hash, jira_sync_status, trello_sync_status, metadata,
 			created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7,
 			$8, $9, $10, $11, $12, $13, $14,
 			$15, $16, $17, 'open', $18, $19,
-			$20, $21, $22,
-			$23, $24
+			$20, $21, $22, $23,
+			$24, $25
 		)
 		ON CONFLICT (repository_id, fingerprint_hash) WHERE status = 'open'
 		DO UPDATE SET
@@ -679,7 +1017,8 @@ func (s *DBTechnicalDebtIssueStore) ReconcileIssuesForAnalyzer(repositoryID uuid
 			line_number = EXCLUDED.line_number,
 			column_number = EXCLUDED.column_number,
 			code_snippet = EXCLUDED.code_snippet,
-			surrounding_context = EXCLUDED.surrounding_context
+			surrounding_context = EXCLUDED.surrounding_context,
+			metadata = EXCLUDED.metadata
 	`
 
 	stmt, err := tx.Prepare(query)
@@ -696,6 +1035,9 @@ func (s *DBTechnicalDebtIssueStore) ReconcileIssuesForAnalyzer(repositoryID uuid
 		if issue.ID == uuid.Nil {
 			issue.ID = uuid.New()
 		}
+		if issue.FingerprintHash == "" {
+			issue.FingerprintHash = issue.Fingerprint()
+		}
 
 		// Ensure sync status defaults are set if empty (though model/DB should handle this)
 		if issue.JiraSyncStatus == "" {
@@ -705,11 +1047,16 @@ func (s *DBTechnicalDebtIssueStore) ReconcileIssuesForAnalyzer(repositoryID uuid
 			issue.TrelloSyncStatus = "pending"
 		}
 
-		_, err := stmt.Exec(
+		metadataJSON, err := marshalIssueMetadata(issue.Metadata)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to marshal metadata for issue %s: %w", issue.FilePath, err)
+		}
+
+		_, err = stmt.Exec(
 			issue.ID, issue.UserID, issue.RepositoryID, issue.AnalysisRunID, issue.FilePath, issue.LineNumber, issue.ColumnNumber,
 			issue.IssueType, issue.Severity, issue.Category, issue.Message, issue.Description, issue.ToolName, issue.ToolRuleID,
 			issue.ConfidenceScore, issue.TechnicalDebtHours, issue.EffortMultiplier, issue.CodeSnippet, issue.SurroundingContext,
-			issue.FingerprintHash, issue.JiraSyncStatus, issue.TrelloSyncStatus,
+			issue.FingerprintHash, issue.JiraSyncStatus, issue.TrelloSyncStatus, metadataJSON,
 			now, now,
 		)
 		if err != nil {
@@ -1010,3 +1357,82 @@ WHERE i.repository_id = $1
 
 	return issues, nil
 }
+
+// GetLatestRunIssueKeys returns the analysis.DiffKey of the issues recorded
+// by the most recent completed analysis run of the repository stored under
+// fullName (e.g. "acme/api", compared case-insensitively). found is false when
+// the repository has no completed run yet.
+func (s *DBTechnicalDebtIssueStore) GetLatestRunIssueKeys(ctx context.Context, fullName string) (keys map[string]bool, found bool, err error) {
+	var runID uuid.UUID
+	err = s.db.QueryRowContext(ctx, `
+		SELECT ar.id FROM analysis_runs ar
+		JOIN user_repositories ur ON ar.repository_id = ur.id
+		WHERE LOWER(ur.full_name) = LOWER($1) AND ar.status = 'completed'
+		ORDER BY COALESCE(ar.completed_at, ar.started_at) DESC
+		LIMIT 1
+	`, fullName).Scan(&runID)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find latest completed run: %w", err)
+	}
+
+	keys, err = s.runIssueKeys(ctx, runID)
+	if err != nil {
+		return nil, false, err
+	}
+	return keys, true, nil
+}
+
+// GetRunIssueKeys returns the full name of the repository of the analysis run
+// runID, or "" when the repository is no longer stored, and the
+// analysis.DiffKey of the issues it recorded. It returns ErrRunNotFound when
+// there is no such run.
+func (s *DBTechnicalDebtIssueStore) GetRunIssueKeys(ctx context.Context, runID uuid.UUID) (fullName string, keys map[string]bool, err error) {
+	var name sql.NullString
+	err = s.db.QueryRowContext(ctx, `
+		SELECT ur.full_name FROM analysis_runs ar
+		LEFT JOIN user_repositories ur ON ar.repository_id = ur.id
+		WHERE ar.id = $1
+	`, runID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil, ErrRunNotFound
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find run %s: %w", runID, err)
+	}
+
+	keys, err = s.runIssueKeys(ctx, runID)
+	if err != nil {
+		return "", nil, err
+	}
+	return name.String, keys, nil
+}
+
+// runIssueKeys computes the keys from the fields of the issues rather than
+// reading fingerprint_hash, which embeds the stored repository ID.
+func (s *DBTechnicalDebtIssueStore) runIssueKeys(ctx context.Context, runID uuid.UUID) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT file_path, issue_type, tool_rule_id, message FROM technical_debt_issues
+		WHERE analysis_run_id = $1
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues for run %s: %w", runID, err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var issue models.TechnicalDebtIssue
+		var toolRuleID sql.NullString
+		if err := rows.Scan(&issue.FilePath, &issue.IssueType, &toolRuleID, &issue.Message); err != nil {
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+		if toolRuleID.Valid {
+			issue.ToolRuleID = &toolRuleID.String
+		}
+		keys[analysis.DiffKey(issue)] = true
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,290 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
+	"github.com/endrilickollari/debtdrone-cli/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIssueFilterWhere_AssignedTo(t *testing.T) {
+	assignee := uuid.New()
+	assigneeID := assignee.String()
+
+	t.Run("constrains on assignee so unassigned issues are excluded", func(t *testing.T) {
+		where, args, err := buildIssueFilterWhere(IssueFilters{AssignedToUserID: &assigneeID})
+		require.NoError(t, err)
+
+		// assigned_to_user_id = $1 is never true for a NULL assignee.
+		assert.Equal(t, "WHERE i.assigned_to_user_id = $1", where)
+		assert.Equal(t, []interface{}{assignee}, args)
+	})
+
+	t.Run("hides closed issues without a status filter", func(t *testing.T) {
+		filters := IssueFilters{AssignedToUserID: &assigneeID, excludedStatuses: closedIssueStatuses}
+		where, args, err := buildIssueFilterWhere(filters)
+		require.NoError(t, err)
+
+		assert.Equal(t, "WHERE i.assigned_to_user_id = $1 AND i.status <> ALL($2)", where)
+		require.Len(t, args, 2)
+		assert.Equal(t, pq.Array(closedIssueStatuses), args[1])
+	})
+
+	t.Run("an explicit status filter wins over the closed default", func(t *testing.T) {
+		resolved := "resolved"
+		severity := "high"
+		filters := IssueFilters{
+			AssignedToUserID: &assigneeID,
+			Status:           &resolved,
+			Severity:         &severity,
+			excludedStatuses: closedIssueStatuses,
+		}
+		where, args, err := buildIssueFilterWhere(filters)
+		require.NoError(t, err)
+
+		assert.Equal(t, "WHERE i.assigned_to_user_id = $1 AND i.severity = $2 AND i.status = $3", where)
+		assert.Equal(t, []interface{}{assignee, "high", "resolved"}, args)
+	})
+
+	t.Run("rejects a malformed assignee ID", func(t *testing.T) {
+		bad := "not-a-uuid"
+		_, _, err := buildIssueFilterWhere(IssueFilters{AssignedToUserID: &bad})
+		assert.ErrorContains(t, err, "invalid assignee ID")
+	})
+}
+
+func TestBatchCreate_InvalidIssues(t *testing.T) {
+	valid := models.TechnicalDebtIssue{
+		FilePath:         "/main.go",
+		Severity:         "low",
+		Category:         "reliability",
+		Message:          "Error return value is not checked",
+		EffortMultiplier: 1.0,
+		Status:           "open",
+	}
+	invalid := valid
+	invalid.Severity = ""
+
+	t.Run("rejects the batch before writing by default", func(t *testing.T) {
+		// The store has no database: reaching it would panic.
+		s := &DBTechnicalDebtIssueStore{}
+		err := s.BatchCreate([]models.TechnicalDebtIssue{valid, invalid, valid, invalid})
+
+		var invalidErr *InvalidIssuesError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.False(t, invalidErr.Skipped)
+		require.Len(t, invalidErr.Invalid, 2)
+		assert.Equal(t, 1, invalidErr.Invalid[0].Index)
+		assert.Equal(t, 3, invalidErr.Invalid[1].Index)
+		assert.Contains(t, err.Error(), "issue 1: invalid severity")
+	})
+
+	t.Run("skip mode reports a batch with nothing valid without writing", func(t *testing.T) {
+		s := &DBTechnicalDebtIssueStore{SkipInvalid: true}
+		err := s.BatchCreate([]models.TechnicalDebtIssue{invalid})
+
+		var invalidErr *InvalidIssuesError
+		require.ErrorAs(t, err, &invalidErr)
+		assert.True(t, invalidErr.Skipped)
+		assert.Equal(t, 0, invalidErr.Invalid[0].Index)
+	})
+
+	t.Run("Create rejects an invalid issue", func(t *testing.T) {
+		s := &DBTechnicalDebtIssueStore{}
+		err := s.Create(&invalid)
+		assert.ErrorContains(t, err, "invalid issue: invalid severity")
+	})
+}
+
+func TestCountOpenByRepository_InvalidID(t *testing.T) {
+	// The store has no database: reaching it would panic.
+	s := &DBTechnicalDebtIssueStore{}
+	_, err := s.CountOpenByRepository("not-a-uuid")
+	assert.ErrorContains(t, err, `invalid repository ID "not-a-uuid"`)
+}
+
+// newFakeIssueStore returns a store whose database is d.
+func newFakeIssueStore(tb testing.TB, d *fakeDB) *DBTechnicalDebtIssueStore {
+	return NewDBTechnicalDebtIssueStore(openFakeDB(tb, d))
+}
+
+// existingIssues answers the lookup of existing issues BatchCreate sends
+// with values.
+func existingIssues(values ...[]driver.Value) func(string, []driver.Value) (*fakeRows, error) {
+	return func(string, []driver.Value) (*fakeRows, error) {
+		return &fakeRows{columns: []string{"id", "file_path", "line_number", "issue_type", "tool_rule_id"}, values: append([][]driver.Value(nil), values...)}, nil
+	}
+}
+
+func batchIssues(repositoryID uuid.UUID, n int) []models.TechnicalDebtIssue {
+	rule := "cyclomatic-complexity"
+	issues := make([]models.TechnicalDebtIssue, n)
+	for i := range issues {
+		line := i + 1
+		issues[i] = models.TechnicalDebtIssue{
+			RepositoryID:     repositoryID,
+			AnalysisRunID:    uuid.New(),
+			FilePath:         "/main.go",
+			LineNumber:       &line,
+			IssueType:        "complexity",
+			ToolRuleID:       &rule,
+			Severity:         "low",
+			Category:         "maintainability",
+			Message:          "Function has high complexity",
+			EffortMultiplier: 1.0,
+			Status:           "open",
+		}
+	}
+	return issues
+}
+
+func TestBatchCreate_Batches(t *testing.T) {
+	repositoryID := uuid.New()
+	issues := batchIssues(repositoryID, 1200)
+	existingID := uuid.New()
+	d := &fakeDB{query: existingIssues(
+		[]driver.Value{existingID.String(), "/main.go", int64(7), "complexity", "cyclomatic-complexity"},
+		// Same place, no rule: a different issue.
+		[]driver.Value{uuid.New().String(), "/main.go", int64(8), "complexity", nil},
+	)}
+	s := newFakeIssueStore(t, d)
+	s.BatchSize = 500
+
+	require.NoError(t, s.BatchCreate(issues))
+
+	assert.Equal(t, 1, d.commits)
+	assert.Len(t, d.queries, 1, "existing issues are loaded once per repository")
+	inserts := d.statements("INSERT INTO")
+	require.Len(t, inserts, 3)
+	columns := len(issueInsertColumns)
+	assert.Equal(t, []int{500 * columns, 500 * columns, 199 * columns}, []int{len(inserts[0].args), len(inserts[1].args), len(inserts[2].args)})
+
+	touches := d.statements("UPDATE")
+	require.Len(t, touches, 1, "the issue already stored at line 7 is touched instead of inserted")
+	assert.Len(t, touches[0].args, 2)
+	assert.NotEqual(t, uuid.Nil, issues[0].ID)
+	assert.NotEmpty(t, issues[0].FingerprintHash)
+}
+
+func TestBatchCreate_RollsBackOnFailure(t *testing.T) {
+	inserts := 0
+	d := &fakeDB{exec: func(query string, args []driver.Value) (driver.Result, error) {
+		if strings.Contains(query, "INSERT INTO") {
+			if inserts++; inserts == 2 {
+				return nil, fmt.Errorf("connection reset")
+			}
+		}
+		return driver.RowsAffected(1), nil
+	}}
+	s := newFakeIssueStore(t, d)
+	s.BatchSize = 10
+
+	err := s.BatchCreate(batchIssues(uuid.New(), 25))
+	assert.ErrorContains(t, err, "failed to insert issues 10-19")
+	assert.Zero(t, d.commits, "a failed batch must not commit the ones before it")
+	assert.Equal(t, 1, d.rollbacks)
+}
+
+func TestBatchCreate_HardcodedEndpoint(t *testing.T) {
+	// Shaped as the endpoints analyzer reports a hardcoded IP address.
+	line, rule := 7, "hardcoded-ip"
+	issue := models.TechnicalDebtIssue{
+		RepositoryID:       uuid.New(),
+		AnalysisRunID:      uuid.New(),
+		FilePath:           "/client.go",
+		LineNumber:         &line,
+		IssueType:          "hardcoded_endpoint",
+		Severity:           "medium",
+		Category:           "configuration",
+		Message:            "Hardcoded IP address 10.0.4.12:6379",
+		ToolName:           "hardcoded_endpoint",
+		ToolRuleID:         &rule,
+		ConfidenceScore:    0.9,
+		TechnicalDebtHours: 0.5,
+		EffortMultiplier:   1.0,
+		Status:             "open",
+		Metadata:           map[string]interface{}{"endpoint": "10.0.4.12:6379", "host": "10.0.4.12"},
+	}
+	d := &fakeDB{}
+	s := newFakeIssueStore(t, d)
+
+	require.NoError(t, s.BatchCreate([]models.TechnicalDebtIssue{issue}))
+	assert.Len(t, d.statements("INSERT INTO"), 1)
+	assert.Equal(t, 1, d.commits)
+}
+
+// BenchmarkBatchCreate inserts 50k issues with a simulated round-trip time.
+// A batch size of 1 costs what the former per-row inserts did, minus their
+// per-row duplicate lookup.
+func BenchmarkBatchCreate(b *testing.B) {
+	for _, batchSize := range []int{1, DefaultIssueBatchSize} {
+		b.Run(fmt.Sprintf("batch-%d", batchSize), func(b *testing.B) {
+			issues := batchIssues(uuid.New(), 50000)
+			d := &fakeDB{latency: 20 * time.Microsecond}
+			s := newFakeIssueStore(b, d)
+			s.BatchSize = batchSize
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.execs = nil
+				if err := s.BatchCreate(issues); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(d.execs)), "statements/op")
+		})
+	}
+}
+
+func TestRunIssueKeys(t *testing.T) {
+	runID := uuid.New()
+	rule := "cyclomatic-complexity"
+	var fullNames []driver.Value
+	d := &fakeDB{query: func(query string, args []driver.Value) (*fakeRows, error) {
+		switch {
+		case strings.Contains(query, "SELECT ar.id"):
+			fullNames = append(fullNames, args[0])
+			return &fakeRows{columns: []string{"id"}, values: [][]driver.Value{{runID.String()}}}, nil
+		case strings.Contains(query, "SELECT ur.full_name"):
+			if args[0] != runID.String() {
+				return &fakeRows{columns: []string{"full_name"}}, nil
+			}
+			return &fakeRows{columns: []string{"full_name"}, values: [][]driver.Value{{"acme/api"}}}, nil
+		}
+		return &fakeRows{columns: []string{"file_path", "issue_type", "tool_rule_id", "message"}, values: [][]driver.Value{
+			{"/main.go", "complexity", rule, "Function 'run' has cyclomatic complexity of 14"},
+			{"/util.go", "large_file", nil, "File defines 32 functions"},
+		}}, nil
+	}}
+	s := newFakeIssueStore(t, d)
+
+	// The same issues found by a local scan, under the path-derived ID.
+	local := []models.TechnicalDebtIssue{
+		{RepositoryID: uuid.New(), FilePath: "/main.go", IssueType: "complexity", ToolRuleID: &rule, Message: "Function 'run' has cyclomatic complexity of 17"},
+		{RepositoryID: uuid.New(), FilePath: "/util.go", IssueType: "large_file", Message: "File defines 35 functions"},
+	}
+
+	fullName, keys, err := s.GetRunIssueKeys(context.Background(), runID)
+	require.NoError(t, err)
+	assert.Equal(t, "acme/api", fullName)
+	for _, issue := range local {
+		assert.True(t, keys[analysis.DiffKey(issue)], "expected %s to match the stored run", issue.FilePath)
+	}
+
+	keys, found, err := s.GetLatestRunIssueKeys(context.Background(), "Acme/API")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, []driver.Value{"Acme/API"}, fullNames)
+
+	_, _, err = s.GetRunIssueKeys(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrRunNotFound)
+}
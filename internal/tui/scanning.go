@@ -12,6 +12,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/endrilickollari/debtdrone-cli/internal/analysis"
 	"github.com/endrilickollari/debtdrone-cli/internal/models"
 	"github.com/endrilickollari/debtdrone-cli/internal/service"
 	"github.com/google/uuid"
@@ -44,16 +45,19 @@ func startScan(path string, maxComplexity int, securityScan bool, progressChan c
 	return func() tea.Msg {
 		go func() {
 			svc := service.NewScanService()
-			ctx := context.WithValue(context.Background(), "isCLI", true)
+			ctx := analysis.WithCLI(context.Background())
 			opts := service.ScanOptions{
 				MaxComplexity: maxComplexity,
 				SecurityScan:  securityScan,
 			}
 
-			issues, err := svc.Run(ctx, path, opts, func(p service.ScanProgress) {
+			result, err := svc.Run(ctx, path, opts, func(p service.ScanProgress) {
+				if p.Done {
+					return
+				}
 				progressChan <- scanProgressMsg{
 					Task:     "Running " + p.AnalyzerName + "...",
-					Progress: float64(p.Index) / float64(p.Total),
+					Progress: float64(p.Completed) / float64(p.Total),
 				}
 				time.Sleep(300 * time.Millisecond)
 			})
@@ -67,7 +71,7 @@ func startScan(path string, maxComplexity int, securityScan bool, progressChan c
 
 			progressChan <- scanProgressMsg{Task: "Finalizing results...", Progress: 1.0}
 			time.Sleep(500 * time.Millisecond)
-			progressChan <- scanCompleteMsg{path: path, issues: issues}
+			progressChan <- scanCompleteMsg{path: path, issues: result.Issues}
 		}()
 		return nil
 	}